@@ -0,0 +1,166 @@
+// Package analytics aggregates gameplay statistics, such as where games
+// open and how often the first player wins, from the games in the store.
+package analytics
+
+import (
+	"sort"
+
+	"htmx-go-app/models"
+)
+
+// Stats summarizes play across a set of games
+type Stats struct {
+	Games         int `json:"games"`         // games looked at
+	FinishedGames int `json:"finishedGames"` // won or drawn
+	Draws         int `json:"draws"`
+
+	// FirstMoves counts, for each cell, the games that opened there, and
+	// CellPlays how often it was played at any point
+	FirstMoves [models.BoardSize][models.BoardSize]int `json:"firstMoves"`
+	CellPlays  [models.BoardSize][models.BoardSize]int `json:"cellPlays"`
+
+	AverageMoves           float64 `json:"averageMoves"`           // per finished game
+	AverageDurationSeconds float64 `json:"averageDurationSeconds"` // per finished game
+	FirstPlayerWins        int     `json:"firstPlayerWins"`
+	FirstPlayerWinRate     float64 `json:"firstPlayerWinRate"` // share of finished games, draws included
+
+	Emojis []EmojiCount `json:"emojis"` // most picked first
+
+	// Openings breaks finished games down by the kind of square they opened
+	// on, center first
+	Openings []OpeningStats `json:"openings"`
+}
+
+// Opening is the kind of square a game's first move is played on
+type Opening string
+
+const (
+	OpeningCenter Opening = "center"
+	OpeningCorner Opening = "corner"
+	OpeningEdge   Opening = "edge"
+)
+
+// OpeningOf tells which kind of square row, col is
+func OpeningOf(row, col int) Opening {
+	center := models.BoardSize / 2
+	switch {
+	case row == center && col == center:
+		return OpeningCenter
+	case row != center && col != center:
+		return OpeningCorner
+	default:
+		return OpeningEdge
+	}
+}
+
+// OpeningStats is how finished games that opened on one kind of square ended
+type OpeningStats struct {
+	Opening          Opening `json:"opening"`
+	Games            int     `json:"games"`
+	FirstPlayerWins  int     `json:"firstPlayerWins"`
+	SecondPlayerWins int     `json:"secondPlayerWins"`
+	Draws            int     `json:"draws"`
+}
+
+// FirstPlayerWinRate is the share of these games the opener won
+func (o OpeningStats) FirstPlayerWinRate() float64 {
+	return o.rate(o.FirstPlayerWins)
+}
+
+// SecondPlayerWinRate is the share of these games the opener lost
+func (o OpeningStats) SecondPlayerWinRate() float64 {
+	return o.rate(o.SecondPlayerWins)
+}
+
+// DrawRate is the share of these games that were drawn
+func (o OpeningStats) DrawRate() float64 {
+	return o.rate(o.Draws)
+}
+
+func (o OpeningStats) rate(count int) float64 {
+	if o.Games == 0 {
+		return 0
+	}
+	return float64(count) / float64(o.Games)
+}
+
+// EmojiCount is how many players picked an emoji
+type EmojiCount struct {
+	Emoji string `json:"emoji"`
+	Count int    `json:"count"`
+}
+
+// MostPopularEmoji returns the emoji picked most often, or "" if none was
+func (s Stats) MostPopularEmoji() string {
+	if len(s.Emojis) == 0 {
+		return ""
+	}
+	return s.Emojis[0].Emoji
+}
+
+// Compute aggregates stats over games
+func Compute(games []*models.Game) Stats {
+	stats := Stats{Games: len(games), Emojis: []EmojiCount{}}
+	stats.Openings = []OpeningStats{{Opening: OpeningCenter}, {Opening: OpeningCorner}, {Opening: OpeningEdge}}
+	openings := map[Opening]*OpeningStats{}
+	for i := range stats.Openings {
+		openings[stats.Openings[i].Opening] = &stats.Openings[i]
+	}
+	emojis := map[string]int{}
+	var moves int
+	var seconds float64
+
+	for _, g := range games {
+		for _, player := range g.Players {
+			if player.Emoji != "" {
+				emojis[player.Emoji]++
+			}
+		}
+		if len(g.Moves) > 0 {
+			stats.FirstMoves[g.Moves[0].Row][g.Moves[0].Col]++
+		}
+		for _, move := range g.Moves {
+			stats.CellPlays[move.Row][move.Col]++
+		}
+		if g.Status != models.GameStatusFinished && g.Status != models.GameStatusDraw {
+			continue
+		}
+
+		stats.FinishedGames++
+		moves += g.MoveCount
+		seconds += g.FinishedAt.Sub(g.StartedAt).Seconds()
+		opening := &OpeningStats{}
+		if len(g.Moves) > 0 {
+			opening = openings[OpeningOf(g.Moves[0].Row, g.Moves[0].Col)]
+			opening.Games++
+		}
+		switch {
+		case g.Status == models.GameStatusDraw:
+			stats.Draws++
+			opening.Draws++
+		case len(g.PlayerOrder) > 0 && g.Winner == g.PlayerOrder[0]:
+			stats.FirstPlayerWins++
+			opening.FirstPlayerWins++
+		default:
+			opening.SecondPlayerWins++
+		}
+	}
+
+	if stats.FinishedGames > 0 {
+		finished := float64(stats.FinishedGames)
+		stats.AverageMoves = float64(moves) / finished
+		stats.AverageDurationSeconds = seconds / finished
+		stats.FirstPlayerWinRate = float64(stats.FirstPlayerWins) / finished
+	}
+
+	for emoji, count := range emojis {
+		stats.Emojis = append(stats.Emojis, EmojiCount{Emoji: emoji, Count: count})
+	}
+	sort.Slice(stats.Emojis, func(i, j int) bool {
+		if stats.Emojis[i].Count != stats.Emojis[j].Count {
+			return stats.Emojis[i].Count > stats.Emojis[j].Count
+		}
+		return stats.Emojis[i].Emoji < stats.Emojis[j].Emoji
+	})
+	return stats
+}
@@ -0,0 +1,149 @@
+// Package audit records administrative and destructive actions, such as
+// bans and deleted games, so they can be reviewed later.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"htmx-go-app/clock"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionGameReset       = "game.reset"
+	ActionGameDeleted     = "game.deleted"
+	ActionGameCancelled   = "game.cancelled"
+	ActionPlayerBanned    = "player.banned"
+	ActionPlayerUnbanned  = "player.unbanned"
+	ActionAnnouncement    = "announcement.posted"
+	ActionAnnouncementEnd = "announcement.cleared"
+	ActionMaintenance     = "maintenance.changed"
+	ActionConfigReloaded  = "config.reloaded"
+	ActionBotRegistered   = "bot.registered"
+	ActionBotRevoked      = "bot.revoked"
+	ActionScenarioCreated = "scenario.created"
+	ActionGameCorrected   = "game.corrected"
+	ActionBackupRestored  = "backup.restored"
+)
+
+// ActorAdmin is the actor for requests made with the admin API key
+const ActorAdmin = "admin"
+
+// MaxEntries bounds how many entries are kept in memory; the log file keeps
+// everything
+const MaxEntries = 1000
+
+// Entry is one recorded action
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"` // ActorAdmin or a player ID
+	IP     string    `json:"ip,omitempty"`
+	Action string    `json:"action"`
+	GameID string    `json:"gameId,omitempty"`
+	Target string    `json:"target,omitempty"` // what the action applied to besides the game, e.g. a banned player
+	Detail string    `json:"detail,omitempty"`
+}
+
+var (
+	mu      sync.Mutex
+	entries []Entry
+	file    *os.File
+)
+
+// Open loads the entries already in the log file at path and appends new
+// ones to it. Without it the log is kept in memory only.
+func Open(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+
+	var loaded []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			f.Close()
+			return fmt.Errorf("reading audit log: %w", err)
+		}
+		loaded = append(loaded, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return fmt.Errorf("reading audit log: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if file != nil {
+		file.Close()
+	}
+	file = f
+	entries = trim(loaded)
+	return nil
+}
+
+// Close stops writing to the log file
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if file == nil {
+		return nil
+	}
+	err := file.Close()
+	file = nil
+	return err
+}
+
+// Record adds an entry, stamping it with the current time if it has none.
+// An error means the entry couldn't be written to the log file; it is
+// still kept in memory.
+func Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = clock.Now().UTC()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	entries = trim(append(entries, entry))
+	if file == nil {
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// Entries returns up to limit entries matching keep, newest first. A limit
+// of zero or less returns every match.
+func Entries(limit int, keep func(Entry) bool) []Entry {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result := []Entry{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if keep != nil && !keep(entries[i]) {
+			continue
+		}
+		result = append(result, entries[i])
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result
+}
+
+func trim(list []Entry) []Entry {
+	if len(list) > MaxEntries {
+		return append([]Entry(nil), list[len(list)-MaxEntries:]...)
+	}
+	return list
+}
@@ -0,0 +1,77 @@
+// Package buildinfo reports which build of the server is running. Version,
+// Commit and BuildTime are stamped in at link time, for example:
+//
+//	go build -ldflags "-X htmx-go-app/buildinfo.Version=v1.2.0 \
+//	  -X htmx-go-app/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X htmx-go-app/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without them, the commit and time recorded by the Go toolchain are used.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set with -ldflags "-X htmx-go-app/buildinfo.<Name>=<value>"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"buildTime,omitempty"`
+	Modified  bool   `json:"modified,omitempty"` // built from a working tree with uncommitted changes
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build information, falling back to the VCS details the Go
+// toolchain embeds when the link-time values weren't set
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildTime == "" {
+				info.BuildTime = setting.Value
+			}
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// String formats the build for logs, e.g. "v1.2.0 (3f2c1ab, 2025-01-02T15:04:05Z)"
+func (i Info) String() string {
+	s := i.Version
+	commit := i.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	switch {
+	case commit != "" && i.BuildTime != "":
+		s += " (" + commit + ", " + i.BuildTime + ")"
+	case commit != "":
+		s += " (" + commit + ")"
+	}
+	return s
+}
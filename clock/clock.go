@@ -0,0 +1,149 @@
+// Package clock is the one source of the current time for game state,
+// expiry and timers. The real clock is used unless a test swaps in a Fake,
+// which only moves when told to, so timeouts can be tested without waiting.
+//
+// Network deadlines and keep-alives stay on the real clock, since the
+// connections they guard run in real time.
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and runs functions after a delay
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a pending AfterFunc call
+type Timer interface {
+	// Stop cancels the call, reporting whether it was still pending
+	Stop() bool
+}
+
+var (
+	mu      sync.RWMutex
+	current Clock = Real{}
+)
+
+// Use replaces the clock; nil restores the real one
+func Use(c Clock) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if c == nil {
+		c = Real{}
+	}
+	current = c
+}
+
+func get() Clock {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Now returns the current time
+func Now() time.Time {
+	return get().Now()
+}
+
+// Since returns the time elapsed since t
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// AfterFunc calls f once d has passed
+func AfterFunc(d time.Duration, f func()) Timer {
+	return get().AfterFunc(d, f)
+}
+
+// Real is the system clock
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+func (Real) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// Fake is a clock that stands still until Advance is called
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFake returns a fake clock set to start
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	timer := &fakeTimer{clock: f, at: f.now.Add(d), fn: fn}
+	f.timers = append(f.timers, timer)
+	return timer
+}
+
+// Advance moves the clock forward by d and runs the timers that came due,
+// earliest first. Unlike real timers they run before Advance returns, so a
+// test can check their effects right away.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	var due, pending []*fakeTimer
+	for _, timer := range f.timers {
+		if timer.at.After(f.now) {
+			pending = append(pending, timer)
+		} else {
+			due = append(due, timer)
+		}
+	}
+	f.timers = pending
+	f.mu.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+	for _, timer := range due {
+		timer.fn()
+	}
+}
+
+// Pending reports how many timers have yet to fire
+func (f *Fake) Pending() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.timers)
+}
+
+type fakeTimer struct {
+	clock *Fake
+	at    time.Time
+	fn    func()
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
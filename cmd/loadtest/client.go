@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"time"
+)
+
+// gameState holds the fields of the JSON game view the simulation needs
+type gameState struct {
+	ID        string       `json:"id"`
+	Status    string       `json:"status"`
+	Board     [3][3]string `json:"board"`
+	MoveCount int          `json:"moveCount"`
+}
+
+// streamEvent is a game event as received by a simulated player
+type streamEvent struct {
+	Type       string
+	ReceivedAt time.Time
+}
+
+// player is one simulated player. The server tells players apart by cookie,
+// so each gets its own jar.
+type player struct {
+	baseURL string
+	http    *http.Client
+}
+
+func newPlayer(baseURL string, transport http.RoundTripper) *player {
+	jar, _ := cookiejar.New(nil)
+	return &player{baseURL: baseURL, http: &http.Client{Jar: jar, Transport: transport}}
+}
+
+// postJSON sends payload and decodes the game state in the response
+func (p *player) postJSON(path string, payload interface{}) (*gameState, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.http.Post(p.baseURL+path, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("POST %s: %s %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	var state gameState
+	return &state, json.NewDecoder(resp.Body).Decode(&state)
+}
+
+func (p *player) createGame(emoji string) (*gameState, error) {
+	return p.postJSON("/api/v1/games", map[string]string{"emoji": emoji})
+}
+
+func (p *player) joinGame(gameID, emoji string) (*gameState, error) {
+	return p.postJSON("/api/v1/game/"+gameID+"/join", map[string]string{"emoji": emoji})
+}
+
+func (p *player) move(gameID string, row, col int) (*gameState, error) {
+	return p.postJSON("/api/v1/game/"+gameID+"/move", map[string]int{"row": row, "col": col})
+}
+
+// subscribe opens the game's JSON event stream for board changes. It returns
+// once the server has sent the initial state, so no later move can be
+// missed, and then delivers events on the channel until ctx is done.
+func (p *player) subscribe(ctx context.Context, gameID string) (<-chan streamEvent, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/v1/game/"+gameID+"/events?types=move,game_winner,game_draw", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("event stream: %s", resp.Status)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	first, err := nextEvent(lines)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if first.Type != "initial" {
+		resp.Body.Close()
+		return nil, fmt.Errorf("event stream: expected initial state, got %s", first.Type)
+	}
+
+	events := make(chan streamEvent, 16)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+		for {
+			event, err := nextEvent(lines)
+			if err != nil {
+				return
+			}
+			events <- event
+		}
+	}()
+	return events, nil
+}
+
+// nextEvent reads up to the next data line of the stream
+func nextEvent(lines *bufio.Scanner) (streamEvent, error) {
+	for lines.Scan() {
+		data, ok := strings.CutPrefix(lines.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var payload struct {
+			Type  string `json:"type"`
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			return streamEvent{}, err
+		}
+		if payload.Error != "" {
+			return streamEvent{}, fmt.Errorf("event stream: %s", payload.Error)
+		}
+
+		return streamEvent{Type: payload.Type, ReceivedAt: time.Now()}, nil
+	}
+	if err := lines.Err(); err != nil {
+		return streamEvent{}, err
+	}
+	return streamEvent{}, io.EOF
+}
@@ -0,0 +1,205 @@
+// Command loadtest plays simulated games against a running server to check
+// how the event broadcaster holds up under load. Each pair of players
+// creates and joins a game over the JSON API, subscribes to its event
+// stream and plays random moves to the end. The report covers move request
+// latency, how long the broadcast of each move took to reach both players,
+// and how many of those broadcasts never arrived.
+//
+// The server's rate and connection limits apply to the load test like to
+// anyone else, so raise or disable them for large runs.
+//
+// Usage:
+//
+//	loadtest -server http://localhost:8080 -pairs 100 -games 5
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+func main() {
+	server := flag.String("server", "http://localhost:8080", "server URL, including any base path")
+	pairs := flag.Int("pairs", 10, "number of player pairs playing at the same time")
+	games := flag.Int("games", 1, "games each pair plays, one after another")
+	grace := flag.Duration("grace", 2*time.Second, "how long to wait for a game's last events before counting them as dropped")
+	thinkTime := flag.Duration("think", 0, "pause before each move")
+	seed := flag.Int64("seed", 0, "seed for the players' moves, 0 picks one from the clock")
+	flag.Parse()
+	log.SetFlags(0)
+
+	if *pairs < 1 || *games < 1 {
+		log.Fatal("-pairs and -games must be at least 1")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = *pairs * 2
+	sim := simulation{
+		baseURL:   strings.TrimRight(*server, "/"),
+		transport: transport,
+		grace:     *grace,
+		thinkTime: *thinkTime,
+	}
+
+	started := time.Now()
+	if *seed == 0 {
+		*seed = started.UnixNano()
+	}
+	log.Printf("seed %d", *seed)
+
+	results := make(chan gameResult)
+	var wg sync.WaitGroup
+	for i := 0; i < *pairs; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for g := 0; g < *games; g++ {
+				results <- sim.playGame(rng)
+			}
+		}(*seed + int64(i))
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var report report
+	for result := range results {
+		report.add(result)
+	}
+	report.print(os.Stdout, time.Since(started))
+	if report.failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// simulation holds the settings shared by every simulated game
+type simulation struct {
+	baseURL   string
+	transport http.RoundTripper
+	grace     time.Duration
+	thinkTime time.Duration
+}
+
+// gameResult is what one simulated game measured
+type gameResult struct {
+	moveLatencies  []time.Duration // move request round trips
+	eventLatencies []time.Duration // from sending a move to a player receiving its broadcast
+	expectedEvents int
+	receivedEvents int
+	err            error
+}
+
+// playGame runs one game between two new players and measures it
+func (s simulation) playGame(rng *rand.Rand) gameResult {
+	var result gameResult
+	a, b := newPlayer(s.baseURL, s.transport), newPlayer(s.baseURL, s.transport)
+
+	state, err := a.createGame("🐱")
+	if err != nil {
+		result.err = err
+		return result
+	}
+	gameID := state.ID
+	if state, err = b.joinGame(gameID, "🚀"); err != nil {
+		result.err = err
+		return result
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var streams []<-chan streamEvent
+	for _, p := range []*player{a, b} {
+		events, err := p.subscribe(ctx, gameID)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		streams = append(streams, events)
+	}
+
+	// Each stream is drained until the game's final event or the grace period
+	// after the last move, whichever comes first. Events carry the game as it
+	// is when they are sent, which may already include later moves, so they
+	// are matched to moves by order rather than by move count.
+	received := make([][]time.Time, len(streams))
+	var collectors sync.WaitGroup
+	for i, events := range streams {
+		collectors.Add(1)
+		go func(i int, events <-chan streamEvent) {
+			defer collectors.Done()
+			for event := range events {
+				received[i] = append(received[i], event.ReceivedAt)
+				if event.Type == "game_winner" || event.Type == "game_draw" {
+					return
+				}
+			}
+		}(i, events)
+	}
+
+	var sentAt []time.Time
+	players := []*player{a, b}
+	for turn := 0; state.Status == "active"; turn++ {
+		if s.thinkTime > 0 {
+			time.Sleep(s.thinkTime)
+		}
+		row, col := randomEmptyCell(state.Board, rng)
+
+		start := time.Now()
+		sentAt = append(sentAt, start)
+		next, err := players[turn%2].move(gameID, row, col)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		result.moveLatencies = append(result.moveLatencies, time.Since(start))
+		state = next
+	}
+
+	collected := make(chan struct{})
+	go func() {
+		collectors.Wait()
+		close(collected)
+	}()
+	select {
+	case <-collected:
+	case <-time.After(s.grace):
+	}
+	cancel()
+	<-collected
+
+	for _, times := range received {
+		result.expectedEvents += len(sentAt)
+		result.receivedEvents += len(times)
+		// With a gap in the stream the pairing is off, so latency is skipped
+		if len(times) != len(sentAt) {
+			continue
+		}
+		for i, at := range times {
+			result.eventLatencies = append(result.eventLatencies, at.Sub(sentAt[i]))
+		}
+	}
+	return result
+}
+
+// randomEmptyCell picks a free cell to play
+func randomEmptyCell(board [3][3]string, rng *rand.Rand) (int, int) {
+	var empty [][2]int
+	for row := range board {
+		for col := range board[row] {
+			if board[row][col] == "" {
+				empty = append(empty, [2]int{row, col})
+			}
+		}
+	}
+	cell := empty[rng.Intn(len(empty))]
+	return cell[0], cell[1]
+}
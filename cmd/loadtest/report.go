@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// maxReportedErrors bounds how many distinct failures are printed
+const maxReportedErrors = 10
+
+// report aggregates the results of every simulated game
+type report struct {
+	games          int
+	failed         int
+	errors         map[string]int
+	moveLatencies  []time.Duration
+	eventLatencies []time.Duration
+	expectedEvents int
+	receivedEvents int
+}
+
+func (r *report) add(result gameResult) {
+	r.games++
+	if result.err != nil {
+		r.failed++
+		if r.errors == nil {
+			r.errors = make(map[string]int)
+		}
+		r.errors[result.err.Error()]++
+		return
+	}
+	r.moveLatencies = append(r.moveLatencies, result.moveLatencies...)
+	r.eventLatencies = append(r.eventLatencies, result.eventLatencies...)
+	r.expectedEvents += result.expectedEvents
+	r.receivedEvents += result.receivedEvents
+}
+
+func (r *report) print(out io.Writer, elapsed time.Duration) {
+	fmt.Fprintf(out, "games:   %d played, %d failed in %s\n", r.games-r.failed, r.failed, elapsed.Round(time.Millisecond))
+	fmt.Fprintf(out, "moves:   %d, %.1f/s, latency %s\n", len(r.moveLatencies), float64(len(r.moveLatencies))/elapsed.Seconds(), percentiles(r.moveLatencies))
+	fmt.Fprintf(out, "events:  %d expected, %d received, %d dropped, delivery %s\n",
+		r.expectedEvents, r.receivedEvents, r.expectedEvents-r.receivedEvents, percentiles(r.eventLatencies))
+
+	reported := 0
+	for message, count := range r.errors {
+		if reported == maxReportedErrors {
+			fmt.Fprintf(out, "  ... and %d more kinds of errors\n", len(r.errors)-reported)
+			break
+		}
+		fmt.Fprintf(out, "  %dx %s\n", count, message)
+		reported++
+	}
+}
+
+// percentiles summarizes latencies as p50/p90/p99/max
+func percentiles(latencies []time.Duration) string {
+	if len(latencies) == 0 {
+		return "n/a"
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	at := func(p float64) time.Duration {
+		return sorted[int(p*float64(len(sorted)-1))].Round(time.Microsecond)
+	}
+	return fmt.Sprintf("p50 %s p90 %s p99 %s max %s", at(0.50), at(0.90), at(0.99), sorted[len(sorted)-1].Round(time.Microsecond))
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+)
+
+// apiGame mirrors the game JSON served by /api/v1
+type apiGame struct {
+	ID          string       `json:"id"`
+	URL         string       `json:"url"`
+	Status      string       `json:"status"`
+	Board       [3][3]string `json:"board"`
+	Players     []apiPlayer  `json:"players"`
+	CurrentTurn string       `json:"currentTurn"`
+	Winner      string       `json:"winner"`
+	MoveCount   int          `json:"moveCount"`
+	You         *apiViewer   `json:"you"`
+}
+
+type apiPlayer struct {
+	Emoji string `json:"emoji"`
+	Name  string `json:"name"`
+}
+
+type apiViewer struct {
+	Emoji    string `json:"emoji"`
+	YourTurn bool   `json:"yourTurn"`
+}
+
+// apiEvent is one event from the JSON event stream
+type apiEvent struct {
+	Type  string   `json:"type"`
+	Game  *apiGame `json:"game"`
+	Error string   `json:"error"`
+}
+
+// client talks to the JSON API. The server identifies players by cookie, so
+// the jar is what makes this client a player.
+type client struct {
+	baseURL string
+	http    *http.Client
+}
+
+func newClient(baseURL string) (*client, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &client{baseURL: strings.TrimRight(baseURL, "/"), http: &http.Client{Jar: jar}}, nil
+}
+
+// do sends payload as JSON and decodes the response into out
+func (c *client) do(method, path string, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Error == "" {
+			apiErr.Error = resp.Status
+		}
+		return fmt.Errorf("%s", apiErr.Error)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *client) createGame(emoji, name, password string, private bool) (*apiGame, error) {
+	request := map[string]interface{}{"emoji": emoji, "name": name}
+	options := map[string]string{"password": password}
+	if private {
+		options["visibility"] = "private"
+	}
+	request["options"] = options
+
+	var g apiGame
+	return &g, c.do(http.MethodPost, "/api/v1/games", request, &g)
+}
+
+func (c *client) joinGame(gameID, emoji, name, password string) (*apiGame, error) {
+	var g apiGame
+	request := map[string]string{"emoji": emoji, "name": name, "password": password}
+	return &g, c.do(http.MethodPost, "/api/v1/game/"+gameID+"/join", request, &g)
+}
+
+func (c *client) move(gameID string, row, col int) (*apiGame, error) {
+	var g apiGame
+	return &g, c.do(http.MethodPost, "/api/v1/game/"+gameID+"/move", map[string]int{"row": row, "col": col}, &g)
+}
+
+// streamEvents sends the game's events to out until the stream ends
+func (c *client) streamEvents(gameID string, out chan<- apiEvent) error {
+	defer close(out)
+
+	resp, err := c.http.Get(c.baseURL + "/api/v1/game/" + gameID + "/events")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("event stream: %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event apiEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("event stream: %w", err)
+		}
+		out <- event
+	}
+	return scanner.Err()
+}
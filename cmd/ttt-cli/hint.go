@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"htmx-go-app/engine"
+	"htmx-go-app/solver"
+)
+
+// hint suggests the best moves for us in g, in the "row col" form moves are
+// entered in
+func hint(g *apiGame) string {
+	if len(g.Players) != 2 || g.You == nil {
+		return "No hint available."
+	}
+	position, err := engine.NewGame(g.Players[0].Emoji, g.Players[1].Emoji, engine.Standard)
+	if err != nil {
+		return "No hint available."
+	}
+	position.Board = engine.Board(g.Board)
+	if g.You.Emoji == g.Players[1].Emoji {
+		position.Turn = 1
+	}
+
+	eval := solver.Evaluate(position)
+	var moves []string
+	for _, cell := range eval.BestMoves {
+		moves = append(moves, fmt.Sprintf("%d %d", cell.Row+1, cell.Col+1))
+	}
+	if len(moves) == 0 {
+		return "No moves left."
+	}
+
+	outlook := "a draw with best play"
+	switch eval.Result {
+	case solver.Win:
+		outlook = fmt.Sprintf("you can force a win in %d", (eval.Plies+1)/2)
+	case solver.Loss:
+		outlook = "your opponent can force a win"
+	}
+	return fmt.Sprintf("Try %s (%s).", strings.Join(moves, " or "), outlook)
+}
@@ -0,0 +1,180 @@
+// Command ttt-cli plays tic-tac-toe in the terminal against a player on the
+// web or another ttt-cli, using the server's JSON API and event stream.
+//
+// Usage:
+//
+//	ttt-cli [flags] new          create a game and wait for an opponent
+//	ttt-cli [flags] join <game>  join a game by ID or URL
+//
+// Type hint at the move prompt to see the best moves.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	server := flag.String("server", "http://localhost:8080", "server URL, including any base path")
+	emoji := flag.String("emoji", "🐱", "emoji to play as")
+	name := flag.String("name", "", "display name")
+	password := flag.String("password", "", "join password to set (new) or enter (join)")
+	private := flag.Bool("private", false, "keep a new game out of the lobby")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: ttt-cli [flags] new | join <game ID or URL>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.SetFlags(0)
+
+	c, err := newClient(*server)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var g *apiGame
+	switch flag.Arg(0) {
+	case "new":
+		g, err = c.createGame(*emoji, *name, *password, *private)
+		if err == nil {
+			fmt.Printf("Created game %s. Share %s with your opponent.\n", g.ID, g.URL)
+		}
+	case "join":
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		g, err = c.joinGame(gameIDFromArg(flag.Arg(1)), *emoji, *name, *password)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := play(c, g.ID, os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// gameIDFromArg accepts a bare ID or a game URL such as
+// https://example.com/game/abc123
+func gameIDFromArg(arg string) string {
+	arg = strings.TrimRight(arg, "/")
+	if i := strings.LastIndex(arg, "/"); i >= 0 {
+		return arg[i+1:]
+	}
+	return arg
+}
+
+// play follows the game's event stream, drawing the board on every change
+// and asking for a move whenever it is our turn
+func play(c *client, gameID string, in io.Reader, out io.Writer) error {
+	events := make(chan apiEvent)
+	streamErr := make(chan error, 1)
+	go func() { streamErr <- c.streamEvents(gameID, events) }()
+
+	input := bufio.NewScanner(in)
+	lastSeen := ""
+	for event := range events {
+		switch {
+		case event.Type == "game_cancelled":
+			fmt.Fprintln(out, "The game was cancelled.")
+			return nil
+		case event.Error != "":
+			return fmt.Errorf("%s", event.Error)
+		case event.Game == nil:
+			continue
+		}
+
+		// Chat, reminders and the like repeat a state we have already shown
+		g := event.Game
+		seen := fmt.Sprint(g.Status, len(g.Players), g.MoveCount)
+		if seen == lastSeen {
+			continue
+		}
+		lastSeen = seen
+
+		drawBoard(out, g)
+		switch {
+		case g.Status == "waiting":
+			fmt.Fprintln(out, "Waiting for an opponent...")
+		case g.Status == "finished" || g.Status == "draw":
+			fmt.Fprintln(out, result(g))
+			return nil
+		case g.You != nil && g.You.YourTurn:
+			if err := promptMove(c, g, input, out); err != nil {
+				return err
+			}
+		case g.CurrentTurn != "":
+			fmt.Fprintf(out, "Waiting for %s to move...\n", g.CurrentTurn)
+		}
+	}
+	return <-streamErr
+}
+
+// promptMove reads "row col" (1-3 each) until the server accepts a move.
+// "hint" suggests the best moves instead.
+func promptMove(c *client, g *apiGame, input *bufio.Scanner, out io.Writer) error {
+	for {
+		fmt.Fprint(out, "Your move (row col): ")
+		if !input.Scan() {
+			if err := input.Err(); err != nil {
+				return err
+			}
+			return io.EOF
+		}
+
+		if strings.TrimSpace(input.Text()) == "hint" {
+			fmt.Fprintln(out, hint(g))
+			continue
+		}
+		var row, col int
+		if _, err := fmt.Sscan(input.Text(), &row, &col); err != nil {
+			fmt.Fprintln(out, "Enter a row and a column from 1 to 3, e.g. 2 2, or hint")
+			continue
+		}
+		if _, err := c.move(g.ID, row-1, col-1); err != nil {
+			fmt.Fprintf(out, "Move rejected: %v\n", err)
+			continue
+		}
+		return nil
+	}
+}
+
+// drawBoard prints the board with 1-based row and column numbers
+func drawBoard(out io.Writer, g *apiGame) {
+	var players []string
+	for _, p := range g.Players {
+		players = append(players, p.Emoji)
+	}
+	fmt.Fprintf(out, "\n%s\n\n    1    2    3\n", strings.Join(players, " vs "))
+	for row, cells := range g.Board {
+		marks := make([]string, len(cells))
+		for col, mark := range cells {
+			if mark == "" {
+				mark = "·"
+			}
+			marks[col] = " " + mark + " "
+		}
+		fmt.Fprintf(out, "%d  %s\n", row+1, strings.Join(marks, "|"))
+	}
+	fmt.Fprintln(out)
+}
+
+func result(g *apiGame) string {
+	switch {
+	case g.Winner == "":
+		return "It's a draw!"
+	case g.You != nil && g.You.Emoji == g.Winner:
+		return "You win! 🏆"
+	default:
+		return g.Winner + " wins."
+	}
+}
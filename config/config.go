@@ -0,0 +1,451 @@
+// Package config loads server settings from defaults, an optional YAML
+// file, environment variables and command-line flags, in increasing order
+// of precedence.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"htmx-go-app/emojis"
+	"htmx-go-app/ratelimit"
+
+	"github.com/nats-io/nats.go"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting the server reads at startup. Most can be
+// reloaded while running; RestartOnly names the ones that can't.
+type Config struct {
+	Addr     string `yaml:"addr"`      // listen address
+	BaseURL  string `yaml:"base_url"`  // public origin for share links; empty uses the request host
+	BasePath string `yaml:"base_path"` // path prefix the app is served under, such as "/tictactoe"
+
+	TrustedProxies []string `yaml:"trusted_proxies"` // reverse proxies whose X-Forwarded-For names the client, as IPs or CIDRs; empty trusts none
+
+	TLSCertFile      string   `yaml:"tls_cert_file"`      // serve HTTPS with this certificate
+	TLSKeyFile       string   `yaml:"tls_key_file"`       // private key for TLSCertFile
+	AutocertDomains  []string `yaml:"autocert_domains"`   // obtain certificates from Let's Encrypt for these hosts
+	AutocertCacheDir string   `yaml:"autocert_cache_dir"` // where issued certificates are kept between restarts
+	HTTPRedirectAddr string   `yaml:"http_redirect_addr"` // plain HTTP listener redirecting to HTTPS, empty disables
+
+	StoreBackend    string        `yaml:"store_backend"`    // where games are kept; only "memory" for now
+	StoreShards     int           `yaml:"store_shards"`     // locks the memory store is split over
+	SnapshotFile    string        `yaml:"snapshot_file"`    // games are restored from and flushed to this file, empty disables
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"` // how long to drain requests before exiting
+
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"` // how long a client may take to send request headers
+	RequestTimeout    time.Duration `yaml:"request_timeout"`     // limit for everything except event streams, 0 disables
+	MaxBodyBytes      int           `yaml:"max_body_bytes"`      // largest request body accepted, 0 is unlimited
+
+	MaxOpenGames     int           `yaml:"max_open_games"`     // waiting games one player or IP may have at once, 0 is unlimited
+	UnclaimedGameTTL time.Duration `yaml:"unclaimed_game_ttl"` // games nobody joined are removed after this
+
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval"`   // SSE keep-alive interval, 0 disables
+	MaxStreamsPerIP   int           `yaml:"max_streams_per_ip"`   // concurrent event streams from one IP, 0 is unlimited
+	MaxStreamsPerGame int           `yaml:"max_streams_per_game"` // concurrent event streams for one game, 0 is unlimited
+	TurnReminderDelay time.Duration `yaml:"turn_reminder_delay"`  // idle time before a turn reminder, 0 disables
+	AbandonAfter      time.Duration `yaml:"abandon_after"`        // idle time before a game's abandonment rule applies, 0 disables
+	InviteTTL         time.Duration `yaml:"invite_ttl"`           // how long invite links stay valid
+	ChatCooldown      time.Duration `yaml:"chat_cooldown"`        // minimum time between a player's chat messages
+	MoveDebounce      time.Duration `yaml:"move_debounce"`        // repeats of a move within this are collapsed into it, 0 disables
+
+	Emojis        []string `yaml:"emojis"`         // emojis players pick from
+	CustomSymbols bool     `yaml:"custom_symbols"` // let players type any single character as their symbol
+
+	SiteName   string `yaml:"site_name"`   // shown in the navbar and page metadata
+	LogoURL    string `yaml:"logo_url"`    // image shown next to the site name, empty shows none
+	ThemeColor string `yaml:"theme_color"` // navbar and browser theme color, as #rrggbb
+
+	CreateRateLimit ratelimit.Limit `yaml:"create_rate_limit"` // games a client may create, e.g. "10/1m"; "0" disables
+	MoveRateLimit   ratelimit.Limit `yaml:"move_rate_limit"`   // moves a client may make
+	ChatRateLimit   ratelimit.Limit `yaml:"chat_rate_limit"`   // chat messages a client may send
+
+	CookieSecure bool          `yaml:"cookie_secure"`  // only send the player cookie over HTTPS
+	CookieMaxAge time.Duration `yaml:"cookie_max_age"` // lifetime of the player cookie
+
+	Headless        bool   `yaml:"headless"`         // serve only the JSON API and event streams, no HTML pages
+	AdminAPIKey     string `yaml:"admin_api_key"`    // key for /api/admin, empty disables the admin API
+	AuditLogFile    string `yaml:"audit_log_file"`   // admin and destructive actions are appended here, empty keeps them in memory
+	MaintenanceMode bool   `yaml:"maintenance_mode"` // pause new games while letting running ones finish; only read at startup, the admin API toggles it after
+	RandomSeed      int    `yaml:"random_seed"`      // makes game IDs and join codes reproducible for tests and simulations, 0 uses crypto/rand
+
+	// Chaos settings degrade the server on purpose so reconnects and resyncs
+	// can be tried out; they are for development only
+	ChaosLatency        time.Duration `yaml:"chaos_latency"`         // each request is delayed by a random time up to this
+	ChaosDropRate       float64       `yaml:"chaos_drop_rate"`       // share of stream events silently dropped, 0 to 1
+	ChaosDisconnectRate float64       `yaml:"chaos_disconnect_rate"` // chance a stream is cut after each event, 0 to 1
+
+	RecordStreamsDir string `yaml:"record_streams_dir"` // log everything sent on each event stream to a file here, for debugging
+
+	EventBus string `yaml:"event_bus"` // "local" or "nats"
+	NATSURL  string `yaml:"nats_url"`
+
+	WebhookURLs   []string `yaml:"webhook_urls"`
+	WebhookSecret string   `yaml:"webhook_secret"`
+
+	DiscordWebhookURL  string `yaml:"discord_webhook_url"`  // post game starts and results to a Discord channel
+	SlackSigningSecret string `yaml:"slack_signing_secret"` // enables the /slack/command endpoint for the Slack app
+
+	TelegramBotToken      string `yaml:"telegram_bot_token"`      // enables the Telegram bot at /telegram/webhook
+	TelegramWebhookSecret string `yaml:"telegram_webhook_secret"` // secret token the bot's webhook was set up with
+}
+
+// Default returns the settings used when nothing else is configured
+func Default() Config {
+	return Config{
+		Addr:              ":8080",
+		AutocertCacheDir:  "certs",
+		StoreBackend:      "memory",
+		StoreShards:       32,
+		ShutdownTimeout:   10 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		RequestTimeout:    10 * time.Second,
+		MaxBodyBytes:      1 << 20,
+		MaxOpenGames:      5,
+		UnclaimedGameTTL:  2 * time.Minute,
+		HeartbeatInterval: 15 * time.Second,
+		MaxStreamsPerIP:   20,
+		MaxStreamsPerGame: 50,
+		TurnReminderDelay: 30 * time.Second,
+		InviteTTL:         30 * time.Minute,
+		ChatCooldown:      time.Second,
+		MoveDebounce:      500 * time.Millisecond,
+		Emojis:            slices.Clone(emojis.Default),
+		SiteName:          "Tic-Tac-Toe",
+		ThemeColor:        "#2c3e50",
+		CreateRateLimit:   ratelimit.Limit{Burst: 10, Per: time.Minute},
+		MoveRateLimit:     ratelimit.Limit{Burst: 120, Per: time.Minute},
+		ChatRateLimit:     ratelimit.Limit{Burst: 20, Per: time.Minute},
+		CookieMaxAge:      24 * time.Hour,
+		EventBus:          "local",
+		NATSURL:           nats.DefaultURL,
+	}
+}
+
+// setting maps one field to its environment variable and flag
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	set   func(c *Config, value string) error
+}
+
+// themeColorPattern is what ThemeColor must look like. It ends up in CSS and
+// the manifest, so nothing else is let through.
+var themeColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+var settings = []setting{
+	{"addr", "ADDR", "listen address", stringSetter(func(c *Config) *string { return &c.Addr })},
+	{"base-url", "BASE_URL", "public base URL used in share links", stringSetter(func(c *Config) *string { return &c.BaseURL })},
+	{"base-path", "BASE_PATH", `path prefix to serve the app under, such as "/tictactoe"`, stringSetter(func(c *Config) *string { return &c.BasePath })},
+	{"trusted-proxies", "TRUSTED_PROXIES", "comma-separated IPs or CIDRs of reverse proxies whose X-Forwarded-For header is believed; empty trusts none", listSetter(func(c *Config) *[]string { return &c.TrustedProxies })},
+	{"tls-cert", "TLS_CERT_FILE", "TLS certificate file", stringSetter(func(c *Config) *string { return &c.TLSCertFile })},
+	{"tls-key", "TLS_KEY_FILE", "TLS private key file", stringSetter(func(c *Config) *string { return &c.TLSKeyFile })},
+	{"autocert-domains", "AUTOCERT_DOMAINS", "comma-separated hosts to get Let's Encrypt certificates for", listSetter(func(c *Config) *[]string { return &c.AutocertDomains })},
+	{"autocert-cache", "AUTOCERT_CACHE_DIR", "directory caching Let's Encrypt certificates", stringSetter(func(c *Config) *string { return &c.AutocertCacheDir })},
+	{"http-redirect-addr", "HTTP_REDIRECT_ADDR", "plain HTTP address redirecting to HTTPS", stringSetter(func(c *Config) *string { return &c.HTTPRedirectAddr })},
+	{"store", "STORE_BACKEND", `game store backend ("memory")`, stringSetter(func(c *Config) *string { return &c.StoreBackend })},
+	{"store-shards", "STORE_SHARDS", "number of shards the memory store is split over", intSetter(func(c *Config) *int { return &c.StoreShards })},
+	{"snapshot-file", "SNAPSHOT_FILE", "file games are restored from and flushed to on shutdown", stringSetter(func(c *Config) *string { return &c.SnapshotFile })},
+	{"shutdown-timeout", "SHUTDOWN_TIMEOUT", "how long to drain requests before exiting", durationSetter(func(c *Config) *time.Duration { return &c.ShutdownTimeout })},
+	{"read-header-timeout", "READ_HEADER_TIMEOUT", "how long a client may take to send request headers", durationSetter(func(c *Config) *time.Duration { return &c.ReadHeaderTimeout })},
+	{"request-timeout", "REQUEST_TIMEOUT", "time limit for requests other than event streams, 0 disables", durationSetter(func(c *Config) *time.Duration { return &c.RequestTimeout })},
+	{"max-body-bytes", "MAX_BODY_BYTES", "largest request body accepted, 0 is unlimited", intSetter(func(c *Config) *int { return &c.MaxBodyBytes })},
+	{"max-open-games", "MAX_OPEN_GAMES", "waiting games one player or IP may have at once, 0 is unlimited", intSetter(func(c *Config) *int { return &c.MaxOpenGames })},
+	{"unclaimed-game-ttl", "UNCLAIMED_GAME_TTL", "how long games nobody joined are kept", durationSetter(func(c *Config) *time.Duration { return &c.UnclaimedGameTTL })},
+	{"heartbeat-interval", "SSE_HEARTBEAT_INTERVAL", "SSE keep-alive interval, 0 disables", durationSetter(func(c *Config) *time.Duration { return &c.HeartbeatInterval })},
+	{"max-streams-per-ip", "MAX_STREAMS_PER_IP", "concurrent event streams from one IP, 0 is unlimited", intSetter(func(c *Config) *int { return &c.MaxStreamsPerIP })},
+	{"max-streams-per-game", "MAX_STREAMS_PER_GAME", "concurrent event streams for one game, 0 is unlimited", intSetter(func(c *Config) *int { return &c.MaxStreamsPerGame })},
+	{"turn-reminder-delay", "TURN_REMINDER_DELAY", "idle time before a turn reminder, 0 disables", durationSetter(func(c *Config) *time.Duration { return &c.TurnReminderDelay })},
+	{"abandon-after", "ABANDON_AFTER", "idle time before a game's abandonment rule applies, 0 (the default) disables", durationSetter(func(c *Config) *time.Duration { return &c.AbandonAfter })},
+	{"invite-ttl", "INVITE_TTL", "how long invite links stay valid", durationSetter(func(c *Config) *time.Duration { return &c.InviteTTL })},
+	{"chat-cooldown", "CHAT_COOLDOWN", "minimum time between a player's chat messages", durationSetter(func(c *Config) *time.Duration { return &c.ChatCooldown })},
+	{"move-debounce", "MOVE_DEBOUNCE", "window in which a repeat of a player's move, e.g. from a double-click, is collapsed into it", durationSetter(func(c *Config) *time.Duration { return &c.MoveDebounce })},
+	{"emojis", "EMOJIS", "comma-separated emojis players pick from", listSetter(func(c *Config) *[]string { return &c.Emojis })},
+	{"custom-symbols", "CUSTOM_SYMBOLS", "let players type any single character or emoji as their symbol instead of picking one", boolSetter(func(c *Config) *bool { return &c.CustomSymbols })},
+	{"site-name", "SITE_NAME", "site name shown in the navbar and page metadata", stringSetter(func(c *Config) *string { return &c.SiteName })},
+	{"logo-url", "LOGO_URL", "image shown next to the site name", stringSetter(func(c *Config) *string { return &c.LogoURL })},
+	{"theme-color", "THEME_COLOR", "navbar and browser theme color, as #rrggbb", stringSetter(func(c *Config) *string { return &c.ThemeColor })},
+	{"create-rate-limit", "CREATE_RATE_LIMIT", `games a client may create, e.g. "10/1m"; "0" disables`, limitSetter(func(c *Config) *ratelimit.Limit { return &c.CreateRateLimit })},
+	{"move-rate-limit", "MOVE_RATE_LIMIT", `moves a client may make, e.g. "120/1m"; "0" disables`, limitSetter(func(c *Config) *ratelimit.Limit { return &c.MoveRateLimit })},
+	{"chat-rate-limit", "CHAT_RATE_LIMIT", `chat messages a client may send, e.g. "20/1m"; "0" disables`, limitSetter(func(c *Config) *ratelimit.Limit { return &c.ChatRateLimit })},
+	{"cookie-secure", "COOKIE_SECURE", "only send the player cookie over HTTPS", boolSetter(func(c *Config) *bool { return &c.CookieSecure })},
+	{"cookie-max-age", "COOKIE_MAX_AGE", "lifetime of the player cookie", durationSetter(func(c *Config) *time.Duration { return &c.CookieMaxAge })},
+	{"headless", "HEADLESS", "serve only the JSON API and event streams, no HTML pages", boolSetter(func(c *Config) *bool { return &c.Headless })},
+	{"admin-api-key", "ADMIN_API_KEY", "key for the admin API, empty disables it", stringSetter(func(c *Config) *string { return &c.AdminAPIKey })},
+	{"audit-log-file", "AUDIT_LOG_FILE", "file admin and destructive actions are appended to", stringSetter(func(c *Config) *string { return &c.AuditLogFile })},
+	{"maintenance", "MAINTENANCE_MODE", "pause new games while letting running ones finish", boolSetter(func(c *Config) *bool { return &c.MaintenanceMode })},
+	{"random-seed", "RANDOM_SEED", "seed game IDs and join codes for reproducible test runs, 0 uses crypto/rand; never set in production", intSetter(func(c *Config) *int { return &c.RandomSeed })},
+	{"chaos-latency", "CHAOS_LATENCY", "delay each request by a random time up to this (development only)", durationSetter(func(c *Config) *time.Duration { return &c.ChaosLatency })},
+	{"chaos-drop-rate", "CHAOS_DROP_RATE", "share of stream events to drop, 0 to 1 (development only)", floatSetter(func(c *Config) *float64 { return &c.ChaosDropRate })},
+	{"chaos-disconnect-rate", "CHAOS_DISCONNECT_RATE", "chance a stream is cut after each event, 0 to 1 (development only)", floatSetter(func(c *Config) *float64 { return &c.ChaosDisconnectRate })},
+	{"record-streams", "RECORD_STREAMS_DIR", "directory to log everything sent on each event stream to, one file per connection (development only)", stringSetter(func(c *Config) *string { return &c.RecordStreamsDir })},
+	{"event-bus", "EVENT_BUS", `event bus ("local" or "nats")`, stringSetter(func(c *Config) *string { return &c.EventBus })},
+	{"nats-url", "NATS_URL", "NATS server URL", stringSetter(func(c *Config) *string { return &c.NATSURL })},
+	{"webhook-urls", "WEBHOOK_URLS", "comma-separated webhook URLs", listSetter(func(c *Config) *[]string { return &c.WebhookURLs })},
+	{"webhook-secret", "WEBHOOK_SECRET", "secret used to sign webhook requests", stringSetter(func(c *Config) *string { return &c.WebhookSecret })},
+	{"discord-webhook-url", "DISCORD_WEBHOOK_URL", "Discord webhook URL to post game starts and results to", stringSetter(func(c *Config) *string { return &c.DiscordWebhookURL })},
+	{"telegram-bot-token", "TELEGRAM_BOT_TOKEN", "Telegram bot token, empty disables the /telegram/webhook endpoint", stringSetter(func(c *Config) *string { return &c.TelegramBotToken })},
+	{"telegram-webhook-secret", "TELEGRAM_WEBHOOK_SECRET", "secret token Telegram sends with each update", stringSetter(func(c *Config) *string { return &c.TelegramWebhookSecret })},
+	{"slack-signing-secret", "SLACK_SIGNING_SECRET", "Slack app signing secret, empty disables the /slack/command endpoint", stringSetter(func(c *Config) *string { return &c.SlackSigningSecret })},
+}
+
+// Load builds the configuration from defaults, the YAML file named by
+// -config or CONFIG_FILE, environment variables and args, each overriding
+// the previous
+func Load(args []string) (Config, error) {
+	fs := flag.NewFlagSet("tictactoe", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file")
+
+	flagValues := make(map[string]string)
+	for _, s := range settings {
+		name := s.flag
+		fs.Func(name, s.usage+" (env "+s.env+")", func(value string) error {
+			flagValues[name] = value
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	c := Default()
+	if *configFile != "" {
+		if err := loadFile(&c, *configFile); err != nil {
+			return Config{}, err
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(s.env); ok && value != "" {
+			if err := s.set(&c, value); err != nil {
+				return Config{}, fmt.Errorf("invalid %s %q: %w", s.env, value, err)
+			}
+		}
+	}
+	for _, s := range settings {
+		if value, ok := flagValues[s.flag]; ok {
+			if err := s.set(&c, value); err != nil {
+				return Config{}, fmt.Errorf("invalid -%s %q: %w", s.flag, value, err)
+			}
+		}
+	}
+
+	c.BasePath = normalizeBasePath(c.BasePath)
+	return c, c.Validate()
+}
+
+// Validate reports settings that can't work together
+func (c Config) Validate() error {
+	if c.StoreBackend != "memory" {
+		return fmt.Errorf("unknown store backend %q", c.StoreBackend)
+	}
+	if c.StoreShards < 1 {
+		return errors.New("store shards must be at least 1")
+	}
+	if c.EventBus != "local" && c.EventBus != "nats" {
+		return fmt.Errorf("unknown event bus %q", c.EventBus)
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("trusted proxy %q is neither an IP nor a CIDR", proxy)
+		}
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return errors.New("TLS needs both a certificate and a key file")
+	}
+	if c.TLSCertFile != "" && len(c.AutocertDomains) > 0 {
+		return errors.New("use either TLS certificate files or autocert, not both")
+	}
+	if c.ShutdownTimeout <= 0 {
+		return errors.New("shutdown timeout must be positive")
+	}
+	if c.ReadHeaderTimeout <= 0 {
+		return errors.New("read header timeout must be positive")
+	}
+	if c.RequestTimeout < 0 {
+		return errors.New("request timeout can't be negative")
+	}
+	if c.MaxBodyBytes < 0 {
+		return errors.New("max body bytes can't be negative")
+	}
+	if c.MaxOpenGames < 0 {
+		return errors.New("max open games can't be negative")
+	}
+	if c.UnclaimedGameTTL <= 0 {
+		return errors.New("unclaimed game TTL must be positive")
+	}
+	if c.AbandonAfter < 0 {
+		return errors.New("abandon after can't be negative")
+	}
+	if c.InviteTTL <= 0 {
+		return errors.New("invite TTL must be positive")
+	}
+	if err := emojis.Validate(c.Emojis); err != nil {
+		return err
+	}
+	if strings.TrimSpace(c.SiteName) == "" {
+		return errors.New("site name can't be empty")
+	}
+	if c.LogoURL != "" && !strings.HasPrefix(c.LogoURL, "/") && !strings.HasPrefix(c.LogoURL, "https://") && !strings.HasPrefix(c.LogoURL, "http://") {
+		return errors.New("logo URL must be an http(s) URL or start with /")
+	}
+	if !themeColorPattern.MatchString(c.ThemeColor) {
+		return fmt.Errorf("theme color %q must be written as #rrggbb", c.ThemeColor)
+	}
+	if c.CookieMaxAge <= 0 {
+		return errors.New("cookie max age must be positive")
+	}
+	if c.ChaosLatency < 0 {
+		return errors.New("chaos latency can't be negative")
+	}
+	if c.ChaosDropRate < 0 || c.ChaosDropRate > 1 || c.ChaosDisconnectRate < 0 || c.ChaosDisconnectRate > 1 {
+		return errors.New("chaos rates must be between 0 and 1")
+	}
+	return nil
+}
+
+// TLS reports whether the server terminates HTTPS itself
+func (c Config) TLS() bool {
+	return c.TLSCertFile != "" || len(c.AutocertDomains) > 0
+}
+
+// normalizeBasePath gives the prefix a leading slash and no trailing one, so
+// "tictactoe/" becomes "/tictactoe" and "/" becomes ""
+func normalizeBasePath(p string) string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return ""
+	}
+	return "/" + p
+}
+
+func loadFile(c *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func stringSetter(field func(*Config) *string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+func intSetter(field func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func floatSetter(field func(*Config) *float64) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*field(c) = f
+		return nil
+	}
+}
+
+func durationSetter(field func(*Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = d
+		return nil
+	}
+}
+
+func boolSetter(field func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+func limitSetter(field func(*Config) *ratelimit.Limit) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = limit
+		return nil
+	}
+}
+
+func listSetter(field func(*Config) *[]string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*field(c) = list
+		return nil
+	}
+}
+
+// RestartOnly lists the settings that differ between c and next but only
+// take effect on restart, such as listeners, TLS and the event bus
+func (c Config) RestartOnly(next Config) []string {
+	var changed []string
+	check := func(name string, same bool) {
+		if !same {
+			changed = append(changed, name)
+		}
+	}
+	check("addr", c.Addr == next.Addr)
+	check("base_url", c.BaseURL == next.BaseURL)
+	check("base_path", c.BasePath == next.BasePath)
+	check("trusted_proxies", slices.Equal(c.TrustedProxies, next.TrustedProxies))
+	check("tls_cert_file", c.TLSCertFile == next.TLSCertFile)
+	check("tls_key_file", c.TLSKeyFile == next.TLSKeyFile)
+	check("autocert_domains", slices.Equal(c.AutocertDomains, next.AutocertDomains))
+	check("autocert_cache_dir", c.AutocertCacheDir == next.AutocertCacheDir)
+	check("http_redirect_addr", c.HTTPRedirectAddr == next.HTTPRedirectAddr)
+	check("store_backend", c.StoreBackend == next.StoreBackend)
+	check("store_shards", c.StoreShards == next.StoreShards)
+	check("snapshot_file", c.SnapshotFile == next.SnapshotFile)
+	check("audit_log_file", c.AuditLogFile == next.AuditLogFile)
+	check("shutdown_timeout", c.ShutdownTimeout == next.ShutdownTimeout)
+	check("read_header_timeout", c.ReadHeaderTimeout == next.ReadHeaderTimeout)
+	check("cookie_secure", c.CookieSecure == next.CookieSecure)
+	check("headless", c.Headless == next.Headless)
+	check("maintenance_mode", c.MaintenanceMode == next.MaintenanceMode)
+	check("random_seed", c.RandomSeed == next.RandomSeed)
+	check("event_bus", c.EventBus == next.EventBus)
+	check("nats_url", c.NATSURL == next.NATSURL)
+	check("webhook_urls", slices.Equal(c.WebhookURLs, next.WebhookURLs))
+	check("webhook_secret", c.WebhookSecret == next.WebhookSecret)
+	return changed
+}
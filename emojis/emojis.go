@@ -0,0 +1,120 @@
+// Package emojis is the catalog of emojis players pick from. The join paths,
+// the selection page and the configuration check all go through it, so the
+// catalog is changed in one place.
+package emojis
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Default are the emojis offered unless the configuration lists others
+var Default = []string{"🐱", "🚀", "🎨", "🌟", "🔥", "⚡", "🎮", "🦄", "🎯", "🌈", "🧙"}
+
+var (
+	mu          sync.RWMutex
+	catalog     = Default
+	allowCustom bool
+)
+
+// Choice is an emoji as offered on the selection page
+type Choice struct {
+	Emoji     string
+	Available bool     // false once another player in the game has it
+	Variants  []Choice // skin tones and the like, offered beside the emoji
+}
+
+// Validate checks a list is usable as the catalog: at least two emojis, so
+// both players can have one, and none listed twice
+func Validate(list []string) error {
+	if len(list) < 2 {
+		return errors.New("at least two emojis are needed")
+	}
+	for i, emoji := range list {
+		if emoji == "" {
+			return errors.New("emojis can't be empty")
+		}
+		if slices.ContainsFunc(list[:i], func(listed string) bool { return Same(listed, emoji) }) {
+			return fmt.Errorf("emoji %s is listed twice", emoji)
+		}
+	}
+	return nil
+}
+
+// Configure replaces the catalog. Emojis already picked in games are kept.
+func Configure(list []string) {
+	mu.Lock()
+	defer mu.Unlock()
+	catalog = slices.Clone(list)
+}
+
+// AllowCustom turns custom symbols on or off. With them on, players may type
+// a symbol of their own instead of picking one from the catalog.
+func AllowCustom(on bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	allowCustom = on
+}
+
+// CustomAllowed reports whether players may type a symbol of their own
+func CustomAllowed() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return allowCustom
+}
+
+// List returns the catalog in display order
+func List() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return slices.Clone(catalog)
+}
+
+// Valid reports whether emoji is in the catalog or is a variant of one that is
+func Valid(emoji string) bool {
+	_, ok := Lookup(emoji)
+	return ok
+}
+
+// Lookup returns how the catalog spells emoji, which may be an emoji in the
+// catalog or one of its variants, and whether it has it at all. Spellings
+// that only differ in variation selectors are the same emoji.
+func Lookup(emoji string) (string, bool) {
+	for _, listed := range List() {
+		if Same(listed, emoji) {
+			return listed, true
+		}
+		for _, variant := range Variants(listed) {
+			if Same(variant, emoji) {
+				return variant, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Choices lists the catalog with each emoji's variants, marking the ones
+// taken reports as taken
+func Choices(taken func(emoji string) bool) []Choice {
+	var choices []Choice
+	for _, emoji := range List() {
+		choice := Choice{Emoji: emoji, Available: !taken(emoji)}
+		for _, variant := range Variants(emoji) {
+			choice.Variants = append(choice.Variants, Choice{Emoji: variant, Available: !taken(variant)})
+		}
+		choices = append(choices, choice)
+	}
+	return choices
+}
+
+// FirstFree returns the first emoji in the catalog that isn't taken, or ""
+func FirstFree(taken func(emoji string) bool) string {
+	for _, emoji := range List() {
+		if !taken(emoji) {
+			return emoji
+		}
+	}
+	return ""
+}
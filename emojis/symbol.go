@@ -0,0 +1,102 @@
+package emojis
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Custom symbols are checked to be one grapheme, i.e. what a reader sees as
+// a single character: a letter or emoji with any accents, skin tones or
+// variation selectors on it, a flag, or emojis joined into one with
+// zero-width joiners. Symbols end up in attributes and text all over the
+// pages, so the characters that mean something in HTML are turned away
+// too.
+
+// maxSymbolBytes is far more than the longest emoji sequence needs
+const maxSymbolBytes = 64
+
+const zeroWidthJoiner = '\u200d'
+
+// markup are the characters that can't be a symbol
+const markup = `<>&"'`
+
+var (
+	errBlankSymbol  = errors.New("symbol can't be blank")
+	errSingleSymbol = errors.New("symbol must be a single character")
+	errMarkupSymbol = errors.New(`symbol can't be <, >, &, " or '`)
+)
+
+// Canonical returns symbol in the form it is stored and compared in, with
+// accents composed so they look the same however they were typed
+func Canonical(symbol string) string {
+	return norm.NFC.String(strings.TrimSpace(symbol))
+}
+
+// Same reports whether two symbols look the same, ignoring whether an emoji
+// asks to be shown as text or as a picture
+func Same(a, b string) bool {
+	return stripVariation(Canonical(a)) == stripVariation(Canonical(b))
+}
+
+// CheckSymbol returns why symbol can't be used as a custom symbol, or nil
+// if it can
+func CheckSymbol(symbol string) error {
+	if strings.TrimSpace(symbol) == "" {
+		return errBlankSymbol
+	}
+	if len(symbol) > maxSymbolBytes || !utf8.ValidString(symbol) {
+		return errSingleSymbol
+	}
+
+	runes := []rune(symbol)
+	base, rest := runes[0], runes[1:]
+	if strings.ContainsRune(markup, base) {
+		return errMarkupSymbol
+	}
+	if !unicode.IsGraphic(base) || unicode.IsSpace(base) || unicode.IsMark(base) {
+		return errSingleSymbol
+	}
+	if isRegionalIndicator(base) {
+		// A flag is two regional indicators and nothing else
+		if len(rest) > 1 || len(rest) == 1 && !isRegionalIndicator(rest[0]) {
+			return errSingleSymbol
+		}
+		return nil
+	}
+	for i := 0; i < len(rest); i++ {
+		switch r := rest[i]; {
+		case extendsGrapheme(r):
+		case r == zeroWidthJoiner && i+1 < len(rest) && unicode.Is(unicode.So, rest[i+1]):
+			i++
+		default:
+			return errSingleSymbol
+		}
+	}
+	return nil
+}
+
+// extendsGrapheme reports whether r joins the character before it rather
+// than starting a new one
+func extendsGrapheme(r rune) bool {
+	return unicode.IsMark(r) ||
+		unicode.Is(unicode.Variation_Selector, r) ||
+		r >= 0x1f3fb && r <= 0x1f3ff || // skin tones
+		r >= 0xe0020 && r <= 0xe007f // tags, as in subdivision flags
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+func stripVariation(symbol string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Variation_Selector, r) {
+			return -1
+		}
+		return r
+	}, symbol)
+}
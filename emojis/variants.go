@@ -0,0 +1,127 @@
+package emojis
+
+import (
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Emojis of people and hands come in five skin tones, and many people come
+// as a woman or a man too. Those variants are offered next to the emoji
+// they are a variant of rather than being listed in the catalog themselves.
+
+// skinTones are the Fitzpatrick modifiers, from light to dark
+var skinTones = []rune{0x1f3fb, 0x1f3fc, 0x1f3fd, 0x1f3fe, 0x1f3ff}
+
+// genderSigns turn a person into a woman or a man when joined on with a
+// zero-width joiner
+var genderSigns = []string{"\u2640\ufe0f", "\u2642\ufe0f"}
+
+// toneBases are the emojis that take a skin tone
+var toneBases = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x261d, Hi: 0x261d, Stride: 1},
+		{Lo: 0x26f9, Hi: 0x26f9, Stride: 1},
+		{Lo: 0x270a, Hi: 0x270d, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f385, Hi: 0x1f385, Stride: 1},
+		{Lo: 0x1f3c2, Hi: 0x1f3c4, Stride: 1},
+		{Lo: 0x1f3c7, Hi: 0x1f3c7, Stride: 1},
+		{Lo: 0x1f3ca, Hi: 0x1f3cc, Stride: 1},
+		{Lo: 0x1f442, Hi: 0x1f443, Stride: 1},
+		{Lo: 0x1f446, Hi: 0x1f450, Stride: 1},
+		{Lo: 0x1f466, Hi: 0x1f478, Stride: 1},
+		{Lo: 0x1f47c, Hi: 0x1f47c, Stride: 1},
+		{Lo: 0x1f481, Hi: 0x1f483, Stride: 1},
+		{Lo: 0x1f485, Hi: 0x1f487, Stride: 1},
+		{Lo: 0x1f4aa, Hi: 0x1f4aa, Stride: 1},
+		{Lo: 0x1f574, Hi: 0x1f575, Stride: 1},
+		{Lo: 0x1f57a, Hi: 0x1f57a, Stride: 1},
+		{Lo: 0x1f590, Hi: 0x1f590, Stride: 1},
+		{Lo: 0x1f595, Hi: 0x1f596, Stride: 1},
+		{Lo: 0x1f645, Hi: 0x1f647, Stride: 1},
+		{Lo: 0x1f64b, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f6a3, Hi: 0x1f6a3, Stride: 1},
+		{Lo: 0x1f6b4, Hi: 0x1f6b6, Stride: 1},
+		{Lo: 0x1f6c0, Hi: 0x1f6c0, Stride: 1},
+		{Lo: 0x1f6cc, Hi: 0x1f6cc, Stride: 1},
+		{Lo: 0x1f90c, Hi: 0x1f90c, Stride: 1},
+		{Lo: 0x1f90f, Hi: 0x1f90f, Stride: 1},
+		{Lo: 0x1f918, Hi: 0x1f91f, Stride: 1},
+		{Lo: 0x1f926, Hi: 0x1f926, Stride: 1},
+		{Lo: 0x1f930, Hi: 0x1f939, Stride: 1},
+		{Lo: 0x1f93d, Hi: 0x1f93e, Stride: 1},
+		{Lo: 0x1f977, Hi: 0x1f977, Stride: 1},
+		{Lo: 0x1f9b5, Hi: 0x1f9b6, Stride: 1},
+		{Lo: 0x1f9b8, Hi: 0x1f9b9, Stride: 1},
+		{Lo: 0x1f9bb, Hi: 0x1f9bb, Stride: 1},
+		{Lo: 0x1f9cd, Hi: 0x1f9cf, Stride: 1},
+		{Lo: 0x1f9d1, Hi: 0x1f9dd, Stride: 1},
+		{Lo: 0x1fac3, Hi: 0x1fac5, Stride: 1},
+		{Lo: 0x1faf0, Hi: 0x1faf8, Stride: 1},
+	},
+}
+
+// genderedBases are the people that also come as a woman and as a man
+var genderedBases = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x26f9, Hi: 0x26f9, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f3c3, Hi: 0x1f3c4, Stride: 1},
+		{Lo: 0x1f3ca, Hi: 0x1f3cc, Stride: 1},
+		{Lo: 0x1f46e, Hi: 0x1f46f, Stride: 1},
+		{Lo: 0x1f470, Hi: 0x1f471, Stride: 1},
+		{Lo: 0x1f473, Hi: 0x1f473, Stride: 1},
+		{Lo: 0x1f477, Hi: 0x1f477, Stride: 1},
+		{Lo: 0x1f481, Hi: 0x1f482, Stride: 1},
+		{Lo: 0x1f486, Hi: 0x1f487, Stride: 1},
+		{Lo: 0x1f575, Hi: 0x1f575, Stride: 1},
+		{Lo: 0x1f645, Hi: 0x1f647, Stride: 1},
+		{Lo: 0x1f64b, Hi: 0x1f64b, Stride: 1},
+		{Lo: 0x1f64d, Hi: 0x1f64e, Stride: 1},
+		{Lo: 0x1f6a3, Hi: 0x1f6a3, Stride: 1},
+		{Lo: 0x1f6b4, Hi: 0x1f6b6, Stride: 1},
+		{Lo: 0x1f926, Hi: 0x1f926, Stride: 1},
+		{Lo: 0x1f935, Hi: 0x1f935, Stride: 1},
+		{Lo: 0x1f937, Hi: 0x1f939, Stride: 1},
+		{Lo: 0x1f93c, Hi: 0x1f93e, Stride: 1},
+		{Lo: 0x1f9b8, Hi: 0x1f9b9, Stride: 1},
+		{Lo: 0x1f9cd, Hi: 0x1f9cf, Stride: 1},
+		{Lo: 0x1f9d4, Hi: 0x1f9d4, Stride: 1},
+		{Lo: 0x1f9d6, Hi: 0x1f9df, Stride: 1},
+	},
+}
+
+// Variants returns the other forms emoji can be picked in: each skin tone,
+// then a woman and a man in each tone, for the emojis that have them. It is
+// nil for emojis that are already a variant or have none.
+func Variants(emoji string) []string {
+	base, size := utf8.DecodeRuneInString(emoji)
+	if rest := emoji[size:]; rest != "" && rest != "\ufe0f" {
+		return nil
+	}
+	toned, gendered := unicode.Is(toneBases, base), unicode.Is(genderedBases, base)
+
+	// Each form of the person: the emoji as listed, then in each tone
+	forms := []string{emoji}
+	if toned {
+		for _, tone := range skinTones {
+			forms = append(forms, string(base)+string(tone))
+		}
+	}
+
+	variants := slices.Clone(forms[1:])
+	if gendered {
+		for _, sign := range genderSigns {
+			for _, form := range forms {
+				variants = append(variants, form+string(zeroWidthJoiner)+sign)
+			}
+		}
+	}
+	if len(variants) == 0 {
+		return nil
+	}
+	return variants
+}
@@ -0,0 +1,209 @@
+// Package engine implements the rules of tic-tac-toe with no dependency on
+// the web server, so command-line tools, bots and tests can embed them.
+//
+// A Board holds the marks placed so far. Marks are any non-empty strings;
+// the server uses the players' emojis. A Game puts two players, turn order
+// and a Variant on top of a board:
+//
+//	g, _ := engine.NewGame("X", "O", engine.Standard)
+//	_ = g.Play(engine.Cell{Row: 1, Col: 1}) // X takes the centre
+//	if g.Status() == engine.Won {
+//		fmt.Println(g.Marks[g.Winner()], "wins")
+//	}
+package engine
+
+import "errors"
+
+// Size is the number of rows and columns on the board
+const Size = 3
+
+// Errors returned for rejected moves
+var (
+	ErrInvalidCell  = errors.New("invalid cell")
+	ErrCellOccupied = errors.New("cell is already occupied")
+	ErrGameOver     = errors.New("game is over")
+	ErrInvalidMarks = errors.New("players need two different, non-empty marks")
+)
+
+// Cell is a position on the board, counted from the top-left corner
+type Cell struct {
+	Row, Col int
+}
+
+// Valid reports whether the cell is on the board
+func (c Cell) Valid() bool {
+	return c.Row >= 0 && c.Row < Size && c.Col >= 0 && c.Col < Size
+}
+
+// Board is a grid of marks, with "" for empty cells
+type Board [Size][Size]string
+
+// lines are the rows, columns and diagonals that win when filled by one mark
+var lines = [][Size]Cell{
+	{{0, 0}, {0, 1}, {0, 2}},
+	{{1, 0}, {1, 1}, {1, 2}},
+	{{2, 0}, {2, 1}, {2, 2}},
+	{{0, 0}, {1, 0}, {2, 0}},
+	{{0, 1}, {1, 1}, {2, 1}},
+	{{0, 2}, {1, 2}, {2, 2}},
+	{{0, 0}, {1, 1}, {2, 2}},
+	{{0, 2}, {1, 1}, {2, 0}},
+}
+
+// At returns the mark in cell, or "" if it is empty
+func (b Board) At(cell Cell) string {
+	return b[cell.Row][cell.Col]
+}
+
+// Place returns a copy of the board with mark in cell
+func (b Board) Place(cell Cell, mark string) (Board, error) {
+	if !cell.Valid() {
+		return b, ErrInvalidCell
+	}
+	if b.At(cell) != "" {
+		return b, ErrCellOccupied
+	}
+	b[cell.Row][cell.Col] = mark
+	return b, nil
+}
+
+// Line returns the mark and cells of a completed line, or "" and nil if
+// there is none
+func (b Board) Line() (string, []Cell) {
+	for _, line := range lines {
+		mark := b.At(line[0])
+		if mark != "" && mark == b.At(line[1]) && mark == b.At(line[2]) {
+			return mark, line[:]
+		}
+	}
+	return "", nil
+}
+
+// Full reports whether every cell is taken
+func (b Board) Full() bool {
+	return len(b.EmptyCells()) == 0
+}
+
+// EmptyCells lists the free cells row by row
+func (b Board) EmptyCells() []Cell {
+	var empty []Cell
+	for row := 0; row < Size; row++ {
+		for col := 0; col < Size; col++ {
+			if b[row][col] == "" {
+				empty = append(empty, Cell{row, col})
+			}
+		}
+	}
+	return empty
+}
+
+// Variant selects how a completed line is scored
+type Variant string
+
+const (
+	Standard Variant = "standard" // completing a line wins
+	Misere   Variant = "misere"   // completing a line loses
+)
+
+// Status is the state of a game
+type Status int
+
+const (
+	InProgress Status = iota
+	Won
+	Drawn
+)
+
+func (s Status) String() string {
+	switch s {
+	case Won:
+		return "won"
+	case Drawn:
+		return "drawn"
+	default:
+		return "in progress"
+	}
+}
+
+// Outcome scores a board under variant for the players holding marks,
+// returning the status and, for a won game, the winner's seat (else -1)
+func Outcome(b Board, marks [2]string, variant Variant) (Status, int) {
+	mark, line := b.Line()
+	switch {
+	case line != nil:
+		seat := 0
+		if mark == marks[1] {
+			seat = 1
+		}
+		if variant == Misere {
+			seat = 1 - seat
+		}
+		return Won, seat
+	case b.Full():
+		return Drawn, -1
+	default:
+		return InProgress, -1
+	}
+}
+
+// Game is a game between two seats, 0 moving first
+type Game struct {
+	Board   Board
+	Marks   [2]string // each seat's mark
+	Variant Variant
+	Turn    int    // seat to move next
+	Moves   []Cell // cells played so far, in order
+}
+
+// NewGame starts an empty game between markA (moving first) and markB
+func NewGame(markA, markB string, variant Variant) (*Game, error) {
+	if markA == "" || markB == "" || markA == markB {
+		return nil, ErrInvalidMarks
+	}
+	if variant == "" {
+		variant = Standard
+	}
+	return &Game{Marks: [2]string{markA, markB}, Variant: variant}, nil
+}
+
+// Status reports whether the game is still going, won or drawn
+func (g *Game) Status() Status {
+	status, _ := Outcome(g.Board, g.Marks, g.Variant)
+	return status
+}
+
+// Winner returns the winning seat, or -1 if nobody has won
+func (g *Game) Winner() int {
+	_, winner := Outcome(g.Board, g.Marks, g.Variant)
+	return winner
+}
+
+// Play places the mark of the seat to move in cell and passes the turn
+func (g *Game) Play(cell Cell) error {
+	if g.Status() != InProgress {
+		return ErrGameOver
+	}
+	board, err := g.Board.Place(cell, g.Marks[g.Turn])
+	if err != nil {
+		return err
+	}
+	g.Board = board
+	g.Moves = append(g.Moves, cell)
+	g.Turn = 1 - g.Turn
+	return nil
+}
+
+// LegalMoves lists the cells the seat to move may play, none once the game is over
+func (g *Game) LegalMoves() []Cell {
+	if g.Status() != InProgress {
+		return nil
+	}
+	return g.Board.EmptyCells()
+}
+
+// Clone returns an independent copy, for exploring moves without touching g
+func (g *Game) Clone() *Game {
+	clone := *g
+	clone.Moves = append([]Cell(nil), g.Moves...)
+	return &clone
+}
@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Games are written down in a compact notation: each move is its number, the
+// seat that played it as X (seat 0) or O (seat 1) and its cell, with columns
+// a to c from the left and rows 1 to 3 from the top:
+//
+//	1. X b2 2. O a1 3. X c3
+
+// ErrInvalidNotation is returned for text that isn't valid move notation
+var ErrInvalidNotation = errors.New("invalid move notation")
+
+// seatLetters are how the seats are written in notation
+var seatLetters = [2]string{"X", "O"}
+
+// SeatLetter returns how the seat is written in notation, "X" or "O"
+func SeatLetter(seat int) string {
+	return seatLetters[seat]
+}
+
+// SeatOf returns the seat written as letter, or -1 if it is not X or O
+func SeatOf(letter string) int {
+	for seat, l := range seatLetters {
+		if letter == l {
+			return seat
+		}
+	}
+	return -1
+}
+
+// Move is one move as written in notation: the seat that played and where
+type Move struct {
+	Seat int
+	Cell Cell
+}
+
+// String writes the cell in notation, e.g. "b2" for the centre
+func (c Cell) String() string {
+	return string(rune('a'+c.Col)) + strconv.Itoa(c.Row+1)
+}
+
+// ParseCell reads a cell written like "b2"
+func ParseCell(s string) (Cell, error) {
+	if len(s) != 2 {
+		return Cell{}, fmt.Errorf("%w: cell %q", ErrInvalidNotation, s)
+	}
+	cell := Cell{Row: int(s[1] - '1'), Col: int(s[0] - 'a')}
+	if !cell.Valid() {
+		return Cell{}, fmt.Errorf("%w: cell %q", ErrInvalidNotation, s)
+	}
+	return cell, nil
+}
+
+// FormatMoves writes moves in notation, numbering them from 1
+func FormatMoves(moves []Move) string {
+	parts := make([]string, 0, len(moves))
+	for i, move := range moves {
+		parts = append(parts, fmt.Sprintf("%d. %s %s", i+1, seatLetters[move.Seat], move.Cell))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseMoves reads moves written by FormatMoves. The moves must be numbered
+// 1, 2, 3 and so on; whether they are legal is up to the caller.
+func ParseMoves(notation string) ([]Move, error) {
+	fields := strings.Fields(notation)
+	if len(fields)%3 != 0 {
+		return nil, fmt.Errorf("%w: each move is a number, a seat and a cell", ErrInvalidNotation)
+	}
+
+	var moves []Move
+	for i := 0; i < len(fields); i += 3 {
+		number, seat, cell := fields[i], fields[i+1], fields[i+2]
+		if number != strconv.Itoa(len(moves)+1)+"." {
+			return nil, fmt.Errorf("%w: expected move %d., got %q", ErrInvalidNotation, len(moves)+1, number)
+		}
+		move := Move{Seat: SeatOf(seat)}
+		if move.Seat < 0 {
+			return nil, fmt.Errorf("%w: seat %q is not X or O", ErrInvalidNotation, seat)
+		}
+		var err error
+		if move.Cell, err = ParseCell(cell); err != nil {
+			return nil, err
+		}
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
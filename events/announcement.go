@@ -0,0 +1,25 @@
+package events
+
+import (
+	"strings"
+
+	"htmx-go-app/models"
+)
+
+// BroadcastAnnouncement sends an "announcement" event with data to every
+// game and lobby stream. Announcements aren't part of any game's history,
+// so they are neither recorded for replay nor published on the bus.
+func BroadcastAnnouncement(data interface{}) {
+	subscribersMu.RLock()
+	keys := make([]string, 0, len(gameSubscribers))
+	for key := range gameSubscribers {
+		if !strings.HasPrefix(key, matchmakingKey("")) {
+			keys = append(keys, key)
+		}
+	}
+	subscribersMu.RUnlock()
+
+	for _, key := range keys {
+		broadcast(key, models.GameEvent{Type: "announcement", GameID: key, Data: data})
+	}
+}
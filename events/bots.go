@@ -0,0 +1,26 @@
+package events
+
+import (
+	"context"
+
+	"htmx-go-app/models"
+)
+
+// botTurnsKey is the subscriber key for a bot's stream of turn notifications
+func botTurnsKey(playerID string) string {
+	return "bot_turns_" + playerID
+}
+
+// CreateBotTurnsSubscriber registers a bot to be told whenever it's its turn
+// in any game
+func CreateBotTurnsSubscriber(playerID string, ctx context.Context) *models.GameSubscriber {
+	return CreateGameSubscriber(botTurnsKey(playerID), playerID, ctx)
+}
+
+// BroadcastBotTurn tells a bot's turn streams that a game is waiting on it
+func BroadcastBotTurn(playerID, gameID string) {
+	broadcast(botTurnsKey(playerID), models.GameEvent{
+		Type:   "your_turn",
+		GameID: gameID,
+	})
+}
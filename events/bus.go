@@ -0,0 +1,46 @@
+package events
+
+import (
+	"log"
+
+	"htmx-go-app/models"
+)
+
+// Bus distributes game events between server instances. The in-process
+// broadcaster needs no bus; when one is configured, every sequenced game
+// event is also published to it, and events published by other instances
+// are delivered to this instance's subscribers.
+type Bus interface {
+	Publish(event models.GameEvent) error
+	Close() error
+}
+
+var bus Bus
+
+// UseBus makes the broadcaster publish game events to b
+func UseBus(b Bus) {
+	bus = b
+}
+
+// publish forwards an event to the configured bus, if any. Bus failures are
+// logged rather than returned so local subscribers are never held up.
+func publish(event models.GameEvent) {
+	if bus == nil {
+		return
+	}
+	if err := bus.Publish(event); err != nil {
+		log.Printf("events: publishing %s for game %s: %v", event.Type, event.GameID, err)
+	}
+}
+
+// deliverRemote hands an event published by another instance to local
+// subscribers. Status events are skipped because they are rendered from the
+// game state of the instance that owns the game, and IDs are dropped because
+// each instance numbers events on its own.
+func deliverRemote(event models.GameEvent) {
+	if event.Type == "game_status" {
+		return
+	}
+	event.ID = 0
+	broadcast(event.GameID, event)
+}
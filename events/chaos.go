@@ -0,0 +1,24 @@
+package events
+
+import (
+	"htmx-go-app/rng"
+	"htmx-go-app/setting"
+)
+
+// Chaos settings make streams unreliable on purpose, so clients' reconnect
+// and resync handling can be exercised during development. Both are off at
+// zero.
+var (
+	ChaosDropRate       setting.Value[float64] // share of events silently dropped instead of sent
+	ChaosDisconnectRate setting.Value[float64] // chance a stream is ended after each event it sends
+)
+
+func chaosDrop() bool {
+	rate := ChaosDropRate.Get()
+	return rate > 0 && rng.Chance(rate)
+}
+
+func chaosDisconnect() bool {
+	rate := ChaosDisconnectRate.Get()
+	return rate > 0 && rng.Chance(rate)
+}
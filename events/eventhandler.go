@@ -2,47 +2,86 @@ package events
 
 import (
 	"context"
-	"crypto/rand"
-	"fmt"
+	"log"
+	"sync"
+	"time"
 
 	"htmx-go-app/models"
+	"htmx-go-app/rng"
 )
 
-// Global subscriber management
-var gameSubscribers = make(map[string][]*models.GameSubscriber)
+// Global subscriber management. The lock also keeps a subscriber's channel
+// from being closed while a broadcast is sending to it.
+var (
+	subscribersMu   sync.RWMutex
+	gameSubscribers = make(map[string][]*models.GameSubscriber)
+)
+
+// SubscriberSweepInterval is how often SweepSubscribers runs once started
+var SubscriberSweepInterval = time.Minute
 
 // generateSubscriberID creates a unique subscriber identifier
 func generateSubscriberID() string {
-	bytes := make([]byte, 8)
-	rand.Read(bytes)
-	return fmt.Sprintf("%x", bytes)
+	return rng.Hex(8)
+}
+
+// CreateGameSubscriber creates and registers a new SSE subscriber for a game
+func CreateGameSubscriber(gameID, playerID string, ctx context.Context) *models.GameSubscriber {
+	return CreateTransportSubscriber(gameID, playerID, TransportSSE, ctx)
 }
 
-// CreateGameSubscriber creates and registers a new subscriber for a game
-func CreateGameSubscriber(gameID string, ctx context.Context) *models.GameSubscriber {
+// CreateTransportSubscriber creates and registers a new subscriber for a game on the given transport
+func CreateTransportSubscriber(gameID, playerID, transport string, ctx context.Context) *models.GameSubscriber {
 	subscriber := &models.GameSubscriber{
-		ID:      generateSubscriberID(),
-		GameID:  gameID,
-		Channel: make(chan models.GameEvent, 10), // Buffer for events
-		Context: ctx,
+		ID:        generateSubscriberID(),
+		GameID:    gameID,
+		PlayerID:  playerID,
+		Transport: transport,
+		Channel:   make(chan models.GameEvent, 10), // Buffer for events
+		Context:   ctx,
 	}
 
+	subscribersMu.Lock()
+	first := connectionsLocked(gameID, playerID) == 0
 	gameSubscribers[gameID] = append(gameSubscribers[gameID], subscriber)
+	subscribersMu.Unlock()
+	if first {
+		presenceChanged(gameID, playerID)
+	}
+
+	// Unregister as soon as the connection goes away rather than when its
+	// handler gets around to returning
+	context.AfterFunc(ctx, func() { RemoveGameSubscriber(subscriber) })
 
 	return subscriber
 }
 
-// RemoveGameSubscriber removes a subscriber and cleans up resources
+// RemoveGameSubscriber unregisters a subscriber and closes its channel. It
+// may be called any number of times; only the call that finds the subscriber
+// still registered closes the channel.
 func RemoveGameSubscriber(subscriber *models.GameSubscriber) {
+	if removeGameSubscriber(subscriber) {
+		presenceChanged(subscriber.GameID, subscriber.PlayerID)
+	}
+}
+
+// removeGameSubscriber does the work of RemoveGameSubscriber, reporting
+// whether it closed the player's last stream to the game
+func removeGameSubscriber(subscriber *models.GameSubscriber) (last bool) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
 	subscribers, exists := gameSubscribers[subscriber.GameID]
 	if !exists {
-		return
+		return false
 	}
 
+	removed := false
 	for i, sub := range subscribers {
 		if sub.ID == subscriber.ID {
 			gameSubscribers[subscriber.GameID] = append(subscribers[:i], subscribers[i+1:]...)
 			close(sub.Channel)
+			removed = true
 			break
 		}
 	}
@@ -50,54 +89,172 @@ func RemoveGameSubscriber(subscriber *models.GameSubscriber) {
 	if len(gameSubscribers[subscriber.GameID]) == 0 {
 		delete(gameSubscribers, subscriber.GameID)
 	}
+	return removed && connectionsLocked(subscriber.GameID, subscriber.PlayerID) == 0
 }
 
-// BroadcastGameEvent sends an event to all subscribers of a game
-func BroadcastGameEvent(gameID string, event models.GameEvent) {
-	subscribers, exists := gameSubscribers[gameID]
+// CloseGame ends the streams of a game that is gone. Each subscriber is sent
+// final and then has its channel closed, so its handler returns instead of
+// waiting on a game that will never change again, and the game's subscriber
+// list and event history are dropped. A subscriber too far behind to take
+// final is closed all the same. It returns how many subscribers there were.
+func CloseGame(gameID string, final models.GameEvent) int {
+	final = recordEvent(gameID, final)
+	publish(final)
 
-	if !exists {
-		return
+	subscribersMu.Lock()
+	subscribers := gameSubscribers[gameID]
+	delete(gameSubscribers, gameID)
+	for _, subscriber := range subscribers {
+		if Wants(subscriber, final.Type) {
+			deliver(subscriber, final)
+		}
+		close(subscriber.Channel)
 	}
+	subscribersMu.Unlock()
 
-	for _, subscriber := range subscribers {
-		select {
-		case subscriber.Channel <- event:
-		case <-subscriber.Context.Done():
-			go RemoveGameSubscriber(subscriber)
-		default:
-			// Channel full, skip this subscriber
+	forgetEvents(gameID)
+	return len(subscribers)
+}
+
+// SweepSubscribers unregisters every subscriber whose context is done and
+// returns how many there were. Subscribers normally unregister themselves, so
+// this is a safety net against leaks.
+func SweepSubscribers() int {
+	var dead []*models.GameSubscriber
+	subscribersMu.RLock()
+	for _, subscribers := range gameSubscribers {
+		for _, subscriber := range subscribers {
+			if subscriber.Context.Err() != nil {
+				dead = append(dead, subscriber)
+			}
 		}
 	}
+	subscribersMu.RUnlock()
+
+	for _, subscriber := range dead {
+		RemoveGameSubscriber(subscriber)
+	}
+	return len(dead)
 }
 
-// BroadcastPersonalizedGameStatus sends personalized game status to all subscribers
-func BroadcastPersonalizedGameStatus(gameID string, game *models.Game) {
-	subscribers, exists := gameSubscribers[gameID]
+// StartSubscriberSweep runs SweepSubscribers every SubscriberSweepInterval
+// until the returned stop function is called
+func StartSubscriberSweep() (stop func()) {
+	ticker := time.NewTicker(SubscriberSweepInterval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if swept := SweepSubscribers(); swept > 0 {
+					log.Printf("swept %d dead event subscribers", swept)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
 
-	if !exists {
+// BroadcastGameEvent numbers an event, buffers it for replay and sends it to
+// all subscribers of a game
+func BroadcastGameEvent(gameID string, event models.GameEvent) {
+	event = recordEvent(gameID, event)
+	publish(event)
+	broadcast(gameID, event)
+}
+
+// broadcast sends an event to all subscribers registered under key
+func broadcast(key string, event models.GameEvent) {
+	subscribersMu.RLock()
+	defer subscribersMu.RUnlock()
+
+	for _, subscriber := range gameSubscribers[key] {
+		if Wants(subscriber, event.Type) {
+			deliver(subscriber, event)
+		}
+	}
+}
+
+// deliver queues an event for one subscriber without blocking the
+// broadcaster. Subscribers whose connection has gone away are skipped; they
+// are being unregistered.
+func deliver(subscriber *models.GameSubscriber, event models.GameEvent) {
+	if subscriber.Context.Err() != nil {
 		return
 	}
 
-	// For each subscriber, we need to determine their playerID and send personalized status
-	// Since we don't have direct access to playerID per subscriber, we'll send to all players
-	// and let the SSE handler figure out the playerID from the request context
-	for _, subscriber := range subscribers {
-		event := models.GameEvent{
-			Type:   "game_status",
-			GameID: gameID,
-			Data: map[string]interface{}{
-				"gameID": gameID,
-				"game":   game,
-			},
-		}
+	select {
+	case subscriber.Channel <- event:
+		countQueued()
+	default:
+		// Channel full, skip this subscriber
+		countDropped(subscriber.GameID)
+	}
+}
 
-		select {
-		case subscriber.Channel <- event:
-		case <-subscriber.Context.Done():
-			go RemoveGameSubscriber(subscriber)
-		default:
-			// Channel full, skip this subscriber
+// newGameStatusEvent creates the unrendered status event kept for replay
+func newGameStatusEvent(gameID string, game *models.Game) models.GameEvent {
+	return models.GameEvent{
+		Type:   "game_status",
+		GameID: gameID,
+		Data: map[string]interface{}{
+			"gameID": gameID,
+			"game":   game,
+		},
+	}
+}
+
+// personalizeStatus renders a status event for the player bound to a subscriber
+func personalizeStatus(status models.GameEvent, subscriber *models.GameSubscriber, game *models.Game, render func(playerID string) string) models.GameEvent {
+	status.Data = map[string]interface{}{
+		"gameID":   status.GameID,
+		"game":     game,
+		"playerID": subscriber.PlayerID,
+		"html":     render(subscriber.PlayerID),
+	}
+	return status
+}
+
+// BroadcastGameUpdate broadcasts a board event together with the status
+// update it causes, rendered for the player bound to each subscriber. Replayed
+// status events are personalized again when they are sent. Subscribers that want both get a single event of the
+// board event's type, with the rendered status added as "statusHTML" and the
+// status event's ID, so a move costs one write and flush instead of two.
+// Both events are still buffered separately for replay.
+func BroadcastGameUpdate(gameID string, event models.GameEvent, game *models.Game, render func(playerID string) string) {
+	event = recordEvent(gameID, event)
+	status := recordEvent(gameID, newGameStatusEvent(gameID, game))
+	publish(event)
+	publish(status)
+
+	boardData, _ := event.Data.(map[string]interface{})
+
+	subscribersMu.RLock()
+	defer subscribersMu.RUnlock()
+
+	for _, subscriber := range gameSubscribers[gameID] {
+		wantsEvent := Wants(subscriber, event.Type)
+		wantsStatus := Wants(subscriber, status.Type)
+
+		switch {
+		case wantsEvent && wantsStatus:
+			data := make(map[string]interface{}, len(boardData)+1)
+			for key, value := range boardData {
+				data[key] = value
+			}
+			data["statusHTML"] = render(subscriber.PlayerID)
+
+			combined := event
+			combined.ID = status.ID
+			combined.Data = data
+			deliver(subscriber, combined)
+		case wantsEvent:
+			deliver(subscriber, event)
+		case wantsStatus:
+			deliver(subscriber, personalizeStatus(status, subscriber, game, render))
 		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,73 @@
+package events
+
+import (
+	"errors"
+	"strings"
+
+	"htmx-go-app/models"
+)
+
+// GameEventTypes lists the event types broadcast on a game stream
+var GameEventTypes = []string{
+	"move",
+	"reset",
+	"game_winner",
+	"game_draw",
+	"celebration",
+	"game_corrected",
+	"game_paused",
+	"game_resumed",
+	"game_status",
+	"player_join",
+	"players_update",
+	"join_state",
+	"game_ready",
+	"game_cancelled",
+	"settings_changed",
+	"chat",
+	"turn_reminder",
+	"pause_request",
+	"announcement",
+}
+
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// ParseEventTypes parses a comma-separated list such as "move,game_winner"
+// into a subscriber filter. An empty list returns nil, which delivers all types.
+func ParseEventTypes(list string) (map[string]bool, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+
+	known := make(map[string]bool, len(GameEventTypes))
+	for _, eventType := range GameEventTypes {
+		known[eventType] = true
+	}
+
+	filter := make(map[string]bool)
+	for _, eventType := range strings.Split(list, ",") {
+		eventType = strings.TrimSpace(eventType)
+		if !known[eventType] {
+			return nil, ErrUnknownEventType
+		}
+		filter[eventType] = true
+	}
+	return filter, nil
+}
+
+// AllEventTypesExcept returns a filter for every game event type but the given ones
+func AllEventTypesExcept(excluded ...string) map[string]bool {
+	filter := make(map[string]bool, len(GameEventTypes))
+	for _, eventType := range GameEventTypes {
+		filter[eventType] = true
+	}
+	for _, eventType := range excluded {
+		delete(filter, eventType)
+	}
+	return filter
+}
+
+// Wants reports whether the subscriber should receive events of the given type
+func Wants(subscriber *models.GameSubscriber, eventType string) bool {
+	return subscriber.EventTypes == nil || subscriber.EventTypes[eventType]
+}
@@ -0,0 +1,28 @@
+package events
+
+import "htmx-go-app/models"
+
+// History returns up to limit recorded events for a game with IDs after
+// afterID, oldest first, keeping only the types in filter (nil keeps all).
+// It also reports whether more matching events follow.
+func History(gameID string, afterID uint64, filter map[string]bool, limit int) ([]models.GameEvent, bool) {
+	eventLogsMu.Lock()
+	defer eventLogsMu.Unlock()
+
+	log, exists := eventLogs[gameID]
+	if !exists {
+		return nil, false
+	}
+
+	var page []models.GameEvent
+	for _, event := range log.events {
+		if event.ID <= afterID || (filter != nil && !filter[event.Type]) {
+			continue
+		}
+		if len(page) == limit {
+			return page, true
+		}
+		page = append(page, event)
+	}
+	return page, false
+}
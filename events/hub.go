@@ -0,0 +1,99 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"htmx-go-app/models"
+	"htmx-go-app/setting"
+)
+
+// Transports a subscriber can be connected through
+const (
+	TransportSSE       = "sse"
+	TransportWebSocket = "ws"
+)
+
+// Sink writes events to one subscriber's connection. Each transport (SSE,
+// WebSocket) provides its own Sink, while all of them share the same
+// subscriber registry and broadcaster.
+type Sink interface {
+	Send(event models.GameEvent) error
+}
+
+// Heartbeater is implemented by sinks that can send a keep-alive on an idle
+// connection, such as an SSE comment or a WebSocket ping
+type Heartbeater interface {
+	Heartbeat() error
+}
+
+// HeartbeatInterval is how often Serve sends keep-alives to sinks that
+// support them. Zero disables heartbeats.
+var HeartbeatInterval = setting.New(15 * time.Second)
+
+var (
+	closingMu sync.Mutex
+	closing   = make(chan struct{})
+)
+
+// CloseStreams ends every stream currently being served, so a shutting down
+// server isn't held open by long-lived SSE and WebSocket connections
+func CloseStreams() {
+	closingMu.Lock()
+	defer closingMu.Unlock()
+	close(closing)
+	closing = make(chan struct{})
+}
+
+func closingSignal() <-chan struct{} {
+	closingMu.Lock()
+	defer closingMu.Unlock()
+	return closing
+}
+
+// Serve delivers events from the subscriber's channel to the sink until the
+// subscriber's context is done or a write fails. Failed heartbeats end the
+// stream too, so dead connections are noticed even when a game is idle.
+func Serve(subscriber *models.GameSubscriber, sink Sink) {
+	closed := closingSignal()
+	var heartbeat <-chan time.Time
+	heartbeater, ok := sink.(Heartbeater)
+	if interval := HeartbeatInterval.Get(); ok && interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-heartbeat:
+			if err := heartbeater.Heartbeat(); err != nil {
+				return
+			}
+		case event, ok := <-subscriber.Channel:
+			if !ok {
+				return
+			}
+			if event.ID != 0 {
+				if event.ID <= subscriber.LastEventID {
+					continue // already replayed
+				}
+				subscriber.LastEventID = event.ID
+			}
+			if chaosDrop() {
+				continue
+			}
+			if err := sink.Send(event); err != nil {
+				return
+			}
+			countSent()
+			if chaosDisconnect() {
+				return
+			}
+		case <-subscriber.Context.Done():
+			return
+		case <-closed:
+			return
+		}
+	}
+}
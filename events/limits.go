@@ -0,0 +1,70 @@
+package events
+
+import (
+	"errors"
+	"sync"
+
+	"htmx-go-app/setting"
+)
+
+// Ceilings on concurrent event stream connections, so a single client can't
+// exhaust goroutines. Zero disables a limit.
+var (
+	MaxConnectionsPerIP   setting.Value[int] // across every stream
+	MaxConnectionsPerGame setting.Value[int] // players and spectators of one game
+)
+
+// Errors returned when a connection would exceed a ceiling
+var (
+	ErrTooManyConnectionsFromIP = errors.New("too many open connections from your address")
+	ErrTooManyGameConnections   = errors.New("too many people are connected to this game")
+)
+
+var (
+	connectionsMu     sync.Mutex
+	connectionsByIP   = make(map[string]int)
+	connectionsByGame = make(map[string]int)
+)
+
+// AcquireConnection reserves a connection slot for a stream from ip to
+// gameID's events. Streams that aren't about one game, such as the lobby,
+// pass an empty gameID and only count towards the IP ceiling. Call release
+// once the stream ends.
+func AcquireConnection(ip, gameID string) (release func(), err error) {
+	perGame := gameID != ""
+
+	connectionsMu.Lock()
+	defer connectionsMu.Unlock()
+
+	if limit := MaxConnectionsPerIP.Get(); limit > 0 && connectionsByIP[ip] >= limit {
+		return nil, ErrTooManyConnectionsFromIP
+	}
+	if limit := MaxConnectionsPerGame.Get(); perGame && limit > 0 && connectionsByGame[gameID] >= limit {
+		return nil, ErrTooManyGameConnections
+	}
+
+	connectionsByIP[ip]++
+	if perGame {
+		connectionsByGame[gameID]++
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			connectionsMu.Lock()
+			defer connectionsMu.Unlock()
+			decrement(connectionsByIP, ip)
+			if perGame {
+				decrement(connectionsByGame, gameID)
+			}
+		})
+	}, nil
+}
+
+func decrement(counts map[string]int, key string) {
+	if counts[key] <= 1 {
+		delete(counts, key)
+		return
+	}
+	counts[key]--
+}
@@ -0,0 +1,21 @@
+package events
+
+import (
+	"context"
+
+	"htmx-go-app/models"
+)
+
+// LobbyID is the subscriber key for the lobby-wide stream. Game IDs are hex,
+// so it can never collide with a game.
+const LobbyID = "lobby"
+
+// CreateLobbySubscriber creates and registers a new subscriber for lobby events
+func CreateLobbySubscriber(ctx context.Context) *models.GameSubscriber {
+	return CreateGameSubscriber(LobbyID, "", ctx)
+}
+
+// BroadcastLobbyEvent sends an event to all lobby subscribers
+func BroadcastLobbyEvent(event models.GameEvent) {
+	broadcast(LobbyID, event)
+}
@@ -0,0 +1,25 @@
+package events
+
+import (
+	"context"
+
+	"htmx-go-app/models"
+)
+
+// matchmakingKey is the subscriber key for a queued player's private stream
+func matchmakingKey(playerID string) string {
+	return "match_" + playerID
+}
+
+// CreateMatchmakingSubscriber registers a queued player to be told when they're matched
+func CreateMatchmakingSubscriber(playerID string, ctx context.Context) *models.GameSubscriber {
+	return CreateGameSubscriber(matchmakingKey(playerID), playerID, ctx)
+}
+
+// BroadcastMatchFound tells a queued player which game they've been matched into
+func BroadcastMatchFound(playerID, gameID string) {
+	broadcast(matchmakingKey(playerID), models.GameEvent{
+		Type:   "match_found",
+		GameID: gameID,
+	})
+}
@@ -0,0 +1,84 @@
+package events
+
+import (
+	"strings"
+	"sync"
+)
+
+// Metrics is a snapshot of event stream load
+type Metrics struct {
+	ConnectionsByTransport map[string]int    // open connections per transport
+	GameSubscribers        map[string]int    // gameID -> open connections
+	LobbySubscribers       int               // connections to the lobby stream
+	MatchmakingSubscribers int               // players waiting in quick match
+	EventsQueued           uint64            // events handed to subscriber channels
+	EventsSent             uint64            // events written to connections
+	EventsDropped          uint64            // events skipped because a subscriber fell behind
+	DroppedByGame          map[string]uint64 // stream key -> dropped events
+}
+
+var (
+	metricsMu     sync.Mutex
+	eventsQueued  uint64
+	eventsSent    uint64
+	eventsDropped uint64
+	droppedByGame = make(map[string]uint64)
+)
+
+func countQueued() {
+	metricsMu.Lock()
+	eventsQueued++
+	metricsMu.Unlock()
+}
+
+func countSent() {
+	metricsMu.Lock()
+	eventsSent++
+	metricsMu.Unlock()
+}
+
+func countDropped(key string) {
+	metricsMu.Lock()
+	eventsDropped++
+	droppedByGame[key]++
+	metricsMu.Unlock()
+}
+
+// Snapshot returns the current subscriber counts and event counters.
+// Matchmaking streams are keyed by player ID, so only their total is reported.
+func Snapshot() Metrics {
+	metrics := Metrics{
+		ConnectionsByTransport: map[string]int{TransportSSE: 0, TransportWebSocket: 0},
+		GameSubscribers:        make(map[string]int),
+		DroppedByGame:          make(map[string]uint64),
+	}
+
+	subscribersMu.RLock()
+	for key, subscribers := range gameSubscribers {
+		for _, subscriber := range subscribers {
+			metrics.ConnectionsByTransport[subscriber.Transport]++
+		}
+		switch {
+		case key == LobbyID:
+			metrics.LobbySubscribers += len(subscribers)
+		case strings.HasPrefix(key, matchmakingKey("")):
+			metrics.MatchmakingSubscribers += len(subscribers)
+		default:
+			metrics.GameSubscribers[key] = len(subscribers)
+		}
+	}
+	subscribersMu.RUnlock()
+
+	metricsMu.Lock()
+	metrics.EventsQueued = eventsQueued
+	metrics.EventsSent = eventsSent
+	metrics.EventsDropped = eventsDropped
+	for key, dropped := range droppedByGame {
+		if !strings.HasPrefix(key, matchmakingKey("")) {
+			metrics.DroppedByGame[key] = dropped
+		}
+	}
+	metricsMu.Unlock()
+
+	return metrics
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"htmx-go-app/models"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSSubjectPrefix is prepended to game IDs to form per-game subjects,
+// e.g. "tictactoe.game.<id>"
+const NATSSubjectPrefix = "tictactoe.game"
+
+// natsMessage is the wire format of an event on NATS. The board is carried
+// separately so receivers get it back as a models.GameBoard; other data is
+// limited to plain values, since games themselves are not shared.
+type natsMessage struct {
+	Origin string                 `json:"origin"`
+	ID     uint64                 `json:"id"`
+	Type   string                 `json:"type"`
+	GameID string                 `json:"gameId"`
+	Board  *models.GameBoard      `json:"board,omitempty"`
+	Data   map[string]interface{} `json:"data,omitempty"`
+}
+
+// NATSBus publishes game events to subject-per-game NATS topics and delivers
+// events from other instances to local subscribers
+type NATSBus struct {
+	conn         *nats.Conn
+	subscription *nats.Subscription
+	origin       string
+}
+
+// NewNATSBus connects to the NATS server at url and subscribes to all game subjects
+func NewNATSBus(url string) (*NATSBus, error) {
+	conn, err := nats.Connect(url, nats.Name("tic-tac-toe"))
+	if err != nil {
+		return nil, fmt.Errorf("connecting to NATS: %w", err)
+	}
+
+	b := &NATSBus{conn: conn, origin: generateSubscriberID()}
+	b.subscription, err = conn.Subscribe(NATSSubjectPrefix+".*", b.receive)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("subscribing to game events: %w", err)
+	}
+
+	return b, nil
+}
+
+// Publish sends the event to its game's subject
+func (b *NATSBus) Publish(event models.GameEvent) error {
+	message := natsMessage{
+		Origin: b.origin,
+		ID:     event.ID,
+		Type:   event.Type,
+		GameID: event.GameID,
+	}
+
+	if data, ok := event.Data.(map[string]interface{}); ok {
+		message.Data = make(map[string]interface{}, len(data))
+		for key, value := range data {
+			switch value := value.(type) {
+			case models.GameBoard:
+				message.Board = &value
+			case string, int, bool:
+				message.Data[key] = value
+			}
+		}
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return b.conn.Publish(NATSSubjectPrefix+"."+event.GameID, payload)
+}
+
+// receive delivers events published by other instances
+func (b *NATSBus) receive(msg *nats.Msg) {
+	var message natsMessage
+	if err := json.Unmarshal(msg.Data, &message); err != nil || message.Origin == b.origin {
+		return
+	}
+
+	data := message.Data
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	if message.Board != nil {
+		data["board"] = *message.Board
+	}
+
+	deliverRemote(models.GameEvent{
+		ID:     message.ID,
+		Type:   message.Type,
+		GameID: message.GameID,
+		Data:   data,
+	})
+}
+
+// Close unsubscribes and drains pending messages before disconnecting
+func (b *NATSBus) Close() error {
+	if err := b.subscription.Unsubscribe(); err != nil {
+		return err
+	}
+	return b.conn.Drain()
+}
@@ -0,0 +1,49 @@
+package events
+
+import "htmx-go-app/models"
+
+// OnPresenceChange, if set, is called after a player's first stream to a
+// game opens or their last one closes, so their opponent can be shown
+// whether they are there
+var OnPresenceChange func(gameID, playerID string)
+
+// Connected reports whether playerID has a stream open to the game on this
+// server
+func Connected(gameID, playerID string) bool {
+	subscribersMu.RLock()
+	defer subscribersMu.RUnlock()
+	return connectionsLocked(gameID, playerID) > 0
+}
+
+// connectionsLocked counts playerID's streams to the game. The caller holds
+// subscribersMu.
+func connectionsLocked(gameID, playerID string) int {
+	count := 0
+	for _, subscriber := range gameSubscribers[gameID] {
+		if subscriber.PlayerID == playerID {
+			count++
+		}
+	}
+	return count
+}
+
+func presenceChanged(gameID, playerID string) {
+	if OnPresenceChange != nil && playerID != "" {
+		OnPresenceChange(gameID, playerID)
+	}
+}
+
+// BroadcastPresence sends event to the game's subscribers on this server
+// other than playerID's own, who know they are there. It isn't numbered,
+// buffered for replay or published to the bus: presence is only known to the
+// server a player is connected to, and only matters when it changes.
+func BroadcastPresence(gameID, playerID string, event models.GameEvent) {
+	subscribersMu.RLock()
+	defer subscribersMu.RUnlock()
+
+	for _, subscriber := range gameSubscribers[gameID] {
+		if subscriber.PlayerID != playerID && Wants(subscriber, event.Type) {
+			deliver(subscriber, event)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"sync"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/models"
+)
+
+// ReplayBufferSize is how many missed events a client that reconnects with a
+// Last-Event-ID can have replayed
+const ReplayBufferSize = 64
+
+// HistorySize is how many events are kept per game for replay and the
+// event history API
+const HistorySize = 1000
+
+// eventLog numbers a game's events and keeps the most recent ones
+type eventLog struct {
+	lastID uint64
+	events []models.GameEvent
+}
+
+var (
+	eventLogsMu sync.Mutex
+	eventLogs   = make(map[string]*eventLog)
+)
+
+// recordEvent assigns the event the next ID and a timestamp, and adds it to
+// the game's history
+func recordEvent(gameID string, event models.GameEvent) models.GameEvent {
+	eventLogsMu.Lock()
+	defer eventLogsMu.Unlock()
+
+	log, exists := eventLogs[gameID]
+	if !exists {
+		log = &eventLog{}
+		eventLogs[gameID] = log
+	}
+
+	log.lastID++
+	event.ID = log.lastID
+	event.At = clock.Now()
+	log.events = append(log.events, event)
+	if len(log.events) > HistorySize {
+		log.events = log.events[len(log.events)-HistorySize:]
+	}
+
+	return event
+}
+
+// forgetEvents drops a game's event history
+func forgetEvents(gameID string) {
+	eventLogsMu.Lock()
+	defer eventLogsMu.Unlock()
+	delete(eventLogs, gameID)
+}
+
+// EventsSince returns the buffered events after lastID. It reports false when
+// events after lastID have already been evicted, or lastID is unknown (for
+// example after a server restart), so the caller must resend full state.
+func EventsSince(gameID string, lastID uint64) ([]models.GameEvent, bool) {
+	eventLogsMu.Lock()
+	defer eventLogsMu.Unlock()
+
+	log, exists := eventLogs[gameID]
+	if !exists {
+		return nil, lastID == 0
+	}
+	if lastID > log.lastID {
+		return nil, false
+	}
+
+	missed := log.lastID - lastID
+	if missed > ReplayBufferSize || missed > uint64(len(log.events)) {
+		return nil, false
+	}
+
+	replay := make([]models.GameEvent, missed)
+	copy(replay, log.events[uint64(len(log.events))-missed:])
+	return replay, true
+}
+
+// Replay sends the subscriber the events it missed since its LastEventID. It
+// reports false, without sending anything, if they are no longer buffered.
+func Replay(subscriber *models.GameSubscriber, sink Sink) bool {
+	missed, ok := EventsSince(subscriber.GameID, subscriber.LastEventID)
+	if !ok {
+		return false
+	}
+
+	for _, event := range missed {
+		if !Wants(subscriber, event.Type) {
+			continue
+		}
+		if err := sink.Send(event); err != nil {
+			break
+		}
+		subscriber.LastEventID = event.ID
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+package game
+
+import (
+	"errors"
+	"time"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/models"
+	"htmx-go-app/setting"
+)
+
+// AbandonAfter is how long the player to move may stay idle before the game
+// counts as abandoned and its AbandonRule is applied. Zero, the default,
+// disables it: players aren't shown a deadline counting down, so nobody
+// loses to one unless the server opts in.
+var AbandonAfter setting.Value[time.Duration]
+
+// Errors returned for abandonment rules and paused games
+var (
+	ErrInvalidAbandonRule = errors.New("invalid abandonment rule")
+	ErrGameNotPaused      = errors.New("game is not paused")
+)
+
+// validAbandonRule reports whether rule is an abandonment rule, "" standing
+// for the default
+func validAbandonRule(rule models.AbandonRule) bool {
+	switch rule {
+	case "", models.AbandonWin, models.AbandonVoid, models.AbandonPause:
+		return true
+	default:
+		return false
+	}
+}
+
+// AbandonRuleOf returns the game's abandonment rule, AbandonWin unless the
+// creator chose another
+func AbandonRuleOf(game *models.Game) models.AbandonRule {
+	if game.AbandonRule == "" {
+		return models.AbandonWin
+	}
+	return game.AbandonRule
+}
+
+// TurnStartedAt is when the current turn began: the last move, or the
+// game's start or resumption if that came later
+func TurnStartedAt(game *models.Game) time.Time {
+	started := game.StartedAt
+	if len(game.Moves) > 0 && game.Moves[len(game.Moves)-1].At.After(started) {
+		started = game.Moves[len(game.Moves)-1].At
+	}
+	if game.ResumedAt.After(started) {
+		started = game.ResumedAt
+	}
+	return started
+}
+
+// Abandonment is a game AbandonIdleGames applied a rule to
+type Abandonment struct {
+	Game *models.Game
+	Rule models.AbandonRule
+}
+
+// AbandonIdleGames applies the abandonment rule of every active game whose
+// player to move has been idle for longer than AbandonAfter: the opponent
+// wins, the game is deleted, or it is paused. Correspondence games have no
+// turn timer and are never abandoned. The games are returned so the caller
+// can tell their subscribers.
+func AbandonIdleGames() []Abandonment {
+	if AbandonAfter.Get() <= 0 {
+		return nil
+	}
+
+	now := clock.Now()
+	idle := Snapshot(func(game *models.Game) bool {
+		return isIdle(game, now)
+	})
+
+	abandoned := make([]Abandonment, 0, len(idle))
+	for _, copied := range idle {
+		game := GetGame(copied.ID)
+		if game == nil {
+			continue
+		}
+		rule, ok := abandon(game, now)
+		if !ok {
+			continue
+		}
+		if rule == models.AbandonVoid {
+			DeleteGame(game.ID)
+		}
+		abandoned = append(abandoned, Abandonment{Game: game, Rule: rule})
+	}
+	return abandoned
+}
+
+// isIdle reports whether the player to move has let the turn run out
+func isIdle(game *models.Game, now time.Time) bool {
+	return IsGameActive(game) && !game.Correspondence && now.Sub(TurnStartedAt(game)) > AbandonAfter.Get()
+}
+
+// abandon applies the game's abandonment rule against the player to move
+// and returns the rule. It checks again under the game's lock that the game
+// is still idle, so a move that arrives at the last moment isn't lost, and
+// reports false if it isn't. A game voided by the rule is left for the
+// caller to delete.
+func abandon(game *models.Game, now time.Time) (models.AbandonRule, bool) {
+	unlock := Lock(game)
+	defer unlock()
+	if !isIdle(game, now) {
+		return "", false
+	}
+	rule := AbandonRuleOf(game)
+	game.AbandonedBy = GetCurrentPlayerID(game)
+
+	switch rule {
+	case models.AbandonVoid:
+		return rule, true
+	case models.AbandonPause:
+		game.Status = models.GameStatusPaused
+	default:
+		game.Status = models.GameStatusFinished
+		game.Winner = game.PlayerOrder[(game.CurrentTurn+1)%len(game.PlayerOrder)]
+		game.FinishedAt = now
+	}
+	game.Nudged = false
+	game.Version++
+	return rule, true
+}
+
+// ResumeGame lets either player pick a paused game up where it was left,
+// with the same player to move
+func ResumeGame(game *models.Game, playerID string) error {
+	unlock := Lock(game)
+	defer unlock()
+
+	if player, exists := game.Players[playerID]; !exists || player.Emoji == "" {
+		return ErrNotAPlayer
+	}
+	if game.Status != models.GameStatusPaused {
+		return ErrGameNotPaused
+	}
+
+	game.Status = models.GameStatusActive
+	game.AbandonedBy = ""
+	game.ResumedAt = clock.Now()
+	game.Version++
+	return nil
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/models"
+)
+
+// BackupVersion is the format version written into backups. Restoring a
+// backup from a newer version is refused rather than half understood.
+// Version 2 boards hold seat letters; version 1 boards held emojis and are
+// rewritten on restore.
+const BackupVersion = 2
+
+// ErrUnsupportedBackup is returned when restoring a backup whose version
+// this server doesn't know
+var ErrUnsupportedBackup = errors.New("unsupported backup version")
+
+// Backup is everything the store holds that can't be recomputed: games, and
+// the players known apart from them, i.e. bots, bans, Telegram links and
+// preferences.
+// Stats are derived from the games, and invites and the match queue are too
+// short-lived to be worth keeping.
+type Backup struct {
+	Version       int                    `json:"version"`
+	CreatedAt     time.Time              `json:"createdAt"`
+	Games         []*models.Game         `json:"games"`
+	Bots          []*models.Bot          `json:"bots"`
+	BannedPlayers []string               `json:"bannedPlayers"`
+	TelegramUsers []*models.TelegramUser `json:"telegramUsers"`
+	MutedCues     []string               `json:"mutedCues,omitempty"`   // players with sound and vibration cues off
+	PieceBadges   []string               `json:"pieceBadges,omitempty"` // players with seat badges on the pieces
+}
+
+// ExportBackup copies the store into a Backup
+func ExportBackup() Backup {
+	backup := Backup{
+		Version:       BackupVersion,
+		CreatedAt:     clock.Now(),
+		Games:         Snapshot(nil),
+		BannedPlayers: BannedPlayers(),
+		MutedCues:     cuesMutedPlayers(),
+		PieceBadges:   pieceBadgesPlayers(),
+	}
+
+	for _, bot := range ListBots() {
+		copied := *bot
+		backup.Bots = append(backup.Bots, &copied)
+	}
+
+	telegramMu.RLock()
+	for _, user := range telegramUsers {
+		copied := *user
+		backup.TelegramUsers = append(backup.TelegramUsers, &copied)
+	}
+	telegramMu.RUnlock()
+	slices.SortFunc(backup.TelegramUsers, func(a, b *models.TelegramUser) int {
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+	return backup
+}
+
+// RestoreBackup replaces the store's contents with the backup's. Games that
+// aren't in the backup are removed as if deleted; the restored games are
+// returned so callers can drop anything they derived from the old ones.
+func RestoreBackup(backup Backup) ([]*models.Game, error) {
+	if backup.Version < 1 || backup.Version > BackupVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedBackup, backup.Version)
+	}
+
+	restored := make(map[string]bool, len(backup.Games))
+	for _, game := range backup.Games {
+		if game == nil || game.ID == "" {
+			return nil, fmt.Errorf("backup holds a game without an ID")
+		}
+		restored[game.ID] = true
+	}
+
+	removed := games.removeIf(func(game *models.Game) bool { return !restored[game.ID] })
+	for _, game := range removed {
+		gameRemoved(game)
+	}
+	slugsMu.Lock()
+	clear(slugs)
+	slugsMu.Unlock()
+	for _, game := range backup.Games {
+		migrateBoard(game)
+		games.put(game)
+		if game.Slug != "" {
+			setSlug(game.Slug, game.ID)
+		}
+	}
+
+	botsMu.Lock()
+	clear(bots)
+	clear(botPlayers)
+	for _, bot := range backup.Bots {
+		bots[bot.ID] = bot
+		botPlayers[bot.PlayerID] = bot
+	}
+	botsMu.Unlock()
+
+	bansMu.Lock()
+	clear(bannedPlayers)
+	for _, playerID := range backup.BannedPlayers {
+		bannedPlayers[playerID] = true
+	}
+	bansMu.Unlock()
+
+	telegramMu.Lock()
+	clear(telegramUsers)
+	clear(telegramPlayers)
+	clear(telegramLogins)
+	for _, user := range backup.TelegramUsers {
+		telegramUsers[user.UserID] = user
+		telegramPlayers[user.PlayerID] = user
+		telegramLogins[user.LoginToken] = user
+	}
+	telegramMu.Unlock()
+
+	preferencesMu.Lock()
+	clear(mutedCues)
+	for _, playerID := range backup.MutedCues {
+		mutedCues[playerID] = true
+	}
+	clear(pieceBadges)
+	for _, playerID := range backup.PieceBadges {
+		pieceBadges[playerID] = true
+	}
+	preferencesMu.Unlock()
+
+	return backup.Games, nil
+}
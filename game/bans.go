@@ -0,0 +1,57 @@
+package game
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrPlayerBanned is returned for requests from a banned player
+var ErrPlayerBanned = errors.New("player is banned")
+
+var (
+	bansMu        sync.RWMutex
+	bannedPlayers = make(map[string]bool)
+)
+
+// BanPlayer blocks the player from using the server and takes them out of
+// the quick match queue
+func BanPlayer(playerID string) {
+	bansMu.Lock()
+	bannedPlayers[playerID] = true
+	bansMu.Unlock()
+
+	LeaveMatchQueue(playerID)
+}
+
+// UnbanPlayer lifts a ban, reporting whether the player was banned
+func UnbanPlayer(playerID string) bool {
+	bansMu.Lock()
+	defer bansMu.Unlock()
+
+	if !bannedPlayers[playerID] {
+		return false
+	}
+	delete(bannedPlayers, playerID)
+	return true
+}
+
+// IsBanned reports whether the player is banned
+func IsBanned(playerID string) bool {
+	bansMu.RLock()
+	defer bansMu.RUnlock()
+	return bannedPlayers[playerID]
+}
+
+// BannedPlayers returns the IDs of all banned players, sorted
+func BannedPlayers() []string {
+	bansMu.RLock()
+	defer bansMu.RUnlock()
+
+	ids := make([]string, 0, len(bannedPlayers))
+	for id := range bannedPlayers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,122 @@
+package game
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/models"
+	"htmx-go-app/rng"
+)
+
+// Errors returned when registering a bot
+var (
+	ErrInvalidBotName    = errors.New("bot name is required and must be at most 24 characters")
+	ErrInvalidWebhookURL = errors.New("webhook URL must be an absolute http or https URL")
+)
+
+var (
+	botsMu     sync.RWMutex
+	bots       = make(map[string]*models.Bot) // bot ID -> bot
+	botPlayers = make(map[string]*models.Bot) // player ID -> bot
+)
+
+// RegisterBot creates a bot and returns it along with its API key. Only a
+// hash of the key is kept, so it can't be shown again.
+func RegisterBot(name, webhookURL string) (*models.Bot, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxPlayerNameLength {
+		return nil, "", ErrInvalidBotName
+	}
+	if webhookURL != "" {
+		parsed, err := url.Parse(webhookURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, "", ErrInvalidWebhookURL
+		}
+	}
+
+	id := rng.Hex(6)
+	apiKey := "ttt_" + rng.Secret(24)
+	bot := &models.Bot{
+		ID:         id,
+		Name:       name,
+		PlayerID:   "bot_" + id,
+		WebhookURL: webhookURL,
+		KeyHash:    sha256.Sum256([]byte(apiKey)),
+		CreatedAt:  clock.Now(),
+	}
+	if webhookURL != "" {
+		bot.WebhookSecret = rng.Secret(16)
+	}
+
+	botsMu.Lock()
+	bots[bot.ID] = bot
+	botPlayers[bot.PlayerID] = bot
+	botsMu.Unlock()
+
+	return bot, apiKey, nil
+}
+
+// BotByKey returns the bot the API key belongs to, or nil
+func BotByKey(apiKey string) *models.Bot {
+	hash := sha256.Sum256([]byte(apiKey))
+
+	botsMu.RLock()
+	defer botsMu.RUnlock()
+	for _, bot := range bots {
+		if subtle.ConstantTimeCompare(hash[:], bot.KeyHash[:]) == 1 {
+			return bot
+		}
+	}
+	return nil
+}
+
+// BotForPlayer returns the bot playing as playerID, or nil for a human
+func BotForPlayer(playerID string) *models.Bot {
+	botsMu.RLock()
+	defer botsMu.RUnlock()
+	return botPlayers[playerID]
+}
+
+// ListBots returns all registered bots, oldest first
+func ListBots() []*models.Bot {
+	botsMu.RLock()
+	defer botsMu.RUnlock()
+
+	result := make([]*models.Bot, 0, len(bots))
+	for _, bot := range bots {
+		result = append(result, bot)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+// DeleteBot revokes a bot's API key, reporting whether it existed. Games it
+// is playing are left alone; it just can't move in them any more.
+func DeleteBot(id string) bool {
+	botsMu.Lock()
+	defer botsMu.Unlock()
+
+	bot, exists := bots[id]
+	if !exists {
+		return false
+	}
+	delete(bots, id)
+	delete(botPlayers, bot.PlayerID)
+	return true
+}
+
+// GamesAwaitingMove lists the active games where it's playerID's turn
+func GamesAwaitingMove(playerID string) []*models.Game {
+	return ListGames(func(g *models.Game) bool {
+		return IsPlayersTurn(g, playerID)
+	})
+}
@@ -0,0 +1,77 @@
+package game
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/models"
+	"htmx-go-app/setting"
+)
+
+const (
+	// MaxChatMessageLength is the longest chat message accepted, in characters
+	MaxChatMessageLength = 200
+	// MaxChatHistory is how many messages a game keeps
+	MaxChatHistory = 50
+)
+
+// ChatCooldown is the minimum time between two messages from the same player
+var ChatCooldown = setting.New(time.Second)
+
+// Errors returned when a chat message is rejected
+var (
+	ErrEmptyMessage    = errors.New("message is empty")
+	ErrMessageTooLong  = errors.New("message is too long")
+	ErrChatRateLimited = errors.New("you're sending messages too quickly")
+)
+
+// PostChatMessage adds a message from a player to the game's chat. Whitespace,
+// including newlines, is collapsed so each message is a single line.
+func PostChatMessage(game *models.Game, playerID, text string) (models.ChatMessage, error) {
+	player, exists := game.Players[playerID]
+	if !exists || player.Emoji == "" {
+		return models.ChatMessage{}, ErrNotAPlayer
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return models.ChatMessage{}, ErrEmptyMessage
+	}
+	if utf8.RuneCountInString(text) > MaxChatMessageLength {
+		return models.ChatMessage{}, ErrMessageTooLong
+	}
+
+	unlock := Lock(game)
+	defer unlock()
+	now := clock.Now()
+	if last, ok := lastChatMessage(game, playerID); ok && now.Sub(last.SentAt) < ChatCooldown.Get() {
+		return models.ChatMessage{}, ErrChatRateLimited
+	}
+
+	message := models.ChatMessage{
+		PlayerID: playerID,
+		Emoji:    player.Emoji,
+		Name:     player.Name,
+		Text:     text,
+		SentAt:   now,
+	}
+	game.Chat = append(game.Chat, message)
+	if len(game.Chat) > MaxChatHistory {
+		game.Chat = game.Chat[len(game.Chat)-MaxChatHistory:]
+	}
+
+	return message, nil
+}
+
+// lastChatMessage finds the player's most recent message
+func lastChatMessage(game *models.Game, playerID string) (models.ChatMessage, bool) {
+	for i := len(game.Chat) - 1; i >= 0; i-- {
+		if game.Chat[i].PlayerID == playerID {
+			return game.Chat[i], true
+		}
+	}
+	return models.ChatMessage{}, false
+}
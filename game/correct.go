@@ -0,0 +1,102 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/engine"
+	"htmx-go-app/models"
+)
+
+// ErrInvalidCorrection is returned for a correction that can't be applied
+var ErrInvalidCorrection = errors.New("invalid correction")
+
+// Correction is an admin edit to a live game, for repairing one a bug has
+// left in a bad state. Fields left nil are not changed.
+type Correction struct {
+	ClearCell *engine.Cell // emptied; a finished game reopens if the board no longer ends it
+	Turn      *int         // seat to move, 0 or 1
+	Winner    *string      // player ID that wins the game, or "" for a draw
+}
+
+// CorrectGame applies the correction to a game that has both players. The
+// cell is cleared first and the winner set last, so a correction can both
+// clear a cell and settle the result. Nothing changes if any part is invalid.
+func CorrectGame(game *models.Game, correction Correction) error {
+	unlock := Lock(game)
+	defer unlock()
+	if len(game.PlayerOrder) < models.MaxPlayersPerGame {
+		return fmt.Errorf("%w: the game has no opponent yet", ErrInvalidCorrection)
+	}
+	if correction.ClearCell == nil && correction.Turn == nil && correction.Winner == nil {
+		return fmt.Errorf("%w: nothing to change", ErrInvalidCorrection)
+	}
+	if cell := correction.ClearCell; cell != nil {
+		if !cell.Valid() {
+			return fmt.Errorf("%w: %w", ErrInvalidCorrection, ErrInvalidCell)
+		}
+		if game.Board.At(*cell) == "" {
+			return fmt.Errorf("%w: cell is already empty", ErrInvalidCorrection)
+		}
+	}
+	if turn := correction.Turn; turn != nil && (*turn < 0 || *turn >= models.MaxPlayersPerGame) {
+		return fmt.Errorf("%w: turn must be a seat, 0 or 1", ErrInvalidCorrection)
+	}
+	if winner := correction.Winner; winner != nil && *winner != "" && game.Players[*winner] == nil {
+		return fmt.Errorf("%w: winner must be a player in the game", ErrInvalidCorrection)
+	}
+
+	if cell := correction.ClearCell; cell != nil {
+		game.Board[cell.Row][cell.Col] = ""
+		game.MoveCount--
+		if i := slices.IndexFunc(game.Moves, func(move models.Move) bool { return move.Cell == *cell }); i >= 0 {
+			game.Moves = slices.Delete(game.Moves, i, i+1)
+			for j := i; j < len(game.Moves); j++ {
+				game.Moves[j].Number = j + 1
+			}
+		}
+		if IsGameFinished(game) && CheckWinner(game) == "" && !IsBoardFull(game) {
+			game.Status = models.GameStatusActive
+			game.Winner = ""
+			game.FinishedAt = time.Time{}
+		}
+	}
+	if turn := correction.Turn; turn != nil {
+		game.CurrentTurn = *turn
+	}
+	if winner := correction.Winner; winner != nil {
+		game.Status = models.GameStatusFinished
+		if *winner == "" {
+			game.Status = models.GameStatusDraw
+		}
+		game.Winner = *winner
+		game.FinishedAt = clock.Now()
+	}
+	game.Nudged = false
+	game.Version++
+	return nil
+}
+
+// Describe summarizes the correction for the audit log, e.g.
+// "cleared 1/2, turn 0"
+func (c Correction) Describe() string {
+	var parts []string
+	if c.ClearCell != nil {
+		parts = append(parts, fmt.Sprintf("cleared %d/%d", c.ClearCell.Row, c.ClearCell.Col))
+	}
+	if c.Turn != nil {
+		parts = append(parts, fmt.Sprintf("turn %d", *c.Turn))
+	}
+	switch {
+	case c.Winner == nil:
+	case *c.Winner == "":
+		parts = append(parts, "draw")
+	default:
+		parts = append(parts, "winner "+*c.Winner)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"time"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/engine"
+	"htmx-go-app/models"
+	"htmx-go-app/setting"
+)
+
+// MoveDebounceWindow is how long after a move a repeat of it, such as the
+// second request of a double-click, is collapsed into the first. Zero
+// disables debouncing.
+var MoveDebounceWindow = setting.New(500 * time.Millisecond)
+
+// lastMove is the most recent move made through MakeMoveOnce, kept with the
+// game's lock. Only a repeat of the move that took the game to version can
+// be a duplicate, so one per game is enough and it never needs pruning.
+type lastMove struct {
+	version  int
+	playerID string
+	cell     engine.Cell
+	at       time.Time
+}
+
+// MakeMoveOnce makes the move like MakeMove, except that a repeat of the
+// game's last move by the same player within MoveDebounceWindow is reported
+// as a duplicate and leaves the game alone. Callers answer duplicates with
+// the game as it stands, the result of the original move. The game's lock
+// is held throughout, so two requests racing for the same turn can't both
+// be applied.
+func MakeMoveOnce(game *models.Game, playerID string, row, col int) (duplicate bool, err error) {
+	lock := games.lock(game.ID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	now := clock.Now()
+	cell := engine.Cell{Row: row, Col: col}
+	if last := lock.lastMove; last.version == game.Version && last.playerID == playerID && last.cell == cell && now.Sub(last.at) < MoveDebounceWindow.Get() {
+		return true, nil
+	}
+
+	if err := MakeMove(game, playerID, row, col); err != nil {
+		return false, err
+	}
+	lock.lastMove = lastMove{version: game.Version, playerID: playerID, cell: cell, at: now}
+	return false, nil
+}
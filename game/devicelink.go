@@ -0,0 +1,65 @@
+package game
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/models"
+)
+
+// DeviceLinkTTL is how long a link to continue a game on another device
+// stays valid. It hands over the player's identity, so it is kept short.
+const DeviceLinkTTL = 5 * time.Minute
+
+// Errors returned when following a device link
+var (
+	ErrDeviceLinkNotFound = errors.New("device link not found")
+	ErrDeviceLinkExpired  = errors.New("device link has expired")
+)
+
+var (
+	deviceLinksMu sync.Mutex
+	deviceLinks   = make(map[string]*models.DeviceLink) // token -> link
+)
+
+// CreateDeviceLink issues a single-use link that signs another browser in
+// as playerID and opens the game there
+func CreateDeviceLink(game *models.Game, playerID string) *models.DeviceLink {
+	deviceLinksMu.Lock()
+	defer deviceLinksMu.Unlock()
+
+	now := clock.Now()
+	for token, link := range deviceLinks {
+		if now.After(link.ExpiresAt) {
+			delete(deviceLinks, token)
+		}
+	}
+
+	link := &models.DeviceLink{
+		Token:     generateInviteToken(),
+		GameID:    game.ID,
+		PlayerID:  playerID,
+		ExpiresAt: now.Add(DeviceLinkTTL),
+	}
+	deviceLinks[link.Token] = link
+	return link
+}
+
+// RedeemDeviceLink uses up the link and returns it. Unlike an invite it
+// can't be followed twice, as each use signs a browser in.
+func RedeemDeviceLink(token string) (*models.DeviceLink, error) {
+	deviceLinksMu.Lock()
+	defer deviceLinksMu.Unlock()
+
+	link, exists := deviceLinks[token]
+	if !exists {
+		return nil, ErrDeviceLinkNotFound
+	}
+	delete(deviceLinks, token)
+	if clock.Now().After(link.ExpiresAt) {
+		return nil, ErrDeviceLinkExpired
+	}
+	return link, nil
+}
@@ -0,0 +1,80 @@
+package game
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/models"
+	"htmx-go-app/rng"
+	"htmx-go-app/setting"
+)
+
+// DefaultInviteTTL is how long an invite link stays valid
+const DefaultInviteTTL = 30 * time.Minute
+
+// InviteTTL is the lifetime given to newly created invites
+var InviteTTL = setting.New(DefaultInviteTTL)
+
+// Errors returned when redeeming an invite
+var (
+	ErrInviteNotFound = errors.New("invite not found")
+	ErrInviteExpired  = errors.New("invite has expired")
+	ErrInviteUsed     = errors.New("invite has already been used")
+)
+
+// Issued invites (token -> Invite)
+var invites = make(map[string]*models.Invite)
+
+// generateInviteToken creates an unguessable URL-safe token
+func generateInviteToken() string {
+	return base64.RawURLEncoding.EncodeToString(rng.SecretBytes(16))
+}
+
+// CreateInvite issues a new single-use invite for the game that expires after ttl
+func CreateInvite(game *models.Game, createdBy string, ttl time.Duration) *models.Invite {
+	removeExpiredInvites()
+
+	invite := &models.Invite{
+		Token:     generateInviteToken(),
+		GameID:    game.ID,
+		CreatedBy: createdBy,
+		ExpiresAt: clock.Now().Add(ttl),
+	}
+	invites[invite.Token] = invite
+	return invite
+}
+
+// RedeemInvite claims the invite for playerID and returns its game. The
+// player who claimed it may follow the link again; anyone else is refused.
+func RedeemInvite(token, playerID string) (*models.Game, error) {
+	invite, exists := invites[token]
+	if !exists {
+		return nil, ErrInviteNotFound
+	}
+	if invite.UsedBy != "" && invite.UsedBy != playerID {
+		return nil, ErrInviteUsed
+	}
+	if invite.UsedBy == "" && clock.Now().After(invite.ExpiresAt) {
+		return nil, ErrInviteExpired
+	}
+
+	game := GetGame(invite.GameID)
+	if game == nil {
+		return nil, ErrInviteNotFound
+	}
+
+	invite.UsedBy = playerID
+	return game, nil
+}
+
+// removeExpiredInvites drops invites that can no longer be redeemed
+func removeExpiredInvites() {
+	now := clock.Now()
+	for token, invite := range invites {
+		if invite.UsedBy == "" && now.After(invite.ExpiresAt) {
+			delete(invites, token)
+		}
+	}
+}
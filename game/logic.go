@@ -1,66 +1,24 @@
 package game
 
-import "htmx-go-app/models"
+import (
+	"htmx-go-app/emojis"
+	"htmx-go-app/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 // CheckWinner returns the playerID of the winner, or empty string if no winner
 func CheckWinner(game *models.Game) string {
-	board := game.Board
-
-	// Check rows
-	for row := 0; row < 3; row++ {
-		if board[row][0] != "" && board[row][0] == board[row][1] && board[row][1] == board[row][2] {
-			// Find playerID by emoji
-			for pID, player := range game.Players {
-				if player.Emoji == board[row][0] {
-					return pID
-				}
-			}
-		}
-	}
-
-	// Check columns
-	for col := 0; col < 3; col++ {
-		if board[0][col] != "" && board[0][col] == board[1][col] && board[1][col] == board[2][col] {
-			// Find playerID by emoji
-			for pID, player := range game.Players {
-				if player.Emoji == board[0][col] {
-					return pID
-				}
-			}
-		}
-	}
-
-	// Check main diagonal (top-left to bottom-right)
-	if board[0][0] != "" && board[0][0] == board[1][1] && board[1][1] == board[2][2] {
-		for pID, player := range game.Players {
-			if player.Emoji == board[0][0] {
-				return pID
-			}
-		}
-	}
-
-	// Check anti-diagonal (top-right to bottom-left)
-	if board[0][2] != "" && board[0][2] == board[1][1] && board[1][1] == board[2][0] {
-		for pID, player := range game.Players {
-			if player.Emoji == board[0][2] {
-				return pID
-			}
-		}
+	mark, _ := game.Board.Line()
+	if mark == "" {
+		return ""
 	}
-
-	return "" // No winner
+	return PlayerAt(game, mark)
 }
 
 // IsBoardFull checks if all cells on the board are filled
 func IsBoardFull(game *models.Game) bool {
-	for row := 0; row < 3; row++ {
-		for col := 0; col < 3; col++ {
-			if game.Board[row][col] == "" {
-				return false
-			}
-		}
-	}
-	return true
+	return game.Board.Full()
 }
 
 // IsGameActive returns true if the game is currently active
@@ -75,7 +33,8 @@ func IsGameFinished(game *models.Game) bool {
 
 // IsGameReady returns true if the game is ready to be played
 func IsGameReady(game *models.Game) bool {
-	return game.Status == models.GameStatusActive || game.Status == models.GameStatusFinished || game.Status == models.GameStatusDraw
+	return game.Status == models.GameStatusActive || game.Status == models.GameStatusFinished || game.Status == models.GameStatusDraw ||
+		game.Status == models.GameStatusPaused
 }
 
 // CanJoinGame returns true if the game can accept more players
@@ -96,17 +55,56 @@ func IsPlayersTurn(game *models.Game, playerID string) bool {
 	return IsGameActive(game) && GetCurrentPlayerID(game) == playerID
 }
 
-// IsEmojiAvailable returns true if the emoji is not already taken by another player
+// IsEmojiAvailable returns true if no other player has the emoji, or a
+// symbol that looks the same
 func IsEmojiAvailable(game *models.Game, emoji string) bool {
 	for _, player := range game.Players {
-		if player.Emoji == emoji {
+		if emojis.Same(player.Emoji, emoji) {
 			return false
 		}
 	}
 	return true
 }
 
+// IsOpenForLobby returns true if the game is public and has a creator waiting for an opponent
+func IsOpenForLobby(game *models.Game) bool {
+	return game.Visibility == models.VisibilityPublic && game.Status == models.GameStatusWaiting && len(game.Players) == 1
+}
+
+// HasPassword returns true if joining the game requires a password
+func HasPassword(game *models.Game) bool {
+	return len(game.PasswordHash) > 0
+}
+
+// RequiresPassword returns true if the player must enter the password before joining.
+// The creator and players already in the game are never asked.
+func RequiresPassword(game *models.Game, playerID string) bool {
+	if !HasPassword(game) || playerID == game.CreatorID {
+		return false
+	}
+	_, alreadyJoined := game.Players[playerID]
+	return !alreadyJoined
+}
+
+// CheckPassword returns true if password matches the game's join password
+func CheckPassword(game *models.Game, password string) bool {
+	return bcrypt.CompareHashAndPassword(game.PasswordHash, []byte(password)) == nil
+}
+
 // IsFirstPlayer returns true if the given player is the first (and only) player in the game
 func IsFirstPlayer(game *models.Game, playerID string) bool {
 	return len(game.Players) == 1 && game.Players[playerID] != nil
-}
\ No newline at end of file
+}
+
+// WinningLine returns the three cells (row, col) of the completed line, or nil if there is none
+func WinningLine(board models.GameBoard) [][2]int {
+	_, line := board.Line()
+	if line == nil {
+		return nil
+	}
+	cells := make([][2]int, 0, len(line))
+	for _, cell := range line {
+		cells = append(cells, [2]int{cell.Row, cell.Col})
+	}
+	return cells
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	"sync"
+
+	"htmx-go-app/models"
+)
+
+// Quick match queue of playerIDs waiting for an opponent, oldest first
+var (
+	matchQueue    []string
+	matchQueueMux sync.Mutex
+)
+
+// JoinMatchQueue pairs the player with the longest-waiting opponent. When
+// one is available a new private game is created for both and returned
+// along with the opponent's ID; otherwise the player is queued and nil is returned.
+func JoinMatchQueue(playerID string) (*models.Game, string) {
+	matchQueueMux.Lock()
+	defer matchQueueMux.Unlock()
+
+	for _, queuedID := range matchQueue {
+		if queuedID == playerID {
+			return nil, "" // already waiting
+		}
+	}
+
+	if len(matchQueue) == 0 {
+		matchQueue = append(matchQueue, playerID)
+		return nil, ""
+	}
+
+	opponentID := matchQueue[0]
+	matchQueue = matchQueue[1:]
+
+	// The opponent waited longer, so they count as the creator
+	matchedGame, err := CreateGame(opponentID, models.GameOptions{Visibility: models.VisibilityPrivate})
+	if err != nil {
+		return nil, ""
+	}
+	return matchedGame, opponentID
+}
+
+// LeaveMatchQueue removes the player from the queue, e.g. when they close the page
+func LeaveMatchQueue(playerID string) {
+	matchQueueMux.Lock()
+	defer matchQueueMux.Unlock()
+
+	for i, queuedID := range matchQueue {
+		if queuedID == playerID {
+			matchQueue = append(matchQueue[:i], matchQueue[i+1:]...)
+			return
+		}
+	}
+}
+
+// MatchQueueLength returns how many players are currently waiting
+func MatchQueueLength() int {
+	matchQueueMux.Lock()
+	defer matchQueueMux.Unlock()
+	return len(matchQueue)
+}
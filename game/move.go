@@ -0,0 +1,66 @@
+package game
+
+import (
+	"errors"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/engine"
+	"htmx-go-app/models"
+)
+
+// Errors returned when a move is rejected
+var (
+	ErrNotAPlayer    = errors.New("player is not in this game")
+	ErrInvalidCell   = engine.ErrInvalidCell
+	ErrGameNotActive = errors.New("game is not active")
+	ErrNotYourTurn   = errors.New("not your turn")
+	ErrCellOccupied  = engine.ErrCellOccupied
+)
+
+// MakeMove places the player's mark at (row, col) and then either finishes
+// the game (win or draw) or passes the turn to the opponent
+func MakeMove(game *models.Game, playerID string, row, col int) error {
+	player, exists := game.Players[playerID]
+	mark := Mark(game, playerID)
+	if !exists || player.Emoji == "" || mark == "" {
+		return ErrNotAPlayer
+	}
+	cell := engine.Cell{Row: row, Col: col}
+	if !cell.Valid() {
+		return ErrInvalidCell
+	}
+	if !IsGameActive(game) {
+		return ErrGameNotActive
+	}
+	if !IsPlayersTurn(game, playerID) {
+		return ErrNotYourTurn
+	}
+	board, err := game.Board.Place(cell, mark)
+	if err != nil {
+		return err
+	}
+
+	game.Board = board
+	game.Moves = append(game.Moves, models.Move{
+		Cell:     cell,
+		PlayerID: playerID,
+		Number:   len(game.Moves) + 1,
+		At:       clock.Now(),
+	})
+	game.MoveCount++
+	game.Version++
+	game.Nudged = false
+
+	if winnerID := CheckWinner(game); winnerID != "" {
+		game.Status = models.GameStatusFinished
+		game.Winner = winnerID
+		game.FinishedAt = clock.Now()
+	} else if IsBoardFull(game) {
+		game.Status = models.GameStatusDraw
+		game.FinishedAt = clock.Now()
+	} else {
+		game.CurrentTurn = (game.CurrentTurn + 1) % 2
+	}
+
+	return nil
+}
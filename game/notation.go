@@ -0,0 +1,23 @@
+package game
+
+import (
+	"slices"
+
+	"htmx-go-app/engine"
+	"htmx-go-app/models"
+)
+
+// Notation writes the game's moves in engine notation, X being the player
+// who joined first, e.g. "1. X b2 2. O a1". Moves set up by a scenario's
+// board rather than played are not included.
+func Notation(game *models.Game) string {
+	moves := make([]engine.Move, 0, len(game.Moves))
+	for _, move := range game.Moves {
+		seat := slices.Index(game.PlayerOrder, move.PlayerID)
+		if seat < 0 {
+			continue
+		}
+		moves = append(moves, engine.Move{Seat: seat, Cell: move.Cell})
+	}
+	return engine.FormatMoves(moves)
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"errors"
+
+	"htmx-go-app/models"
+)
+
+// Errors returned when pausing a game by agreement
+var (
+	ErrPauseAlreadyAsked = errors.New("you already asked to pause this game")
+	ErrNoPauseAsked      = errors.New("nobody asked to pause this game")
+)
+
+// RequestPause asks to pause the game. Once the opponent asks too, which is
+// how they agree, the game is paused and paused is true.
+func RequestPause(game *models.Game, playerID string) (paused bool, err error) {
+	unlock := Lock(game)
+	defer unlock()
+
+	if player, exists := game.Players[playerID]; !exists || player.Emoji == "" {
+		return false, ErrNotAPlayer
+	}
+	if !IsGameActive(game) {
+		return false, ErrGameNotActive
+	}
+
+	switch game.PauseAsker {
+	case playerID:
+		return false, ErrPauseAlreadyAsked
+	case "":
+		game.PauseAsker = playerID
+		return false, nil
+	}
+
+	game.Status = models.GameStatusPaused
+	game.PauseAsker = ""
+	game.AbandonedBy = ""
+	game.Nudged = false
+	game.Version++
+	return true, nil
+}
+
+// DeclinePause turns down the opponent's request to pause, or withdraws the
+// player's own
+func DeclinePause(game *models.Game, playerID string) error {
+	unlock := Lock(game)
+	defer unlock()
+
+	if player, exists := game.Players[playerID]; !exists || player.Emoji == "" {
+		return ErrNotAPlayer
+	}
+	if game.PauseAsker == "" {
+		return ErrNoPauseAsked
+	}
+	game.PauseAsker = ""
+	return nil
+}
+
+// PlayerGames returns copies of the games playerID has a seat in, newest first
+func PlayerGames(playerID string) []*models.Game {
+	return Snapshot(func(game *models.Game) bool {
+		player, exists := game.Players[playerID]
+		return exists && player.Emoji != ""
+	})
+}
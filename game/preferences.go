@@ -0,0 +1,97 @@
+package game
+
+import (
+	"sort"
+	"sync"
+)
+
+var (
+	preferencesMu sync.RWMutex
+	mutedCues     = make(map[string]bool)             // players who turned sound and vibration cues off
+	pieceBadges   = make(map[string]bool)             // players who want seat badges next to the emojis
+	locales       = make(map[string]LocalePreference) // playerID -> how their browser writes dates
+)
+
+// LocalePreference is the time zone and language a player's browser reported,
+// used to write dates and times the way the player reads them
+type LocalePreference struct {
+	TimeZone string // IANA name, e.g. "Europe/Zurich"; "" if unknown
+	Language string // BCP 47 tag, e.g. "de-CH"; "" if unknown
+}
+
+// SetCuesMuted records whether the player wants sound and vibration cues off
+func SetCuesMuted(playerID string, muted bool) {
+	preferencesMu.Lock()
+	defer preferencesMu.Unlock()
+	if muted {
+		mutedCues[playerID] = true
+	} else {
+		delete(mutedCues, playerID)
+	}
+}
+
+// CuesMuted reports whether the player turned cues off
+func CuesMuted(playerID string) bool {
+	preferencesMu.RLock()
+	defer preferencesMu.RUnlock()
+	return mutedCues[playerID]
+}
+
+// cuesMutedPlayers returns the players who turned cues off, sorted
+func cuesMutedPlayers() []string {
+	preferencesMu.RLock()
+	defer preferencesMu.RUnlock()
+	return sortedPlayers(mutedCues)
+}
+
+// SetPieceBadges records whether the player wants each piece marked with
+// its seat's letter, so pieces can be told apart without relying on color
+func SetPieceBadges(playerID string, on bool) {
+	preferencesMu.Lock()
+	defer preferencesMu.Unlock()
+	if on {
+		pieceBadges[playerID] = true
+	} else {
+		delete(pieceBadges, playerID)
+	}
+}
+
+// PieceBadges reports whether the player turned piece badges on
+func PieceBadges(playerID string) bool {
+	preferencesMu.RLock()
+	defer preferencesMu.RUnlock()
+	return pieceBadges[playerID]
+}
+
+// pieceBadgesPlayers returns the players who turned piece badges on, sorted
+func pieceBadgesPlayers() []string {
+	preferencesMu.RLock()
+	defer preferencesMu.RUnlock()
+	return sortedPlayers(pieceBadges)
+}
+
+// sortedPlayers returns the players set in a preference map, sorted. The
+// caller holds preferencesMu.
+func sortedPlayers(set map[string]bool) []string {
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// SetLocalePreference records the player's time zone and language
+func SetLocalePreference(playerID string, preference LocalePreference) {
+	preferencesMu.Lock()
+	defer preferencesMu.Unlock()
+	locales[playerID] = preference
+}
+
+// LocalePreferenceOf returns the player's time zone and language, empty if
+// their browser never reported them
+func LocalePreferenceOf(playerID string) LocalePreference {
+	preferencesMu.RLock()
+	defer preferencesMu.RUnlock()
+	return locales[playerID]
+}
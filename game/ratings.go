@@ -0,0 +1,77 @@
+package game
+
+import (
+	"math"
+	"sort"
+
+	"htmx-go-app/models"
+)
+
+// Elo rating parameters. Everyone starts at InitialRating; each game moves
+// the two ratings by at most ratingK points.
+const (
+	InitialRating = 1000
+	ratingK       = 32
+)
+
+// PlayerRecord is how a player has been doing across their finished games
+type PlayerRecord struct {
+	Rating int // Elo rating, InitialRating before the first finished game
+	Streak int // games won in a row, up to the player's latest result
+	Games  int // finished games played
+}
+
+// PlayerRecords returns the records of the given players. Like the stats,
+// they are derived from the finished games, replayed in the order they ended.
+func PlayerRecords(playerIDs ...string) map[string]PlayerRecord {
+	finished := Snapshot(func(game *models.Game) bool {
+		return IsGameFinished(game) && len(game.PlayerOrder) == 2
+	})
+	sort.SliceStable(finished, func(i, j int) bool {
+		return finished[i].FinishedAt.Before(finished[j].FinishedAt)
+	})
+
+	ratings := make(map[string]float64)
+	records := make(map[string]PlayerRecord)
+	rating := func(playerID string) float64 {
+		if r, ok := ratings[playerID]; ok {
+			return r
+		}
+		return InitialRating
+	}
+
+	for _, game := range finished {
+		a, b := game.PlayerOrder[0], game.PlayerOrder[1]
+		scoreA := 0.5
+		switch game.Winner {
+		case a:
+			scoreA = 1
+		case b:
+			scoreA = 0
+		}
+
+		expectedA := 1 / (1 + math.Pow(10, (rating(b)-rating(a))/400))
+		change := ratingK * (scoreA - expectedA)
+		ratings[a] = rating(a) + change
+		ratings[b] = rating(b) - change
+
+		for _, playerID := range game.PlayerOrder {
+			record := records[playerID]
+			record.Games++
+			if game.Winner == playerID {
+				record.Streak++
+			} else {
+				record.Streak = 0
+			}
+			records[playerID] = record
+		}
+	}
+
+	result := make(map[string]PlayerRecord, len(playerIDs))
+	for _, playerID := range playerIDs {
+		record := records[playerID]
+		record.Rating = int(math.Round(rating(playerID)))
+		result[playerID] = record
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+package game
+
+import (
+	"errors"
+	"time"
+
+	"htmx-go-app/models"
+	"htmx-go-app/setting"
+)
+
+// TurnReminderDelay is how long the active player may stay idle before a
+// turn reminder is sent. Zero disables automatic reminders.
+var TurnReminderDelay = setting.New(30 * time.Second)
+
+// Errors returned when a nudge is rejected
+var (
+	ErrNudgeOwnTurn  = errors.New("it's your turn, not your opponent's")
+	ErrAlreadyNudged = errors.New("you already nudged your opponent this turn")
+)
+
+// Nudge records the waiting player's one manual reminder for the current turn
+func Nudge(game *models.Game, playerID string) error {
+	unlock := Lock(game)
+	defer unlock()
+	player, exists := game.Players[playerID]
+	if !exists || player.Emoji == "" {
+		return ErrNotAPlayer
+	}
+	if !IsGameActive(game) {
+		return ErrGameNotActive
+	}
+	if IsPlayersTurn(game, playerID) {
+		return ErrNudgeOwnTurn
+	}
+	if game.Nudged {
+		return ErrAlreadyNudged
+	}
+
+	game.Nudged = true
+	return nil
+}
@@ -0,0 +1,145 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/engine"
+	"htmx-go-app/models"
+)
+
+// ErrInvalidScenario is returned for a scenario that can't be set up
+var ErrInvalidScenario = errors.New("invalid scenario")
+
+// Scenario describes a game to set up in a given state, for demos and tests
+type Scenario struct {
+	Players    []ScenarioPlayer // in seat order, so the first one moves first on an empty board
+	Board      models.GameBoard // marks must be the players' emojis
+	Moves      string           // moves in engine notation to replay instead of setting Board
+	Turn       *int             // seat to move; nil works it out from the marks
+	Visibility models.GameVisibility
+}
+
+// ScenarioPlayer is one seat of a scenario
+type ScenarioPlayer struct {
+	Emoji string
+	Name  string
+}
+
+// CreateScenario stores a new game in the state the scenario describes. With
+// one player the board must be empty and the game waits for an opponent;
+// with two it is active, or won or drawn if the board says so. Moves, if
+// given, are replayed one by one under the usual rules instead. The game is
+// only stored once it is set up.
+func CreateScenario(scenario Scenario) (*models.Game, error) {
+	if len(scenario.Players) < 1 || len(scenario.Players) > models.MaxPlayersPerGame {
+		return nil, fmt.Errorf("%w: needs one or two players", ErrInvalidScenario)
+	}
+	if scenario.Visibility == "" {
+		scenario.Visibility = models.VisibilityPublic
+	}
+
+	seats := map[string]int{}
+	for seat, player := range scenario.Players {
+		if _, taken := seats[player.Emoji]; taken {
+			return nil, fmt.Errorf("%w: players need different emojis", ErrInvalidScenario)
+		}
+		seats[player.Emoji] = seat
+	}
+	marks := [models.MaxPlayersPerGame]int{}
+	var board models.GameBoard
+	for r, row := range scenario.Board {
+		for c, mark := range row {
+			if mark == "" {
+				continue
+			}
+			seat, ok := seats[mark]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q on the board is not a player's emoji", ErrInvalidScenario, mark)
+			}
+			marks[seat]++
+			board[r][c] = engine.SeatLetter(seat)
+		}
+	}
+	moveCount := marks[0] + marks[1]
+	if len(scenario.Players) == 1 && (moveCount > 0 || scenario.Moves != "") {
+		return nil, fmt.Errorf("%w: a game waiting for an opponent has an empty board", ErrInvalidScenario)
+	}
+	moves, err := engine.ParseMoves(scenario.Moves)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidScenario, err)
+	}
+	if len(moves) > 0 && (moveCount > 0 || scenario.Turn != nil) {
+		return nil, fmt.Errorf("%w: give either moves or a board and turn", ErrInvalidScenario)
+	}
+
+	turn := 0
+	if marks[0] > marks[1] {
+		turn = 1
+	}
+	if scenario.Turn != nil {
+		if *scenario.Turn < 0 || *scenario.Turn >= len(scenario.Players) {
+			return nil, fmt.Errorf("%w: turn must be a seat, 0 or 1", ErrInvalidScenario)
+		}
+		turn = *scenario.Turn
+	}
+
+	creatorID := GeneratePlayerID()
+	game, err := newGame(creatorID, models.GameOptions{Visibility: scenario.Visibility})
+	if err != nil {
+		return nil, err
+	}
+	for seat, player := range scenario.Players {
+		playerID := creatorID
+		if seat > 0 {
+			playerID = GeneratePlayerID()
+		}
+		if err := AddPlayerToGame(game, playerID, player.Emoji, player.Name); err != nil {
+			return nil, err
+		}
+	}
+	if len(moves) > 0 {
+		if err := replayMoves(game, moves); err != nil {
+			return nil, err
+		}
+	} else if len(scenario.Players) > 1 {
+		setBoard(game, board, moveCount, turn)
+	}
+
+	storeGame(game)
+	for _, playerID := range game.PlayerOrder {
+		allowSessionStart(playerID)
+	}
+	return game, nil
+}
+
+// setBoard puts the scenario's marks on the board of a new active game and
+// finishes it if they decide it
+func setBoard(game *models.Game, board models.GameBoard, moveCount, turn int) {
+	game.Board = board
+	game.MoveCount = moveCount
+	game.CurrentTurn = turn
+	game.Version++
+
+	status, winner := engine.Outcome(game.Board, [2]string{engine.SeatLetter(0), engine.SeatLetter(1)}, engine.Standard)
+	switch status {
+	case engine.Won:
+		game.Status = models.GameStatusFinished
+		game.Winner = game.PlayerOrder[winner]
+		game.FinishedAt = clock.Now()
+	case engine.Drawn:
+		game.Status = models.GameStatusDraw
+		game.FinishedAt = clock.Now()
+	}
+}
+
+// replayMoves plays moves on the scenario's empty board
+func replayMoves(game *models.Game, moves []engine.Move) error {
+	for i, move := range moves {
+		if err := MakeMove(game, game.PlayerOrder[move.Seat], move.Cell.Row, move.Cell.Col); err != nil {
+			return fmt.Errorf("%w: move %d: %v", ErrInvalidScenario, i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package game
+
+import (
+	"crypto/sha256"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/models"
+	"htmx-go-app/rng"
+	"htmx-go-app/setting"
+)
+
+// SessionTTL is how long a session lasts without being used, matching the
+// lifetime of the player cookie
+var SessionTTL = setting.New(24 * time.Hour)
+
+// Errors returned for sessions
+var (
+	ErrSessionRevoked  = errors.New("session has been signed out")
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// playerSessions are the browsers signed in as one player. Once a player
+// has had a session, a browser presenting the player's ID without one of
+// them is refused, so the entry outlives the last session being revoked,
+// and is saved with the snapshot so it outlives a restart too.
+type playerSessions struct {
+	sessions  map[string]*models.Session // session ID -> session
+	revokedAt time.Time
+}
+
+var (
+	sessionsMu       sync.Mutex
+	sessionsByPlayer = make(map[string]*playerSessions) // player ID -> sessions
+	sessionsByToken  = make(map[[32]byte]*models.Session)
+	lastSessionSweep time.Time
+
+	// sessionless are players seated without a browser, such as by an admin
+	// scenario. The first browser to present one of their IDs is signed in
+	// as them.
+	sessionless = make(map[string]bool)
+)
+
+// StartSession signs a browser in as playerID. The returned session is the
+// only one that carries its token.
+func StartSession(playerID, userAgent, ip string) *models.Session {
+	removeIdleSessions()
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	now := clock.Now()
+	token := rng.Secret(16)
+	session := &models.Session{
+		ID:         rng.Hex(6),
+		TokenHash:  sha256.Sum256([]byte(token)),
+		PlayerID:   playerID,
+		UserAgent:  userAgent,
+		IP:         ip,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	player := sessionsByPlayer[playerID]
+	if player == nil {
+		player = &playerSessions{sessions: make(map[string]*models.Session)}
+		sessionsByPlayer[playerID] = player
+	}
+	player.sessions[session.ID] = session
+	sessionsByToken[session.TokenHash] = session
+
+	started := *session
+	started.Token = token
+	return &started
+}
+
+// ResumeSession finds the session a browser's cookies belong to and marks
+// it as just used. A player seated without a browser is given a session the
+// first time their ID is presented, reported by started. Any other player
+// ID presented without a live token of its own gets ErrSessionRevoked.
+func ResumeSession(playerID, token, userAgent, ip string) (session *models.Session, started bool, err error) {
+	sessionsMu.Lock()
+	session = sessionsByToken[sha256.Sum256([]byte(token))]
+	if session != nil && session.PlayerID == playerID {
+		session.LastSeenAt = clock.Now()
+		session.IP = ip
+		sessionsMu.Unlock()
+		return session, false, nil
+	}
+	claimable := sessionless[playerID]
+	delete(sessionless, playerID)
+	sessionsMu.Unlock()
+
+	if !claimable {
+		return nil, false, ErrSessionRevoked
+	}
+	return StartSession(playerID, userAgent, ip), true, nil
+}
+
+// allowSessionStart lets the first browser that presents playerID be signed
+// in as that player, who was seated without one
+func allowSessionStart(playerID string) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	sessionless[playerID] = true
+}
+
+// PlayerSessions lists copies of the player's sessions, most recently used
+// first
+func PlayerSessions(playerID string) []models.Session {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	var result []models.Session
+	if player := sessionsByPlayer[playerID]; player != nil {
+		for _, session := range player.sessions {
+			result = append(result, *session)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastSeenAt.After(result[j].LastSeenAt)
+	})
+	return result
+}
+
+// RevokeSession signs one of the player's browsers out. Its next request
+// is treated as coming from a new visitor.
+func RevokeSession(playerID, sessionID string) error {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	player := sessionsByPlayer[playerID]
+	if player == nil || player.sessions[sessionID] == nil {
+		return ErrSessionNotFound
+	}
+	delete(sessionsByToken, player.sessions[sessionID].TokenHash)
+	delete(player.sessions, sessionID)
+	player.revokedAt = clock.Now()
+	return nil
+}
+
+// removeIdleSessions drops sessions unused for longer than SessionTTL, and
+// players left without any once their last revocation is as old and they
+// no longer have a seat in any game, as until then their ID is worth
+// stealing. It runs at most once a minute.
+func removeIdleSessions() {
+	now := clock.Now()
+	sessionsMu.Lock()
+	since := now.Sub(lastSessionSweep)
+	due := since >= time.Minute || since < 0 // or the clock was set back
+	if due {
+		lastSessionSweep = now
+	}
+	sessionsMu.Unlock()
+	if !due {
+		return
+	}
+
+	// Gathered before taking sessionsMu, as the store has locks of its own
+	seated := make(map[string]bool)
+	Snapshot(func(game *models.Game) bool {
+		for _, playerID := range game.PlayerOrder {
+			seated[playerID] = true
+		}
+		return false
+	})
+
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	ttl := SessionTTL.Get()
+	for playerID, player := range sessionsByPlayer {
+		for id, session := range player.sessions {
+			if now.Sub(session.LastSeenAt) > ttl {
+				delete(sessionsByToken, session.TokenHash)
+				delete(player.sessions, id)
+			}
+		}
+		if len(player.sessions) == 0 && now.Sub(player.revokedAt) > ttl && !seated[playerID] {
+			delete(sessionsByPlayer, playerID)
+		}
+	}
+	for playerID := range sessionless {
+		if !seated[playerID] {
+			delete(sessionless, playerID)
+		}
+	}
+}
+
+// savedSessions is one player's session state as kept in snapshots, tokens
+// only as hashes
+type savedSessions struct {
+	PlayerID    string           `json:"playerId"`
+	RevokedAt   time.Time        `json:"revokedAt,omitzero"`
+	Sessions    []models.Session `json:"sessions"`
+	Sessionless bool             `json:"sessionless,omitempty"` // seated without a browser, and none has claimed the seat yet
+}
+
+// saveSessions copies every player's session state for a snapshot
+func saveSessions() []savedSessions {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	saved := make([]savedSessions, 0, len(sessionsByPlayer))
+	for playerID, player := range sessionsByPlayer {
+		entry := savedSessions{PlayerID: playerID, RevokedAt: player.revokedAt, Sessions: make([]models.Session, 0, len(player.sessions))}
+		for _, session := range player.sessions {
+			entry.Sessions = append(entry.Sessions, *session)
+		}
+		saved = append(saved, entry)
+	}
+	for playerID := range sessionless {
+		saved = append(saved, savedSessions{PlayerID: playerID, Sessionless: true})
+	}
+	sort.Slice(saved, func(i, j int) bool { return saved[i].PlayerID < saved[j].PlayerID })
+	return saved
+}
+
+// restoreSessions replaces the session state with what a snapshot saved
+func restoreSessions(saved []savedSessions) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	clear(sessionsByPlayer)
+	clear(sessionsByToken)
+	clear(sessionless)
+	for _, entry := range saved {
+		if entry.Sessionless {
+			sessionless[entry.PlayerID] = true
+			continue
+		}
+		player := &playerSessions{sessions: make(map[string]*models.Session, len(entry.Sessions)), revokedAt: entry.RevokedAt}
+		for _, session := range entry.Sessions {
+			session.PlayerID = entry.PlayerID
+			player.sessions[session.ID] = &session
+			sessionsByToken[session.TokenHash] = &session
+		}
+		sessionsByPlayer[entry.PlayerID] = player
+	}
+}
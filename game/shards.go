@@ -0,0 +1,197 @@
+package game
+
+import (
+	"hash/fnv"
+	"slices"
+	"sync"
+
+	"htmx-go-app/models"
+)
+
+// DefaultStoreShards is how many shards the game store starts with. Games
+// are spread over the shards by ID, each behind its own lock, so requests
+// for different games rarely wait on each other. BenchmarkGameStore
+// compares shard counts on the machine at hand.
+const DefaultStoreShards = 32
+
+// gameShard holds the games whose IDs hash to it, each with its own lock
+type gameShard struct {
+	mu    sync.RWMutex
+	games map[string]*models.Game
+	locks map[string]*gameLock
+}
+
+// gameLock is held while a stored game changes and while it is copied, so a
+// copy never sees a change half applied. The shard lock may be taken before
+// a game lock but never while holding one.
+type gameLock struct {
+	sync.Mutex
+	lastMove lastMove
+}
+
+// gameStore is the in-memory game store, split into shards
+type gameStore struct {
+	shards []*gameShard
+}
+
+func newGameStore(shardCount int) *gameStore {
+	store := &gameStore{shards: make([]*gameShard, max(shardCount, 1))}
+	for i := range store.shards {
+		store.shards[i] = &gameShard{games: make(map[string]*models.Game), locks: make(map[string]*gameLock)}
+	}
+	return store
+}
+
+// Global game storage
+var games = newGameStore(DefaultStoreShards)
+
+// ConfigureStore splits the store into shardCount shards, moving any games
+// already stored over. It swaps the store out from under anyone using it,
+// so it is only called at startup, before the server is listening; tests
+// build stores of their own instead.
+func ConfigureStore(shardCount int) {
+	games = games.resharded(shardCount)
+}
+
+// resharded returns a new store of shardCount shards holding the same games
+func (s *gameStore) resharded(shardCount int) *gameStore {
+	store := newGameStore(shardCount)
+	s.each(store.put)
+	return store
+}
+
+func (s *gameStore) shard(id string) *gameShard {
+	hash := fnv.New32a()
+	hash.Write([]byte(id))
+	return s.shards[hash.Sum32()%uint32(len(s.shards))]
+}
+
+func (s *gameStore) get(id string) *models.Game {
+	shard := s.shard(id)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	return shard.games[id]
+}
+
+func (s *gameStore) put(game *models.Game) {
+	shard := s.shard(game.ID)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	shard.games[game.ID] = game
+	if shard.locks[game.ID] == nil {
+		shard.locks[game.ID] = &gameLock{}
+	}
+}
+
+// lock returns the lock of the game with the given ID. A game that isn't
+// stored gets a lock of its own, as nothing else can reach it.
+func (s *gameStore) lock(id string) *gameLock {
+	shard := s.shard(id)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	if lock := shard.locks[id]; lock != nil {
+		return lock
+	}
+	return &gameLock{}
+}
+
+// remove deletes the game with the given ID and returns it, or nil if there
+// was none
+func (s *gameStore) remove(id string) *models.Game {
+	shard := s.shard(id)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	game := shard.games[id]
+	delete(shard.games, id)
+	delete(shard.locks, id)
+	return game
+}
+
+// removeWhen deletes the game with the given ID if match, checked under the
+// game's lock, holds for it, and returns the game it deleted
+func (s *gameStore) removeWhen(id string, match func(*models.Game) bool) *models.Game {
+	shard := s.shard(id)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	game := shard.games[id]
+	if game == nil {
+		return nil
+	}
+	lock := shard.locks[id]
+	lock.Lock()
+	matched := match(game)
+	lock.Unlock()
+	if !matched {
+		return nil
+	}
+	delete(shard.games, id)
+	delete(shard.locks, id)
+	return game
+}
+
+// removeIf deletes the games matching match, checked under each game's
+// lock, and returns them
+func (s *gameStore) removeIf(match func(*models.Game) bool) []*models.Game {
+	var removed []*models.Game
+	for _, shard := range s.shards {
+		shard.mu.Lock()
+		for id, game := range shard.games {
+			lock := shard.locks[id]
+			lock.Lock()
+			matched := match(game)
+			lock.Unlock()
+			if matched {
+				removed = append(removed, game)
+				delete(shard.games, id)
+				delete(shard.locks, id)
+			}
+		}
+		shard.mu.Unlock()
+	}
+	return removed
+}
+
+// each calls fn for every game, one shard at a time. fn must not call back
+// into the store.
+func (s *gameStore) each(fn func(*models.Game)) {
+	for _, shard := range s.shards {
+		shard.mu.RLock()
+		for _, game := range shard.games {
+			fn(game)
+		}
+		shard.mu.RUnlock()
+	}
+}
+
+// copies returns copies of the games in one shard matching filter. Each
+// game is filtered and copied under its own lock.
+func (shard *gameShard) copies(filter func(*models.Game) bool) []*models.Game {
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	var result []*models.Game
+	for id, game := range shard.games {
+		lock := shard.locks[id]
+		lock.Lock()
+		if filter == nil || filter(game) {
+			result = append(result, cloneGame(game))
+		}
+		lock.Unlock()
+	}
+	return result
+}
+
+// cloneGame deep-copies a game, so the copy shares nothing with the stored
+// game that later moves could change
+func cloneGame(game *models.Game) *models.Game {
+	clone := *game
+	clone.Players = make(map[string]*models.Player, len(game.Players))
+	for id, player := range game.Players {
+		copied := *player
+		clone.Players[id] = &copied
+	}
+	clone.PlayerOrder = slices.Clone(game.PlayerOrder)
+	clone.Moves = slices.Clone(game.Moves)
+	clone.PasswordHash = slices.Clone(game.PasswordHash)
+	clone.Chat = slices.Clone(game.Chat)
+	return &clone
+}
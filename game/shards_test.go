@@ -0,0 +1,84 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"htmx-go-app/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkGameStore looks games up from many goroutines at once, with a
+// creation now and then, for a range of shard counts. Run it with -cpu set
+// to the production core count when tuning -store-shards.
+func BenchmarkGameStore(b *testing.B) {
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("bench_%d", i)
+	}
+
+	for _, shards := range []int{1, 8, 32, 128} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			store := newGameStore(shards)
+			for _, id := range ids {
+				store.put(&models.Game{ID: id})
+			}
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					if i%100 == 0 {
+						id := generateGameID()
+						store.put(&models.Game{ID: id})
+						store.remove(id)
+					}
+					store.get(ids[i%len(ids)])
+					i++
+				}
+			})
+		})
+	}
+}
+
+func TestReshardedStore(t *testing.T) {
+	store := newGameStore(DefaultStoreShards)
+	stored := &models.Game{ID: "resharded"}
+	store.put(stored)
+
+	moved := store.resharded(7)
+	assert.Len(t, moved.shards, 7)
+	assert.Same(t, stored, moved.get(stored.ID), "games move over to the new shards")
+	require.NotNil(t, moved.shard(stored.ID).locks[stored.ID], "and get a lock there")
+	assert.Len(t, newGameStore(0).shards, 1, "there is always a shard")
+}
+
+func TestRemoveWhen(t *testing.T) {
+	store := newGameStore(DefaultStoreShards)
+	stored := &models.Game{ID: "remove_when", Status: models.GameStatusActive}
+	store.put(stored)
+
+	assert.Nil(t, store.removeWhen(stored.ID, isWaiting), "a game that started is kept")
+	assert.Same(t, stored, store.get(stored.ID))
+
+	stored.Status = models.GameStatusWaiting
+	assert.Same(t, stored, store.removeWhen(stored.ID, isWaiting))
+	assert.Nil(t, store.get(stored.ID))
+	assert.Nil(t, store.removeWhen(stored.ID, isWaiting), "nothing is left to remove")
+}
+
+func TestRemoveIf(t *testing.T) {
+	store := newGameStore(DefaultStoreShards)
+	kept := &models.Game{ID: "remove_if_kept", Status: models.GameStatusActive}
+	stale := &models.Game{ID: "remove_if_stale", Status: models.GameStatusWaiting}
+	store.put(kept)
+	store.put(stale)
+
+	removed := store.removeIf(func(game *models.Game) bool {
+		assert.False(t, store.shard(game.ID).locks[game.ID].TryLock(), "each game is checked under its lock")
+		return isWaiting(game)
+	})
+	assert.Equal(t, []*models.Game{stale}, removed)
+	assert.Same(t, kept, store.get(kept.ID))
+	assert.Nil(t, store.get(stale.ID))
+}
@@ -0,0 +1,82 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"htmx-go-app/models"
+	"htmx-go-app/rng"
+)
+
+var slugAdjectives = []string{
+	"blue", "red", "green", "gold", "silver", "purple", "orange", "pink",
+	"brave", "calm", "clever", "happy", "lucky", "quick", "quiet", "sunny",
+	"bold", "fuzzy", "jolly", "mighty", "swift", "witty", "cosmic", "frosty",
+}
+
+var slugNouns = []string{
+	"tiger", "panda", "otter", "falcon", "koala", "dolphin", "fox", "owl",
+	"rocket", "comet", "planet", "dragon", "unicorn", "wizard", "pirate", "ninja",
+	"cactus", "maple", "river", "thunder", "pixel", "banana", "mango", "walnut",
+}
+
+// Join codes (slug -> gameID)
+var (
+	slugsMu sync.RWMutex
+	slugs   = make(map[string]string)
+)
+
+// generateSlug creates a readable join code like "blue-tiger-42" that isn't
+// in use yet. Callers hold slugsMu.
+func generateSlug() string {
+	for {
+		slug := fmt.Sprintf("%s-%s-%d",
+			slugAdjectives[rng.Intn(len(slugAdjectives))],
+			slugNouns[rng.Intn(len(slugNouns))],
+			rng.Intn(90)+10)
+		if _, taken := slugs[slug]; !taken {
+			return slug
+		}
+	}
+}
+
+// registerSlug assigns a fresh join code to the game
+func registerSlug(game *models.Game) {
+	slugsMu.Lock()
+	defer slugsMu.Unlock()
+	game.Slug = generateSlug()
+	slugs[game.Slug] = game.ID
+}
+
+// setSlug points an existing join code at a game, as when restoring it
+func setSlug(slug, gameID string) {
+	slugsMu.Lock()
+	defer slugsMu.Unlock()
+	slugs[slug] = gameID
+}
+
+// unregisterSlug frees a removed game's join code
+func unregisterSlug(slug string) {
+	slugsMu.Lock()
+	defer slugsMu.Unlock()
+	delete(slugs, slug)
+}
+
+// NormalizeGameCode cleans up a user-typed code, e.g. " Blue Tiger 42 " -> "blue-tiger-42"
+func NormalizeGameCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.Join(strings.Fields(strings.ReplaceAll(code, "-", " ")), "-")
+}
+
+// ResolveGameCode finds a game by join code or by its hex game ID
+func ResolveGameCode(code string) *models.Game {
+	code = NormalizeGameCode(code)
+	slugsMu.RLock()
+	gameID, ok := slugs[code]
+	slugsMu.RUnlock()
+	if ok {
+		return GetGame(gameID)
+	}
+	return GetGame(code)
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"htmx-go-app/models"
+)
+
+// SnapshotVersion is the format version written into snapshot files.
+// Version 1 files, from before the format was versioned, are a bare array
+// of games and still load. Version 3 adds players' sessions, so signed-out
+// devices stay signed out across restarts. A file from a newer version is
+// refused rather than half understood.
+const SnapshotVersion = 3
+
+// ErrUnsupportedSnapshot is returned when loading a snapshot whose version
+// this server doesn't know
+var ErrUnsupportedSnapshot = errors.New("unsupported snapshot version")
+
+// snapshotFile is what a snapshot file holds
+type snapshotFile struct {
+	Version  int             `json:"version"`
+	Games    []*models.Game  `json:"games"`
+	Sessions []savedSessions `json:"sessions,omitempty"`
+}
+
+// SaveSnapshot writes every game and session to path as JSON, replacing the
+// file atomically so a crash mid-write never leaves a truncated snapshot
+func SaveSnapshot(path string) error {
+	data, err := json.Marshal(snapshotFile{Version: SnapshotVersion, Games: Snapshot(nil), Sessions: saveSessions()})
+	if err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadSnapshot restores the games and sessions saved by SaveSnapshot and
+// returns how many games were loaded. Files from before sessions were saved
+// leave the sessions alone. A missing file is not an error; there is just nothing to restore.
+func LoadSnapshot(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var saved snapshotFile
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		saved.Version = 1
+		err = json.Unmarshal(data, &saved.Games)
+	} else {
+		err = json.Unmarshal(data, &saved)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("decoding snapshot %s: %w", path, err)
+	}
+	if saved.Version < 1 || saved.Version > SnapshotVersion {
+		return 0, fmt.Errorf("snapshot %s: %w: %d", path, ErrUnsupportedSnapshot, saved.Version)
+	}
+
+	if saved.Version >= 3 {
+		restoreSessions(saved.Sessions)
+	}
+	for _, game := range saved.Games {
+		migrateBoard(game)
+		games.put(game)
+		if game.Slug != "" {
+			setSlug(game.Slug, game.ID)
+		}
+	}
+	return len(saved.Games), nil
+}
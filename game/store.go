@@ -1,85 +1,405 @@
 package game
 
 import (
-	"crypto/rand"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"htmx-go-app/clock"
+	"htmx-go-app/emojis"
 	"htmx-go-app/models"
+	"htmx-go-app/rng"
+	"htmx-go-app/setting"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Errors returned when a player cannot join a game
+var (
+	ErrGameFull            = errors.New("game is full")
+	ErrPlayerAlreadyInGame = errors.New("player already in game")
+	ErrEmojiTaken          = errors.New("emoji already taken")
+	ErrInvalidEmoji        = errors.New("invalid emoji")
+	ErrInvalidName         = errors.New("name must be at most 24 characters")
+)
+
+// MaxPlayerNameLength is the longest display name a player may choose
+const MaxPlayerNameLength = 24
+
+// Errors returned when changing game settings
+var (
+	ErrGameAlreadyStarted = errors.New("game has already started")
+	ErrInvalidVisibility  = errors.New("invalid visibility")
+	ErrInvalidPassword    = errors.New("invalid password")
+	ErrTooManyOpenGames   = errors.New("too many open games, finish or join one first")
+	ErrNotCreator         = errors.New("only the game's creator can do that")
 )
 
-// Global game storage
-var games = make(map[string]*models.Game)
+// MaxOpenGamesPerCreator caps how many waiting games one player or IP address
+// may have at once. Zero means no limit.
+var MaxOpenGamesPerCreator setting.Value[int]
+
+// OnGameRemoved, if set, is called with each game after it is deleted or
+// expires, so whatever still refers to it can let go
+var OnGameRemoved func(*models.Game)
+
+// UnclaimedGameTTL is how long a game nobody has picked an emoji in is kept
+// before it is removed
+var UnclaimedGameTTL = setting.New(2 * time.Minute)
 
 // generateGameID creates a unique game identifier
 func generateGameID() string {
-	bytes := make([]byte, 4)
-	rand.Read(bytes)
-	return fmt.Sprintf("%x", bytes)
+	return rng.Hex(4)
 }
 
-// GeneratePlayerID creates a unique player identifier
+// GeneratePlayerID creates a unique player identifier. It doubles as the
+// session cookie, so it stays unguessable even when randomness is seeded.
 func GeneratePlayerID() string {
-	bytes := make([]byte, 8)
-	rand.Read(bytes)
-	return fmt.Sprintf("player_%x", bytes)
+	return "player_" + rng.Secret(8)
+}
+
+// CreateGame creates a new game with the given options and stores it
+func CreateGame(creatorID string, options models.GameOptions) (*models.Game, error) {
+	game, err := newGame(creatorID, options)
+	if err != nil {
+		return nil, err
+	}
+	storeGame(game)
+	return game, nil
 }
 
-// CreateGame creates a new game and stores it
-func CreateGame() *models.Game {
+// newGame creates a new game with the given options without storing it, so
+// it can be set up before anyone else can see it
+func newGame(creatorID string, options models.GameOptions) (*models.Game, error) {
+	if options.Visibility != models.VisibilityPublic && options.Visibility != models.VisibilityPrivate {
+		return nil, ErrInvalidVisibility
+	}
+	if !validAbandonRule(options.AbandonRule) {
+		return nil, ErrInvalidAbandonRule
+	}
+
+	removeUnclaimedGames()
+	if limit := MaxOpenGamesPerCreator.Get(); limit > 0 && countOpenGames(creatorID, options.CreatorIP) >= limit {
+		return nil, ErrTooManyOpenGames
+	}
+
+	var passwordHash []byte
+	if options.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(options.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, ErrInvalidPassword
+		}
+		passwordHash = hash
+	}
+
 	id := generateGameID()
 	game := &models.Game{
-		ID:          id,
-		Board:       models.GameBoard{},
-		Players:     make(map[string]*models.Player),
-		PlayerOrder: make([]string, 0),
-		Status:      models.GameStatusWaiting, // Start in waiting state
+		ID:             id,
+		Board:          models.GameBoard{},
+		Players:        make(map[string]*models.Player),
+		PlayerOrder:    make([]string, 0),
+		Status:         models.GameStatusWaiting, // Start in waiting state
+		CreatedAt:      clock.Now(),
+		Visibility:     options.Visibility,
+		CreatorID:      creatorID,
+		CreatorIP:      options.CreatorIP,
+		PasswordHash:   passwordHash,
+		AbandonRule:    options.AbandonRule,
+		Correspondence: options.Correspondence,
+		UnclaimedTTL:   options.UnclaimedTTL,
+	}
+	return game, nil
+}
+
+// storeGame gives a new game its join code and stores it
+func storeGame(game *models.Game) {
+	registerSlug(game)
+	games.put(game)
+}
+
+// countOpenGames counts the waiting games created by the player or from the
+// IP address. Games created for nobody in particular only count by address.
+func countOpenGames(creatorID, creatorIP string) int {
+	return len(Snapshot(func(game *models.Game) bool {
+		if !isWaiting(game) {
+			return false
+		}
+		return (creatorID != "" && game.CreatorID == creatorID) || (creatorIP != "" && game.CreatorIP == creatorIP)
+	}))
+}
+
+// removeUnclaimedGames drops games nobody joined within their UnclaimedTTL,
+// or UnclaimedGameTTL for games without one
+func removeUnclaimedGames() {
+	now := clock.Now()
+	defaultTTL := UnclaimedGameTTL.Get()
+	removed := games.removeIf(func(game *models.Game) bool {
+		ttl := game.UnclaimedTTL
+		if ttl <= 0 {
+			ttl = defaultTTL
+		}
+		return len(game.Players) == 0 && now.Sub(game.CreatedAt) > ttl
+	})
+	for _, game := range removed {
+		unregisterSlug(game.Slug)
+		gameRemoved(game)
 	}
-	games[id] = game
-	return game
 }
 
 // GetGame retrieves a game by ID
 func GetGame(id string) *models.Game {
-	return games[id]
+	return games.get(id)
+}
+
+// DeleteGame removes a game and its join code, reporting whether it existed
+func DeleteGame(id string) bool {
+	game := games.remove(id)
+	if game == nil {
+		return false
+	}
+	unregisterSlug(game.Slug)
+	gameRemoved(game)
+	return true
+}
+
+func gameRemoved(game *models.Game) {
+	if OnGameRemoved != nil {
+		OnGameRemoved(game)
+	}
+}
+
+// CancelGame removes a game nobody has joined yet on behalf of its creator,
+// freeing its ID and join code
+func CancelGame(game *models.Game, playerID string) error {
+	if game.CreatorID != playerID {
+		return ErrNotCreator
+	}
+	unlock := Lock(game)
+	waiting := isWaiting(game)
+	unlock()
+	if !waiting {
+		return ErrGameAlreadyStarted
+	}
+
+	// An opponent may have joined since, so the status is checked again as
+	// the game is removed
+	if games.removeWhen(game.ID, isWaiting) == nil {
+		return ErrGameAlreadyStarted
+	}
+	unregisterSlug(game.Slug)
+	gameRemoved(game)
+	return nil
+}
+
+// isWaiting reports whether the game is still waiting for an opponent
+func isWaiting(game *models.Game) bool {
+	return game.Status == models.GameStatusWaiting
+}
+
+// Lock locks the game against other changes and against being copied by
+// Snapshot and Range, and returns the function that unlocks it. Everything
+// that changes a stored game holds its lock while doing so, and must not
+// call into the store until it has unlocked.
+func Lock(game *models.Game) (unlock func()) {
+	lock := games.lock(game.ID)
+	lock.Lock()
+	return lock.Unlock
+}
+
+// ListGames returns the games matching filter (all games if nil), newest first
+func ListGames(filter func(*models.Game) bool) []*models.Game {
+	var result []*models.Game
+	games.each(func(game *models.Game) {
+		if filter == nil || filter(game) {
+			result = append(result, game)
+		}
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result
+}
+
+// Snapshot returns copies of the games matching filter (all games if nil),
+// newest first. Each game is copied under its lock (see Lock), so the lobby,
+// dashboards and stats can take their time rendering them without holding
+// up moves or seeing one half applied. Changes to the copies are not stored.
+func Snapshot(filter func(*models.Game) bool) []*models.Game {
+	var result []*models.Game
+	for _, shard := range games.shards {
+		result = append(result, shard.copies(filter)...)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result
+}
+
+// Range calls fn with a copy of each game, in no particular order, until fn
+// returns false. No lock is held while fn runs, so it may call back into the
+// store.
+func Range(fn func(*models.Game) bool) {
+	for _, shard := range games.shards {
+		for _, game := range shard.copies(nil) {
+			if !fn(game) {
+				return
+			}
+		}
+	}
+}
+
+// FinishedGames returns copies of the won and drawn games finished at or
+// after since, oldest first (ties broken by ID). When afterTime is set, only games ordered
+// after (afterTime, afterID) are returned, for paging through results.
+func FinishedGames(since, afterTime time.Time, afterID string) []*models.Game {
+	result := Snapshot(func(g *models.Game) bool {
+		if (g.Status != models.GameStatusFinished && g.Status != models.GameStatusDraw) || g.FinishedAt.Before(since) {
+			return false
+		}
+		if !afterTime.IsZero() {
+			return g.FinishedAt.After(afterTime) || (g.FinishedAt.Equal(afterTime) && g.ID > afterID)
+		}
+		return true
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].FinishedAt.Equal(result[j].FinishedAt) {
+			return result[i].FinishedAt.Before(result[j].FinishedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// SetGameVisibility changes whether the game is listed in the lobby; only allowed before it starts
+func SetGameVisibility(game *models.Game, visibility models.GameVisibility) error {
+	if visibility != models.VisibilityPublic && visibility != models.VisibilityPrivate {
+		return ErrInvalidVisibility
+	}
+	unlock := Lock(game)
+	defer unlock()
+	if game.Status != models.GameStatusWaiting {
+		return ErrGameAlreadyStarted
+	}
+
+	game.Visibility = visibility
+	game.Version++
+	return nil
 }
 
-// AddPlayerToGame adds a player with the given emoji to the game
-func AddPlayerToGame(game *models.Game, playerID, emoji string) error {
+// SettingsChange lists the options a creator wants to change before anyone
+// joins. Zero values leave the current setting alone.
+type SettingsChange struct {
+	Visibility    models.GameVisibility
+	Password      string             // new join password
+	ClearPassword bool               // remove the join password
+	AbandonRule   models.AbandonRule // what happens if the player to move goes idle
+}
+
+// UpdateGameSettings applies change to a game that is still waiting for an
+// opponent. Only the creator may change settings.
+func UpdateGameSettings(game *models.Game, playerID string, change SettingsChange) error {
+	if game.CreatorID != playerID {
+		return ErrNotCreator
+	}
+	if change.Visibility != "" && change.Visibility != models.VisibilityPublic && change.Visibility != models.VisibilityPrivate {
+		return ErrInvalidVisibility
+	}
+	if change.Password != "" && change.ClearPassword {
+		return ErrInvalidPassword
+	}
+	if !validAbandonRule(change.AbandonRule) {
+		return ErrInvalidAbandonRule
+	}
+
+	var passwordHash []byte
+	if change.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(change.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return ErrInvalidPassword
+		}
+		passwordHash = hash
+	}
+
+	// Checked under the lock, after the slow hashing, so the opponent can't
+	// join in between
+	unlock := Lock(game)
+	defer unlock()
+	if game.Status != models.GameStatusWaiting {
+		return ErrGameAlreadyStarted
+	}
+	if change.Visibility != "" {
+		game.Visibility = change.Visibility
+	}
+	if change.AbandonRule != "" {
+		game.AbandonRule = change.AbandonRule
+	}
+	switch {
+	case passwordHash != nil:
+		game.PasswordHash = passwordHash
+	case change.ClearPassword:
+		game.PasswordHash = nil
+	}
+	game.Version++
+	return nil
+}
+
+// AddPlayerToGame adds a player with the given emoji and optional display name to the game.
+// With custom symbols on, the emoji may be any symbol emojis.CheckSymbol accepts.
+func AddPlayerToGame(game *models.Game, playerID, emoji, name string) error {
+	emoji = emojis.Canonical(emoji)
+	listed, inCatalog := emojis.Lookup(emoji)
+	if inCatalog {
+		// Spelled as the catalog spells it, with or without variation selectors
+		emoji = listed
+	}
+
+	unlock := Lock(game)
+	defer unlock()
+
 	// Check if game is full
 	if len(game.Players) >= models.MaxPlayersPerGame {
-		return fmt.Errorf("game is full")
+		return ErrGameFull
 	}
 
 	// Check if player already in game
 	if _, exists := game.Players[playerID]; exists {
-		return fmt.Errorf("player already in game")
+		return ErrPlayerAlreadyInGame
 	}
 
 	if !IsEmojiAvailable(game, emoji) {
-		return fmt.Errorf("emoji already taken")
+		return ErrEmojiTaken
 	}
 
-	// Check if emoji is in available list
-	emojiValid := false
-	for _, availableEmoji := range models.AvailableEmojis {
-		if availableEmoji == emoji {
-			emojiValid = true
-			break
+	if !inCatalog {
+		if !emojis.CustomAllowed() {
+			return ErrInvalidEmoji
+		}
+		if err := emojis.CheckSymbol(emoji); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidEmoji, err)
 		}
 	}
-	if !emojiValid {
-		return fmt.Errorf("invalid emoji")
+
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > MaxPlayerNameLength {
+		return ErrInvalidName
 	}
 
 	player := &models.Player{
 		ID:       playerID,
 		Emoji:    emoji,
-		JoinedAt: time.Now(),
+		Name:     name,
+		JoinedAt: clock.Now(),
 	}
 
 	game.Players[playerID] = player
 	game.PlayerOrder = append(game.PlayerOrder, playerID)
+	game.Version++
 
 	// Update game status based on player count
 	if len(game.Players) == 1 {
@@ -88,7 +408,9 @@ func AddPlayerToGame(game *models.Game, playerID, emoji string) error {
 		game.Status = models.GameStatusActive // Start the game with first player's turn
 		game.CurrentTurn = 0                  // Player 1 (index 0) goes first
 		game.MoveCount = 0
+		game.Moves = nil
+		game.StartedAt = clock.Now()
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"slices"
+
+	"htmx-go-app/engine"
+	"htmx-go-app/models"
+)
+
+// Boards hold each seat's letter, X or O, rather than the symbol its player
+// picked, so what a player is called on the board never depends on what
+// they typed. Anything shown to people maps the letters back to symbols.
+
+// Mark returns the letter playerID's pieces leave on the board, or "" if
+// they have no seat
+func Mark(game *models.Game, playerID string) string {
+	seat := slices.Index(game.PlayerOrder, playerID)
+	if seat < 0 || seat >= models.MaxPlayersPerGame {
+		return ""
+	}
+	return engine.SeatLetter(seat)
+}
+
+// PlayerAt returns the ID of the player whose pieces carry mark, or ""
+func PlayerAt(game *models.Game, mark string) string {
+	seat := engine.SeatOf(mark)
+	if seat < 0 || seat >= len(game.PlayerOrder) {
+		return ""
+	}
+	return game.PlayerOrder[seat]
+}
+
+// Symbols maps each seat's letter to the symbol its player picked. It is
+// nil for a nil game, leaving the letters to stand for themselves.
+func Symbols(game *models.Game) map[string]string {
+	if game == nil {
+		return nil
+	}
+	symbols := make(map[string]string, len(game.PlayerOrder))
+	for seat, playerID := range game.PlayerOrder {
+		if player, ok := game.Players[playerID]; ok && seat < models.MaxPlayersPerGame {
+			symbols[engine.SeatLetter(seat)] = player.Emoji
+		}
+	}
+	return symbols
+}
+
+// SymbolBoard returns the game's board with each letter replaced by its
+// player's symbol, the way clients of the API and bots expect to see it
+func SymbolBoard(game *models.Game) models.GameBoard {
+	symbols := Symbols(game)
+	board := game.Board
+	for row := range board {
+		for col, mark := range board[row] {
+			if symbol, ok := symbols[mark]; ok {
+				board[row][col] = symbol
+			}
+		}
+	}
+	return board
+}
+
+// migrateBoard rewrites a board saved when boards held the players' emojis
+// to hold seat letters. Boards holding nothing but letters are left alone.
+func migrateBoard(game *models.Game) {
+	current := true
+	for _, row := range game.Board {
+		for _, mark := range row {
+			if mark != "" && engine.SeatOf(mark) < 0 {
+				current = false
+			}
+		}
+	}
+	if current {
+		return
+	}
+	for seat, playerID := range game.PlayerOrder {
+		player, ok := game.Players[playerID]
+		if !ok || seat >= models.MaxPlayersPerGame || player.Emoji == "" {
+			continue
+		}
+		for row := range game.Board {
+			for col, mark := range game.Board[row] {
+				if mark == player.Emoji {
+					game.Board[row][col] = engine.SeatLetter(seat)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package game
+
+import (
+	"sync"
+
+	"htmx-go-app/models"
+	"htmx-go-app/rng"
+)
+
+var (
+	telegramMu      sync.RWMutex
+	telegramUsers   = make(map[int64]*models.TelegramUser)  // Telegram user ID -> link
+	telegramPlayers = make(map[string]*models.TelegramUser) // player ID -> link
+	telegramLogins  = make(map[string]*models.TelegramUser) // login token -> link
+)
+
+// TelegramPlayer returns the player a Telegram user plays as, creating one
+// on first contact. The chat is updated so turns go to where they last wrote.
+func TelegramPlayer(userID, chatID int64) *models.TelegramUser {
+	telegramMu.Lock()
+	defer telegramMu.Unlock()
+
+	if user, exists := telegramUsers[userID]; exists {
+		user.ChatID = chatID
+		return user
+	}
+	user := &models.TelegramUser{
+		UserID:     userID,
+		ChatID:     chatID,
+		PlayerID:   GeneratePlayerID(),
+		LoginToken: rng.Secret(16),
+	}
+	telegramUsers[userID] = user
+	telegramPlayers[user.PlayerID] = user
+	telegramLogins[user.LoginToken] = user
+	return user
+}
+
+// TelegramUserForPlayer returns the Telegram account playing as playerID, or nil
+func TelegramUserForPlayer(playerID string) *models.TelegramUser {
+	telegramMu.RLock()
+	defer telegramMu.RUnlock()
+	return telegramPlayers[playerID]
+}
+
+// TelegramUserByLogin returns the Telegram account a login token belongs to, or nil
+func TelegramUserByLogin(token string) *models.TelegramUser {
+	telegramMu.RLock()
+	defer telegramMu.RUnlock()
+	return telegramLogins[token]
+}
@@ -0,0 +1,41 @@
+package game
+
+import (
+	"time"
+
+	"htmx-go-app/models"
+)
+
+// Duration is how long a finished game took from its start to the last
+// move, or zero while it is still going
+func Duration(game *models.Game) time.Duration {
+	if !IsGameFinished(game) || game.StartedAt.IsZero() {
+		return 0
+	}
+	return game.FinishedAt.Sub(game.StartedAt)
+}
+
+// AverageThinkTime is how long the player took per move on average, timed
+// from the previous move or the start of the game. It reports false if the
+// player has no timed moves.
+func AverageThinkTime(game *models.Game, playerID string) (time.Duration, bool) {
+	player, ok := game.Players[playerID]
+	if !ok || player.Emoji == "" {
+		return 0, false
+	}
+
+	var total time.Duration
+	moves := 0
+	previous := game.StartedAt
+	for _, move := range game.Moves {
+		if move.PlayerID == playerID && !previous.IsZero() {
+			total += move.At.Sub(previous)
+			moves++
+		}
+		previous = move.At
+	}
+	if moves == 0 {
+		return 0, false
+	}
+	return total / time.Duration(moves), true
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+)
+
+// AbandonSweepInterval is how often the janitor looks for abandoned games
+var AbandonSweepInterval = time.Minute
+
+// SweepAbandonedGames applies the abandonment rule of every game whose
+// player to move has gone idle for longer than game.AbandonAfter, tells the
+// games' subscribers and returns how many games there were
+func SweepAbandonedGames() int {
+	abandoned := game.AbandonIdleGames()
+	for _, a := range abandoned {
+		gameData := a.Game
+		switch a.Rule {
+		case models.AbandonVoid:
+			// Deleting the game already closed its streams with game_cancelled
+		case models.AbandonPause:
+			broadcastBoardUpdate(gameData, "game_paused")
+		default:
+			events.BroadcastGameUpdate(gameData.ID, models.GameEvent{
+				Type:   "game_winner",
+				GameID: gameData.ID,
+				Data: map[string]interface{}{
+					"board":     gameData.Board,
+					"winner":    gameData.Winner,
+					"emoji":     gameData.Players[gameData.Winner].Emoji,
+					"abandoned": true,
+				},
+			}, gameData, func(playerID string) string {
+				return gameStatusFragment(gameData, playerID)
+			})
+			announceGameLifecycle("game_finished", gameData)
+		}
+		broadcastLobbyUpdate(gameData.ID)
+		scheduleTurnReminder(gameData)
+	}
+	return len(abandoned)
+}
+
+// StartAbandonSweep runs SweepAbandonedGames every AbandonSweepInterval
+// until the returned stop function is called
+func StartAbandonSweep() (stop func()) {
+	ticker := time.NewTicker(AbandonSweepInterval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if swept := SweepAbandonedGames(); swept > 0 {
+					log.Printf("applied the abandonment rule to %d idle games", swept)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// broadcastBoardUpdate sends the game's board under eventType together with
+// each player's status
+func broadcastBoardUpdate(gameData *models.Game, eventType string) {
+	events.BroadcastGameUpdate(gameData.ID, models.GameEvent{
+		Type:   eventType,
+		GameID: gameData.ID,
+		Data: map[string]interface{}{
+			"board": gameData.Board,
+		},
+	}, gameData, func(playerID string) string {
+		return gameStatusFragment(gameData, playerID)
+	})
+}
+
+// abandonRuleText describes what happens to a game when the player to move
+// goes idle, or "" when abandonment is off
+func abandonRuleText(rule models.AbandonRule) string {
+	after := game.AbandonAfter.Get()
+	if after <= 0 {
+		return ""
+	}
+	idle := after.String()
+	switch rule {
+	case models.AbandonVoid:
+		return "idle " + idle + ": game is void"
+	case models.AbandonPause:
+		return "idle " + idle + ": game pauses"
+	default:
+		return "idle " + idle + ": opponent wins"
+	}
+}
+
+// renderAbandonedHTML explains how a game that was abandoned ended up where
+// it is. Players of a paused game get a button to resume it.
+func renderAbandonedHTML(gameData *models.Game, playerID string) string {
+	idle, ok := gameData.Players[gameData.AbandonedBy]
+	if !ok && gameData.Status != models.GameStatusPaused {
+		return ""
+	}
+
+	if gameData.Status != models.GameStatusPaused {
+		return fmt.Sprintf(`<p class="game-abandoned" data-testid="game-abandoned">%s stopped playing, so the game went to their opponent.</p>`, idle.Emoji)
+	}
+
+	message := "⏸️ This game is paused."
+	if ok {
+		message = fmt.Sprintf("⏸️ This game is paused because %s stopped playing.", idle.Emoji)
+	}
+	resume := ""
+	if isGamePlayer(gameData, playerID) {
+		resume = fmt.Sprintf(` <button class="btn btn-primary btn-small" hx-post="%s" hx-target="#game-status" hx-swap="outerHTML" data-testid="resume-game">Resume</button>`,
+			URLPath("/api/game/", gameData.ID, "/resume"))
+	}
+	return fmt.Sprintf(`<div class="game-paused" data-testid="game-paused">%s%s</div>`, message, resume)
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"htmx-go-app/audit"
+	"htmx-go-app/buildinfo"
+	"htmx-go-app/clock"
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/setting"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAPIKey guards the /api/admin endpoints. The admin API is disabled
+// while it is empty.
+var AdminAPIKey setting.Value[string]
+
+var serverStartedAt = clock.Now()
+
+// adminGame is the admin view of a game. Unlike the public API it includes
+// player IDs, so players can be looked up and banned.
+type adminGame struct {
+	apiGame
+	CreatorID   string   `json:"creatorId"`
+	CreatorIP   string   `json:"creatorIp,omitempty"`
+	PlayerIDs   []string `json:"playerIds"`
+	Subscribers int      `json:"subscribers"`
+}
+
+type adminStats struct {
+	Build          buildinfo.Info            `json:"build"`
+	UptimeSeconds  int64                     `json:"uptimeSeconds"`
+	Games          int                       `json:"games"`
+	GamesByStatus  map[models.GameStatus]int `json:"gamesByStatus"`
+	OpenLobbyGames int                       `json:"openLobbyGames"`
+	MatchQueue     int                       `json:"matchQueue"`
+	BannedPlayers  int                       `json:"bannedPlayers"`
+	Connections    map[string]int            `json:"connections"`
+	LobbyListeners int                       `json:"lobbyListeners"`
+	EventsSent     uint64                    `json:"eventsSent"`
+	EventsDropped  uint64                    `json:"eventsDropped"`
+	JoinSources    map[string]int            `json:"joinSources"` // games by the channel that brought the second player
+}
+
+// RequireAdminKey lets a request through only if it carries AdminAPIKey as a
+// bearer token or in the X-API-Key header
+func RequireAdminKey(c *gin.Context) {
+	adminKey := AdminAPIKey.Get()
+	if adminKey == "" {
+		renderAPIError(c, http.StatusNotFound, "Admin API is disabled")
+		c.Abort()
+		return
+	}
+
+	key := c.GetHeader("X-API-Key")
+	if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+		key = bearer
+	}
+	if subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) != 1 {
+		renderAPIError(c, http.StatusUnauthorized, "Invalid API key")
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// RejectBannedPlayers stops banned players before any handler runs
+func RejectBannedPlayers(c *gin.Context) {
+	playerID, ok := requestPlayerID(c)
+	if !ok || !game.IsBanned(playerID) {
+		c.Next()
+		return
+	}
+
+	if isJSONAPIRequest(c) {
+		renderAPIError(c, http.StatusForbidden, game.ErrPlayerBanned.Error())
+	} else {
+		renderError(c, http.StatusForbidden, "You have been banned from this server.")
+	}
+	c.Abort()
+}
+
+func newAdminGame(c *gin.Context, gameData *models.Game, subscribers int) adminGame {
+	return adminGame{
+		apiGame:     newAPIGame(c, gameData, ""),
+		CreatorID:   gameData.CreatorID,
+		CreatorIP:   gameData.CreatorIP,
+		PlayerIDs:   append([]string{}, gameData.PlayerOrder...),
+		Subscribers: subscribers,
+	}
+}
+
+// AdminListGamesHandler lists games, newest first, optionally filtered by
+// ?status=, ?visibility= and ?player= (a player ID)
+func AdminListGamesHandler(c *gin.Context) {
+	status := models.GameStatus(c.Query("status"))
+	visibility := models.GameVisibility(c.Query("visibility"))
+	playerID := c.Query("player")
+
+	games := game.Snapshot(func(g *models.Game) bool {
+		if status != "" && g.Status != status {
+			return false
+		}
+		if visibility != "" && g.Visibility != visibility {
+			return false
+		}
+		if playerID != "" && g.Players[playerID] == nil && g.CreatorID != playerID {
+			return false
+		}
+		return true
+	})
+
+	subscribers := events.Snapshot().GameSubscribers
+	response := make([]adminGame, 0, len(games))
+	for _, g := range games {
+		response = append(response, newAdminGame(c, g, subscribers[g.ID]))
+	}
+	c.JSON(http.StatusOK, gin.H{"games": response})
+}
+
+// AdminDeleteGameHandler removes a game
+func AdminDeleteGameHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderAPIError(c, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	game.DeleteGame(gameData.ID)
+	broadcastLobbyGameEvent("game_finished", gameData)
+	recordAdminAudit(c, audit.ActionGameDeleted, gameData.ID, "", string(gameData.Status))
+	c.Status(http.StatusNoContent)
+}
+
+// AdminBanPlayerHandler bans a player by ID
+func AdminBanPlayerHandler(c *gin.Context) {
+	game.BanPlayer(c.Param("id"))
+	recordAdminAudit(c, audit.ActionPlayerBanned, "", c.Param("id"), "")
+	c.Status(http.StatusNoContent)
+}
+
+// AdminUnbanPlayerHandler lifts a player's ban
+func AdminUnbanPlayerHandler(c *gin.Context) {
+	if !game.UnbanPlayer(c.Param("id")) {
+		renderAPIError(c, http.StatusNotFound, "Player is not banned")
+		return
+	}
+	recordAdminAudit(c, audit.ActionPlayerUnbanned, "", c.Param("id"), "")
+	c.Status(http.StatusNoContent)
+}
+
+// AdminListBansHandler lists banned player IDs
+func AdminListBansHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"players": game.BannedPlayers()})
+}
+
+// AdminStatsHandler reports server-wide counts
+func AdminStatsHandler(c *gin.Context) {
+	metrics := events.Snapshot()
+	stats := adminStats{
+		Build:          buildinfo.Get(),
+		UptimeSeconds:  int64(clock.Since(serverStartedAt).Seconds()),
+		GamesByStatus:  make(map[models.GameStatus]int),
+		JoinSources:    make(map[string]int),
+		MatchQueue:     game.MatchQueueLength(),
+		BannedPlayers:  len(game.BannedPlayers()),
+		Connections:    metrics.ConnectionsByTransport,
+		LobbyListeners: metrics.LobbySubscribers,
+		EventsSent:     metrics.EventsSent,
+		EventsDropped:  metrics.EventsDropped,
+	}
+	game.Range(func(g *models.Game) bool {
+		stats.Games++
+		stats.GamesByStatus[g.Status]++
+		if game.IsOpenForLobby(g) {
+			stats.OpenLobbyGames++
+		}
+		if g.JoinSource != "" {
+			stats.JoinSources[g.JoinSource]++
+		}
+		return true
+	})
+	c.JSON(http.StatusOK, stats)
+}
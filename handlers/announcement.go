@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"htmx-go-app/audit"
+	"htmx-go-app/clock"
+	"htmx-go-app/events"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxAnnouncementLength is the longest banner message an admin can post
+const MaxAnnouncementLength = 280
+
+var (
+	announcementMu     sync.Mutex
+	announcement       *models.Announcement
+	lastAnnouncementID uint64
+)
+
+type announcementRequest struct {
+	Message  string `json:"message"`
+	Duration string `json:"duration"` // optional Go duration, e.g. "10m"
+}
+
+// apiAnnouncement is the JSON form of an announcement
+type apiAnnouncement struct {
+	ID        uint64     `json:"id"`
+	Message   string     `json:"message"`
+	PostedAt  time.Time  `json:"postedAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+func newAPIAnnouncement(a models.Announcement) apiAnnouncement {
+	response := apiAnnouncement{ID: a.ID, Message: a.Message, PostedAt: a.PostedAt}
+	if !a.ExpiresAt.IsZero() {
+		response.ExpiresAt = &a.ExpiresAt
+	}
+	return response
+}
+
+// currentAnnouncement returns the announcement on display, if any
+func currentAnnouncement() *models.Announcement {
+	announcementMu.Lock()
+	defer announcementMu.Unlock()
+
+	if announcement != nil && !announcement.ExpiresAt.IsZero() && clock.Now().After(announcement.ExpiresAt) {
+		announcement = nil
+	}
+	return announcement
+}
+
+// AnnouncementHTML renders the announcement banner for the base layout. The
+// empty container is rendered too, so live announcements have a swap target.
+func AnnouncementHTML() template.HTML {
+	return template.HTML(renderAnnouncementHTML(currentAnnouncement()))
+}
+
+func renderAnnouncementHTML(a *models.Announcement) string {
+	if a == nil {
+		return `<div id="announcement"></div>`
+	}
+	return fmt.Sprintf(`<div id="announcement"><div class="announcement" role="status" data-announcement-id="%d"><span>%s</span><button type="button" class="announcement-dismiss" aria-label="Dismiss announcement">×</button></div></div>`,
+		a.ID, html.EscapeString(a.Message))
+}
+
+// postAnnouncement replaces the current announcement and pushes it to every
+// open page. Announcements with a duration are cleared again when it ends.
+func postAnnouncement(message string, duration time.Duration) models.Announcement {
+	announcementMu.Lock()
+	lastAnnouncementID++
+	posted := models.Announcement{
+		ID:       lastAnnouncementID,
+		Message:  message,
+		PostedAt: clock.Now(),
+	}
+	if duration > 0 {
+		posted.ExpiresAt = posted.PostedAt.Add(duration)
+	}
+	announcement = &posted
+	announcementMu.Unlock()
+
+	events.BroadcastAnnouncement(map[string]interface{}{"announcement": &posted})
+
+	if duration > 0 {
+		clock.AfterFunc(duration, func() { clearAnnouncement(posted.ID) })
+	}
+	return posted
+}
+
+// clearAnnouncement takes the announcement down, or only the one with the
+// given ID if id is non-zero
+func clearAnnouncement(id uint64) bool {
+	announcementMu.Lock()
+	if announcement == nil || (id != 0 && announcement.ID != id) {
+		announcementMu.Unlock()
+		return false
+	}
+	announcement = nil
+	announcementMu.Unlock()
+
+	events.BroadcastAnnouncement(map[string]interface{}{"announcement": (*models.Announcement)(nil)})
+	return true
+}
+
+// announcementFromEvent extracts the announcement carried by an
+// "announcement" event; nil means the banner was taken down
+func announcementFromEvent(event models.GameEvent) (*models.Announcement, bool) {
+	dataMap, ok := event.Data.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	a, ok := dataMap["announcement"].(*models.Announcement)
+	return a, ok
+}
+
+// AdminPostAnnouncementHandler shows a banner message on every page
+func AdminPostAnnouncementHandler(c *gin.Context) {
+	var request announcementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	message := strings.TrimSpace(request.Message)
+	if message == "" || len([]rune(message)) > MaxAnnouncementLength {
+		renderAPIError(c, http.StatusBadRequest, fmt.Sprintf("Message must be 1 to %d characters", MaxAnnouncementLength))
+		return
+	}
+
+	var duration time.Duration
+	if request.Duration != "" {
+		d, err := time.ParseDuration(request.Duration)
+		if err != nil || d <= 0 {
+			renderAPIError(c, http.StatusBadRequest, "Invalid duration")
+			return
+		}
+		duration = d
+	}
+
+	posted := postAnnouncement(message, duration)
+	recordAdminAudit(c, audit.ActionAnnouncement, "", "", message)
+	c.JSON(http.StatusCreated, newAPIAnnouncement(posted))
+}
+
+// AdminGetAnnouncementHandler returns the announcement on display
+func AdminGetAnnouncementHandler(c *gin.Context) {
+	current := currentAnnouncement()
+	if current == nil {
+		renderAPIError(c, http.StatusNotFound, "No announcement")
+		return
+	}
+	c.JSON(http.StatusOK, newAPIAnnouncement(*current))
+}
+
+// AdminClearAnnouncementHandler takes the announcement down
+func AdminClearAnnouncementHandler(c *gin.Context) {
+	clearAnnouncement(0)
+	recordAdminAudit(c, audit.ActionAnnouncementEnd, "", "", "")
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,366 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiPlayer is the public view of a player. Player IDs double as session
+// cookies, so they are never exposed for anyone but the requester.
+type apiPlayer struct {
+	Emoji               string  `json:"emoji"`
+	Name                string  `json:"name,omitempty"`
+	Seat                int     `json:"seat"`
+	AverageThinkSeconds float64 `json:"averageThinkSeconds,omitempty"` // per move, once the player has moved
+}
+
+// apiViewer describes the requesting player's place in the game
+type apiViewer struct {
+	PlayerID string `json:"playerId"`
+	Emoji    string `json:"emoji"`
+	Seat     int    `json:"seat"`
+	YourTurn bool   `json:"yourTurn"`
+	// Cue is "your_turn", "win", "lose" or "draw" when the client should
+	// play a sound or vibrate, unless CuesMuted
+	Cue       string `json:"cue,omitempty"`
+	CuesMuted bool   `json:"cuesMuted,omitempty"`
+}
+
+// apiGame is the JSON representation of a game
+type apiGame struct {
+	ID              string                `json:"id"`
+	Code            string                `json:"code"`
+	URL             string                `json:"url"`
+	Status          models.GameStatus     `json:"status"`
+	Visibility      models.GameVisibility `json:"visibility"`
+	HasPassword     bool                  `json:"hasPassword"`
+	AbandonRule     models.AbandonRule    `json:"abandonRule"`
+	Correspondence  bool                  `json:"correspondence"`
+	Board           models.GameBoard      `json:"board"`
+	Players         []apiPlayer           `json:"players"`
+	CurrentTurn     string                `json:"currentTurn,omitempty"` // emoji of the player to move
+	Winner          string                `json:"winner,omitempty"`      // emoji of the winner
+	WinningLine     [][2]int              `json:"winningLine,omitempty"` // row and column of each cell in the winning line
+	MoveCount       int                   `json:"moveCount"`
+	CreatedAt       time.Time             `json:"createdAt"`
+	StartedAt       *time.Time            `json:"startedAt,omitempty"`
+	FinishedAt      *time.Time            `json:"finishedAt,omitempty"`
+	DurationSeconds float64               `json:"durationSeconds,omitempty"` // from start to the last move, once finished
+	You             *apiViewer            `json:"you,omitempty"`
+}
+
+type createGameRequest struct {
+	Emoji   string `json:"emoji"` // optional; joins the creator right away
+	Name    string `json:"name"`
+	Options struct {
+		Visibility     models.GameVisibility `json:"visibility"`
+		Password       string                `json:"password"`
+		AbandonRule    models.AbandonRule    `json:"abandonRule"`
+		Correspondence bool                  `json:"correspondence"`
+	} `json:"options"`
+}
+
+type joinGameRequest struct {
+	Emoji    string `json:"emoji" binding:"required"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// newAPIGame builds the JSON view of a game as seen by playerID
+func newAPIGame(c *gin.Context, gameData *models.Game, playerID string) apiGame {
+	response := apiGame{
+		ID:             gameData.ID,
+		Code:           gameData.Slug,
+		URL:            absoluteURL(c, "/game/"+gameData.ID),
+		Status:         gameData.Status,
+		Visibility:     gameData.Visibility,
+		AbandonRule:    game.AbandonRuleOf(gameData),
+		Correspondence: gameData.Correspondence,
+		HasPassword:    game.HasPassword(gameData),
+		Board:          game.SymbolBoard(gameData),
+		Players:        []apiPlayer{},
+		MoveCount:      gameData.MoveCount,
+		CreatedAt:      gameData.CreatedAt,
+	}
+
+	for seat, pID := range gameData.PlayerOrder {
+		player := gameData.Players[pID]
+		response.Players = append(response.Players, apiPlayer{
+			Emoji:               player.Emoji,
+			Name:                player.Name,
+			Seat:                seat,
+			AverageThinkSeconds: averageThinkSeconds(gameData, pID),
+		})
+		if pID == playerID {
+			response.You = &apiViewer{
+				PlayerID:  playerID,
+				Emoji:     player.Emoji,
+				Seat:      seat,
+				YourTurn:  game.IsPlayersTurn(gameData, playerID),
+				Cue:       cueFor(gameData, playerID),
+				CuesMuted: game.CuesMuted(playerID),
+			}
+		}
+	}
+
+	if current, ok := gameData.Players[game.GetCurrentPlayerID(gameData)]; ok {
+		response.CurrentTurn = current.Emoji
+	}
+	if winner, ok := gameData.Players[gameData.Winner]; ok {
+		response.Winner = winner.Emoji
+		response.WinningLine = game.WinningLine(gameData.Board)
+	}
+	if !gameData.StartedAt.IsZero() {
+		response.StartedAt = &gameData.StartedAt
+	}
+	if game.IsGameFinished(gameData) {
+		response.FinishedAt = &gameData.FinishedAt
+		response.DurationSeconds = game.Duration(gameData).Seconds()
+	}
+
+	return response
+}
+
+// averageThinkSeconds is the player's average time per move, 0 before they moved
+func averageThinkSeconds(gameData *models.Game, playerID string) float64 {
+	average, _ := game.AverageThinkTime(gameData, playerID)
+	return average.Seconds()
+}
+
+// APICreateGameHandler creates a game from JSON and optionally joins the creator
+func APICreateGameHandler(c *gin.Context) {
+	var request createGameRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if request.Options.Visibility == "" {
+		request.Options.Visibility = models.VisibilityPublic
+	}
+
+	playerID := getPlayerIDFromContext(c)
+	request.Name = defaultBotName(playerID, request.Name)
+	gameData, err := game.CreateGame(playerID, models.GameOptions{
+		Visibility:     request.Options.Visibility,
+		Password:       request.Options.Password,
+		CreatorIP:      c.ClientIP(),
+		AbandonRule:    request.Options.AbandonRule,
+		Correspondence: request.Options.Correspondence,
+	})
+	if err != nil {
+		renderAPIGameError(c, err)
+		return
+	}
+	announceGameLifecycle("game_created", gameData)
+
+	if request.Emoji != "" {
+		if err := game.AddPlayerToGame(gameData, playerID, request.Emoji, request.Name); err != nil {
+			renderAPIGameError(c, err)
+			return
+		}
+		announcePlayerJoined(gameData, playerID)
+	}
+
+	c.JSON(http.StatusCreated, newAPIGame(c, gameData, playerID))
+}
+
+// APIJoinGameHandler joins the requesting player to a game from JSON
+func APIJoinGameHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderAPIError(c, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	var request joinGameRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Emoji is required")
+		return
+	}
+
+	playerID := getPlayerIDFromContext(c)
+	request.Name = defaultBotName(playerID, request.Name)
+	if game.RequiresPassword(gameData, playerID) && !game.CheckPassword(gameData, request.Password) {
+		renderAPIError(c, http.StatusForbidden, "Incorrect password")
+		return
+	}
+
+	if err := game.AddPlayerToGame(gameData, playerID, request.Emoji, request.Name); err != nil {
+		renderAPIGameError(c, err)
+		return
+	}
+	recordJoinSource(c, gameData)
+	announcePlayerJoined(gameData, playerID)
+
+	c.JSON(http.StatusOK, newAPIGame(c, gameData, playerID))
+}
+
+// APIGetGameHandler returns the current state of a game as JSON
+func APIGetGameHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderAPIError(c, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	// The state is rendered for the player in the cookie, and pollers
+	// revalidate with the ETag instead of downloading it again
+	playerID := getPlayerIDFromContext(c)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Vary", "Cookie")
+	if notModified(c, gameData, "api", playerID) {
+		return
+	}
+	c.JSON(http.StatusOK, newAPIGame(c, gameData, playerID))
+}
+
+type moveRequest struct {
+	Row *int `json:"row" binding:"required"`
+	Col *int `json:"col" binding:"required"`
+}
+
+// APIMoveHandler makes a move from JSON and returns the updated game state
+func APIMoveHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderAPIError(c, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	var request moveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Row and col are required")
+		return
+	}
+
+	playerID := getPlayerIDFromContext(c)
+	if err := applyMove(gameData, playerID, *request.Row, *request.Col); err != nil {
+		renderAPIGameError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAPIGame(c, gameData, playerID))
+}
+
+// apiEvent is a structured game event for non-HTMX clients. Every event
+// carries the full game state as seen by the receiving player.
+type apiEvent struct {
+	ID           uint64           `json:"id,omitempty"`
+	Type         string           `json:"type"`
+	GameID       string           `json:"gameId,omitempty"`
+	Game         *apiGame         `json:"game,omitempty"`
+	Chat         *apiChatMessage  `json:"chat,omitempty"`
+	Announcement *apiAnnouncement `json:"announcement,omitempty"` // absent on an announcement event when it was cleared
+	Error        string           `json:"error,omitempty"`
+}
+
+// apiChatMessage is the JSON form of a chat message
+type apiChatMessage struct {
+	Emoji  string    `json:"emoji"`
+	Name   string    `json:"name,omitempty"`
+	Text   string    `json:"text"`
+	SentAt time.Time `json:"sentAt"`
+}
+
+// newAPIEvent converts a broadcast event into its JSON form
+func newAPIEvent(c *gin.Context, event models.GameEvent, playerID string) apiEvent {
+	gameData := game.GetGame(event.GameID)
+	if gameData == nil {
+		// A cancelled game is already gone when its subscribers hear about it
+		if event.Type == "game_cancelled" {
+			return apiEvent{ID: event.ID, Type: event.Type, GameID: event.GameID}
+		}
+		return apiEvent{Type: "error", GameID: event.GameID, Error: "Game not found"}
+	}
+
+	view := newAPIGame(c, gameData, playerID)
+	response := apiEvent{ID: event.ID, Type: event.Type, GameID: event.GameID, Game: &view}
+
+	if dataMap, ok := event.Data.(map[string]interface{}); ok {
+		if announcement, ok := dataMap["announcement"].(*models.Announcement); ok && announcement != nil {
+			view := newAPIAnnouncement(*announcement)
+			response.Announcement = &view
+		}
+		if message, ok := dataMap["message"].(models.ChatMessage); ok {
+			response.Chat = &apiChatMessage{
+				Emoji:  message.Emoji,
+				Name:   message.Name,
+				Text:   message.Text,
+				SentAt: message.SentAt,
+			}
+		}
+	}
+	return response
+}
+
+// jsonSSESink writes events to an SSE connection as JSON instead of HTML
+type jsonSSESink struct {
+	c        *gin.Context
+	playerID string
+}
+
+func (s jsonSSESink) Send(event models.GameEvent) error {
+	data, err := json.Marshal(newAPIEvent(s.c, event, s.playerID))
+	if err != nil {
+		return err
+	}
+
+	writeSSEEvent(s.c, event.ID, event.Type, string(data))
+	return s.c.Request.Context().Err()
+}
+
+func (s jsonSSESink) Heartbeat() error {
+	return writeSSEHeartbeat(s.c)
+}
+
+// APIGameEventsHandler streams the game's events as JSON over SSE
+func APIGameEventsHandler(c *gin.Context) {
+	gameID := c.Param("id")
+
+	gameData := game.GetGame(gameID)
+	if gameData == nil {
+		renderAPIError(c, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Access-Control-Allow-Origin", "*")
+
+	// game_status only re-renders HTML, so it is skipped unless asked for
+	eventTypes, err := eventTypeFilter(c, "game_status")
+	if err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Unknown event type")
+		return
+	}
+
+	release, err := acquireStream(c, gameID)
+	if err != nil {
+		data, _ := json.Marshal(apiEvent{Type: "connection_rejected", GameID: gameID, Error: err.Error()})
+		rejectSSEStream(c, string(data))
+		return
+	}
+	defer release()
+
+	playerID := getPlayerIDFromContext(c)
+	subscriber := events.CreateGameSubscriber(gameID, playerID, c.Request.Context())
+	subscriber.EventTypes = eventTypes
+	defer events.RemoveGameSubscriber(subscriber)
+
+	sink := jsonSSESink{c: c, playerID: playerID}
+	if !resumeGameStream(c, subscriber, sink) {
+		if err := sink.Send(models.GameEvent{Type: "initial", GameID: gameID}); err != nil {
+			return
+		}
+	}
+
+	events.Serve(subscriber, sink)
+}
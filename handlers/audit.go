@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"htmx-go-app/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordAudit adds entry to the audit log with the client's IP. Failing to
+// persist it is logged rather than failing the action.
+func recordAudit(c *gin.Context, entry audit.Entry) {
+	entry.IP = c.ClientIP()
+	if entry.Actor == "" {
+		entry.Actor = "anonymous"
+	}
+	if err := audit.Record(entry); err != nil {
+		log.Printf("audit: %v", err)
+	}
+}
+
+// recordAdminAudit records an action taken through the admin API
+func recordAdminAudit(c *gin.Context, action, gameID, target, detail string) {
+	recordAudit(c, audit.Entry{
+		Actor:  audit.ActorAdmin,
+		Action: action,
+		GameID: gameID,
+		Target: target,
+		Detail: detail,
+	})
+}
+
+// AdminAuditLogHandler lists audit entries, newest first, optionally
+// filtered by ?action=, ?actor= and ?game=, and capped by ?limit= (100 by
+// default)
+func AdminAuditLogHandler(c *gin.Context) {
+	action, actor, gameID := c.Query("action"), c.Query("actor"), c.Query("game")
+
+	limit := 100
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			renderAPIError(c, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	entries := audit.Entries(limit, func(e audit.Entry) bool {
+		return (action == "" || e.Action == action) &&
+			(actor == "" || e.Actor == actor) &&
+			(gameID == "" || e.GameID == gameID)
+	})
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"htmx-go-app/audit"
+	"htmx-go-app/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBackupSize bounds an uploaded backup once decompressed
+const maxBackupSize = 256 << 20
+
+// AdminBackupHandler downloads the whole store as a gzipped JSON archive
+func AdminBackupHandler(c *gin.Context) {
+	backup := game.ExportBackup()
+	filename := fmt.Sprintf("tictactoe-backup-%s.json.gz", backup.CreatedAt.UTC().Format("20060102T150405Z"))
+
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	archive := gzip.NewWriter(c.Writer)
+	if err := json.NewEncoder(archive).Encode(backup); err != nil {
+		c.Error(err)
+		return
+	}
+	archive.Close()
+}
+
+// AdminRestoreHandler replaces the store with an archive downloaded from
+// AdminBackupHandler. Plain JSON is accepted as well as gzipped.
+func AdminRestoreHandler(c *gin.Context) {
+	body := bufio.NewReader(c.Request.Body)
+	var reader io.Reader = body
+	if magic, _ := body.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		archive, err := gzip.NewReader(body)
+		if err != nil {
+			renderAPIError(c, http.StatusBadRequest, "Invalid gzip archive")
+			return
+		}
+		defer archive.Close()
+		reader = archive
+	}
+
+	var backup game.Backup
+	if err := json.NewDecoder(io.LimitReader(reader, maxBackupSize)).Decode(&backup); err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Invalid backup")
+		return
+	}
+	restored, err := game.RestoreBackup(backup)
+	if err != nil {
+		renderAPIError(c, http.StatusBadRequest, capitalize(err.Error()))
+		return
+	}
+
+	for _, gameData := range restored {
+		forgetFragments(gameData.ID)
+		scheduleTurnReminder(gameData)
+	}
+	recordAdminAudit(c, audit.ActionBackupRestored, "", "", fmt.Sprintf("%d games from %s", len(restored), backup.CreatedAt.Format(time.RFC3339)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"games":         len(restored),
+		"bots":          len(backup.Bots),
+		"bannedPlayers": len(backup.BannedPlayers),
+		"telegramUsers": len(backup.TelegramUsers),
+	})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"htmx-go-app/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TogglePieceBadgesHandler turns the player's piece badges on or off, for
+// every game they play or watch. The game the toggle was pressed in has its
+// board redrawn along with the button.
+func TogglePieceBadgesHandler(c *gin.Context) {
+	playerID := getPlayerIDFromContext(c)
+	on := !game.PieceBadges(playerID)
+	game.SetPieceBadges(playerID, on)
+
+	gameID := c.PostForm("game")
+	response := renderBadgeToggleHTML(gameID, on)
+	if gameData := game.GetGame(gameID); gameData != nil {
+		response += outOfBand(gameBoardFragment(gameData, playerID))
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, response)
+}
+
+func renderBadgeToggleHTML(gameID string, on bool) string {
+	label, pressed := "🔤 Piece Badges Off", "false"
+	if on {
+		label, pressed = "🔤 Piece Badges On", "true"
+	}
+	return fmt.Sprintf(`<button id="badge-toggle" hx-post="%s" hx-vals='{"game": "%s"}' hx-swap="outerHTML" class="btn btn-secondary" data-testid="badge-toggle" aria-pressed="%s" title="Mark each piece with its seat's letter">%s</button>`,
+		URLPath("/api/preferences/badges"), gameID, pressed, label)
+}
+
+// renderPieceBadgeHTML renders a piece's seat badge, a letter in a shape of
+// its own, or nothing without a badge. Screen readers get the letter from
+// the cell's label instead.
+func renderPieceBadgeHTML(badge string) string {
+	if badge == "" {
+		return ""
+	}
+	return fmt.Sprintf(`<span class="piece-badge piece-badge-%s" aria-hidden="true">%s</span>`, strings.ToLower(badge), badge)
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import "strings"
+
+// BasePath is the prefix the app is mounted under, such as "/tictactoe".
+// It is empty when serving from the site root.
+var BasePath string
+
+// URLPath joins elem onto BasePath, giving a path the browser can request.
+// It is also available to templates as "path".
+func URLPath(elem ...string) string {
+	return BasePath + strings.Join(elem, "")
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"htmx-go-app/audit"
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/webhooks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// botPlayerKey is the context key holding an authenticated bot's player ID
+const botPlayerKey = "botPlayerID"
+
+// apiBot is the JSON view of a bot. The API key and webhook secret are only
+// included in the registration response.
+type apiBot struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	PlayerID      string    `json:"playerId"`
+	WebhookURL    string    `json:"webhookUrl,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	APIKey        string    `json:"apiKey,omitempty"`
+	WebhookSecret string    `json:"webhookSecret,omitempty"`
+}
+
+type registerBotRequest struct {
+	Name       string `json:"name"`
+	WebhookURL string `json:"webhookUrl"`
+}
+
+func newAPIBot(bot *models.Bot) apiBot {
+	return apiBot{
+		ID:         bot.ID,
+		Name:       bot.Name,
+		PlayerID:   bot.PlayerID,
+		WebhookURL: bot.WebhookURL,
+		CreatedAt:  bot.CreatedAt,
+	}
+}
+
+// AuthenticateBot lets JSON API requests carrying a bot's API key as a bearer
+// token act as that bot instead of the player in the cookie. Other requests,
+// including the admin API with its own key, pass through untouched.
+func AuthenticateBot(c *gin.Context) {
+	apiKey, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || !strings.HasPrefix(c.Request.URL.Path, URLPath("/api/v1/")) {
+		c.Next()
+		return
+	}
+
+	bot := game.BotByKey(apiKey)
+	if bot == nil {
+		renderAPIError(c, http.StatusUnauthorized, "Invalid bot API key")
+		c.Abort()
+		return
+	}
+	c.Set(botPlayerKey, bot.PlayerID)
+	c.Next()
+}
+
+// requestPlayerID returns the authenticated bot's player ID, or else the one
+// in the player cookie if its session checked out, without issuing a new
+// cookie
+func requestPlayerID(c *gin.Context) (string, bool) {
+	if playerID := c.GetString(botPlayerKey); playerID != "" {
+		return playerID, true
+	}
+	playerID := c.GetString(sessionPlayerKey)
+	return playerID, playerID != ""
+}
+
+// requireBot returns the bot making the request, answering 401 if there is none
+func requireBot(c *gin.Context) *models.Bot {
+	bot := game.BotForPlayer(c.GetString(botPlayerKey))
+	if bot == nil {
+		renderAPIError(c, http.StatusUnauthorized, "Bot API key required")
+	}
+	return bot
+}
+
+// defaultBotName names a bot's seat after the bot unless the request chose a name
+func defaultBotName(playerID, name string) string {
+	if bot := game.BotForPlayer(playerID); bot != nil && name == "" {
+		return bot.Name
+	}
+	return name
+}
+
+// APIRegisterBotHandler registers a bot and returns its API key, which is
+// shown only this once
+func APIRegisterBotHandler(c *gin.Context) {
+	var request registerBotRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	bot, apiKey, err := game.RegisterBot(request.Name, request.WebhookURL)
+	if err != nil {
+		renderAPIError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	recordAudit(c, audit.Entry{
+		Actor:  bot.PlayerID,
+		Action: audit.ActionBotRegistered,
+		Target: bot.ID,
+		Detail: bot.Name,
+	})
+
+	response := newAPIBot(bot)
+	response.APIKey = apiKey
+	response.WebhookSecret = bot.WebhookSecret
+	c.JSON(http.StatusCreated, response)
+}
+
+// APIBotHandler describes the authenticated bot and the games waiting on its move
+func APIBotHandler(c *gin.Context) {
+	bot := requireBot(c)
+	if bot == nil {
+		return
+	}
+
+	turns := []apiGame{}
+	for _, gameData := range game.GamesAwaitingMove(bot.PlayerID) {
+		turns = append(turns, newAPIGame(c, gameData, bot.PlayerID))
+	}
+	c.JSON(http.StatusOK, gin.H{"bot": newAPIBot(bot), "yourTurn": turns})
+}
+
+// APIBotTurnsHandler streams a your_turn event, carrying the game as JSON,
+// whenever any game is waiting on the authenticated bot. Games already
+// waiting are sent on connect, so a reconnecting bot may see one twice.
+func APIBotTurnsHandler(c *gin.Context) {
+	bot := requireBot(c)
+	if bot == nil {
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	release, err := acquireStream(c, "")
+	if err != nil {
+		data, _ := json.Marshal(apiEvent{Type: "connection_rejected", Error: err.Error()})
+		rejectSSEStream(c, string(data))
+		return
+	}
+	defer release()
+
+	// Subscribe before catching up so a turn arriving in between can't be missed
+	subscriber := events.CreateBotTurnsSubscriber(bot.PlayerID, c.Request.Context())
+	defer events.RemoveGameSubscriber(subscriber)
+
+	sink := jsonSSESink{c: c, playerID: bot.PlayerID}
+	for _, gameData := range game.GamesAwaitingMove(bot.PlayerID) {
+		if err := sink.Send(models.GameEvent{Type: "your_turn", GameID: gameData.ID}); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	events.Serve(subscriber, sink)
+}
+
+// notifyTurn tells the player whose turn it is to move, if they play as a
+// bot or through Telegram
+func notifyTurn(gameData *models.Game) {
+	notifyBotTurn(gameData)
+	notifyTelegramPlayers(gameData)
+	notifyCorrespondenceTurn(gameData)
+}
+
+// notifyBotTurn tells the player to move, if it is a bot, on its turn stream
+// and webhook
+func notifyBotTurn(gameData *models.Game) {
+	bot := game.BotForPlayer(game.GetCurrentPlayerID(gameData))
+	if bot == nil || !game.IsGameActive(gameData) {
+		return
+	}
+
+	events.BroadcastBotTurn(bot.PlayerID, gameData.ID)
+	if bot.WebhookURL != "" {
+		event := newAPIEvent(nil, models.GameEvent{Type: "your_turn", GameID: gameData.ID}, bot.PlayerID)
+		webhooks.Send(bot.WebhookURL, bot.WebhookSecret, webhooks.EventBotTurn, event)
+	}
+}
+
+// AdminListBotsHandler lists registered bots, oldest first
+func AdminListBotsHandler(c *gin.Context) {
+	bots := []apiBot{}
+	for _, bot := range game.ListBots() {
+		bots = append(bots, newAPIBot(bot))
+	}
+	c.JSON(http.StatusOK, gin.H{"bots": bots})
+}
+
+// AdminDeleteBotHandler revokes a bot's API key
+func AdminDeleteBotHandler(c *gin.Context) {
+	if !game.DeleteBot(c.Param("id")) {
+		renderAPIError(c, http.StatusNotFound, "Bot not found")
+		return
+	}
+	recordAdminAudit(c, audit.ActionBotRevoked, "", c.Param("id"), "")
+	c.Status(http.StatusNoContent)
+}
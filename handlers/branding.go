@@ -0,0 +1,34 @@
+package handlers
+
+import "sync"
+
+// Branding is how the site presents itself: the name in the navbar and
+// page metadata, an optional logo and the theme color
+type Branding struct {
+	SiteName   string
+	LogoURL    string // shown next to the name when set
+	ThemeColor string // e.g. "#2c3e50"
+}
+
+// DefaultBranding is used until SetBranding is called
+var DefaultBranding = Branding{SiteName: "Tic-Tac-Toe", ThemeColor: "#2c3e50"}
+
+var (
+	brandingMu sync.RWMutex
+	branding   = DefaultBranding
+)
+
+// SetBranding replaces the branding. Pages pick it up on their next render.
+func SetBranding(b Branding) {
+	brandingMu.Lock()
+	defer brandingMu.Unlock()
+	branding = b
+}
+
+// CurrentBranding returns the branding for the base layout, where it is
+// available as "brand"
+func CurrentBranding() Branding {
+	brandingMu.RLock()
+	defer brandingMu.RUnlock()
+	return branding
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"bytes"
+	"sync"
+)
+
+// fragmentBufferSize fits a rendered board, the largest fragment sent on
+// every move, without growing
+const fragmentBufferSize = 4 << 10
+
+// maxPooledBufferSize keeps buffers that grew for an unusually large
+// fragment from being held on to by the pool. Benchmarks set it below zero
+// to measure the render path without pooling.
+var maxPooledBufferSize = 64 << 10
+
+// fragmentBuffers recycles the buffers fragments and SSE frames are built
+// in, so broadcasting a move doesn't allocate fresh ones per subscriber
+var fragmentBuffers = sync.Pool{
+	New: func() interface{} {
+		return bytes.NewBuffer(make([]byte, 0, fragmentBufferSize))
+	},
+}
+
+// getBuffer returns an empty buffer from the pool; hand it back with putBuffer
+func getBuffer() *bytes.Buffer {
+	return fragmentBuffers.Get().(*bytes.Buffer)
+}
+
+// putBuffer returns b to the pool. Its contents must no longer be in use.
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBufferSize {
+		return
+	}
+	b.Reset()
+	fragmentBuffers.Put(b)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BenchmarkRenderPath renders what a move sends each subscriber: the board
+// and status fragments, each written out as an SSE frame. It runs with the
+// buffer pool and again with every buffer dropped after use, as rendering
+// was before pooling; compare allocs/op between the two.
+func BenchmarkRenderPath(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+	gameData := &models.Game{
+		ID: "bench",
+		Board: models.GameBoard{
+			{"X", "O", ""},
+			{"", "X", ""},
+			{"", "", "O"},
+		},
+		Players: map[string]*models.Player{
+			"player_a": {ID: "player_a", Emoji: "🐱"},
+			"player_b": {ID: "player_b", Emoji: "🚀"},
+		},
+		PlayerOrder: []string{"player_a", "player_b"},
+		Status:      models.GameStatusActive,
+		MoveCount:   4,
+	}
+	symbols := game.Symbols(gameData)
+
+	for _, bench := range []struct {
+		name      string
+		poolLimit int
+	}{
+		{"pooled", maxPooledBufferSize},
+		{"unpooled", -1},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			defer func(limit int) { maxPooledBufferSize = limit }(maxPooledBufferSize)
+			maxPooledBufferSize = bench.poolLimit
+
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				board := renderGameBoardHTML(gameData.ID, gameData.Board, true, symbols, false)
+				status := renderGameStatusHTML(gameData.ID, "player_a", gameData)
+				writeSSEEvent(c, uint64(i+1), "move", board)
+				writeSSEEvent(c, uint64(i+1), "game_status", status)
+				recorder.Body.Reset()
+			}
+		})
+	}
+}
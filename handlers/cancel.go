@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"htmx-go-app/audit"
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CancelGameHandler lets the creator close a game nobody has joined yet.
+// Anyone watching it is told and sent home by GameRemoved, and htmx callers
+// are redirected there too.
+func CancelGameHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	playerID := getPlayerIDFromContext(c)
+	if err := game.CancelGame(gameData, playerID); err != nil {
+		renderGameError(c, err)
+		return
+	}
+
+	broadcastLobbyGameEvent("game_finished", gameData)
+	broadcastLobbyUpdate(gameData.ID)
+	recordAudit(c, audit.Entry{Actor: playerID, Action: audit.ActionGameCancelled, GameID: gameData.ID})
+
+	if c.GetHeader("HX-Request") == "true" {
+		c.Header("HX-Redirect", URLPath("/"))
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GameRemoved closes the event streams of a game that was cancelled, deleted
+// or expired, telling its subscribers with a final game_cancelled event, and
+// forgets its cached fragments. It is meant for game.OnGameRemoved.
+func GameRemoved(gameData *models.Game) {
+	events.CloseGame(gameData.ID, models.GameEvent{
+		Type:   "game_cancelled",
+		GameID: gameData.ID,
+		Data: map[string]interface{}{
+			"status": "cancelled",
+		},
+	})
+	forgetFragments(gameData.ID)
+}
+
+// renderGameCancelledHTML tells a waiting page its game is gone; data-redirect
+// takes the browser back home
+func renderGameCancelledHTML() string {
+	home := URLPath("/")
+	return fmt.Sprintf(`<div class="game-cancelled" data-redirect="%s"><p>This game was cancelled.</p><a href="%s" class="btn btn-primary">Back Home</a></div>`, home, home)
+}
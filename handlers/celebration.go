@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+)
+
+// broadcastCelebration tells every subscriber a game was just won, so both
+// players' pages highlight the winning line and throw confetti at the same
+// time. The line is worked out from the board when rendering, which keeps
+// the event's data to plain values for the event bus.
+func broadcastCelebration(gameData *models.Game) {
+	winner, ok := gameData.Players[gameData.Winner]
+	if !ok {
+		return
+	}
+	events.BroadcastGameEvent(gameData.ID, models.GameEvent{
+		Type:   "celebration",
+		GameID: gameData.ID,
+		Data: map[string]interface{}{
+			"board":  gameData.Board,
+			"winner": gameData.Winner,
+			"emoji":  winner.Emoji,
+		},
+	})
+}
+
+// renderCelebrationHTML renders the celebration region. data-line lists the
+// winning cells as "row-col" pairs for script.js to highlight; data-winner
+// marks the winner's own page.
+func renderCelebrationHTML(board models.GameBoard, emoji string, isWinner bool) string {
+	var cells []string
+	for _, cell := range game.WinningLine(board) {
+		cells = append(cells, fmt.Sprintf("%d-%d", cell[0], cell[1]))
+	}
+
+	attrs := ""
+	if isWinner {
+		attrs = ` data-winner="true"`
+	}
+	return fmt.Sprintf(`<div id="celebration" class="celebration" data-testid="celebration" data-line="%s" data-emoji="%s"%s aria-hidden="true"></div>`,
+		strings.Join(cells, " "), html.EscapeString(emoji), attrs)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"time"
+
+	"htmx-go-app/rng"
+	"htmx-go-app/setting"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChaosLatency, when positive, delays every request by a random time up to
+// it, to try the app out over a slow connection. Development only.
+var ChaosLatency setting.Value[time.Duration]
+
+// InjectLatency applies ChaosLatency before handling the request
+func InjectLatency(c *gin.Context) {
+	latency := ChaosLatency.Get()
+	if latency <= 0 {
+		c.Next()
+		return
+	}
+
+	delay := time.Duration(rng.Intn(int(latency)))
+	select {
+	case <-time.After(delay):
+	case <-c.Request.Context().Done():
+		c.Abort()
+		return
+	}
+	c.Next()
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChatHandler posts the "message" form field to the game's chat and
+// broadcasts it as a chat event. The sender sees it arrive over the event
+// stream like everyone else, so the response has no body.
+func ChatHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	message, err := game.PostChatMessage(gameData, getPlayerIDFromContext(c), c.PostForm("message"))
+	if err != nil {
+		renderGameError(c, err)
+		return
+	}
+
+	events.BroadcastGameEvent(gameData.ID, models.GameEvent{
+		Type:   "chat",
+		GameID: gameData.ID,
+		Data: map[string]interface{}{
+			"message": message,
+		},
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+// renderChatPanelHTML renders the chat history and the form to post to it
+func renderChatPanelHTML(gameData *models.Game) string {
+	response := `<section class="chat-panel" aria-label="Chat"><ul id="chat-messages" class="chat-messages" aria-live="polite">`
+	for _, message := range gameData.Chat {
+		response += renderChatMessageHTML(message)
+	}
+	response += `</ul>`
+	response += fmt.Sprintf(`<form class="chat-form" hx-post="%s" hx-swap="none" hx-on::after-request="if(event.detail.successful) this.reset()">`, URLPath("/api/game/", gameData.ID, "/chat"))
+	response += fmt.Sprintf(`<input type="text" name="message" maxlength="%d" required autocomplete="off" placeholder="Say something…" aria-label="Chat message">`, game.MaxChatMessageLength)
+	response += `<button type="submit" class="btn btn-secondary btn-small">Send</button></form></section>`
+	return response
+}
+
+// renderChatMessageHTML renders one message. Names and text are escaped
+// since both come straight from players.
+func renderChatMessageHTML(message models.ChatMessage) string {
+	author := message.Emoji
+	if message.Name != "" {
+		author += " " + template.HTMLEscapeString(message.Name)
+	}
+	return fmt.Sprintf(`<li class="chat-message"><span class="chat-author">%s</span> <span class="chat-text">%s</span></li>`,
+		author, template.HTMLEscapeString(message.Text))
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"htmx-go-app/audit"
+	"htmx-go-app/engine"
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type correctionRequest struct {
+	ClearCell *engine.Cell `json:"clearCell"` // {"row": 1, "col": 2}
+	Turn      *int         `json:"turn"`      // seat to move
+	Winner    *string      `json:"winner"`    // player ID, or "" for a draw
+}
+
+// AdminCorrectGameHandler edits a live game to repair one a bug has left in
+// a bad state: it can clear a cell, hand the turn to a seat or settle the
+// result. Players see the corrected board straight away.
+func AdminCorrectGameHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderAPIError(c, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	var request correctionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	correction := game.Correction{
+		ClearCell: request.ClearCell,
+		Turn:      request.Turn,
+		Winner:    request.Winner,
+	}
+
+	wasFinished := game.IsGameFinished(gameData)
+	if err := game.CorrectGame(gameData, correction); err != nil {
+		renderAPIGameError(c, err)
+		return
+	}
+	recordAdminAudit(c, audit.ActionGameCorrected, gameData.ID, "", correction.Describe())
+
+	gameID := gameData.ID
+	events.BroadcastGameUpdate(gameID, models.GameEvent{
+		Type:   "game_corrected",
+		GameID: gameID,
+		Data: map[string]interface{}{
+			"board":      gameData.Board,
+			"nextTurn":   gameData.CurrentTurn,
+			"nextPlayer": game.GetCurrentPlayerID(gameData),
+		},
+	}, gameData, func(playerID string) string {
+		return gameStatusFragment(gameData, playerID)
+	})
+	if game.IsGameFinished(gameData) && !wasFinished {
+		announceGameLifecycle("game_finished", gameData)
+	}
+	scheduleTurnReminder(gameData)
+	notifyTurn(gameData)
+
+	c.JSON(http.StatusOK, newAdminGame(c, gameData, events.Snapshot().GameSubscribers[gameID]))
+}
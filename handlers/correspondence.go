@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/render"
+	"htmx-go-app/webhooks"
+)
+
+// turnNotice is the data of a game.turn webhook. It names the player to move
+// by label rather than playerID, which doubles as their session cookie.
+type turnNotice struct {
+	gameSummary
+	PlayerToMove string `json:"playerToMove"`
+	URL          string `json:"url,omitempty"`
+}
+
+// notifyCorrespondenceTurn tells the configured webhooks that a
+// correspondence game is waiting on a player, so the receiving service can
+// email or push the player. Telegram-linked players are already told by
+// notifyTelegramPlayers.
+func notifyCorrespondenceTurn(gameData *models.Game) {
+	if !gameData.Correspondence || !game.IsGameActive(gameData) {
+		return
+	}
+
+	webhooks.Notify(webhooks.EventGameTurn, turnNotice{
+		gameSummary:  newGameSummary(gameData),
+		PlayerToMove: render.PlayerLabel(gameData, game.GetCurrentPlayerID(gameData)),
+		URL:          notifyURL("/game/" + gameData.ID),
+	})
+}
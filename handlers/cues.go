@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"htmx-go-app/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ToggleCuesHandler turns the player's sound and vibration cues off or back
+// on, for every game they play
+func ToggleCuesHandler(c *gin.Context) {
+	playerID := getPlayerIDFromContext(c)
+	muted := !game.CuesMuted(playerID)
+	game.SetCuesMuted(playerID, muted)
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, renderCueToggleHTML(muted))
+}
+
+func renderCueToggleHTML(muted bool) string {
+	label, pressed := "🔔 Sounds On", "false"
+	if muted {
+		label, pressed = "🔕 Sounds Off", "true"
+	}
+	return fmt.Sprintf(`<button id="cue-toggle" hx-post="%s" hx-swap="outerHTML" class="btn btn-secondary" data-testid="cue-toggle" aria-pressed="%s">%s</button>`,
+		URLPath("/api/preferences/cues"), pressed, label)
+}
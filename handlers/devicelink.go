@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateDeviceLinkHandler gives a player a link to carry on the game in
+// another browser, such as their phone, as the same player
+func CreateDeviceLinkHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	playerID, _ := requestPlayerID(c)
+	link := game.CreateDeviceLink(gameData, playerID)
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, renderDeviceLinkHTML(absoluteURL(c, "/continue/"+link.Token), link, viewerLocale(c)))
+}
+
+// DeviceLinkRedeemHandler signs this browser in as the player who made the
+// link and opens their game. Both browsers then play and follow the game
+// as that player.
+func DeviceLinkRedeemHandler(c *gin.Context) {
+	link, err := game.RedeemDeviceLink(c.Param("token"))
+	if err != nil {
+		renderGameError(c, err)
+		return
+	}
+
+	signIn(c, link.PlayerID)
+	c.Redirect(http.StatusSeeOther, URLPath("/game/", link.GameID))
+}
+
+func renderDeviceLinkHTML(linkURL string, link *models.DeviceLink, locale Locale) string {
+	return fmt.Sprintf(`<div id="device-link" class="game-invite" data-testid="device-link"><input type="text" class="url-input" value="%s" readonly onclick="this.select()"><p>Open this on your other device · single use · expires at %s · <a href="%s">Manage devices</a></p></div>`,
+		html.EscapeString(linkURL), locale.FormatClock(link.ExpiresAt), URLPath("/sessions"))
+}
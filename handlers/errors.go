@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"strings"
+
+	"htmx-go-app/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorPage describes the default title and message shown for a status code
+type errorPage struct {
+	Title   string
+	Message string
+}
+
+var errorPages = map[int]errorPage{
+	http.StatusBadRequest: {
+		Title:   "Bad Request",
+		Message: "The request could not be understood.",
+	},
+	http.StatusForbidden: {
+		Title:   "Not Your Game",
+		Message: "You are not a player in this game.",
+	},
+	http.StatusNotFound: {
+		Title:   "Game Not Found",
+		Message: "The game you're looking for doesn't exist or has expired.",
+	},
+	http.StatusConflict: {
+		Title:   "Conflict",
+		Message: "The game changed before your request could be applied.",
+	},
+	http.StatusTooManyRequests: {
+		Title:   "Slow Down",
+		Message: "You're doing that too often. Please wait a moment.",
+	},
+	http.StatusRequestEntityTooLarge: {
+		Title:   "Too Large",
+		Message: "That request was too large for the server to accept.",
+	},
+	http.StatusGone: {
+		Title:   "Link Expired",
+		Message: "This link is no longer valid. Ask for a new one.",
+	},
+	http.StatusInternalServerError: {
+		Title:   "Something Went Wrong",
+		Message: "An unexpected error occurred. Please try again.",
+	},
+	http.StatusServiceUnavailable: {
+		Title:   "Down for Maintenance",
+		Message: "New games are paused while we do some maintenance. Games in progress can still be finished. Please check back soon.",
+	},
+}
+
+// renderError responds with a full error page, or with an error fragment
+// for HTMX requests. An empty message falls back to the status default.
+func renderError(c *gin.Context, status int, message string) {
+	page, ok := errorPages[status]
+	if !ok {
+		page = errorPage{Title: http.StatusText(status)}
+	}
+	if message == "" {
+		message = page.Message
+	}
+
+	// There are no pages to show errors on in headless mode
+	if Headless {
+		renderAPIError(c, status, message)
+		return
+	}
+
+	if c.GetHeader("HX-Request") == "true" {
+		// Error fragments are swapped into the layout's error region
+		c.Header("HX-Retarget", "#error-message")
+		c.Header("HX-Reswap", "innerHTML")
+		c.Header("Content-Type", "text/html")
+		c.String(status, renderErrorFragmentHTML(status, page.Title, message))
+		return
+	}
+
+	c.HTML(status, "error.html", gin.H{
+		"Title":      page.Title,
+		"StatusCode": status,
+		"Message":    message,
+	})
+}
+
+func renderErrorFragmentHTML(status int, title, message string) string {
+	return fmt.Sprintf(`<div class="error-fragment" role="alert" data-status="%d"><strong>%s</strong> %s</div>`,
+		status, html.EscapeString(title), html.EscapeString(message))
+}
+
+func renderNotFound(c *gin.Context) {
+	renderError(c, http.StatusNotFound, "")
+}
+
+func renderForbidden(c *gin.Context) {
+	renderError(c, http.StatusForbidden, "")
+}
+
+func renderBadRequest(c *gin.Context, message string) {
+	renderError(c, http.StatusBadRequest, message)
+}
+
+func renderConflict(c *gin.Context, message string) {
+	renderError(c, http.StatusConflict, message)
+}
+
+func renderInternalError(c *gin.Context, err error) {
+	log.Printf("internal error on %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	renderError(c, http.StatusInternalServerError, "")
+}
+
+// gameErrorStatus maps errors from the game package to an HTTP status
+func gameErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, game.ErrGameFull),
+		errors.Is(err, game.ErrPlayerAlreadyInGame),
+		errors.Is(err, game.ErrEmojiTaken),
+		errors.Is(err, game.ErrGameAlreadyStarted),
+		errors.Is(err, game.ErrInviteUsed),
+		errors.Is(err, game.ErrGameNotActive),
+		errors.Is(err, game.ErrNotYourTurn),
+		errors.Is(err, game.ErrCellOccupied),
+		errors.Is(err, game.ErrNudgeOwnTurn),
+		errors.Is(err, game.ErrAlreadyNudged),
+		errors.Is(err, game.ErrGameNotPaused),
+		errors.Is(err, game.ErrPauseAlreadyAsked),
+		errors.Is(err, game.ErrNoPauseAsked):
+		return http.StatusConflict
+	case errors.Is(err, game.ErrChatRateLimited),
+		errors.Is(err, game.ErrTooManyOpenGames):
+		return http.StatusTooManyRequests
+	case errors.Is(err, game.ErrNotAPlayer),
+		errors.Is(err, game.ErrNotCreator):
+		return http.StatusForbidden
+	case errors.Is(err, game.ErrInviteNotFound),
+		errors.Is(err, game.ErrDeviceLinkNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, game.ErrInviteExpired),
+		errors.Is(err, game.ErrDeviceLinkExpired):
+		return http.StatusGone
+	case errors.Is(err, game.ErrInvalidEmoji),
+		errors.Is(err, game.ErrInvalidVisibility),
+		errors.Is(err, game.ErrInvalidAbandonRule),
+		errors.Is(err, game.ErrInvalidPassword),
+		errors.Is(err, game.ErrInvalidName),
+		errors.Is(err, game.ErrInvalidCell),
+		errors.Is(err, game.ErrEmptyMessage),
+		errors.Is(err, game.ErrMessageTooLong),
+		errors.Is(err, game.ErrInvalidScenario),
+		errors.Is(err, game.ErrInvalidCorrection):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// renderGameError maps errors from the game package to the matching error response
+func renderGameError(c *gin.Context, err error) {
+	status := gameErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		renderInternalError(c, err)
+		return
+	}
+	renderError(c, status, capitalize(err.Error()))
+}
+
+// renderAPIError responds with a JSON error body for the JSON API
+func renderAPIError(c *gin.Context, status int, message string) {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	c.JSON(status, gin.H{"error": message})
+}
+
+// isJSONAPIRequest reports whether the request is for the JSON API, whose
+// errors are JSON bodies rather than error pages
+func isJSONAPIRequest(c *gin.Context) bool {
+	if Headless {
+		return true
+	}
+	path := c.Request.URL.Path
+	return strings.HasPrefix(path, URLPath("/api/v1/")) || strings.HasPrefix(path, URLPath("/api/admin/"))
+}
+
+// renderAPIGameError is the JSON API counterpart of renderGameError
+func renderAPIGameError(c *gin.Context, err error) {
+	status := gameErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("internal error on %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		renderAPIError(c, status, "")
+		return
+	}
+	renderAPIError(c, status, err.Error())
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// NotFoundHandler renders the 404 page for unknown routes
+func NotFoundHandler(c *gin.Context) {
+	renderError(c, http.StatusNotFound, "The page you're looking for doesn't exist.")
+}
+
+// RecoveryHandler renders the 500 page when a handler panics
+func RecoveryHandler(c *gin.Context, recovered any) {
+	renderInternalError(c, fmt.Errorf("panic: %v", recovered))
+	c.Abort()
+}
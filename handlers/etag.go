@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gameETag tags one representation of a game as viewerID sees it. Every
+// change to a game bumps its Version, and the viewer's preferences are the
+// only other input to what they are shown, so the tag is known before
+// anything is rendered. Images, which look the same to everyone, pass an
+// empty viewerID. The viewer is hashed in rather than spelled out so the
+// tag doesn't carry their player ID.
+func gameETag(gameData *models.Game, representation, viewerID string) string {
+	h := sha256.New()
+	for _, part := range []string{
+		gameData.ID,
+		strconv.Itoa(gameData.Version),
+		representation,
+		viewerID,
+		strconv.FormatBool(game.CuesMuted(viewerID)),
+		strconv.FormatBool(game.PieceBadges(viewerID)),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return `"` + base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:12]) + `"`
+}
+
+// notModified tags the response with gameETag. Pollers that send the tag
+// back in If-None-Match get an empty 304 until something changes, and
+// notModified reports true so the caller can skip rendering altogether.
+func notModified(c *gin.Context, gameData *models.Game, representation, viewerID string) bool {
+	etag := gameETag(gameData, representation, viewerID)
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using the
+// weak comparison conditional GETs call for
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/render"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedSize is how many finished games the archive feed lists
+const feedSize = 50
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated time.Time   `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID        string    `xml:"id"`
+	Title     string    `xml:"title"`
+	Updated   time.Time `xml:"updated"`
+	Published time.Time `xml:"published"`
+	Link      atomLink  `xml:"link"`
+	Summary   string    `xml:"summary"`
+}
+
+// ArchiveFeedHandler serves an Atom feed of the most recently finished
+// public games, newest first, each linking to its result page
+func ArchiveFeedHandler(c *gin.Context) {
+	var finished []*models.Game
+	for _, gameData := range game.FinishedGames(time.Time{}, time.Time{}, "") {
+		if gameData.Visibility == models.VisibilityPublic {
+			finished = append(finished, gameData)
+		}
+	}
+	if len(finished) > feedSize {
+		finished = finished[len(finished)-feedSize:]
+	}
+
+	feedURL := absoluteURL(c, "/archive/feed.atom")
+	feed := atomFeed{
+		ID:      feedURL,
+		Title:   "Tic-Tac-Toe: finished games",
+		Updated: clock.Now().UTC(),
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: feedURL},
+			{Rel: "alternate", Type: "text/html", Href: absoluteURL(c, "/")},
+		},
+		Author: atomAuthor{Name: "Tic-Tac-Toe"},
+	}
+	if len(finished) > 0 {
+		feed.Updated = finished[len(finished)-1].FinishedAt.UTC()
+	}
+	for i := len(finished) - 1; i >= 0; i-- {
+		gameData := finished[i]
+		resultURL := absoluteURL(c, "/game/"+gameData.ID+"/result")
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:        resultURL,
+			Title:     render.ResultText(gameData),
+			Updated:   gameData.FinishedAt.UTC(),
+			Published: gameData.FinishedAt.UTC(),
+			Link:      atomLink{Rel: "alternate", Type: "text/html", Href: resultURL},
+			Summary:   gamePlayersText(gameData) + " · " + render.DurationText(gameData),
+		})
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		renderInternalError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), body...))
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GameFragmentHandler returns one section of the game page, as rendered for
+// the requesting player: board, status, players or move-order. The client re-requests
+// them after its event stream reconnects, in case it missed updates.
+func GameFragmentHandler(c *gin.Context) {
+	gameID := c.Param("id")
+	gameData := game.GetGame(gameID)
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	// The player cards also show who is connected and records from other
+	// games, which the game's Version doesn't follow, so they go untagged
+	section := c.Param("section")
+	versioned := true
+	var render func(gameData *models.Game, playerID string) string
+	switch section {
+	case "board":
+		render = gameBoardFragment
+	case "status":
+		render = gameStatusFragment
+	case "players":
+		render, versioned = renderPlayersHTML, false
+	case "move-order":
+		render = func(gameData *models.Game, _ string) string { return renderMoveOrderHTML(gameData) }
+	default:
+		renderNotFound(c)
+		return
+	}
+
+	playerID := getPlayerIDFromContext(c)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Vary", "Cookie")
+	if versioned && notModified(c, gameData, "fragment/"+section, playerID) {
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(render(gameData, playerID)))
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"sync"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+)
+
+// The board and status fragments only depend on the game, on whether the
+// viewer is the player to move, on whether they want piece badges and, for
+// the status, on the viewer's cue: the opponent and every spectator see the
+// same thing. They are cached per game, version, viewer role and these
+// preferences, so a broadcast to a crowded game renders each view once
+// instead of once per subscriber. Every change to a game bumps its
+// Version, which leaves the previous renderings behind.
+
+// viewerRole is how a fragment's viewer relates to the game's turn
+type viewerRole int
+
+const (
+	viewerWaiting viewerRole = iota // the opponent, a spectator or anyone else
+	viewerToMove                    // the player whose turn it is
+)
+
+type fragmentKey struct {
+	section string
+	role    viewerRole
+	cue     string // the status fragment's cue, which differs between the players of a finished game
+	muted   bool
+	resume  bool // whether the status offers to resume a paused game, which only players may
+	badges  bool // whether the board marks pieces with their seat's letter
+}
+
+// gameFragments holds one version's renderings of a game
+type gameFragments struct {
+	version   int
+	fragments map[fragmentKey]string
+}
+
+var (
+	fragmentCacheMu sync.Mutex
+	fragmentCache   = make(map[string]*gameFragments) // game ID -> renderings
+	// fragmentCachePruneAt is the size at which games that are gone are dropped
+	fragmentCachePruneAt = 256
+
+	fragmentRenders   uint64 // fragments rendered because the cache had none
+	fragmentCacheHits uint64 // fragments served from the cache
+)
+
+// roleOf returns playerID's view of the game
+func roleOf(gameData *models.Game, playerID string) viewerRole {
+	if game.IsPlayersTurn(gameData, playerID) {
+		return viewerToMove
+	}
+	return viewerWaiting
+}
+
+// gameBoardFragment renders the game's current board as playerID sees it
+func gameBoardFragment(gameData *models.Game, playerID string) string {
+	role := roleOf(gameData, playerID)
+	badges := game.PieceBadges(playerID)
+	return cachedFragment(gameData, fragmentKey{section: "board", role: role, badges: badges}, func() string {
+		return renderGameBoardHTML(gameData.ID, gameData.Board, role == viewerToMove, game.Symbols(gameData), badges)
+	})
+}
+
+// gameStatusFragment renders the game's current status as playerID sees it
+func gameStatusFragment(gameData *models.Game, playerID string) string {
+	if gameData == nil {
+		return renderGameStatusHTML("", playerID, nil)
+	}
+	key := fragmentKey{
+		section: "status",
+		role:    roleOf(gameData, playerID),
+		cue:     cueFor(gameData, playerID),
+		muted:   game.CuesMuted(playerID),
+		resume:  gameData.Status == models.GameStatusPaused && isGamePlayer(gameData, playerID),
+	}
+	return cachedFragment(gameData, key, func() string {
+		return renderGameStatusHTML(gameData.ID, playerID, gameData)
+	})
+}
+
+// eventBoardFragment renders the board carried by an event. Events replayed
+// after the game has moved on show an older board, which is not cached.
+func eventBoardFragment(gameID string, board models.GameBoard, playerID string) string {
+	gameData := game.GetGame(gameID)
+	if gameData != nil && gameData.Board == board {
+		return gameBoardFragment(gameData, playerID)
+	}
+	return renderGameBoardHTML(gameID, board, canPlayerMove(gameID, playerID), game.Symbols(gameData), game.PieceBadges(playerID))
+}
+
+// cachedFragment returns the fragment cached under key for the game's
+// current version, rendering and caching it if there is none
+func cachedFragment(gameData *models.Game, key fragmentKey, render func() string) string {
+	fragmentCacheMu.Lock()
+	defer fragmentCacheMu.Unlock()
+
+	cached := fragmentCache[gameData.ID]
+	if cached != nil && cached.version == gameData.Version {
+		if fragment, ok := cached.fragments[key]; ok {
+			fragmentCacheHits++
+			return fragment
+		}
+	} else {
+		cached = &gameFragments{version: gameData.Version, fragments: make(map[fragmentKey]string)}
+		fragmentCache[gameData.ID] = cached
+		pruneFragmentCache()
+	}
+
+	fragment := render()
+	fragmentRenders++
+	cached.fragments[key] = fragment
+	return fragment
+}
+
+// pruneFragmentCache drops the renderings of deleted games once the cache
+// has grown to fragmentCachePruneAt, and then waits for it to double before
+// looking again. Callers hold fragmentCacheMu.
+func pruneFragmentCache() {
+	if len(fragmentCache) < fragmentCachePruneAt {
+		return
+	}
+	for gameID := range fragmentCache {
+		if game.GetGame(gameID) == nil {
+			delete(fragmentCache, gameID)
+		}
+	}
+	fragmentCachePruneAt = max(256, 2*len(fragmentCache))
+}
+
+// forgetFragments drops the renderings of a game that is gone
+func forgetFragments(gameID string) {
+	fragmentCacheMu.Lock()
+	defer fragmentCacheMu.Unlock()
+	delete(fragmentCache, gameID)
+}
+
+// fragmentCacheStats returns how many fragments were rendered and how many
+// were served from the cache
+func fragmentCacheStats() (renders, hits uint64) {
+	fragmentCacheMu.Lock()
+	defer fragmentCacheMu.Unlock()
+	return fragmentRenders, fragmentCacheHits
+}
@@ -2,40 +2,71 @@ package handlers
 
 import (
 	"fmt"
+	"html"
+	"html/template"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"htmx-go-app/audit"
+	"htmx-go-app/clock"
+	"htmx-go-app/emojis"
 	"htmx-go-app/events"
 	"htmx-go-app/game"
 	"htmx-go-app/models"
+	"htmx-go-app/setting"
 
 	"github.com/gin-gonic/gin"
 )
 
-
+// Player cookie settings, overridden from the server configuration
+var (
+	PlayerCookieMaxAge = setting.New(24 * time.Hour)
+	SecureCookies      = false
+)
 
 func getPlayerIDFromContext(c *gin.Context) string {
-	// Simple approach: use session cookie or generate new ID
-	playerID, err := c.Cookie("player_id")
-	if err != nil || playerID == "" {
+	// Simple approach: use the bot's identity, the session cookie or a new ID
+	playerID, ok := requestPlayerID(c)
+	if !ok {
 		playerID = game.GeneratePlayerID()
-		c.SetCookie("player_id", playerID, 3600*24, "/", "", false, true)
+		signIn(c, playerID)
 	}
 	return playerID
 }
 
-
 func HomeHandler(c *gin.Context) {
 	data := gin.H{
-		"Title": "Tic-Tac-Toe Game",
+		"Title":        "Tic-Tac-Toe Game",
+		"AbandonAfter": game.AbandonAfter.Get(),
 	}
 
 	c.HTML(http.StatusOK, "home.html", data)
 }
 
 func NewGameHandler(c *gin.Context) {
-	newGame := game.CreateGame()
-	c.Redirect(http.StatusSeeOther, "/game/"+newGame.ID+"/select-emoji")
+	// Options come from the query string (plain link) or the advanced creation form
+	visibility := models.VisibilityPublic
+	if c.Request.FormValue("visibility") == string(models.VisibilityPrivate) {
+		visibility = models.VisibilityPrivate
+	}
+
+	newGame, err := game.CreateGame(getPlayerIDFromContext(c), models.GameOptions{
+		Visibility:     visibility,
+		Password:       c.PostForm("password"),
+		CreatorIP:      c.ClientIP(),
+		AbandonRule:    models.AbandonRule(c.Request.FormValue("abandon_rule")),
+		Correspondence: c.Request.FormValue("correspondence") == "true",
+	})
+	if err != nil {
+		renderGameError(c, err)
+		return
+	}
+	announceGameLifecycle("game_created", newGame)
+
+	c.Redirect(http.StatusSeeOther, URLPath("/game/", newGame.ID, "/select-emoji"))
 }
 
 func GamePageHandler(c *gin.Context) {
@@ -43,9 +74,7 @@ func GamePageHandler(c *gin.Context) {
 	gameData := game.GetGame(gameID)
 
 	if gameData == nil {
-		c.HTML(http.StatusNotFound, "404.html", gin.H{
-			"Title": "Game Not Found",
-		})
+		renderNotFound(c)
 		return
 	}
 
@@ -55,25 +84,17 @@ func GamePageHandler(c *gin.Context) {
 
 	if !playerExists || player.Emoji == "" {
 		// Redirect to emoji selection
-		c.Redirect(http.StatusSeeOther, "/game/"+gameID+"/select-emoji")
+		c.Redirect(http.StatusSeeOther, URLPath("/game/", gameID, "/select-emoji"))
 		return
 	}
 
 	// Only allow access when game is ready (2 players)
 	if !game.IsGameReady(gameData) {
 		// Redirect back to emoji selection (will show waiting state if needed)
-		c.Redirect(http.StatusSeeOther, "/game/"+gameID+"/select-emoji")
+		c.Redirect(http.StatusSeeOther, URLPath("/game/", gameID, "/select-emoji"))
 		return
 	}
 
-	// Get player list for display
-	var playerEmojis []string
-	for _, pID := range gameData.PlayerOrder {
-		if p, exists := gameData.Players[pID]; exists {
-			playerEmojis = append(playerEmojis, p.Emoji)
-		}
-	}
-
 	// Get current turn information
 	currentTurnPlayerID := game.GetCurrentPlayerID(gameData)
 	var currentTurnEmoji string
@@ -94,7 +115,7 @@ func GamePageHandler(c *gin.Context) {
 	data := gin.H{
 		"Title":            "Tic-Tac-Toe Game #" + gameID,
 		"GameID":           gameID,
-		"PlayerEmojis":     playerEmojis,
+		"PlayersHTML":      template.HTML(renderPlayersHTML(gameData, playerID)),
 		"CurrentPlayer":    player,
 		"GameStatus":       gameData.Status,
 		"CurrentTurnEmoji": currentTurnEmoji,
@@ -102,88 +123,74 @@ func GamePageHandler(c *gin.Context) {
 		"WinnerEmoji":      winnerEmoji,
 		"IsGameActive":     game.IsGameActive(gameData),
 		"IsGameFinished":   game.IsGameFinished(gameData),
+		"BoardHTML":        template.HTML(gameBoardFragment(gameData, playerID)),
+		"ChatHTML":         template.HTML(renderChatPanelHTML(gameData)),
+		"MoveOrderHTML":    template.HTML(renderMoveOrderHTML(gameData)),
+		"SummaryHTML":      template.HTML(renderGameSummaryHTML(gameData)),
+		"AbandonedHTML":    template.HTML(renderAbandonedHTML(gameData, playerID)),
+		"PauseRequestHTML": template.HTML(renderPauseRequestHTML(gameData, gameData.PauseAsker, playerID)),
+		"CueToggleHTML":    template.HTML(renderCueToggleHTML(game.CuesMuted(playerID))),
+		"BadgeToggleHTML":  template.HTML(renderBadgeToggleHTML(gameData.ID, game.PieceBadges(playerID))),
+		"Meta":             gameMeta(c, gameData),
 	}
 
 	c.HTML(http.StatusOK, "game.html", data)
 }
 
+// EmojiSelectionHandler serves the join page in whatever state the visitor's
+// join is in. A seated player whose game has started goes straight to it.
 func EmojiSelectionHandler(c *gin.Context) {
-	gameID := c.Param("id")
-	gameData := game.GetGame(gameID)
-
+	gameData := game.GetGame(c.Param("id"))
 	if gameData == nil {
-		c.HTML(http.StatusNotFound, "404.html", gin.H{
-			"Title": "Game Not Found",
-		})
+		renderNotFound(c)
 		return
 	}
 
-	playerID := getPlayerIDFromContext(c)
-
-	// Check if game is full
-	if !game.CanJoinGame(gameData) {
-		// Check if this player is already in the game
-		if _, exists := gameData.Players[playerID]; !exists {
-			c.HTML(http.StatusOK, "game-full.html", gin.H{
-				"Title": "Game Full",
-			})
-			return
-		}
-	}
-
-	// If player already has emoji selected
-	if player, exists := gameData.Players[playerID]; exists && player.Emoji != "" {
-		// Check if this is the first player and game is still waiting
-		if game.IsFirstPlayer(gameData, playerID) && gameData.Status == models.GameStatusWaiting {
-			// Show waiting state
-			scheme := "http"
-			if c.Request.TLS != nil {
-				scheme = "https"
-			}
-			host := c.Request.Host
-			gameURL := fmt.Sprintf("%s://%s/game/%s", scheme, host, gameID)
-
-			data := gin.H{
-				"Title":          "Waiting for Opponent",
-				"GameID":         gameID,
-				"GameURL":        gameURL,
-				"SelectedEmoji":  player.Emoji,
-				"IsWaitingState": true,
-				"IsFirstPlayer":  true,
-			}
-			c.HTML(http.StatusOK, "emoji-selection.html", data)
-			return
-		}
-
-		// If game is ready, redirect to game
-		if game.IsGameReady(gameData) {
-			c.Redirect(http.StatusSeeOther, "/game/"+gameID)
-			return
-		}
-	}
-
-	// Get available emojis (not taken by other players)
-	var availableEmojiList []map[string]interface{}
-	for _, emoji := range models.AvailableEmojis {
-		available := game.IsEmojiAvailable(gameData, emoji)
-		availableEmojiList = append(availableEmojiList, map[string]interface{}{
-			"emoji":     emoji,
-			"available": available,
-		})
+	if joinStateOf(gameData, getPlayerIDFromContext(c)) == joinReady {
+		c.Redirect(http.StatusSeeOther, URLPath("/game/", gameData.ID))
+		return
 	}
+	renderJoinPage(c, http.StatusOK, "emoji-selection.html", gameData, "")
+}
 
-	// Determine if this would be the first player
-	wouldBeFirst := len(gameData.Players) == 0
+// renderJoinPage renders the join page, or with name "join-state.html" just
+// its state fragment, for the visitor's current join state. passwordError
+// is shown under the password prompt while selecting.
+func renderJoinPage(c *gin.Context, status int, name string, gameData *models.Game, passwordError string) {
+	playerID := getPlayerIDFromContext(c)
+	state := joinStateOf(gameData, playerID)
 
 	data := gin.H{
-		"Title":           "Select Your Emoji",
-		"GameID":          gameID,
-		"AvailableEmojis": availableEmojiList,
-		"IsWaitingState":  false,
-		"IsFirstPlayer":   wouldBeFirst,
-	}
-
-	c.HTML(http.StatusOK, "emoji-selection.html", data)
+		"GameID":    gameData.ID,
+		"JoinState": state,
+		"Meta":      gameMeta(c, gameData),
+	}
+	switch state {
+	case joinSelecting:
+		data["Title"] = "Select Your Emoji"
+		// Emojis other players have picked are shown but can't be chosen
+		data["AvailableEmojis"] = emojis.Choices(func(emoji string) bool {
+			return !game.IsEmojiAvailable(gameData, emoji)
+		})
+		data["CustomSymbols"] = emojis.CustomAllowed()
+		data["IsFirstPlayer"] = len(gameData.Players) == 0
+		data["RequiresPassword"] = game.RequiresPassword(gameData, playerID)
+		data["RulesHTML"] = template.HTML(renderGameRulesHTML(gameData.Visibility, game.HasPassword(gameData), game.AbandonRuleOf(gameData), gameData.Correspondence))
+		data["PasswordError"] = passwordError
+	case joinWaiting:
+		data["Title"] = "Waiting for Opponent"
+		data["GameURL"] = taggedURL(c, "/game/"+gameData.ID, "link")
+		data["EmailURL"] = template.URL("mailto:?subject=" + url.PathEscape("Play tic-tac-toe with me") + "&body=" + url.PathEscape(taggedURL(c, "/game/"+gameData.ID, "email")))
+		data["GameCode"] = gameData.Slug
+		data["SettingsHTML"] = template.HTML(renderGameSettingsHTML(gameData))
+		data["SelectedEmoji"] = gameData.Players[playerID].Emoji
+	case joinReady:
+		data["Title"] = "Opponent Joined"
+	case joinFull:
+		data["Title"] = "Game Full"
+	}
+
+	c.HTML(status, name, data)
 }
 
 func EmojiSelectionSubmitHandler(c *gin.Context) {
@@ -191,42 +198,71 @@ func EmojiSelectionSubmitHandler(c *gin.Context) {
 	gameData := game.GetGame(gameID)
 
 	if gameData == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		renderNotFound(c)
 		return
 	}
 
 	playerID := getPlayerIDFromContext(c)
 	selectedEmoji := c.PostForm("emoji")
+	if selectedEmoji == "" {
+		// Typed into the custom symbol field rather than picked
+		selectedEmoji = c.PostForm("symbol")
+	}
 
 	if selectedEmoji == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No emoji selected"})
+		renderBadRequest(c, "No emoji selected")
 		return
 	}
 
-	isFirstPlayerJoining := len(gameData.Players) == 0
-	err := game.AddPlayerToGame(gameData, playerID, selectedEmoji)
-	isGameReadyNow := gameData.Status == models.GameStatusActive
+	// Protected games need the password before the player is added
+	if game.RequiresPassword(gameData, playerID) && !game.CheckPassword(gameData, c.PostForm("password")) {
+		renderJoinPage(c, http.StatusForbidden, "emoji-selection.html", gameData, "Incorrect password. Please try again.")
+		return
+	}
 
+	err := game.AddPlayerToGame(gameData, playerID, selectedEmoji, c.PostForm("name"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		renderGameError(c, err)
 		return
 	}
 
+	recordJoinSource(c, gameData)
+	announcePlayerJoined(gameData, playerID)
+
+	// The join page takes it from here: the creator waits there, while the
+	// opponent who filled the game is ready to play
+	if joinStateOf(gameData, playerID) == joinReady {
+		c.Redirect(http.StatusSeeOther, URLPath("/game/", gameID))
+		return
+	}
+	c.Redirect(http.StatusSeeOther, URLPath("/game/", gameID, "/select-emoji"))
+}
+
+// announcePlayerJoined broadcasts the events that follow a successful join:
+// the lobby update, player_join, and game_ready once both seats are taken
+func announcePlayerJoined(gameData *models.Game, playerID string) {
+	gameID := gameData.ID
+
+	// Open games appear in (and disappear from) the lobby as players join
+	broadcastLobbyUpdate(gameID)
+
 	// Broadcast player join event
 	events.BroadcastGameEvent(gameID, models.GameEvent{
 		Type:   "player_join",
 		GameID: gameID,
 		Data: map[string]interface{}{
 			"playerID": playerID,
-			"emoji":    selectedEmoji,
+			"emoji":    gameData.Players[playerID].Emoji,
 		},
 	})
+	broadcastPlayersUpdate(gameID)
+	broadcastJoinState(gameID)
+
+	if gameData.Status == models.GameStatusActive {
+		announceGameLifecycle("game_filled", gameData)
+		scheduleTurnReminder(gameData)
+		notifyTurn(gameData)
 
-	if isFirstPlayerJoining {
-		// First player stays in waiting state (will be shown by EmojiSelectionHandler)
-		c.Redirect(http.StatusSeeOther, "/game/"+gameID+"/select-emoji")
-	} else if isGameReadyNow {
-		// Second player joining - game is active, both players enter
 		events.BroadcastGameEvent(gameID, models.GameEvent{
 			Type:   "game_ready",
 			GameID: gameID,
@@ -234,17 +270,12 @@ func EmojiSelectionSubmitHandler(c *gin.Context) {
 				"status": "active",
 			},
 		})
-		c.Redirect(http.StatusSeeOther, "/game/"+gameID)
-	} else {
-		// Fallback
-		c.Redirect(http.StatusSeeOther, "/game/"+gameID+"/select-emoji")
 	}
 }
 
-
 func GameMoveHandler(c *gin.Context) {
 	if c.GetHeader("HX-Request") != "true" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "HTMX request required"})
+		renderBadRequest(c, "HTMX request required")
 		return
 	}
 
@@ -254,79 +285,62 @@ func GameMoveHandler(c *gin.Context) {
 
 	gameData := game.GetGame(gameID)
 	if gameData == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		renderNotFound(c)
 		return
 	}
 
-	// Get player ID and check if player exists
+	// RequireGamePlayer has already checked the player is in the game
 	playerID := getPlayerIDFromContext(c)
-	player, exists := gameData.Players[playerID]
-	if !exists || player.Emoji == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Player not registered"})
-		return
-	}
 
 	row, err := strconv.Atoi(rowStr)
 	if err != nil || row < 0 || row > 2 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid row"})
+		renderBadRequest(c, "Invalid row")
 		return
 	}
 
 	col, err := strconv.Atoi(colStr)
 	if err != nil || col < 0 || col > 2 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid column"})
+		renderBadRequest(c, "Invalid column")
 		return
 	}
 
-	// Check if game is finished
-	if game.IsGameFinished(gameData) {
-		renderGameBoard(c, gameID)
-		return
-	}
+	// Rule violations (finished game, wrong turn, taken cell) leave the game
+	// unchanged, so the player simply gets the current board back
+	_ = applyMove(gameData, playerID, row, col)
 
-	// Check if it's the player's turn
-	if !game.IsPlayersTurn(gameData, playerID) {
-		renderGameBoard(c, gameID)
-		return
-	}
+	renderGameBoard(c, gameID)
+}
 
-	// Check if cell is empty
-	if gameData.Board[row][col] != "" {
-		renderGameBoard(c, gameID)
-		return
+// applyMove makes the move and broadcasts the resulting events to all
+// subscribers. A repeated request for the move just made, as from a
+// double-click, succeeds without doing anything, so the caller answers it with
+// the current game like the original.
+func applyMove(gameData *models.Game, playerID string, row, col int) error {
+	duplicate, err := game.MakeMoveOnce(gameData, playerID, row, col)
+	if err != nil || duplicate {
+		return err
 	}
 
-	// Make the move
-	gameData.Board[row][col] = player.Emoji
-	gameData.MoveCount++
-
-	// Check for winner
-	winnerID := game.CheckWinner(gameData)
-	if winnerID != "" {
-		gameData.Status = models.GameStatusFinished
-		gameData.Winner = winnerID
+	gameID := gameData.ID
 
-		// Broadcast winner event
-		events.BroadcastGameEvent(gameID, models.GameEvent{
+	// Winner, draw or plain move event for the board
+	var event models.GameEvent
+	switch gameData.Status {
+	case models.GameStatusFinished:
+		event = models.GameEvent{
 			Type:   "game_winner",
 			GameID: gameID,
 			Data: map[string]interface{}{
 				"board":    gameData.Board,
-				"winner":   winnerID,
-				"emoji":    gameData.Players[winnerID].Emoji,
+				"winner":   gameData.Winner,
+				"emoji":    gameData.Players[gameData.Winner].Emoji,
 				"playerID": playerID,
 				"row":      row,
 				"col":      col,
 			},
-		})
-
-		// Send personalized game status updates to each player
-		events.BroadcastPersonalizedGameStatus(gameID, gameData)
-	} else if game.IsBoardFull(gameData) {
-		gameData.Status = models.GameStatusDraw
-
-		// Broadcast draw event
-		events.BroadcastGameEvent(gameID, models.GameEvent{
+		}
+	case models.GameStatusDraw:
+		event = models.GameEvent{
 			Type:   "game_draw",
 			GameID: gameID,
 			Data: map[string]interface{}{
@@ -335,67 +349,83 @@ func GameMoveHandler(c *gin.Context) {
 				"row":      row,
 				"col":      col,
 			},
-		})
-
-		// Send personalized game status updates to each player
-		events.BroadcastPersonalizedGameStatus(gameID, gameData)
-	} else {
-		// Switch turns
-		gameData.CurrentTurn = (gameData.CurrentTurn + 1) % 2
-
-		// Broadcast move event
-		events.BroadcastGameEvent(gameID, models.GameEvent{
+		}
+	default:
+		event = models.GameEvent{
 			Type:   "move",
 			GameID: gameID,
 			Data: map[string]interface{}{
 				"board":      gameData.Board,
 				"playerID":   playerID,
-				"emoji":      player.Emoji,
+				"emoji":      gameData.Players[playerID].Emoji,
 				"row":        row,
 				"col":        col,
 				"nextTurn":   gameData.CurrentTurn,
 				"nextPlayer": game.GetCurrentPlayerID(gameData),
 			},
-		})
-
-		// Send personalized game status updates to each player
-		events.BroadcastPersonalizedGameStatus(gameID, gameData)
+		}
 	}
 
-	renderGameBoard(c, gameID)
+	// Broadcast the board together with each player's personalized status
+	events.BroadcastGameUpdate(gameID, event, gameData, func(playerID string) string {
+		return gameStatusFragment(gameData, playerID)
+	})
+
+	if gameData.Status == models.GameStatusFinished {
+		broadcastCelebration(gameData)
+	}
+	if game.IsGameFinished(gameData) {
+		announceGameLifecycle("game_finished", gameData)
+	}
+	scheduleTurnReminder(gameData)
+	notifyTurn(gameData)
+	return nil
 }
 
 func GameResetHandler(c *gin.Context) {
 	if c.GetHeader("HX-Request") != "true" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "HTMX request required"})
+		renderBadRequest(c, "HTMX request required")
 		return
 	}
 
 	gameID := c.Param("id")
 	gameData := game.GetGame(gameID)
 	if gameData == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		renderNotFound(c)
 		return
 	}
 
 	// Reset all game state
+	unlock := game.Lock(gameData)
 	gameData.Board = models.GameBoard{}
 	gameData.Status = models.GameStatusActive
 	gameData.Winner = ""
 	gameData.MoveCount = 0
+	gameData.Moves = nil
 	gameData.CurrentTurn = 0
-
-	// Broadcast reset event to all subscribers
-	events.BroadcastGameEvent(gameID, models.GameEvent{
+	gameData.Nudged = false
+	gameData.AbandonedBy = ""
+	gameData.PauseAsker = ""
+	gameData.Version++
+	gameData.StartedAt = clock.Now()
+	gameData.FinishedAt = time.Time{}
+	unlock()
+
+	// Broadcast the reset board together with each player's personalized status
+	events.BroadcastGameUpdate(gameID, models.GameEvent{
 		Type:   "reset",
 		GameID: gameID,
 		Data: map[string]interface{}{
 			"board": gameData.Board,
 		},
+	}, gameData, func(playerID string) string {
+		return gameStatusFragment(gameData, playerID)
 	})
+	scheduleTurnReminder(gameData)
+	notifyTurn(gameData)
 
-	// Send personalized game status updates to each player
-	events.BroadcastPersonalizedGameStatus(gameID, gameData)
+	playerID, _ := requestPlayerID(c)
+	recordAudit(c, audit.Entry{Actor: playerID, Action: audit.ActionGameReset, GameID: gameID})
 
 	renderGameBoard(c, gameID)
 }
@@ -403,76 +433,93 @@ func GameResetHandler(c *gin.Context) {
 func renderGameBoard(c *gin.Context, gameID string) {
 	gameData := game.GetGame(gameID)
 	if gameData == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		renderNotFound(c)
 		return
 	}
 
-	response := `<div id="game-board" class="game-board">`
-
-	for row := 0; row < 3; row++ {
-		response += `<div class="game-row">`
-		for col := 0; col < 3; col++ {
-			cellValue := gameData.Board[row][col]
-			response += fmt.Sprintf(`<div class="game-cell" hx-post="/api/game/%s/move/%d/%d" hx-target="#game-board" hx-swap="outerHTML">%s</div>`, gameID, row, col, cellValue)
-		}
-		response += `</div>`
-	}
-
-	response += `</div>`
+	playerID := getPlayerIDFromContext(c)
+	response := gameBoardFragment(gameData, playerID)
 
 	c.Header("Content-Type", "text/html")
 	c.String(http.StatusOK, response)
 }
 
-
 func GameSSEHandler(c *gin.Context) {
 	gameID := c.Param("id")
 
 	// Validate game exists
 	gameData := game.GetGame(gameID)
 	if gameData == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		renderNotFound(c)
 		return
 	}
 
+	eventTypes, err := eventTypeFilter(c)
+	if err != nil {
+		renderBadRequest(c, "Unknown event type")
+		return
+	}
+
+	// Events are rendered for the player this connection belongs to
+	playerID := getPlayerIDFromContext(c)
+
 	// Set SSE headers
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
 	c.Header("Connection", "keep-alive")
 	c.Header("Access-Control-Allow-Origin", "*")
 
+	release, err := acquireStream(c, gameID)
+	if err != nil {
+		rejectSSEStream(c, renderConnectionRejectedHTML(err))
+		return
+	}
+	defer release()
+
 	// Create subscriber
-	subscriber := events.CreateGameSubscriber(gameID, c.Request.Context())
+	subscriber := events.CreateGameSubscriber(gameID, playerID, c.Request.Context())
+	subscriber.EventTypes = eventTypes
 	defer events.RemoveGameSubscriber(subscriber)
 
-	// Send initial game state
-	sendInitialGameState(c, gameData)
+	// Replay what a reconnecting client missed, or send the initial game state
+	sink := sseSink{c: c, playerID: playerID}
+	if !resumeGameStream(c, subscriber, sink) {
+		sendInitialGameState(c, playerID, gameData)
+	}
 
 	// Listen for events
-	for {
-		select {
-		case event := <-subscriber.Channel:
-			sendSSEEvent(c, event)
-		case <-subscriber.Context.Done():
-			return
-		}
-	}
+	events.Serve(subscriber, sink)
+}
+
+// sseSink writes events to an SSE connection as server-rendered HTML fragments
+type sseSink struct {
+	c        *gin.Context
+	playerID string
+}
+
+func (s sseSink) Send(event models.GameEvent) error {
+	sendSSEEvent(s.c, s.playerID, event)
+	return s.c.Request.Context().Err()
 }
 
-func sendInitialGameState(c *gin.Context, gameData *models.Game) {
+func (s sseSink) Heartbeat() error {
+	return writeSSEHeartbeat(s.c)
+}
+
+func sendInitialGameState(c *gin.Context, playerID string, gameData *models.Game) {
 	event := models.GameEvent{
 		Type:   "initial",
 		GameID: gameData.ID,
 		Data:   gameData.Board,
 	}
-	sendSSEEvent(c, event)
+	sendSSEEvent(c, playerID, event)
 }
 
-func sendSSEEvent(c *gin.Context, event models.GameEvent) {
+func sendSSEEvent(c *gin.Context, playerID string, event models.GameEvent) {
 	var eventData string
 
 	switch event.Type {
-	case "move", "reset", "game_winner", "game_draw":
+	case "move", "reset", "game_winner", "game_draw", "game_corrected", "game_paused", "game_resumed":
 		// Extract board from the data map
 		dataMap, ok := event.Data.(map[string]interface{})
 		if !ok {
@@ -482,10 +529,16 @@ func sendSSEEvent(c *gin.Context, event models.GameEvent) {
 		if !ok {
 			return
 		}
-		eventData = renderGameBoardHTML(event.GameID, board)
+		eventData = eventBoardFragment(event.GameID, board, playerID)
 
-		fmt.Fprintf(c.Writer, "event: %s\n", event.Type)
-		fmt.Fprintf(c.Writer, "data: %s\n\n", eventData)
+		// A coalesced update carries the status too, swapped out of band
+		if statusHTML, ok := dataMap["statusHTML"].(string); ok {
+			eventData += outOfBand(statusHTML)
+		}
+		// The move order appears once the game is over and goes with a reset
+		if gameData := game.GetGame(event.GameID); gameData != nil {
+			eventData += outOfBand(renderMoveOrderHTML(gameData))
+		}
 
 	case "game_status":
 		// Extract game status data
@@ -493,16 +546,13 @@ func sendSSEEvent(c *gin.Context, event models.GameEvent) {
 		if !ok {
 			return
 		}
-		gameID, _ := dataMap["gameID"].(string)
+		// Live events arrive rendered for this subscriber; replayed ones are rendered here
+		if html, ok := dataMap["html"].(string); ok {
+			eventData = html
+			break
+		}
 		gameData, _ := dataMap["game"].(*models.Game)
-
-		// Get playerID from the current request context
-		playerID := getPlayerIDFromContext(c)
-
-		eventData = renderGameStatusHTML(gameID, playerID, gameData)
-
-		fmt.Fprintf(c.Writer, "event: %s\n", event.Type)
-		fmt.Fprintf(c.Writer, "data: %s\n\n", eventData)
+		eventData = gameStatusFragment(gameData, playerID)
 
 	case "initial":
 		// For initial event, data should still be GameBoard directly
@@ -510,46 +560,226 @@ func sendSSEEvent(c *gin.Context, event models.GameEvent) {
 		if !ok {
 			return
 		}
-		eventData = renderGameBoardHTML(event.GameID, board)
+		eventData = eventBoardFragment(event.GameID, board, playerID)
+
+	case "chat":
+		dataMap, ok := event.Data.(map[string]interface{})
+		if !ok {
+			return
+		}
+		message, ok := dataMap["message"].(models.ChatMessage)
+		if !ok {
+			return
+		}
+		eventData = renderChatMessageHTML(message)
+
+	case "turn_reminder":
+		dataMap, ok := event.Data.(map[string]interface{})
+		if !ok {
+			return
+		}
+		remindedPlayerID, _ := dataMap["playerID"].(string)
+		nudge, _ := dataMap["nudge"].(bool)
+		eventData = renderTurnReminderHTML(remindedPlayerID, playerID, nudge)
+
+	case "pause_request":
+		dataMap, ok := event.Data.(map[string]interface{})
+		if !ok {
+			return
+		}
+		gameData := game.GetGame(event.GameID)
+		if gameData == nil {
+			return
+		}
+		askerID, _ := dataMap["playerID"].(string)
+		eventData = renderPauseRequestHTML(gameData, askerID, playerID)
+
+	case "announcement":
+		announcement, ok := announcementFromEvent(event)
+		if !ok {
+			return
+		}
+		eventData = renderAnnouncementHTML(announcement)
 
-		fmt.Fprintf(c.Writer, "event: %s\n", event.Type)
-		fmt.Fprintf(c.Writer, "data: %s\n\n", eventData)
+	case "celebration":
+		dataMap, ok := event.Data.(map[string]interface{})
+		if !ok {
+			return
+		}
+		board, _ := dataMap["board"].(models.GameBoard)
+		winner, _ := dataMap["winner"].(string)
+		emoji, _ := dataMap["emoji"].(string)
+		eventData = renderCelebrationHTML(board, emoji, winner == playerID)
 
 	case "player_join":
-		fmt.Fprintf(c.Writer, "event: player_join\n")
-		fmt.Fprintf(c.Writer, "data: Player joined game\n\n")
+		eventData = "Player joined game"
+
+	case "players_update":
+		gameData := game.GetGame(event.GameID)
+		if gameData == nil {
+			return
+		}
+		eventData = renderPlayersHTML(gameData, playerID)
+
+	case "join_state":
+		gameData := game.GetGame(event.GameID)
+		if gameData == nil {
+			return
+		}
+		eventData = string(joinStateOf(gameData, playerID))
 
 	case "game_ready":
 		// This triggers redirect to game page for waiting players
-		fmt.Fprintf(c.Writer, "event: game_ready\n")
-		fmt.Fprintf(c.Writer, "data: Game is ready\n\n")
+		eventData = "Game is ready"
+
+	case "game_cancelled":
+		eventData = renderGameCancelledHTML()
+
+	case "settings_changed":
+		dataMap, ok := event.Data.(map[string]interface{})
+		if !ok {
+			return
+		}
+		visibility, _ := dataMap["visibility"].(models.GameVisibility)
+		passwordRequired, _ := dataMap["passwordRequired"].(bool)
+		abandonRule, _ := dataMap["abandonRule"].(string)
+		correspondence, _ := dataMap["correspondence"].(bool)
+		eventData = renderGameRulesHTML(visibility, passwordRequired, models.AbandonRule(abandonRule), correspondence)
+
+	default:
+		return
 	}
 
-	c.Writer.Flush()
+	writeSSEEvent(c, event.ID, event.Type, eventData)
 }
 
-func renderGameBoardHTML(gameID string, board models.GameBoard) string {
-	response := `<div id="game-board" class="game-board">`
+// renderGameBoardHTML renders the board fragment as an ARIA grid. Only empty
+// cells are clickable, and only when canMove is set; all other cells are
+// rendered disabled but stay focusable so screen readers can inspect them.
+// Cells show the symbol symbols maps their seat letter to, escaped since
+// players may type their own; with badges set the letter is shown too.
+func renderGameBoardHTML(gameID string, board models.GameBoard, canMove bool, symbols map[string]string, badges bool) string {
+	b := getBuffer()
+	defer putBuffer(b)
 
+	b.WriteString(`<div id="game-board" class="game-board" data-testid="game-board" role="grid" aria-label="Tic-tac-toe board">`)
 	for row := 0; row < 3; row++ {
-		response += `<div class="game-row">`
+		b.WriteString(`<div class="game-row" role="row">`)
 		for col := 0; col < 3; col++ {
-			cellValue := board[row][col]
-			response += fmt.Sprintf(`<div class="game-cell" hx-post="/api/game/%s/move/%d/%d" hx-target="#game-board" hx-swap="outerHTML">%s</div>`, gameID, row, col, cellValue)
+			mark := board[row][col]
+			cellValue, badge := mark, ""
+			if symbol, ok := symbols[mark]; ok {
+				cellValue = symbol
+			}
+			if badges {
+				badge = mark
+			}
+			cellValue = html.EscapeString(cellValue)
+			label := cellAriaLabel(row, col, cellValue, badge)
+			if canMove && mark == "" {
+				fmt.Fprintf(b, `<div class="game-cell" role="gridcell" tabindex="0" data-testid="cell-%d-%d" data-row="%d" data-col="%d" aria-label="%s" hx-post="%s" hx-trigger="click, keyup[key=='Enter'||key==' ']" hx-target="#game-board" hx-swap="outerHTML">%s</div>`, row, col, row, col, label, URLPath(fmt.Sprintf("/api/game/%s/move/%d/%d", gameID, row, col)), cellValue)
+			} else {
+				fmt.Fprintf(b, `<div class="game-cell disabled" role="gridcell" tabindex="0" data-testid="cell-%d-%d" data-row="%d" data-col="%d" aria-label="%s" aria-disabled="true">%s%s</div>`, row, col, row, col, label, cellValue, renderPieceBadgeHTML(badge))
+			}
 		}
-		response += `</div>`
+		b.WriteString(`</div>`)
 	}
+	b.WriteString(`</div>`)
+	return b.String()
+}
 
-	response += `</div>`
-	return response
+// cellAriaLabel describes a cell for screen readers, e.g. "row 1 column 2,
+// empty" or, with the piece's seat badge, "row 1 column 2, 🐱 (X)"
+func cellAriaLabel(row, col int, cellValue, badge string) string {
+	content := "empty"
+	if cellValue != "" {
+		content = cellValue
+	}
+	if badge != "" {
+		content += " (" + badge + ")"
+	}
+	return fmt.Sprintf("row %d column %d, %s", row+1, col+1, content)
+}
+
+// outOfBand marks a fragment's root element for an htmx out-of-band swap, so
+// it replaces the element with the same id instead of the event's target
+func outOfBand(fragment string) string {
+	end := strings.Index(fragment, ">")
+	if end < 0 {
+		return fragment
+	}
+	return fragment[:end] + ` hx-swap-oob="true"` + fragment[end:]
+}
+
+// renderGameSummaryHTML sums up a finished game: how long it took and each
+// player's average time per move
+func renderGameSummaryHTML(gameData *models.Game) string {
+	if !game.IsGameFinished(gameData) {
+		return ""
+	}
+
+	parts := []string{"Game took " + game.Duration(gameData).Round(time.Second).String()}
+	for _, playerID := range gameData.PlayerOrder {
+		if average, ok := game.AverageThinkTime(gameData, playerID); ok {
+			parts = append(parts, fmt.Sprintf("%s thought %s per move", gameData.Players[playerID].Emoji, average.Round(100*time.Millisecond)))
+		}
+	}
+	return fmt.Sprintf(`<p class="game-summary" data-testid="game-summary">%s · <a href="%s">Share result</a></p>`,
+		strings.Join(parts, " · "), URLPath("/game/", gameData.ID, "/result"))
+}
+
+// canPlayerMove reports whether the player may currently move in the game
+func canPlayerMove(gameID, playerID string) bool {
+	gameData := game.GetGame(gameID)
+	if gameData == nil {
+		return false
+	}
+	return game.IsPlayersTurn(gameData, playerID)
+}
+
+// Cues tell the page when to play a sound or vibrate for the player
+const (
+	cueYourTurn = "your_turn"
+	cueWin      = "win"
+	cueLose     = "lose"
+	cueDraw     = "draw"
+)
+
+// cueFor returns the cue the game's state calls for to playerID, or "" for
+// none. Spectators get none.
+func cueFor(gameData *models.Game, playerID string) string {
+	if _, isPlayer := gameData.Players[playerID]; !isPlayer {
+		return ""
+	}
+	switch {
+	case game.IsPlayersTurn(gameData, playerID):
+		return cueYourTurn
+	case gameData.Status == models.GameStatusDraw:
+		return cueDraw
+	case gameData.Status == models.GameStatusFinished && gameData.Winner == playerID:
+		return cueWin
+	case gameData.Status == models.GameStatusFinished && gameData.Winner != "":
+		return cueLose
+	default:
+		return ""
+	}
 }
 
 func renderGameStatusHTML(gameID, playerID string, gameData *models.Game) string {
 	if gameData == nil {
-		return `<div id="game-status"></div>`
+		return `<div id="game-status" data-testid="game-status" role="status" aria-live="polite"></div>`
 	}
 
-	response := `<div id="game-status">`
+	b := getBuffer()
+	defer putBuffer(b)
+	b.WriteString(`<div id="game-status" data-testid="game-status" role="status" aria-live="polite"`)
+	if cue := cueFor(gameData, playerID); cue != "" {
+		fmt.Fprintf(b, ` data-cue="%s" data-cue-version="%d"`, cue, gameData.Version)
+		if game.CuesMuted(playerID) {
+			b.WriteString(` data-cue-muted="true"`)
+		}
+	}
+	b.WriteString(`>`)
 
 	// Turn indicator for active games
 	if game.IsGameActive(gameData) {
@@ -558,26 +788,29 @@ func renderGameStatusHTML(gameID, playerID string, gameData *models.Game) string
 			currentPlayer := gameData.Players[currentTurnPlayerID]
 			isPlayersTurnValue := game.IsPlayersTurn(gameData, playerID)
 
-			response += `<div class="turn-indicator">`
+			b.WriteString(`<div class="turn-indicator" data-testid="turn-indicator">`)
 			if isPlayersTurnValue {
-				response += fmt.Sprintf(`<span>🎯 Your turn! (%s)</span>`, currentPlayer.Emoji)
+				fmt.Fprintf(b, `<span>🎯 Your turn! (%s)</span>`, currentPlayer.Emoji)
 			} else {
-				response += fmt.Sprintf(`<span>%s's turn</span>`, currentPlayer.Emoji)
+				fmt.Fprintf(b, `<span>%s's turn</span>`, currentPlayer.Emoji)
 			}
-			response += `</div>`
+			b.WriteString(`</div>`)
 		}
 	}
 
+	b.WriteString(renderAbandonedHTML(gameData, playerID))
+
 	// Game result for finished games
 	if game.IsGameFinished(gameData) {
 		if gameData.Winner != "" {
 			winner := gameData.Players[gameData.Winner]
-			response += fmt.Sprintf(`<div class="game-result winner">🏆 %s wins!</div>`, winner.Emoji)
+			fmt.Fprintf(b, `<div class="game-result winner" data-testid="game-result">🏆 %s wins!</div>`, winner.Emoji)
 		} else if gameData.Status == models.GameStatusDraw {
-			response += `<div class="game-result draw">🤝 It's a draw!</div>`
+			b.WriteString(`<div class="game-result draw" data-testid="game-result">🤝 It's a draw!</div>`)
 		}
+		b.WriteString(renderGameSummaryHTML(gameData))
 	}
 
-	response += `</div>`
-	return response
+	b.WriteString(`</div>`)
+	return b.String()
 }
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headless turns off the HTML pages and htmx endpoints, leaving the JSON API
+// and JSON event streams for deployments with their own frontend
+var Headless bool
+
+// headlessRoutes are the non-/api/v1 routes that keep working in headless
+// mode because they speak JSON (or metrics) rather than HTML
+var headlessRoutes = map[string]bool{
+	"/api/game/:id/ws":             true,
+	"/api/game/:id/events/history": true,
+	"/api/lobby/events":            true,
+	"/api/version":                 true,
+	"/metrics":                     true,
+	"/slack/command":               true,
+	"/telegram/webhook":            true,
+}
+
+// RequireAPIRouteWhenHeadless answers 404 for HTML routes while Headless is set
+func RequireAPIRouteWhenHeadless(c *gin.Context) {
+	if !Headless || isHeadlessRoute(strings.TrimPrefix(c.FullPath(), BasePath)) {
+		c.Next()
+		return
+	}
+
+	renderAPIError(c, http.StatusNotFound, "Not available in headless mode")
+	c.Abort()
+}
+
+func isHeadlessRoute(route string) bool {
+	return strings.HasPrefix(route, "/api/v1/") || strings.HasPrefix(route, "/api/admin/") || headlessRoutes[route]
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+)
+
+// renderHeatmapHTML draws per-cell counts as a board, each cell shaded by
+// its count relative to the busiest one
+func renderHeatmapHTML(testID, label string, counts [models.BoardSize][models.BoardSize]int) string {
+	busiest := 0
+	for _, row := range counts {
+		for _, count := range row {
+			busiest = max(busiest, count)
+		}
+	}
+
+	response := fmt.Sprintf(`<table class="heatmap" data-testid="%s" aria-label="%s">`, testID, html.EscapeString(label))
+	for row := range counts {
+		response += `<tr>`
+		for col, count := range counts[row] {
+			shade := 0.0
+			if busiest > 0 {
+				shade = float64(count) / float64(busiest)
+			}
+			response += fmt.Sprintf(`<td style="background-color: rgba(220, 53, 69, %.2f)" aria-label="row %d column %d, %d">%d</td>`, shade*0.8, row+1, col+1, count, count)
+		}
+		response += `</tr>`
+	}
+	response += `</table>`
+	return response
+}
+
+// renderMoveOrderHTML shows the final board with the number of the move that
+// filled each cell, so a finished game can be followed move by move
+func renderMoveOrderHTML(gameData *models.Game) string {
+	if !game.IsGameFinished(gameData) || len(gameData.Moves) == 0 {
+		return `<div id="move-order"></div>`
+	}
+
+	var order [models.BoardSize][models.BoardSize]int
+	board := game.SymbolBoard(gameData)
+	for i, move := range gameData.Moves {
+		order[move.Row][move.Col] = i + 1
+	}
+
+	response := `<div id="move-order" class="move-order"><h3>Move order</h3><table class="heatmap" data-testid="move-order" aria-label="Order the cells were played in">`
+	for row := range order {
+		response += `<tr>`
+		for col, number := range order[row] {
+			mark := html.EscapeString(board[row][col])
+			if number == 0 {
+				response += fmt.Sprintf(`<td aria-label="row %d column %d, %s">%s</td>`, row+1, col+1, cellContent(mark), mark)
+				continue
+			}
+			response += fmt.Sprintf(`<td aria-label="row %d column %d, %s, move %d">%s<span class="move-number">%d</span></td>`, row+1, col+1, cellContent(mark), number, mark, number)
+		}
+		response += `</tr>`
+	}
+	response += `</table></div>`
+	return response
+}
+
+func cellContent(mark string) string {
+	if mark == "" {
+		return "empty"
+	}
+	return mark
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+// apiHistoryEvent is a recorded event as returned by the history API
+type apiHistoryEvent struct {
+	ID   uint64                 `json:"id"`
+	Type string                 `json:"type"`
+	At   time.Time              `json:"at"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// historyPlayerKeys are event data keys holding player IDs. IDs double as
+// session cookies, so the history reports the player's emoji instead.
+var historyPlayerKeys = map[string]string{
+	"playerID":   "player",
+	"winner":     "winner",
+	"nextPlayer": "nextPlayer",
+}
+
+// EventHistoryHandler lists the events recorded for a game, oldest first.
+// Pages are requested with after (the last ID seen) and limit, and types
+// keeps only the listed event types.
+func EventHistoryHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderAPIError(c, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	after, err := strconv.ParseUint(c.DefaultQuery("after", "0"), 10, 64)
+	if err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Invalid after cursor")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultHistoryLimit)))
+	if err != nil || limit < 1 || limit > maxHistoryLimit {
+		renderAPIError(c, http.StatusBadRequest, "Limit must be between 1 and "+strconv.Itoa(maxHistoryLimit))
+		return
+	}
+	filter, err := events.ParseEventTypes(c.Query("types"))
+	if err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Unknown event type")
+		return
+	}
+
+	recorded, hasMore := events.History(gameData.ID, after, filter, limit)
+
+	history := make([]apiHistoryEvent, 0, len(recorded))
+	for _, event := range recorded {
+		history = append(history, apiHistoryEvent{
+			ID:   event.ID,
+			Type: event.Type,
+			At:   event.At,
+			Data: historyEventData(gameData, event),
+		})
+	}
+
+	response := gin.H{"events": history, "hasMore": hasMore}
+	if hasMore {
+		response["nextAfter"] = history[len(history)-1].ID
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// historyEventData keeps the public parts of an event's data. Rendered HTML
+// and the live game are dropped, and player IDs are replaced with emojis.
+func historyEventData(gameData *models.Game, event models.GameEvent) map[string]interface{} {
+	dataMap, ok := event.Data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	data := make(map[string]interface{})
+	for key, value := range dataMap {
+		switch key {
+		case "board", "row", "col", "emoji", "nextTurn", "nudge":
+			data[key] = value
+		case "message":
+			if message, ok := value.(models.ChatMessage); ok {
+				data[key] = apiChatMessage{
+					Emoji:  message.Emoji,
+					Name:   message.Name,
+					Text:   message.Text,
+					SentAt: message.SentAt,
+				}
+			}
+		default:
+			if publicKey, ok := historyPlayerKeys[key]; ok {
+				playerID, _ := value.(string)
+				if player, exists := gameData.Players[playerID]; exists {
+					data[publicKey] = player.Emoji
+				}
+			}
+		}
+	}
+	return data
+}
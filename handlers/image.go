@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/render"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
+)
+
+// setBoardImageCacheHeaders lets finished boards be cached, since they can no longer change
+func setBoardImageCacheHeaders(c *gin.Context, gameData *models.Game) {
+	if game.IsGameFinished(gameData) {
+		c.Header("Cache-Control", "public, max-age=86400")
+	} else {
+		c.Header("Cache-Control", "no-cache")
+	}
+}
+
+// BoardSVGHandler renders the current or final board as an SVG image
+func BoardSVGHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	setBoardImageCacheHeaders(c, gameData)
+	if notModified(c, gameData, "board.svg", "") {
+		return
+	}
+	c.Data(http.StatusOK, "image/svg+xml", render.BoardSVG(gameData))
+}
+
+// BoardPNGHandler renders the current or final board as a PNG image
+func BoardPNGHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	setBoardImageCacheHeaders(c, gameData)
+	if notModified(c, gameData, "board.png", "") {
+		return
+	}
+	image, err := render.BoardPNG(gameData)
+	if err != nil {
+		renderInternalError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", image)
+}
+
+// QRCodeHandler renders the game's invite URL as a QR code so a second player can join by scanning
+func QRCodeHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	image, err := qrcode.Encode(taggedURL(c, "/game/"+gameData.ID, "qr"), qrcode.Medium, 256)
+	if err != nil {
+		renderInternalError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "image/png", image)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateInviteHandler lets the waiting creator issue a single-use, expiring invite link
+func CreateInviteHandler(c *gin.Context) {
+	gameID := c.Param("id")
+	gameData := game.GetGame(gameID)
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	playerID := getPlayerIDFromContext(c)
+	if !game.IsFirstPlayer(gameData, playerID) {
+		renderForbidden(c)
+		return
+	}
+
+	invite := game.CreateInvite(gameData, playerID, game.InviteTTL.Get())
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, renderInviteHTML(absoluteURL(c, "/join/"+invite.Token), invite, viewerLocale(c)))
+}
+
+// InviteRedeemHandler redeems an invite token and sends the player to emoji selection
+func InviteRedeemHandler(c *gin.Context) {
+	playerID := getPlayerIDFromContext(c)
+	gameData, err := game.RedeemInvite(c.Param("token"), playerID)
+	if err != nil {
+		renderGameError(c, err)
+		return
+	}
+
+	rememberJoinSource(c, "invite")
+	c.Redirect(http.StatusSeeOther, URLPath("/game/", gameData.ID, "/select-emoji"))
+}
+
+func renderInviteHTML(inviteURL string, invite *models.Invite, locale Locale) string {
+	escapedURL := html.EscapeString(inviteURL)
+	return fmt.Sprintf(`<div id="game-invite" class="game-invite"><input type="text" class="url-input invite-url" value="%s" readonly onclick="this.select()"><p>Single use · expires at %s</p></div>`,
+		escapedURL, locale.FormatClock(invite.ExpiresAt))
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"htmx-go-app/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JoinByCodeHandler resolves a join code (or game ID) typed on the home page
+// and sends the player to emoji selection for that game
+func JoinByCodeHandler(c *gin.Context) {
+	code := c.Query("code")
+	if strings.TrimSpace(code) == "" {
+		renderBadRequest(c, "Please enter a game code")
+		return
+	}
+
+	gameData := game.ResolveGameCode(code)
+	if gameData == nil {
+		renderError(c, http.StatusNotFound, "No game found for code \""+game.NormalizeGameCode(code)+"\".")
+		return
+	}
+
+	rememberJoinSource(c, "code")
+	c.Redirect(http.StatusSeeOther, URLPath("/game/", gameData.ID, "/select-emoji"))
+}
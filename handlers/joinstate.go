@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// joinState is where a visitor stands on a game's join page. It only ever
+// moves forward, selecting → waiting → ready, or selecting → ready for the
+// opponent, or selecting → full for anyone too late. The page shows the
+// state the server works out from the game, and re-fetches it whenever a
+// join_state event says it may have changed.
+type joinState string
+
+const (
+	joinSelecting joinState = "selecting" // picking an emoji
+	joinWaiting   joinState = "waiting"   // seated, waiting for an opponent
+	joinReady     joinState = "ready"     // seated in a game that has started; the page moves on to it
+	joinFull      joinState = "full"      // both seats went to others
+)
+
+// joinStateOf works out playerID's join state from the game alone
+func joinStateOf(gameData *models.Game, playerID string) joinState {
+	_, seated := gameData.Players[playerID]
+	switch {
+	case seated && gameData.Status == models.GameStatusWaiting:
+		return joinWaiting
+	case seated:
+		return joinReady
+	case game.CanJoinGame(gameData):
+		return joinSelecting
+	default:
+		return joinFull
+	}
+}
+
+// JoinStateHandler renders the join page's state fragment for the visitor.
+// Unlike the page, it answers a ready visitor with the ready state, whose
+// data-redirect takes the browser into the game.
+func JoinStateHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+	renderJoinPage(c, http.StatusOK, "join-state.html", gameData, "")
+}
+
+// broadcastJoinState tells join pages their state may have changed. Each
+// subscriber is sent the state as it stands for them.
+func broadcastJoinState(gameID string) {
+	events.BroadcastGameEvent(gameID, models.GameEvent{
+		Type:   "join_state",
+		GameID: gameID,
+	})
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LobbyHandler renders the list of open games waiting for an opponent
+func LobbyHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "lobby.html", gin.H{
+		"Title":     "Open Games",
+		"LobbyHTML": template.HTML(renderLobbyListHTML(game.Snapshot(game.IsOpenForLobby))),
+	})
+}
+
+// LobbyJoinHandler sends a player from the lobby to emoji selection. Only
+// public games still waiting for an opponent can be joined this way; private
+// games are reported as missing so the lobby can't be used to find them.
+func LobbyJoinHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil || gameData.Visibility != models.VisibilityPublic {
+		renderNotFound(c)
+		return
+	}
+	if !game.IsOpenForLobby(gameData) {
+		renderConflict(c, "This game is no longer waiting for an opponent.")
+		return
+	}
+
+	rememberJoinSource(c, "lobby")
+	c.Redirect(http.StatusSeeOther, URLPath("/game/", gameData.ID, "/select-emoji"))
+}
+
+// LobbySSEHandler streams lobby-wide events: game_created, game_filled and
+// game_finished for public games as JSON, each followed by the refreshed
+// lobby list (lobby_update) for the lobby page
+func LobbySSEHandler(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Access-Control-Allow-Origin", "*")
+
+	release, err := acquireStream(c, "")
+	if err != nil {
+		rejectSSEStream(c, renderConnectionRejectedHTML(err))
+		return
+	}
+	defer release()
+
+	subscriber := events.CreateLobbySubscriber(c.Request.Context())
+	defer events.RemoveGameSubscriber(subscriber)
+
+	sendLobbySSEEvent(c, models.GameEvent{Type: "lobby_update", GameID: events.LobbyID})
+
+	events.Serve(subscriber, lobbySink{c})
+}
+
+// lobbySink writes lobby events to an SSE connection
+type lobbySink struct {
+	c *gin.Context
+}
+
+func (s lobbySink) Send(event models.GameEvent) error {
+	sendLobbySSEEvent(s.c, event)
+	return s.c.Request.Context().Err()
+}
+
+func (s lobbySink) Heartbeat() error {
+	return writeSSEHeartbeat(s.c)
+}
+
+func sendLobbySSEEvent(c *gin.Context, event models.GameEvent) {
+	switch event.Type {
+	case "game_created", "game_filled", "game_finished":
+		summary, ok := event.Data.(gameSummary)
+		if !ok {
+			return
+		}
+		data, err := json.Marshal(summary)
+		if err != nil {
+			return
+		}
+		writeSSEEvent(c, 0, event.Type, string(data))
+	case "announcement":
+		if announcement, ok := announcementFromEvent(event); ok {
+			writeSSEEvent(c, 0, event.Type, renderAnnouncementHTML(announcement))
+		}
+		return
+	case "lobby_update":
+	default:
+		return
+	}
+
+	// Always render the current list so missed events can't leave it stale
+	writeSSEEvent(c, 0, "lobby_update", renderLobbyListHTML(game.Snapshot(game.IsOpenForLobby)))
+}
+
+// gameSummary is the JSON payload of lifecycle events on the lobby stream and webhooks
+type gameSummary struct {
+	GameID  string   `json:"gameId"`
+	Code    string   `json:"code"`
+	Status  string   `json:"status"`
+	Players []string `json:"players"`
+	Winner  string   `json:"winner,omitempty"`
+}
+
+// broadcastLobbyUpdate tells lobby subscribers that the set of open games changed
+func broadcastLobbyUpdate(gameID string) {
+	events.BroadcastLobbyEvent(models.GameEvent{
+		Type:   "lobby_update",
+		GameID: gameID,
+	})
+}
+
+// broadcastLobbyGameEvent tells lobby subscribers that a game was created,
+// filled or finished. Private games are never announced in the lobby.
+func broadcastLobbyGameEvent(eventType string, gameData *models.Game) {
+	if gameData.Visibility != models.VisibilityPublic {
+		return
+	}
+
+	events.BroadcastLobbyEvent(models.GameEvent{
+		Type:   eventType,
+		GameID: gameData.ID,
+		Data:   newGameSummary(gameData),
+	})
+}
+
+func newGameSummary(gameData *models.Game) gameSummary {
+	summary := gameSummary{
+		GameID:  gameData.ID,
+		Code:    gameData.Slug,
+		Status:  string(gameData.Status),
+		Players: []string{},
+	}
+	for _, playerID := range gameData.PlayerOrder {
+		summary.Players = append(summary.Players, gameData.Players[playerID].Emoji)
+	}
+	if winner, ok := gameData.Players[gameData.Winner]; ok {
+		summary.Winner = winner.Emoji
+	}
+	return summary
+}
+
+func renderLobbyListHTML(openGames []*models.Game) string {
+	if len(openGames) == 0 {
+		return `<div id="lobby-games" class="lobby-games"><p class="lobby-empty">No open games right now. Start one!</p></div>`
+	}
+
+	response := `<div id="lobby-games" class="lobby-games"><ul class="lobby-list">`
+	for _, openGame := range openGames {
+		creator := openGame.Players[openGame.PlayerOrder[0]]
+		response += fmt.Sprintf(`<li class="lobby-game" data-game-id="%s">`, openGame.ID)
+		response += fmt.Sprintf(`<span class="lobby-creator">%s</span>`, creator.Emoji)
+		lock := ""
+		if game.HasPassword(openGame) {
+			lock = "🔒 "
+		}
+		response += fmt.Sprintf(`<span class="lobby-details">%s%d×%d · created %s</span>`, lock, models.BoardSize, models.BoardSize, formatAge(clock.Since(openGame.CreatedAt)))
+		response += fmt.Sprintf(`<a href="%s" class="btn btn-primary btn-small">Join</a>`, URLPath("/lobby/join/", openGame.ID))
+		response += `</li>`
+	}
+	response += `</ul></div>`
+	return response
+}
+
+// formatAge renders a duration as a short relative time, e.g. "3m ago"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	_ "time/tzdata" // resolve browser time zones on hosts without a zoneinfo database
+
+	"htmx-go-app/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// languageTag loosely matches a BCP 47 language tag such as "en" or "de-CH"
+var languageTag = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// Locale is how a viewer reads dates and times: in their time zone, in their
+// language's date order
+type Locale struct {
+	Language string         // BCP 47 tag; "" writes dates as year-month-day
+	Location *time.Location // nil means UTC
+}
+
+// viewerLocale returns the requesting player's locale: the time zone and
+// language their browser reported, the language falling back to the
+// request's Accept-Language
+func viewerLocale(c *gin.Context) Locale {
+	var preference game.LocalePreference
+	if playerID, ok := requestPlayerID(c); ok {
+		preference = game.LocalePreferenceOf(playerID)
+	}
+
+	locale := Locale{Language: preference.Language}
+	if locale.Language == "" {
+		locale.Language = acceptedLanguage(c.GetHeader("Accept-Language"))
+	}
+	if preference.TimeZone != "" {
+		if location, err := time.LoadLocation(preference.TimeZone); err == nil {
+			locale.Location = location
+		}
+	}
+	return locale
+}
+
+// acceptedLanguage returns the first language of an Accept-Language header,
+// which browsers list most preferred first
+func acceptedLanguage(header string) string {
+	first, _, _ := strings.Cut(header, ",")
+	first, _, _ = strings.Cut(first, ";")
+	first = strings.TrimSpace(first)
+	if !languageTag.MatchString(first) {
+		return ""
+	}
+	return first
+}
+
+// layouts returns the date and clock layouts customary for the language
+func (l Locale) layouts() (date, clock string) {
+	language := strings.ToLower(l.Language)
+	base, _, _ := strings.Cut(language, "-")
+	switch {
+	case language == "en" || language == "en-us":
+		return "Jan 2, 2006", "3:04 PM"
+	case base == "en":
+		return "2 Jan 2006", "15:04"
+	case base == "de" || base == "da" || base == "fi" || base == "nb" || base == "pl" ||
+		base == "ru" || base == "tr" || base == "cs" || base == "uk":
+		return "02.01.2006", "15:04"
+	case base == "fr" || base == "es" || base == "it" || base == "pt" || base == "el":
+		return "02/01/2006", "15:04"
+	case base == "nl":
+		return "02-01-2006", "15:04"
+	case base == "ja" || base == "zh":
+		return "2006/01/02", "15:04"
+	default:
+		return "2006-01-02", "15:04"
+	}
+}
+
+func (l Locale) location() *time.Location {
+	if l.Location == nil {
+		return time.UTC
+	}
+	return l.Location
+}
+
+// Format writes t as a date and time in the viewer's time zone, naming the zone
+func (l Locale) Format(t time.Time) string {
+	date, clock := l.layouts()
+	return t.In(l.location()).Format(date + " " + clock + " MST")
+}
+
+// FormatClock writes t as a time of day in the viewer's time zone, for
+// times that are at most a day away
+func (l Locale) FormatClock(t time.Time) string {
+	_, clock := l.layouts()
+	return t.In(l.location()).Format(clock)
+}
+
+// LocalTime renders t as a <time> element written for the viewer. It is
+// the localTime template function; zero times render nothing.
+func LocalTime(locale Locale, t time.Time) template.HTML {
+	if t.IsZero() {
+		return ""
+	}
+	return template.HTML(fmt.Sprintf(`<time datetime="%s">%s</time>`,
+		t.UTC().Format(time.RFC3339), template.HTMLEscapeString(locale.Format(t))))
+}
+
+// SetLocaleHandler records the time zone and language the player's browser
+// reports, so pages write dates the way the player reads them
+func SetLocaleHandler(c *gin.Context) {
+	preference := game.LocalePreference{
+		TimeZone: c.PostForm("timezone"),
+		Language: c.PostForm("language"),
+	}
+	if preference.TimeZone != "" {
+		// "Local" would be the server's own zone
+		if _, err := time.LoadLocation(preference.TimeZone); err != nil || preference.TimeZone == "Local" {
+			renderAPIError(c, http.StatusBadRequest, "Unknown time zone")
+			return
+		}
+	}
+	if preference.Language != "" && !languageTag.MatchString(preference.Language) {
+		renderAPIError(c, http.StatusBadRequest, "Invalid language tag")
+		return
+	}
+
+	game.SetLocalePreference(getPlayerIDFromContext(c), preference)
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"htmx-go-app/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+var maintenanceMode atomic.Bool
+
+// SetMaintenanceMode turns maintenance mode on or off. While it is on, no
+// games can be created or joined, but games already in progress carry on.
+func SetMaintenanceMode(enabled bool) {
+	maintenanceMode.Store(enabled)
+}
+
+// InMaintenanceMode reports whether maintenance mode is on
+func InMaintenanceMode() bool {
+	return maintenanceMode.Load()
+}
+
+// BlockDuringMaintenance turns away requests that would start or join a
+// game while maintenance mode is on
+func BlockDuringMaintenance(c *gin.Context) {
+	if !InMaintenanceMode() {
+		c.Next()
+		return
+	}
+
+	c.Header("Retry-After", "300")
+	if isJSONAPIRequest(c) {
+		renderAPIError(c, http.StatusServiceUnavailable, "The server is in maintenance mode, new games are paused")
+	} else {
+		renderError(c, http.StatusServiceUnavailable, "")
+	}
+	c.Abort()
+}
+
+type maintenanceRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// AdminGetMaintenanceHandler reports whether maintenance mode is on
+func AdminGetMaintenanceHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"enabled": InMaintenanceMode()})
+}
+
+// AdminSetMaintenanceHandler turns maintenance mode on or off
+func AdminSetMaintenanceHandler(c *gin.Context) {
+	var request maintenanceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		renderAPIError(c, http.StatusBadRequest, `Expected {"enabled": true|false}`)
+		return
+	}
+
+	SetMaintenanceMode(*request.Enabled)
+	recordAdminAudit(c, audit.ActionMaintenance, "", "", strconv.FormatBool(*request.Enabled))
+	c.JSON(http.StatusOK, gin.H{"enabled": InMaintenanceMode()})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuickMatchHandler renders the "looking for an opponent" page. The player
+// joins the queue when its event stream connects and leaves when it closes.
+func QuickMatchHandler(c *gin.Context) {
+	getPlayerIDFromContext(c) // make sure the stream request carries a player cookie
+
+	c.HTML(http.StatusOK, "quick-match.html", gin.H{
+		"Title": "Quick Match",
+	})
+}
+
+// MatchmakingSSEHandler queues the player and streams a match_found event once paired
+func MatchmakingSSEHandler(c *gin.Context) {
+	playerID := getPlayerIDFromContext(c)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	release, err := acquireStream(c, "")
+	if err != nil {
+		rejectSSEStream(c, renderConnectionRejectedHTML(err))
+		return
+	}
+	defer release()
+
+	// Subscribe before queueing so a match made right away can't be missed
+	subscriber := events.CreateMatchmakingSubscriber(playerID, c.Request.Context())
+	defer events.RemoveGameSubscriber(subscriber)
+	defer game.LeaveMatchQueue(playerID)
+
+	c.Writer.Flush()
+
+	if matchedGame, opponentID := game.JoinMatchQueue(playerID); matchedGame != nil {
+		announceGameLifecycle("game_created", matchedGame)
+		events.BroadcastMatchFound(opponentID, matchedGame.ID)
+		sendMatchFoundEvent(c, matchedGame.ID)
+	}
+
+	// Keep the stream open after a match so the browser doesn't reconnect
+	// (and re-queue) before it has navigated to the game
+	events.Serve(subscriber, matchmakingSink{c})
+}
+
+// matchmakingSink writes match notifications to an SSE connection
+type matchmakingSink struct {
+	c *gin.Context
+}
+
+func (s matchmakingSink) Send(event models.GameEvent) error {
+	if event.Type == "match_found" {
+		sendMatchFoundEvent(s.c, event.GameID)
+	}
+	return s.c.Request.Context().Err()
+}
+
+func (s matchmakingSink) Heartbeat() error {
+	return writeSSEHeartbeat(s.c)
+}
+
+func sendMatchFoundEvent(c *gin.Context, gameID string) {
+	fmt.Fprintf(c.Writer, "event: match_found\n")
+	fmt.Fprintf(c.Writer, "data: %s\n\n", renderMatchFoundHTML(gameID))
+	c.Writer.Flush()
+}
+
+// renderMatchFoundHTML renders the swap target for a match; data-redirect makes
+// script.js navigate there, and the link is a fallback without JavaScript
+func renderMatchFoundHTML(gameID string) string {
+	url := URLPath("/game/", gameID, "/select-emoji")
+	return fmt.Sprintf(`<div id="match-status" class="match-status" data-redirect="%s"><p>Opponent found!</p><a href="%s" class="btn btn-primary">Go to Game</a></div>`, url, url)
+}
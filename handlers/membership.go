@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// publicFragments are the fragment sections that look the same to everyone,
+// so spectators and the stats page may fetch them
+var publicFragments = map[string]bool{
+	"players":    true,
+	"move-order": true,
+}
+
+// RequireGamePlayer stops requests for the game in the :id parameter unless
+// they come from one of its seated players. Outsiders get a 403 error
+// fragment instead of being able to render or change someone else's game,
+// and a missing game a 404. No cookie is issued to visitors without one.
+func RequireGamePlayer(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		c.Abort()
+		return
+	}
+	if publicFragments[c.Param("section")] {
+		c.Next()
+		return
+	}
+
+	playerID, _ := requestPlayerID(c)
+	if !isGamePlayer(gameData, playerID) {
+		renderForbidden(c)
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// RequirePrivateGamePlayer stops requests for a private game in the :id
+// parameter unless they come from one of its seated players. Anyone may
+// watch a public game, so those pass, as do missing games for the handler
+// to report. Errors are JSON, like the responses of the routes it guards.
+func RequirePrivateGamePlayer(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil || gameData.Visibility != models.VisibilityPrivate {
+		c.Next()
+		return
+	}
+
+	playerID, _ := requestPlayerID(c)
+	if !isGamePlayer(gameData, playerID) {
+		renderAPIError(c, http.StatusForbidden, "")
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// isGamePlayer reports whether playerID has a seat with a chosen emoji in the game
+func isGamePlayer(gameData *models.Game, playerID string) bool {
+	player, exists := gameData.Players[playerID]
+	return exists && player.Emoji != ""
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"htmx-go-app/models"
+	"htmx-go-app/render"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PageMeta holds the Open Graph and Twitter card tags rendered by the base layout
+type PageMeta struct {
+	Title       string
+	Description string
+	URL         string
+	Image       string
+	ImageAlt    string
+}
+
+// PublicBaseURL, when set, is used for absolute links instead of the request host
+var PublicBaseURL string
+
+// absoluteURL builds a full URL for path on the host the request came in on
+func absoluteURL(c *gin.Context, path string) string {
+	if PublicBaseURL != "" {
+		return strings.TrimSuffix(PublicBaseURL, "/") + URLPath(path)
+	}
+	if c == nil {
+		// Outside a request, such as in a webhook, there is no host to go by
+		return URLPath(path)
+	}
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, c.Request.Host, URLPath(path))
+}
+
+// gameMeta builds link preview tags for a game, using the board image as preview
+func gameMeta(c *gin.Context, gameData *models.Game) PageMeta {
+	caption := render.Caption(gameData)
+
+	title := "Tic-Tac-Toe"
+	description := caption
+	if len(gameData.PlayerOrder) > 0 {
+		title = "Tic-Tac-Toe: " + caption
+	}
+	if gameData.Status == models.GameStatusWaiting {
+		title = "Join my Tic-Tac-Toe game!"
+		description = caption + " · pick an emoji and play"
+	}
+
+	return PageMeta{
+		Title:       title,
+		Description: description,
+		URL:         absoluteURL(c, "/game/"+gameData.ID),
+		Image:       absoluteURL(c, "/api/game/"+gameData.ID+"/board.png"),
+		ImageAlt:    "Tic-tac-toe board: " + caption,
+	}
+}
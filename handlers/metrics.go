@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"htmx-go-app/events"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsHandler reports event stream load and fragment rendering in the
+// Prometheus text format
+func MetricsHandler(c *gin.Context) {
+	metrics := events.Snapshot()
+
+	var b strings.Builder
+	writeMetric(&b, "tictactoe_connections", "gauge", "Open event stream connections by transport.")
+	for _, transport := range sortedKeys(metrics.ConnectionsByTransport) {
+		fmt.Fprintf(&b, "tictactoe_connections{transport=%q} %d\n", transport, metrics.ConnectionsByTransport[transport])
+	}
+
+	writeMetric(&b, "tictactoe_game_subscribers", "gauge", "Open event stream connections per game.")
+	for _, gameID := range sortedKeys(metrics.GameSubscribers) {
+		fmt.Fprintf(&b, "tictactoe_game_subscribers{game=%q} %d\n", gameID, metrics.GameSubscribers[gameID])
+	}
+
+	writeMetric(&b, "tictactoe_lobby_subscribers", "gauge", "Open lobby stream connections.")
+	fmt.Fprintf(&b, "tictactoe_lobby_subscribers %d\n", metrics.LobbySubscribers)
+
+	writeMetric(&b, "tictactoe_matchmaking_subscribers", "gauge", "Players waiting for a quick match.")
+	fmt.Fprintf(&b, "tictactoe_matchmaking_subscribers %d\n", metrics.MatchmakingSubscribers)
+
+	writeMetric(&b, "tictactoe_events_queued_total", "counter", "Events handed to subscribers.")
+	fmt.Fprintf(&b, "tictactoe_events_queued_total %d\n", metrics.EventsQueued)
+
+	writeMetric(&b, "tictactoe_events_sent_total", "counter", "Events written to connections.")
+	fmt.Fprintf(&b, "tictactoe_events_sent_total %d\n", metrics.EventsSent)
+
+	writeMetric(&b, "tictactoe_events_dropped_total", "counter", "Events skipped because a subscriber fell behind.")
+	fmt.Fprintf(&b, "tictactoe_events_dropped_total %d\n", metrics.EventsDropped)
+
+	writeMetric(&b, "tictactoe_game_events_dropped_total", "counter", "Events skipped per game, to find slow-subscriber hotspots.")
+	for _, gameID := range sortedKeys(metrics.DroppedByGame) {
+		fmt.Fprintf(&b, "tictactoe_game_events_dropped_total{game=%q} %d\n", gameID, metrics.DroppedByGame[gameID])
+	}
+
+	renders, hits := fragmentCacheStats()
+	writeMetric(&b, "tictactoe_fragment_renders_total", "counter", "Board and status fragments rendered.")
+	fmt.Fprintf(&b, "tictactoe_fragment_renders_total %d\n", renders)
+
+	writeMetric(&b, "tictactoe_fragment_cache_hits_total", "counter", "Board and status fragments reused from an earlier rendering.")
+	fmt.Fprintf(&b, "tictactoe_fragment_cache_hits_total %d\n", hits)
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
+
+func writeMetric(b *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"strings"
+
+	"htmx-go-app/models"
+	"htmx-go-app/notify"
+	"htmx-go-app/render"
+	"htmx-go-app/webhooks"
+)
+
+// notifyChatServices posts a game's start and result to the configured chat
+// services, and its result back to the Slack channel it was created from.
+// Private games are only posted back to Slack.
+func notifyChatServices(eventType string, gameData *models.Game) {
+	players := gamePlayersText(gameData)
+	var message notify.Message
+	switch eventType {
+	case "game_filled":
+		message = notify.Message{
+			Event: webhooks.EventGameStarted,
+			Title: "Game started",
+			Text:  players,
+			URL:   notifyURL("/game/" + gameData.ID),
+		}
+	case "game_finished":
+		message = notify.Message{
+			Event:    webhooks.EventGameFinished,
+			Title:    render.ResultText(gameData),
+			Text:     players + " · " + render.DurationText(gameData),
+			URL:      notifyURL("/game/" + gameData.ID + "/result"),
+			ImageURL: notifyURL("/api/game/" + gameData.ID + "/board.png"),
+		}
+	default:
+		return
+	}
+
+	if eventType == "game_finished" {
+		notify.PostSlackResult(gameData.ID, message)
+	}
+	if gameData.Visibility == models.VisibilityPublic {
+		notify.Post(message)
+	}
+}
+
+// gamePlayersText lists the players in join order, e.g. "🐱 Ann vs 🚀"
+func gamePlayersText(gameData *models.Game) string {
+	var players []string
+	for _, playerID := range gameData.PlayerOrder {
+		players = append(players, render.PlayerLabel(gameData, playerID))
+	}
+	return strings.Join(players, " vs ")
+}
+
+// notifyURL returns an absolute link for path, or "" without a PublicBaseURL,
+// since chat services can't resolve links relative to a request
+func notifyURL(path string) string {
+	if PublicBaseURL == "" {
+		return ""
+	}
+	return absoluteURL(nil, path)
+}
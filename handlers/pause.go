@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PauseGameHandler asks the opponent to pause the game, or agrees to their
+// request. Both players' pause regions follow the request through
+// pause_request events; once both agree the game is paused for everyone.
+func PauseGameHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	playerID := getPlayerIDFromContext(c)
+	paused, err := game.RequestPause(gameData, playerID)
+	if err != nil {
+		renderGameError(c, err)
+		return
+	}
+
+	broadcastPauseRequest(gameData)
+	if paused {
+		broadcastBoardUpdate(gameData, "game_paused")
+		broadcastLobbyUpdate(gameData.ID)
+		scheduleTurnReminder(gameData)
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, renderPauseRequestHTML(gameData, gameData.PauseAsker, playerID))
+}
+
+// DeclinePauseHandler turns down the opponent's request to pause, or
+// withdraws the player's own
+func DeclinePauseHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	playerID := getPlayerIDFromContext(c)
+	if err := game.DeclinePause(gameData, playerID); err != nil {
+		renderGameError(c, err)
+		return
+	}
+
+	broadcastPauseRequest(gameData)
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, renderPauseRequestHTML(gameData, "", playerID))
+}
+
+// ResumeGameHandler lets either player pick a paused game back up. The
+// opponent hears about it through a game_resumed event. Resuming from the
+// history page, which posts redirect=true, takes the player into the game.
+func ResumeGameHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	playerID := getPlayerIDFromContext(c)
+	if err := game.ResumeGame(gameData, playerID); err != nil {
+		renderGameError(c, err)
+		return
+	}
+
+	broadcastBoardUpdate(gameData, "game_resumed")
+	broadcastLobbyUpdate(gameData.ID)
+	scheduleTurnReminder(gameData)
+	notifyTurn(gameData)
+
+	if c.PostForm("redirect") == "true" {
+		c.Header("HX-Redirect", URLPath("/game/", gameData.ID))
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, gameStatusFragment(gameData, playerID))
+}
+
+// broadcastPauseRequest tells subscribers who, if anyone, is waiting for the
+// opponent to agree to a pause
+func broadcastPauseRequest(gameData *models.Game) {
+	events.BroadcastGameEvent(gameData.ID, models.GameEvent{
+		Type:   "pause_request",
+		GameID: gameData.ID,
+		Data: map[string]interface{}{
+			"playerID": gameData.PauseAsker,
+		},
+	})
+}
+
+// renderPauseRequestHTML renders the pause region as playerID sees it while
+// askerID waits for an answer: the asker can withdraw, the opponent agree or
+// decline, and everyone else sees nothing
+func renderPauseRequestHTML(gameData *models.Game, askerID, playerID string) string {
+	const empty = `<div id="pause-request" class="pause-request" aria-live="polite"></div>`
+	if askerID == "" || !isGamePlayer(gameData, playerID) || !game.IsGameActive(gameData) {
+		return empty
+	}
+
+	pauseURL := URLPath("/api/game/", gameData.ID, "/pause")
+	if askerID == playerID {
+		return fmt.Sprintf(`<div id="pause-request" class="pause-request" aria-live="polite" data-testid="pause-request">⏸️ Waiting for your opponent to agree to a pause. <button class="btn btn-secondary btn-small" hx-post="%s/decline" hx-target="#pause-request" hx-swap="outerHTML">Cancel</button></div>`,
+			pauseURL)
+	}
+	asker, ok := gameData.Players[askerID]
+	if !ok {
+		return empty
+	}
+	return fmt.Sprintf(`<div id="pause-request" class="pause-request" aria-live="polite" data-testid="pause-request">⏸️ %s wants to pause the game and finish it later. <button class="btn btn-primary btn-small" hx-post="%s" hx-target="#pause-request" hx-swap="outerHTML" data-testid="agree-pause">Agree</button> <button class="btn btn-secondary btn-small" hx-post="%s/decline" hx-target="#pause-request" hx-swap="outerHTML">Decline</button></div>`,
+		asker.Emoji, pauseURL, pauseURL)
+}
+
+// HistoryPageHandler lists the games the player has a seat in, newest first.
+// Paused games can be resumed from here.
+func HistoryPageHandler(c *gin.Context) {
+	playerID, ok := requestPlayerID(c)
+	var games []*models.Game
+	if ok {
+		games = game.PlayerGames(playerID)
+	}
+
+	c.HTML(http.StatusOK, "history.html", gin.H{
+		"Title":       "Your Games",
+		"HistoryHTML": template.HTML(renderPlayerGamesHTML(games, playerID, viewerLocale(c))),
+	})
+}
+
+// renderPlayerGamesHTML lists the games with when playerID joined each and
+// when it finished, written in the viewer's locale
+func renderPlayerGamesHTML(games []*models.Game, playerID string, locale Locale) string {
+	if len(games) == 0 {
+		return `<p data-testid="history-empty">You haven't played any games yet.</p>`
+	}
+
+	var b strings.Builder
+	b.WriteString(`<ul class="history" data-testid="history">`)
+	for _, gameData := range games {
+		var players []string
+		for _, id := range gameData.PlayerOrder {
+			players = append(players, gameData.Players[id].Emoji)
+		}
+
+		action := fmt.Sprintf(`<a href="%s" class="btn btn-secondary btn-small">Open</a>`, URLPath("/game/", gameData.ID))
+		if gameData.Status == models.GameStatusPaused {
+			action = fmt.Sprintf(`<button hx-post="%s" hx-vals='{"redirect": "true"}' class="btn btn-primary btn-small" data-testid="resume-%s">Resume</button>`,
+				URLPath("/api/game/", gameData.ID, "/resume"), gameData.ID)
+		}
+		dates := "joined " + string(LocalTime(locale, gameData.Players[playerID].JoinedAt))
+		if !gameData.FinishedAt.IsZero() {
+			dates += " · finished " + string(LocalTime(locale, gameData.FinishedAt))
+		}
+		fmt.Fprintf(&b, `<li class="history-game" data-testid="history-%s"><span>%s · %s · %s</span> %s</li>`,
+			gameData.ID, strings.Join(players, " vs "), historyStatus(gameData), dates, action)
+	}
+	b.WriteString(`</ul>`)
+	return b.String()
+}
+
+// historyStatus describes where a game stands for the history page
+func historyStatus(gameData *models.Game) string {
+	switch gameData.Status {
+	case models.GameStatusWaiting:
+		return "waiting for an opponent"
+	case models.GameStatusPaused:
+		return "⏸️ paused"
+	case models.GameStatusDraw:
+		return "draw"
+	case models.GameStatusFinished:
+		if winner, ok := gameData.Players[gameData.Winner]; ok {
+			return winner.Emoji + " won"
+		}
+		return "finished"
+	default:
+		return "in progress"
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+)
+
+// PresenceChanged refreshes a game's player cards for everyone else when a
+// player connects or leaves. It is meant for events.OnPresenceChange.
+func PresenceChanged(gameID, playerID string) {
+	if gameData := game.GetGame(gameID); gameData != nil && isGamePlayer(gameData, playerID) {
+		events.BroadcastPresence(gameID, playerID, models.GameEvent{
+			Type:   "players_update",
+			GameID: gameID,
+		})
+	}
+}
+
+// broadcastPlayersUpdate tells subscribers to redraw the player cards after
+// a player joins or a game's result changes their records. The cards are
+// rendered as each event is sent, so they show who is connected at that
+// moment.
+func broadcastPlayersUpdate(gameID string) {
+	events.BroadcastGameEvent(gameID, models.GameEvent{
+		Type:   "players_update",
+		GameID: gameID,
+	})
+}
+
+// renderPlayersHTML renders a card for each seat in join order, as viewerID
+// sees it: the player's emoji and name, rating, winning streak and whether
+// they are connected, the viewer always being there. An empty seat says it
+// is waiting for an opponent.
+func renderPlayersHTML(gameData *models.Game, viewerID string) string {
+	records := game.PlayerRecords(gameData.PlayerOrder...)
+
+	var cards []string
+	for seat := 0; seat < 2; seat++ {
+		if seat >= len(gameData.PlayerOrder) {
+			cards = append(cards, fmt.Sprintf(`<div class="player-card player-card-empty" data-testid="player-card-%d"><span class="player-card-emoji">❔</span><span class="player-card-name">Waiting for an opponent</span></div>`, seat))
+			continue
+		}
+
+		playerID := gameData.PlayerOrder[seat]
+		player := gameData.Players[playerID]
+		name := player.Name
+		if name == "" {
+			name = fmt.Sprintf("Player %d", seat+1)
+		}
+		record := records[playerID]
+
+		streak := ""
+		if record.Streak > 1 {
+			streak = fmt.Sprintf(`<span class="player-card-streak" title="Games won in a row">🔥 %d</span>`, record.Streak)
+		}
+		connection, connected := "offline", "false"
+		if playerID == viewerID || events.Connected(gameData.ID, playerID) {
+			connection, connected = "online", "true"
+		}
+
+		cards = append(cards, fmt.Sprintf(`<div class="player-card" data-testid="player-card-%d"><span class="player-card-emoji">%s</span><span class="player-card-name">%s</span><span class="player-card-rating" title="Rating over %d finished games">⭐ %d</span>%s<span class="player-card-connection" data-connected="%s">%s</span></div>`,
+			seat, html.EscapeString(player.Emoji), html.EscapeString(name), record.Games, record.Rating, streak, connected, connection))
+	}
+	return `<div id="game-players" class="players-display player-cards" data-testid="game-players">` + strings.Join(cards, `<span class="player-cards-vs">vs</span>`) + `</div>`
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"htmx-go-app/engine"
+	"htmx-go-app/rng"
+	"htmx-go-app/solver"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PuzzlesHandler sends the player to a random practice puzzle, of the
+// difficulty given in the query if any
+func PuzzlesHandler(c *gin.Context) {
+	difficulty, filtered := solver.ParseDifficulty(c.Query("difficulty"))
+
+	var candidates []solver.Puzzle
+	for _, p := range solver.Puzzles() {
+		if !filtered || p.Difficulty == difficulty {
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		renderNotFound(c)
+		return
+	}
+
+	target := "/puzzles/" + candidates[rng.Intn(len(candidates))].ID
+	if filtered {
+		target += "?difficulty=" + url.QueryEscape(difficulty.String())
+	}
+	c.Redirect(http.StatusSeeOther, URLPath(target))
+}
+
+// PuzzlePageHandler shows a puzzle for the player to solve
+func PuzzlePageHandler(c *gin.Context) {
+	puzzle, err := solver.PuzzleByID(c.Param("id"))
+	if err != nil {
+		renderNotFound(c)
+		return
+	}
+
+	c.HTML(http.StatusOK, "puzzles.html", gin.H{
+		"Title":      "Puzzles",
+		"Task":       puzzleTask(puzzle),
+		"Difficulty": puzzle.Difficulty.String(),
+		"PuzzleHTML": template.HTML(renderPuzzleHTML(puzzle, nil, "")),
+		"NextURL":    nextPuzzleURL(c),
+	})
+}
+
+// PuzzleAnswerHandler checks the cell the player picked and reveals the
+// solutions
+func PuzzleAnswerHandler(c *gin.Context) {
+	puzzle, err := solver.PuzzleByID(c.Param("id"))
+	if err != nil {
+		renderNotFound(c)
+		return
+	}
+	row, err := strconv.Atoi(c.Param("row"))
+	if err != nil || row < 0 || row > 2 {
+		renderBadRequest(c, "Invalid row")
+		return
+	}
+	col, err := strconv.Atoi(c.Param("col"))
+	if err != nil || col < 0 || col > 2 {
+		renderBadRequest(c, "Invalid column")
+		return
+	}
+	picked := engine.Cell{Row: row, Col: col}
+	if puzzle.Position().Board.At(picked) != "" {
+		renderBadRequest(c, "That cell is taken")
+		return
+	}
+
+	verdict := "Not quite."
+	if puzzle.Solves(picked) {
+		verdict = "Correct!"
+	}
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, renderPuzzleHTML(puzzle, &picked, verdict))
+}
+
+// puzzleTask describes what the seat to move has to do
+func puzzleTask(puzzle solver.Puzzle) string {
+	g := puzzle.Position()
+	mark := g.Marks[g.Turn]
+	if puzzle.Kind == solver.WinInOne {
+		return mark + " to move and win"
+	}
+	return mark + " to move: don't lose"
+}
+
+// nextPuzzleURL links to another puzzle of the difficulty being practised
+func nextPuzzleURL(c *gin.Context) string {
+	if difficulty, ok := solver.ParseDifficulty(c.Query("difficulty")); ok {
+		return URLPath("/puzzles?difficulty=" + url.QueryEscape(difficulty.String()))
+	}
+	return URLPath("/puzzles")
+}
+
+// renderPuzzleHTML draws the puzzle's board. Until a cell is picked the
+// empty cells can be clicked; afterwards the board shows the pick, marks
+// the solutions and gives the verdict.
+func renderPuzzleHTML(puzzle solver.Puzzle, picked *engine.Cell, verdict string) string {
+	b := getBuffer()
+	defer putBuffer(b)
+
+	g := puzzle.Position()
+	board := g.Board
+	if picked != nil {
+		board[picked.Row][picked.Col] = g.Marks[g.Turn]
+	}
+
+	b.WriteString(`<div id="puzzle" class="puzzle" data-testid="puzzle">`)
+	b.WriteString(`<div class="game-board" data-testid="puzzle-board" role="grid" aria-label="Puzzle board">`)
+	for row := 0; row < 3; row++ {
+		b.WriteString(`<div class="game-row" role="row">`)
+		for col := 0; col < 3; col++ {
+			cell := engine.Cell{Row: row, Col: col}
+			cellValue := template.HTMLEscapeString(board[row][col])
+			label := cellAriaLabel(row, col, cellValue, "")
+			switch {
+			case picked == nil && cellValue == "":
+				fmt.Fprintf(b, `<div class="game-cell" role="gridcell" tabindex="0" data-testid="cell-%d-%d" aria-label="%s" hx-post="%s" hx-trigger="click, keyup[key=='Enter'||key==' ']" hx-target="#puzzle" hx-swap="outerHTML"></div>`, row, col, label, URLPath(fmt.Sprintf("/puzzles/%s/%d/%d", puzzle.ID, row, col)))
+			default:
+				classes := []string{"game-cell", "disabled"}
+				if picked != nil && puzzle.Solves(cell) {
+					classes = append(classes, "puzzle-solution")
+				}
+				if picked != nil && *picked == cell {
+					classes = append(classes, "puzzle-picked")
+				}
+				fmt.Fprintf(b, `<div class="%s" role="gridcell" tabindex="0" data-testid="cell-%d-%d" aria-label="%s" aria-disabled="true">%s</div>`, strings.Join(classes, " "), row, col, label, cellValue)
+			}
+		}
+		b.WriteString(`</div>`)
+	}
+	b.WriteString(`</div>`)
+
+	if picked != nil {
+		var solutions []string
+		for _, cell := range puzzle.Solutions {
+			solutions = append(solutions, fmt.Sprintf("row %d column %d", cell.Row+1, cell.Col+1))
+		}
+		fmt.Fprintf(b, `<p class="puzzle-verdict" data-testid="puzzle-verdict" role="status">%s Solutions: %s.</p>`, verdict, strings.Join(solutions, ", "))
+	}
+	b.WriteString(`</div>`)
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ManifestHandler serves the web app manifest used when installing the game
+func ManifestHandler(c *gin.Context) {
+	c.Header("Content-Type", "application/manifest+json")
+	c.Header("Cache-Control", "public, max-age=86400")
+	brand := CurrentBranding()
+	c.JSON(http.StatusOK, gin.H{
+		"name":             brand.SiteName,
+		"short_name":       brand.SiteName,
+		"description":      "Real-time multiplayer tic-tac-toe with emoji",
+		"start_url":        URLPath("/"),
+		"scope":            URLPath("/"),
+		"display":          "standalone",
+		"background_color": "#f5f5f5",
+		"theme_color":      brand.ThemeColor,
+		"icons": []gin.H{
+			{
+				"src":     URLPath("/static/icons/icon.svg"),
+				"sizes":   "any",
+				"type":    "image/svg+xml",
+				"purpose": "any maskable",
+			},
+		},
+	})
+}
+
+// ServiceWorkerHandler serves the service worker from the site root so its
+// scope covers every page. It must never be cached long, or clients would
+// keep running an old worker after a deploy.
+func ServiceWorkerHandler(staticDir string) gin.HandlerFunc {
+	swPath := filepath.Join(staticDir, "js", "sw.js")
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/javascript; charset=utf-8")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Service-Worker-Allowed", URLPath("/"))
+		c.File(swPath)
+	}
+}
+
+// OfflineHandler renders the offline shell cached by the service worker
+func OfflineHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "offline.html", gin.H{
+		"Title": "Reconnecting",
+	})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"htmx-go-app/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rate limiters for actions scripted clients could flood. They start
+// disabled; the server configures their limits at startup.
+var (
+	CreateGameLimiter = ratelimit.New(ratelimit.Limit{})
+	MoveLimiter       = ratelimit.New(ratelimit.Limit{})
+	ChatLimiter       = ratelimit.New(ratelimit.Limit{})
+)
+
+var errRateLimited = errors.New("too many requests, slow down")
+
+// rateLimitKeys identifies the client by IP and, if it has one, by player,
+// so neither switching IPs nor sharing one behind NAT gets around the limit
+// for long
+func rateLimitKeys(c *gin.Context) []string {
+	keys := []string{"ip:" + c.ClientIP()}
+	if playerID, ok := requestPlayerID(c); ok {
+		keys = append(keys, "player:"+playerID)
+	}
+	return keys
+}
+
+// RateLimit rejects requests over limiter's limit with 429 Too Many
+// Requests, as a JSON error on the JSON API and an error page or fragment
+// everywhere else
+func RateLimit(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, retryAfter := limiter.Allow(rateLimitKeys(c)...)
+		if ok {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		if isJSONAPIRequest(c) {
+			renderAPIError(c, http.StatusTooManyRequests, errRateLimited.Error())
+		} else {
+			renderError(c, http.StatusTooManyRequests, "")
+		}
+		c.Abort()
+	}
+}
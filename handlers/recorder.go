@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/rng"
+	"htmx-go-app/setting"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamRecordDir, when set, makes every SSE and WebSocket connection write
+// what the server sends it to its own log file in this directory, each line
+// stamped with the time it was written. When a player reports a missed
+// update, the log shows whether it was ever sent. Development only.
+var StreamRecordDir setting.Value[string]
+
+// streamRecorderKey is the context key holding a request's streamRecorder
+const streamRecorderKey = "streamRecorder"
+
+// streamRecorder logs one connection's output. The file is only created once
+// the response turns out to be a stream, so plain requests leave no trace.
+type streamRecorder struct {
+	dir    string
+	header string
+	mu     sync.Mutex
+	file   *os.File
+	failed bool
+	midway bool // the last write ended mid-line
+}
+
+// RecordStreams records the request's event stream, if it has one, while
+// StreamRecordDir is set
+func RecordStreams(c *gin.Context) {
+	dir := StreamRecordDir.Get()
+	if dir == "" {
+		c.Next()
+		return
+	}
+
+	recorder := &streamRecorder{
+		dir:    dir,
+		header: fmt.Sprintf("# %s %s from %s", c.Request.Method, c.Request.URL.RequestURI(), c.ClientIP()),
+	}
+	defer recorder.close()
+	c.Set(streamRecorderKey, recorder)
+	c.Writer = &recordingWriter{ResponseWriter: c.Writer, recorder: recorder}
+	c.Next()
+}
+
+// streamRecorderOf returns the request's streamRecorder, or nil if its
+// stream isn't being recorded
+func streamRecorderOf(c *gin.Context) *streamRecorder {
+	value, ok := c.Get(streamRecorderKey)
+	if !ok {
+		return nil
+	}
+	return value.(*streamRecorder)
+}
+
+// recordMessage logs a message written outside the HTTP response, such as a
+// WebSocket frame. A nil recorder records nothing.
+func (r *streamRecorder) recordMessage(message interface{}) {
+	if r == nil {
+		return
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	r.write(append(data, '\n'))
+}
+
+// recordingWriter tees an event stream response into its recorder
+type recordingWriter struct {
+	gin.ResponseWriter
+	recorder *streamRecorder
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+		w.recorder.write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Unwrap lets http.ResponseController reach the underlying connection
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// write appends data to the log, timestamping each line it starts
+func (r *streamRecorder) write(data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.open() {
+		return
+	}
+
+	stamp := clock.Now().UTC().Format(time.RFC3339Nano) + " "
+	var out bytes.Buffer
+	for len(data) > 0 {
+		if !r.midway {
+			out.WriteString(stamp)
+		}
+		line, rest, found := bytes.Cut(data, []byte("\n"))
+		out.Write(line)
+		if found {
+			out.WriteByte('\n')
+		}
+		r.midway = !found
+		data = rest
+	}
+	r.file.Write(out.Bytes())
+}
+
+// open creates the log file on first use. A file that can't be created is
+// reported once and recording is skipped for the connection.
+func (r *streamRecorder) open() bool {
+	if r.file != nil || r.failed {
+		return r.file != nil
+	}
+
+	name := clock.Now().UTC().Format("20060102T150405.000") + "-" + rng.Hex(4) + ".log"
+	err := os.MkdirAll(r.dir, 0o755)
+	if err == nil {
+		r.file, err = os.Create(filepath.Join(r.dir, name))
+	}
+	if err != nil {
+		log.Printf("recording stream: %v", err)
+		r.failed = true
+		return false
+	}
+	fmt.Fprintln(r.file, r.header)
+	return true
+}
+
+func (r *streamRecorder) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file != nil {
+		r.file.Close()
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"time"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// joinSourceCookie remembers which channel brought a visitor to a game until
+// they join it, since the tag is lost on the redirects to emoji selection
+const joinSourceCookie = "join_source"
+
+// joinSourceTTL is how long a followed link counts towards the next join
+const joinSourceTTL = time.Hour
+
+// directJoinSource is recorded for joins through links that weren't tagged
+const directJoinSource = "direct"
+
+// joinSources are the channels a game link can be tagged with as ?src=. The
+// lobby, join codes and invites tag themselves when followed.
+var joinSources = map[string]bool{
+	"link":     true, // copied from the waiting page
+	"qr":       true,
+	"email":    true,
+	"discord":  true,
+	"slack":    true,
+	"telegram": true,
+	"lobby":    true,
+	"code":     true,
+	"invite":   true,
+}
+
+// TrackJoinSource remembers the channel in a link's ?src= tag, if it is a
+// known one, so it can be recorded when the visitor joins a game
+func TrackJoinSource(c *gin.Context) {
+	if source := c.Query("src"); source != "" {
+		rememberJoinSource(c, source)
+	}
+	c.Next()
+}
+
+func rememberJoinSource(c *gin.Context, source string) {
+	if joinSources[source] {
+		c.SetCookie(joinSourceCookie, source, int(joinSourceTTL.Seconds()), URLPath("/"), "", SecureCookies, true)
+	}
+}
+
+// taggedURL returns the absolute link to path tagged with the join source
+func taggedURL(c *gin.Context, path, source string) string {
+	return absoluteURL(c, path) + "?src=" + source
+}
+
+// recordJoinSource notes which channel brought the second player, once the
+// game has filled, and forgets it so it doesn't count for a later game
+func recordJoinSource(c *gin.Context, gameData *models.Game) {
+	if gameData.Status != models.GameStatusActive || gameData.JoinSource != "" {
+		return
+	}
+
+	source, err := c.Cookie(joinSourceCookie)
+	if err != nil || !joinSources[source] {
+		source = directJoinSource
+	}
+	unlock := game.Lock(gameData)
+	gameData.JoinSource = source
+	unlock()
+	c.SetCookie(joinSourceCookie, "", -1, URLPath("/"), "", SecureCookies, true)
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"htmx-go-app/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReloadConfig re-reads the server configuration and applies the settings
+// that can change at runtime. It is set by main; nil means reloading isn't
+// available.
+var ReloadConfig func() error
+
+// AdminReloadConfigHandler reloads the configuration, as SIGHUP does
+func AdminReloadConfigHandler(c *gin.Context) {
+	if ReloadConfig == nil {
+		renderAPIError(c, http.StatusNotImplemented, "Config reloading is not available")
+		return
+	}
+	if err := ReloadConfig(); err != nil {
+		renderAPIError(c, http.StatusUnprocessableEntity, "Config not reloaded: "+err.Error())
+		return
+	}
+	recordAdminAudit(c, audit.ActionConfigReloaded, "", "", "")
+	c.JSON(http.StatusOK, gin.H{"reloaded": true})
+}
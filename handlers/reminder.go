@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	reminderTimersMu sync.Mutex
+	reminderTimers   = make(map[string]clock.Timer) // gameID -> pending reminder
+)
+
+// scheduleTurnReminder (re)starts the idle timer for the current turn. The
+// reminder is only sent if nobody has moved by the time it fires.
+// Correspondence games have no idle timer; their players are notified of
+// each turn instead.
+func scheduleTurnReminder(gameData *models.Game) {
+	reminderTimersMu.Lock()
+	defer reminderTimersMu.Unlock()
+
+	if timer, exists := reminderTimers[gameData.ID]; exists {
+		timer.Stop()
+		delete(reminderTimers, gameData.ID)
+	}
+	delay := game.TurnReminderDelay.Get()
+	if delay <= 0 || !game.IsGameActive(gameData) || gameData.Correspondence {
+		return
+	}
+
+	moveCount := gameData.MoveCount
+	reminderTimers[gameData.ID] = clock.AfterFunc(delay, func() {
+		reminderTimersMu.Lock()
+		delete(reminderTimers, gameData.ID)
+		reminderTimersMu.Unlock()
+
+		if game.IsGameActive(gameData) && gameData.MoveCount == moveCount {
+			broadcastTurnReminder(gameData, false)
+		}
+	})
+}
+
+// broadcastTurnReminder tells subscribers the active player is being waited
+// on; nudge marks reminders sent by the opponent rather than the timer
+func broadcastTurnReminder(gameData *models.Game, nudge bool) {
+	events.BroadcastGameEvent(gameData.ID, models.GameEvent{
+		Type:   "turn_reminder",
+		GameID: gameData.ID,
+		Data: map[string]interface{}{
+			"playerID": game.GetCurrentPlayerID(gameData),
+			"nudge":    nudge,
+		},
+	})
+}
+
+// NudgeHandler lets the waiting player remind their opponent, once per turn
+func NudgeHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	if err := game.Nudge(gameData, getPlayerIDFromContext(c)); err != nil {
+		renderGameError(c, err)
+		return
+	}
+
+	broadcastTurnReminder(gameData, true)
+	c.Status(http.StatusNoContent)
+}
+
+// renderTurnReminderHTML renders the reminder region. Only the player being
+// waited on sees the prompt; data-active tells script.js to pulse the board.
+func renderTurnReminderHTML(remindedPlayerID, playerID string, nudge bool) string {
+	if remindedPlayerID != playerID {
+		return `<div id="turn-reminder" class="turn-reminder" aria-live="polite"></div>`
+	}
+
+	message := "⏰ It's your turn!"
+	if nudge {
+		message = "👋 Your opponent nudged you. It's your turn!"
+	}
+	return `<div id="turn-reminder" class="turn-reminder" aria-live="polite" data-active="true">` + message + `</div>`
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"htmx-go-app/game"
+	"htmx-go-app/render"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResultPageHandler shows a finished game's result card, a page meant to be
+// shared. Games still going redirect to the game itself.
+func ResultPageHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+	if !game.IsGameFinished(gameData) {
+		c.Redirect(http.StatusSeeOther, URLPath("/game/", gameData.ID))
+		return
+	}
+
+	players := gamePlayersText(gameData)
+	result := render.ResultText(gameData)
+	shareURL := absoluteURL(c, "/game/"+gameData.ID+"/result")
+
+	c.HTML(http.StatusOK, "result.html", gin.H{
+		"Title":    "Tic-Tac-Toe: " + result,
+		"GameID":   gameData.ID,
+		"Players":  players,
+		"Result":   result,
+		"Duration": render.DurationText(gameData),
+		"Finished": gameData.FinishedAt,
+		"Locale":   viewerLocale(c),
+		"ShareURL": shareURL,
+		"Meta": PageMeta{
+			Title:       "Tic-Tac-Toe: " + result,
+			Description: players + " · " + render.DurationText(gameData),
+			URL:         shareURL,
+			Image:       absoluteURL(c, "/api/game/"+gameData.ID+"/board.png"),
+			ImageAlt:    "Final board: " + render.Caption(gameData),
+		},
+	})
+}
+
+// ResultCardSVGHandler renders a finished game's result card as an image
+func ResultCardSVGHandler(c *gin.Context) {
+	gameData := game.GetGame(c.Param("id"))
+	if gameData == nil || !game.IsGameFinished(gameData) {
+		renderNotFound(c)
+		return
+	}
+
+	setBoardImageCacheHeaders(c, gameData)
+	if notModified(c, gameData, "result.svg", "") {
+		return
+	}
+	c.Data(http.StatusOK, "image/svg+xml", render.ResultCardSVG(gameData))
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Page sizes for the results API
+const (
+	defaultResultsLimit = 50
+	maxResultsLimit     = 200
+)
+
+// apiGameResult summarizes a finished game for leaderboards and analytics
+type apiGameResult struct {
+	ID              string      `json:"id"`
+	Result          string      `json:"result"` // "win" or "draw"
+	Players         []apiPlayer `json:"players"`
+	Winner          *apiPlayer  `json:"winner,omitempty"`
+	Moves           int         `json:"moves"`
+	Notation        string      `json:"notation,omitempty"` // e.g. "1. X b2 2. O a1"; empty for games set up mid-play
+	StartedAt       time.Time   `json:"startedAt"`
+	FinishedAt      time.Time   `json:"finishedAt"`
+	DurationSeconds float64     `json:"durationSeconds"`
+}
+
+func newAPIGameResult(gameData *models.Game) apiGameResult {
+	result := apiGameResult{
+		ID:              gameData.ID,
+		Result:          "draw",
+		Players:         []apiPlayer{},
+		Moves:           gameData.MoveCount,
+		Notation:        game.Notation(gameData),
+		StartedAt:       gameData.StartedAt,
+		FinishedAt:      gameData.FinishedAt,
+		DurationSeconds: game.Duration(gameData).Seconds(),
+	}
+
+	for seat, playerID := range gameData.PlayerOrder {
+		player := apiPlayer{
+			Emoji:               gameData.Players[playerID].Emoji,
+			Name:                gameData.Players[playerID].Name,
+			Seat:                seat,
+			AverageThinkSeconds: averageThinkSeconds(gameData, playerID),
+		}
+		result.Players = append(result.Players, player)
+		if playerID == gameData.Winner {
+			result.Result = "win"
+			result.Winner = &player
+		}
+	}
+	return result
+}
+
+// APIListResultsHandler lists finished games (won or drawn) oldest first, so
+// integrations can ingest results incrementally. ?status=finished is the only
+// supported filter, ?since= (RFC 3339) skips games finished before then, and
+// ?limit= caps the page. Further pages are fetched by passing the returned
+// next cursor as ?cursor=.
+func APIListResultsHandler(c *gin.Context) {
+	if status := c.DefaultQuery("status", "finished"); status != "finished" {
+		renderAPIError(c, http.StatusBadRequest, `Only status=finished is supported`)
+		return
+	}
+
+	var since time.Time
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			renderAPIError(c, http.StatusBadRequest, "since must be an RFC 3339 timestamp")
+			return
+		}
+		since = parsed
+	}
+
+	limit := defaultResultsLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxResultsLimit {
+			renderAPIError(c, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxResultsLimit))
+			return
+		}
+		limit = n
+	}
+
+	afterTime, afterID, err := decodeResultsCursor(c.Query("cursor"))
+	if err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	finished := game.FinishedGames(since, afterTime, afterID)
+	response := gin.H{"games": []apiGameResult{}}
+	if len(finished) > limit {
+		finished = finished[:limit]
+		last := finished[limit-1]
+		response["next"] = encodeResultsCursor(last.FinishedAt, last.ID)
+	}
+
+	results := make([]apiGameResult, 0, len(finished))
+	for _, gameData := range finished {
+		results = append(results, newAPIGameResult(gameData))
+	}
+	response["games"] = results
+	c.JSON(http.StatusOK, response)
+}
+
+// A results cursor points just past the last game of a page, identified by
+// its finish time and ID since several games can finish at the same instant
+func encodeResultsCursor(finishedAt time.Time, gameID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(finishedAt.Format(time.RFC3339Nano) + " " + gameID))
+}
+
+func decodeResultsCursor(cursor string) (time.Time, string, error) {
+	if cursor == "" {
+		return time.Time{}, "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	at, gameID, _ := strings.Cut(string(raw), " ")
+	finishedAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return finishedAt, gameID, nil
+}
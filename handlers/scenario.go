@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"htmx-go-app/audit"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type scenarioRequest struct {
+	Players []struct {
+		Emoji string `json:"emoji"`
+		Name  string `json:"name"`
+	} `json:"players"`
+	Board      models.GameBoard      `json:"board"`
+	Moves      string                `json:"moves"` // e.g. "1. X b2 2. O a1", replayed instead of board and turn
+	Turn       *int                  `json:"turn"`  // seat to move, worked out from the board if absent
+	Visibility models.GameVisibility `json:"visibility"`
+}
+
+// AdminCreateScenarioHandler sets up a game in the state described by the
+// JSON body, so demos and tests can start from an edge case instead of
+// replaying every move. The response includes the player IDs, which can be
+// used as player_id cookies to take a seat.
+func AdminCreateScenarioHandler(c *gin.Context) {
+	var request scenarioRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	scenario := game.Scenario{
+		Board:      request.Board,
+		Moves:      request.Moves,
+		Turn:       request.Turn,
+		Visibility: request.Visibility,
+	}
+	for _, player := range request.Players {
+		scenario.Players = append(scenario.Players, game.ScenarioPlayer{Emoji: player.Emoji, Name: player.Name})
+	}
+
+	gameData, err := game.CreateScenario(scenario)
+	if err != nil {
+		renderAPIGameError(c, err)
+		return
+	}
+	broadcastLobbyUpdate(gameData.ID)
+	scheduleTurnReminder(gameData)
+	recordAdminAudit(c, audit.ActionScenarioCreated, gameData.ID, "", string(gameData.Status))
+
+	c.JSON(http.StatusCreated, newAdminGame(c, gameData, 0))
+}
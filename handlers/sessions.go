@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// sessionCookie holds the browser's session token next to player_id
+	sessionCookie = "session_id"
+	// sessionPlayerKey and sessionIDKey are the context keys for the player
+	// and session a request's cookies were checked against
+	sessionPlayerKey = "sessionPlayerID"
+	sessionIDKey     = "sessionID"
+)
+
+// signIn makes this browser a session of playerID, replacing any cookies it
+// had
+func signIn(c *gin.Context, playerID string) {
+	session := game.StartSession(playerID, c.Request.UserAgent(), c.ClientIP())
+	setSessionCookies(c, playerID, session.Token, int(PlayerCookieMaxAge.Get().Seconds()))
+	c.Set(sessionPlayerKey, playerID)
+	c.Set(sessionIDKey, session.ID)
+}
+
+func setSessionCookies(c *gin.Context, playerID, token string, maxAge int) {
+	c.SetCookie("player_id", playerID, maxAge, URLPath("/"), "", SecureCookies, true)
+	c.SetCookie(sessionCookie, token, maxAge, URLPath("/"), "", SecureCookies, true)
+}
+
+// CheckSession accepts the player cookie only together with a live session
+// of that player. A browser that was signed out remotely has its cookies
+// cleared and carries on as a new visitor.
+func CheckSession(c *gin.Context) {
+	playerID, err := c.Cookie("player_id")
+	if err != nil || playerID == "" || c.GetString(botPlayerKey) != "" {
+		c.Next()
+		return
+	}
+
+	token, _ := c.Cookie(sessionCookie)
+	session, started, err := game.ResumeSession(playerID, token, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		setSessionCookies(c, "", "", -1)
+		c.Next()
+		return
+	}
+	if started {
+		setSessionCookies(c, playerID, session.Token, int(PlayerCookieMaxAge.Get().Seconds()))
+	}
+	c.Set(sessionPlayerKey, playerID)
+	c.Set(sessionIDKey, session.ID)
+	c.Next()
+}
+
+// SessionsPageHandler lists the browsers signed in as this player
+func SessionsPageHandler(c *gin.Context) {
+	playerID, ok := requestPlayerID(c)
+	var sessions []models.Session
+	if ok {
+		sessions = game.PlayerSessions(playerID)
+	}
+
+	c.HTML(http.StatusOK, "sessions.html", gin.H{
+		"Title":        "Your Devices",
+		"SessionsHTML": template.HTML(renderSessionsHTML(sessions, c.GetString(sessionIDKey))),
+	})
+}
+
+// RevokeSessionHandler signs one of this player's other browsers out
+func RevokeSessionHandler(c *gin.Context) {
+	playerID, ok := requestPlayerID(c)
+	if !ok {
+		renderForbidden(c)
+		return
+	}
+	if c.Param("session") == c.GetString(sessionIDKey) {
+		renderBadRequest(c, "This is the device you are using")
+		return
+	}
+	if err := game.RevokeSession(playerID, c.Param("session")); err != nil {
+		renderNotFound(c)
+		return
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, "")
+}
+
+func renderSessionsHTML(sessions []models.Session, currentID string) string {
+	if len(sessions) == 0 {
+		return `<p data-testid="sessions-empty">No devices are signed in yet.</p>`
+	}
+
+	response := `<ul class="sessions" data-testid="sessions">`
+	for _, session := range sessions {
+		device := session.UserAgent
+		if device == "" {
+			device = "Unknown device"
+		}
+		if len(device) > 80 {
+			device = device[:80] + "…"
+		}
+
+		action := `<strong data-testid="current-session">This device</strong>`
+		if session.ID != currentID {
+			action = fmt.Sprintf(`<button hx-post="%s" hx-target="closest li" hx-swap="outerHTML" class="btn btn-secondary btn-small">Sign Out</button>`,
+				URLPath("/sessions/", session.ID, "/revoke"))
+		}
+		response += fmt.Sprintf(`<li class="session" data-testid="session-%s"><span>%s · %s · last active %s</span> %s</li>`,
+			session.ID, html.EscapeString(device), html.EscapeString(session.IP), formatAge(clock.Now().Sub(session.LastSeenAt)), action)
+	}
+	response += `</ul>`
+	return response
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GameSettingsHandler lets the creator change a waiting game's visibility,
+// join password and abandonment rule, returning the refreshed settings fragment. Anyone about to
+// join is sent the new rules as a settings_changed event.
+func GameSettingsHandler(c *gin.Context) {
+	gameID := c.Param("id")
+	gameData := game.GetGame(gameID)
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	change := game.SettingsChange{
+		Visibility:    models.GameVisibility(c.PostForm("visibility")),
+		Password:      c.PostForm("password"),
+		ClearPassword: c.PostForm("clear_password") == "true",
+		AbandonRule:   models.AbandonRule(c.PostForm("abandon_rule")),
+	}
+	if err := game.UpdateGameSettings(gameData, getPlayerIDFromContext(c), change); err != nil {
+		renderGameError(c, err)
+		return
+	}
+
+	announceSettingsChanged(gameData)
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, renderGameSettingsHTML(gameData))
+}
+
+// announceSettingsChanged refreshes the lobby and tells the game's
+// subscribers about the current rules
+func announceSettingsChanged(gameData *models.Game) {
+	broadcastLobbyUpdate(gameData.ID)
+
+	events.BroadcastGameEvent(gameData.ID, models.GameEvent{
+		Type:   "settings_changed",
+		GameID: gameData.ID,
+		Data: map[string]interface{}{
+			"visibility":       gameData.Visibility,
+			"passwordRequired": game.HasPassword(gameData),
+			"abandonRule":      string(game.AbandonRuleOf(gameData)),
+			"correspondence":   gameData.Correspondence,
+		},
+	})
+}
+
+// renderGameSettingsHTML renders the creator's settings panel on the waiting
+// page: the visibility toggle, a form to set or remove the join password and
+// the abandonment rule
+func renderGameSettingsHTML(gameData *models.Game) string {
+	settingsURL := URLPath("/api/game/", gameData.ID, "/settings")
+
+	password := fmt.Sprintf(`<form class="game-password-setting" hx-post="%s" hx-target="#game-settings" hx-swap="outerHTML"><label for="game-password-setting">Join password</label> <input type="password" id="game-password-setting" name="password" class="code-input" placeholder="New password" autocomplete="new-password" required> <button type="submit" class="btn btn-secondary btn-small">Set Password</button></form>`, settingsURL)
+	if game.HasPassword(gameData) {
+		password = fmt.Sprintf(`<div class="game-password-setting"><span>🔒 Joining needs a password.</span> <button class="btn btn-secondary btn-small" hx-post="%s" hx-vals='{"clear_password": "true"}' hx-target="#game-settings" hx-swap="outerHTML">Remove Password</button></div>`, settingsURL)
+	}
+
+	return fmt.Sprintf(`<div id="game-settings" class="game-settings">%s%s%s</div>`,
+		renderVisibilityControlHTML(gameData.ID, gameData.Visibility), password, renderAbandonRuleSettingHTML(gameData, settingsURL))
+}
+
+// abandonRuleChoices are the abandonment rules in the order they are offered
+var abandonRuleChoices = []struct {
+	rule  models.AbandonRule
+	label string
+}{
+	{models.AbandonWin, "Opponent wins"},
+	{models.AbandonVoid, "Game is void"},
+	{models.AbandonPause, "Game pauses"},
+}
+
+// renderAbandonRuleSettingHTML lets the creator choose what happens if a
+// player stops playing. It is left out while abandonment is off and for
+// correspondence games, which are never abandoned.
+func renderAbandonRuleSettingHTML(gameData *models.Game, settingsURL string) string {
+	after := game.AbandonAfter.Get()
+	if after <= 0 || gameData.Correspondence {
+		return ""
+	}
+
+	current := game.AbandonRuleOf(gameData)
+	var options strings.Builder
+	for _, choice := range abandonRuleChoices {
+		selected := ""
+		if choice.rule == current {
+			selected = " selected"
+		}
+		fmt.Fprintf(&options, `<option value="%s"%s>%s</option>`, choice.rule, selected, choice.label)
+	}
+	return fmt.Sprintf(`<div class="game-abandon-setting"><label for="game-abandon-rule">If a player is idle for %s</label> <select id="game-abandon-rule" name="abandon_rule" hx-post="%s" hx-trigger="change" hx-target="#game-settings" hx-swap="outerHTML">%s</select></div>`,
+		after, settingsURL, options.String())
+}
+
+// renderGameRulesHTML summarizes a game's settings for someone about to join
+func renderGameRulesHTML(visibility models.GameVisibility, passwordRequired bool, abandonRule models.AbandonRule, correspondence bool) string {
+	rules := "🌍 Public game"
+	if visibility == models.VisibilityPrivate {
+		rules = "🔒 Private game"
+	}
+	if passwordRequired {
+		rules += " · 🔑 password required"
+	}
+	if correspondence {
+		rules += " · 📬 correspondence: no time limit, players are notified of their turn"
+	} else if abandon := abandonRuleText(abandonRule); abandon != "" {
+		rules += " · ⏱️ " + abandon
+	}
+	return fmt.Sprintf(`<p id="game-rules" class="game-rules">%s</p>`, html.EscapeString(rules))
+}
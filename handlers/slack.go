@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"htmx-go-app/clock"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/notify"
+	"htmx-go-app/setting"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SlackSigningSecret verifies requests from the Slack app. The /slack/command
+// endpoint is disabled while it is empty.
+var SlackSigningSecret setting.Value[string]
+
+// slackRequestMaxAge is how old a signed Slack request may be, so a captured
+// one can't be replayed later
+const slackRequestMaxAge = 5 * time.Minute
+
+// slackMention matches the user a slash command names, either escaped by
+// Slack as <@U123|name> or as plain @name
+var slackMention = regexp.MustCompile(`^(<@[A-Z0-9]+(\|[^>]*)?>|@\S+)$`)
+
+// slackChallengeTTL is how long a challenge posted to a channel stays open
+// for its players to turn up
+const slackChallengeTTL = 24 * time.Hour
+
+var errInvalidSlackSignature = errors.New("invalid Slack signature")
+
+// SlackCommandHandler answers the /tictactoe slash command. "/tictactoe @user"
+// creates a private game and posts its link to the channel as a challenge;
+// the result is posted back when the game ends.
+func SlackCommandHandler(c *gin.Context) {
+	if SlackSigningSecret.Get() == "" {
+		renderAPIError(c, http.StatusNotFound, "Slack integration is disabled")
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Could not read request")
+		return
+	}
+	if err := verifySlackRequest(c.Request.Header, body); err != nil {
+		renderAPIError(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Invalid form body")
+		return
+	}
+
+	opponent := strings.TrimSpace(form.Get("text"))
+	if !slackMention.MatchString(opponent) {
+		c.JSON(http.StatusOK, gin.H{
+			"response_type": "ephemeral",
+			"text":          "Usage: " + form.Get("command") + " @user",
+		})
+		return
+	}
+
+	// Every command comes from Slack's servers, so the open-games limit goes
+	// by who issued the challenge rather than by address
+	gameData, err := game.CreateGame(slackCreatorID(form), models.GameOptions{
+		Visibility:   models.VisibilityPrivate,
+		UnclaimedTTL: slackChallengeTTL,
+	})
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"response_type": "ephemeral", "text": "Could not create a game: " + err.Error()})
+		return
+	}
+	if responseURL := form.Get("response_url"); responseURL != "" {
+		notify.FollowInSlack(gameData.ID, responseURL)
+	}
+	announceGameLifecycle("game_created", gameData)
+
+	text := "<@" + form.Get("user_id") + "> challenged " + opponent + " to tic-tac-toe: <" +
+		taggedURL(c, "/game/"+gameData.ID, "slack") + "|join the game>"
+	c.JSON(http.StatusOK, notify.SlackReply(text))
+}
+
+// slackCreatorID names the Slack user who issued a command as the creator
+// of the game it made. It can't be mistaken for a player ID.
+func slackCreatorID(form url.Values) string {
+	return "slack:" + form.Get("team_id") + ":" + form.Get("user_id")
+}
+
+// verifySlackRequest checks Slack's signature of the request body, which is
+// an HMAC-SHA256 of "v0:<timestamp>:<body>" keyed with the signing secret
+func verifySlackRequest(header http.Header, body []byte) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errInvalidSlackSignature
+	}
+	age := clock.Now().Sub(time.Unix(seconds, 0))
+	if age > slackRequestMaxAge || age < -slackRequestMaxAge {
+		return errInvalidSlackSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(SlackSigningSecret.Get()))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return errInvalidSlackSignature
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"htmx-go-app/events"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeSSEEvent writes one event and flushes it. Sequenced events carry an
+// id line so the browser reports it as Last-Event-ID when it reconnects.
+func writeSSEEvent(c *gin.Context, id uint64, eventType, data string) {
+	b := getBuffer()
+	defer putBuffer(b)
+
+	if id != 0 {
+		b.WriteString("id: ")
+		b.WriteString(strconv.FormatUint(id, 10))
+		b.WriteByte('\n')
+	}
+	b.WriteString("event: ")
+	b.WriteString(eventType)
+	b.WriteString("\ndata: ")
+	b.WriteString(data)
+	b.WriteString("\n\n")
+
+	// One write per frame, straight from the pooled buffer
+	c.Writer.Write(b.Bytes())
+	c.Writer.Flush()
+}
+
+// rejectedStreamRetry is how long browsers wait before reconnecting a
+// stream that was turned away for exceeding a connection ceiling
+const rejectedStreamRetry = 30 * time.Second
+
+// acquireStream reserves a connection slot for the client's stream to
+// gameID's events, or to a non-game stream if gameID is empty
+func acquireStream(c *gin.Context, gameID string) (release func(), err error) {
+	return events.AcquireConnection(c.ClientIP(), gameID)
+}
+
+// rejectSSEStream answers a stream over a connection ceiling with a single
+// connection_rejected event, and tells the browser to back off before it
+// reconnects
+func rejectSSEStream(c *gin.Context, data string) {
+	fmt.Fprintf(c.Writer, "retry: %d\n", rejectedStreamRetry.Milliseconds())
+	writeSSEEvent(c, 0, "connection_rejected", data)
+}
+
+// renderConnectionRejectedHTML is the error fragment for a rejected stream
+func renderConnectionRejectedHTML(err error) string {
+	return renderErrorFragmentHTML(http.StatusTooManyRequests, "Too Many Connections", capitalize(err.Error())+". Close some tabs and reload.")
+}
+
+// lastEventID reads the ID of the last event a reconnecting client saw, from
+// the Last-Event-ID header or, for clients that reconnect manually, the
+// lastEventId query parameter
+func lastEventID(c *gin.Context) (uint64, bool) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("lastEventId")
+	}
+	if value == "" {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// eventTypeFilter reads the event types a client subscribed to with the
+// types query parameter. Without one, the client gets every type except
+// defaultExcluded, which lets JSON transports skip HTML-only status events.
+func eventTypeFilter(c *gin.Context, defaultExcluded ...string) (map[string]bool, error) {
+	filter, err := events.ParseEventTypes(c.Query("types"))
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil && len(defaultExcluded) > 0 {
+		filter = events.AllEventTypesExcept(defaultExcluded...)
+	}
+	return filter, nil
+}
+
+// resumeGameStream replays the events a reconnecting client missed. It
+// reports false when the client is new or too far behind, in which case the
+// caller sends the full initial state instead.
+func resumeGameStream(c *gin.Context, subscriber *models.GameSubscriber, sink events.Sink) bool {
+	id, ok := lastEventID(c)
+	if !ok {
+		return false
+	}
+
+	subscriber.LastEventID = id
+	if events.Replay(subscriber, sink) {
+		return true
+	}
+
+	subscriber.LastEventID = 0
+	return false
+}
+
+// writeSSEHeartbeat sends an SSE comment line. Browsers ignore it, but it
+// keeps proxies from closing idle streams and surfaces dead connections as
+// write errors.
+func writeSSEHeartbeat(c *gin.Context) error {
+	if _, err := fmt.Fprint(c.Writer, ": keep-alive\n\n"); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return c.Request.Context().Err()
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+
+	"htmx-go-app/analytics"
+	"htmx-go-app/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openingRow is one line of the stats page's table of results by opening
+type openingRow struct {
+	Opening                analytics.Opening
+	Games                  int
+	FirstPlayerWinPercent  float64
+	DrawPercent            float64
+	SecondPlayerWinPercent float64
+}
+
+// StatsPageHandler renders gameplay statistics for everyone to browse
+func StatsPageHandler(c *gin.Context) {
+	stats := analytics.Compute(game.Snapshot(nil))
+	var openings []openingRow
+	for _, o := range stats.Openings {
+		openings = append(openings, openingRow{
+			Opening:                o.Opening,
+			Games:                  o.Games,
+			FirstPlayerWinPercent:  o.FirstPlayerWinRate() * 100,
+			DrawPercent:            o.DrawRate() * 100,
+			SecondPlayerWinPercent: o.SecondPlayerWinRate() * 100,
+		})
+	}
+	c.HTML(http.StatusOK, "stats.html", gin.H{
+		"Title":                 "Game Stats",
+		"Stats":                 stats,
+		"OpeningsHTML":          template.HTML(renderHeatmapHTML("stats-openings", "Number of games opened in each cell", stats.FirstMoves)),
+		"HeatmapHTML":           template.HTML(renderHeatmapHTML("stats-heatmap", "Number of times each cell was played", stats.CellPlays)),
+		"FirstPlayerWinPercent": stats.FirstPlayerWinRate * 100,
+		"Openings":              openings,
+	})
+}
+
+// APIStatsHandler returns the same statistics as JSON
+func APIStatsHandler(c *gin.Context) {
+	stats := analytics.Compute(game.Snapshot(nil))
+	c.JSON(http.StatusOK, gin.H{
+		"stats":            stats,
+		"mostPopularEmoji": stats.MostPopularEmoji(),
+	})
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"htmx-go-app/emojis"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/render"
+	"htmx-go-app/setting"
+	"htmx-go-app/telegram"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TelegramWebhookSecret, when set, must arrive with every update Telegram
+// posts to /telegram/webhook, as set with the bot's setWebhook call
+var TelegramWebhookSecret setting.Value[string]
+
+const telegramHelp = "Play tic-tac-toe here in Telegram:\n" +
+	"/new - create a game and get a code for your opponent\n" +
+	"/join <code> - join a game by its code\n" +
+	"I'll message you whenever it's your turn."
+
+// TelegramWebhookHandler handles the updates Telegram posts for the bot:
+// the /new and /join commands and presses of the board buttons
+func TelegramWebhookHandler(c *gin.Context) {
+	if !telegram.Enabled() {
+		renderAPIError(c, http.StatusNotFound, "Telegram bot is disabled")
+		return
+	}
+	secret := c.GetHeader(telegram.SecretTokenHeader)
+	if want := TelegramWebhookSecret.Get(); want != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(want)) != 1 {
+		renderAPIError(c, http.StatusUnauthorized, "Invalid secret token")
+		return
+	}
+
+	var update telegram.Update
+	if err := c.ShouldBindJSON(&update); err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Invalid update")
+		return
+	}
+
+	switch {
+	case update.CallbackQuery != nil:
+		handleTelegramButton(update.CallbackQuery)
+	case update.Message != nil && update.Message.From != nil:
+		handleTelegramCommand(update.Message)
+	}
+	// Telegram only needs to know the update arrived; replies are sent separately
+	c.Status(http.StatusOK)
+}
+
+func handleTelegramCommand(message *telegram.Message) {
+	user := game.TelegramPlayer(message.From.ID, message.Chat.ID)
+	command, argument, _ := strings.Cut(strings.TrimSpace(message.Text), " ")
+	// In groups commands may be addressed as /new@SomeBot
+	command, _, _ = strings.Cut(command, "@")
+
+	switch command {
+	case "/new":
+		telegram.SendMessage(user.ChatID, createTelegramGame(user, message.From.FirstName), nil)
+	case "/join":
+		if reply := joinTelegramGame(user, message.From.FirstName, argument); reply != "" {
+			telegram.SendMessage(user.ChatID, reply, nil)
+		}
+	default:
+		telegram.SendMessage(user.ChatID, telegramHelp, nil)
+	}
+}
+
+// createTelegramGame creates a private game with the user in the first seat
+// and returns the reply telling them how to invite an opponent
+func createTelegramGame(user *models.TelegramUser, name string) string {
+	if InMaintenanceMode() {
+		return errorPages[http.StatusServiceUnavailable].Message
+	}
+
+	gameData, err := game.CreateGame(user.PlayerID, models.GameOptions{Visibility: models.VisibilityPrivate})
+	if err != nil {
+		return "Could not create a game: " + err.Error()
+	}
+	if err := game.AddPlayerToGame(gameData, user.PlayerID, firstFreeEmoji(gameData), telegramName(name)); err != nil {
+		return "Could not create a game: " + err.Error()
+	}
+	announceGameLifecycle("game_created", gameData)
+	announcePlayerJoined(gameData, user.PlayerID)
+
+	reply := "Game created! Your opponent can join with\n/join " + gameData.Slug
+	if link := notifyURL("/game/" + gameData.ID); link != "" {
+		reply += "\nor on the web at " + link + "?src=telegram"
+	}
+	return reply
+}
+
+// joinTelegramGame seats the user in the game with the code. It returns a
+// reply only if joining failed; otherwise the turn notifications follow.
+func joinTelegramGame(user *models.TelegramUser, name, code string) string {
+	if strings.TrimSpace(code) == "" {
+		return "Which game? Send /join <code>."
+	}
+	gameData := game.ResolveGameCode(code)
+	if gameData == nil {
+		return "There's no game with that code."
+	}
+	if game.RequiresPassword(gameData, user.PlayerID) {
+		return "That game needs a password. Join it on the web instead."
+	}
+	if err := game.AddPlayerToGame(gameData, user.PlayerID, firstFreeEmoji(gameData), telegramName(name)); err != nil {
+		return capitalize(err.Error()) + "."
+	}
+	if gameData.Status == models.GameStatusActive {
+		unlock := game.Lock(gameData)
+		gameData.JoinSource = "telegram"
+		unlock()
+	}
+	announcePlayerJoined(gameData, user.PlayerID)
+	return ""
+}
+
+// firstFreeEmoji picks the first emoji nobody in the game has taken
+func firstFreeEmoji(gameData *models.Game) string {
+	return emojis.FirstFree(func(emoji string) bool {
+		return !game.IsEmojiAvailable(gameData, emoji)
+	})
+}
+
+// telegramName shortens a Telegram first name to fit as a display name
+func telegramName(name string) string {
+	runes := []rune(strings.TrimSpace(name))
+	if len(runes) > game.MaxPlayerNameLength {
+		runes = runes[:game.MaxPlayerNameLength]
+	}
+	return string(runes)
+}
+
+// handleTelegramButton makes the move a board button stands for. The pressed
+// board is replaced by the new position; the opponent is told it's their turn.
+func handleTelegramButton(query *telegram.CallbackQuery) {
+	var gameID string
+	var row, col int
+	if _, err := fmt.Sscanf(strings.ReplaceAll(query.Data, ":", " "), "move %s %d %d", &gameID, &row, &col); err != nil {
+		telegram.AnswerCallback(query.ID, "")
+		return
+	}
+
+	chatID := query.From.ID
+	if query.Message != nil {
+		chatID = query.Message.Chat.ID
+	}
+	user := game.TelegramPlayer(query.From.ID, chatID)
+	gameData := game.GetGame(gameID)
+	if gameData == nil {
+		telegram.AnswerCallback(query.ID, "That game no longer exists.")
+		return
+	}
+	if _, playing := gameData.Players[user.PlayerID]; !playing {
+		telegram.AnswerCallback(query.ID, "You're not playing in this game.")
+		return
+	}
+	if err := applyMove(gameData, user.PlayerID, row, col); err != nil {
+		telegram.AnswerCallback(query.ID, capitalize(err.Error())+".")
+		return
+	}
+
+	telegram.AnswerCallback(query.ID, "")
+	if query.Message != nil {
+		text := "Game over."
+		if game.IsGameActive(gameData) {
+			text = "You played. Waiting for " + render.PlayerLabel(gameData, game.GetCurrentPlayerID(gameData)) + "..."
+		}
+		telegram.EditMessage(chatID, query.Message.MessageID, text, telegramBoard(gameData, user, false))
+	}
+}
+
+// notifyTelegramPlayers messages the player whose turn it is, if they play
+// through Telegram, with the board to move on, and every Telegram player in
+// the game once it is over
+func notifyTelegramPlayers(gameData *models.Game) {
+	if game.IsGameFinished(gameData) {
+		for _, playerID := range gameData.PlayerOrder {
+			if user := game.TelegramUserForPlayer(playerID); user != nil {
+				telegram.SendMessage(user.ChatID, telegramResultText(gameData, playerID), telegramBoard(gameData, user, false))
+			}
+		}
+		return
+	}
+
+	user := game.TelegramUserForPlayer(game.GetCurrentPlayerID(gameData))
+	if user == nil || !game.IsGameActive(gameData) {
+		return
+	}
+	telegram.SendMessage(user.ChatID, "Your turn! "+gamePlayersText(gameData), telegramBoard(gameData, user, true))
+}
+
+func telegramResultText(gameData *models.Game, playerID string) string {
+	switch gameData.Winner {
+	case "":
+		return "It's a draw! " + gamePlayersText(gameData)
+	case playerID:
+		return "You win! 🏆 " + gamePlayersText(gameData)
+	default:
+		return render.PlayerLabel(gameData, gameData.Winner) + " wins. " + gamePlayersText(gameData)
+	}
+}
+
+// telegramBoard draws the board as an inline keyboard. With playable set,
+// each cell is a button for moving there. A last row links to the game on
+// the web, signing the browser in as the player, when a public URL is known.
+func telegramBoard(gameData *models.Game, user *models.TelegramUser, playable bool) *telegram.Keyboard {
+	keyboard := &telegram.Keyboard{}
+	for row, cells := range game.SymbolBoard(gameData) {
+		var buttons []telegram.Button
+		for col, mark := range cells {
+			if mark == "" {
+				mark = "·"
+			}
+			data := "done"
+			if playable {
+				data = "move:" + gameData.ID + ":" + strconv.Itoa(row) + ":" + strconv.Itoa(col)
+			}
+			buttons = append(buttons, telegram.Button{Text: mark, Data: data})
+		}
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, buttons)
+	}
+	if link := notifyURL("/telegram/login/" + user.LoginToken + "?game=" + gameData.ID); link != "" {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []telegram.Button{{Text: "Open in browser", URL: link}})
+	}
+	return keyboard
+}
+
+// TelegramLoginHandler signs the browser in as a Telegram user's player, so
+// they can keep playing on the web, and opens the game the link was for
+func TelegramLoginHandler(c *gin.Context) {
+	user := game.TelegramUserByLogin(c.Param("token"))
+	if user == nil {
+		renderError(c, http.StatusGone, "")
+		return
+	}
+
+	signIn(c, user.PlayerID)
+	if gameData := game.GetGame(c.Query("game")); gameData != nil {
+		c.Redirect(http.StatusSeeOther, URLPath("/game/", gameData.ID))
+		return
+	}
+	c.Redirect(http.StatusSeeOther, URLPath("/"))
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"htmx-go-app/setting"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limits for ordinary requests, off unless the server configuration sets
+// them. Event streams are exempt from RequestTimeout as they stay open.
+var (
+	RequestTimeout     setting.Value[time.Duration] // zero disables the timeout
+	MaxRequestBodySize setting.Value[int64]         // zero disables the limit
+)
+
+// LimitRequestBody rejects bodies larger than MaxRequestBodySize, and stops
+// reading ones that turn out larger than their Content-Length claimed
+func LimitRequestBody(c *gin.Context) {
+	limit := MaxRequestBodySize.Get()
+	if limit <= 0 || c.Request.Body == nil {
+		c.Next()
+		return
+	}
+
+	if c.Request.ContentLength > limit {
+		if isJSONAPIRequest(c) {
+			renderAPIError(c, http.StatusRequestEntityTooLarge, "Request body too large")
+		} else {
+			renderError(c, http.StatusRequestEntityTooLarge, "")
+		}
+		c.Abort()
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	c.Next()
+}
+
+// timeoutResponseGrace is how long past the request's deadline the
+// connection stays writable, so the timeout response itself can go out
+const timeoutResponseGrace = time.Second
+
+// Timeout bounds how long a request may take. Handlers see a context
+// deadline, and the connection gets read and write deadlines so a client
+// that trickles its body or reads the response slowly is cut off. The
+// response is held back until the handler finishes, and a handler still
+// running at the deadline is answered with 503 Service Unavailable.
+func Timeout(c *gin.Context) {
+	timeout := RequestTimeout.Get()
+	if timeout <= 0 {
+		c.Next()
+		return
+	}
+
+	deadline := time.Now().Add(timeout)
+	ctx, cancel := context.WithDeadline(c.Request.Context(), deadline)
+	defer cancel()
+	c.Request = c.Request.WithContext(ctx)
+
+	// The deadlines belong to the connection, so they are cleared again for
+	// whatever request comes next on it; that may be a long-lived stream
+	rc := http.NewResponseController(c.Writer)
+	rc.SetReadDeadline(deadline)
+	rc.SetWriteDeadline(deadline.Add(timeoutResponseGrace))
+	defer func() {
+		rc.SetReadDeadline(time.Time{})
+		rc.SetWriteDeadline(time.Time{})
+	}()
+
+	tw := newTimeoutWriter(c.Writer, isJSONAPIRequest(c))
+	c.Writer = tw
+	timer := time.AfterFunc(timeout, tw.timeOut)
+	defer func() {
+		// A panicking handler's partial response is dropped for the
+		// recovery handler to replace
+		timer.Stop()
+		c.Writer = tw.ResponseWriter
+		tw.discard()
+	}()
+
+	// A handler that gave up once the context expired still ran out of time
+	c.Next()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		tw.timeOut()
+	} else {
+		tw.release()
+	}
+}
+
+// timeoutWriter buffers a handler's response, headers and all, so that
+// Timeout can send either it or a 503 in its place. Only the goroutine that
+// gets there first touches the real writer, under mu.
+type timeoutWriter struct {
+	gin.ResponseWriter // the real writer
+
+	mu          sync.Mutex
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	done        bool // the real writer has been given its response
+	json        bool // the timeout is reported as a JSON error
+}
+
+func newTimeoutWriter(w gin.ResponseWriter, json bool) *timeoutWriter {
+	return &timeoutWriter{ResponseWriter: w, header: w.Header().Clone(), status: http.StatusOK, json: json}
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.wroteHeader {
+		w.status = status
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.wroteHeader = true
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.wroteHeader = true
+	return w.body.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.wroteHeader {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.wroteHeader
+}
+
+// Flush does nothing: the response goes out once the handler is done
+func (w *timeoutWriter) Flush() {}
+
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("timeoutWriter: connections under a request timeout can't be hijacked")
+}
+
+// release sends the handler's response, unless the timeout beat it
+func (w *timeoutWriter) release() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done {
+		return
+	}
+	w.done = true
+
+	header := w.ResponseWriter.Header()
+	clear(header)
+	for key, values := range w.header {
+		header[key] = values
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.wroteHeader {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if w.body.Len() > 0 {
+		w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
+
+// discard drops whatever the handler wrote that hasn't been sent
+func (w *timeoutWriter) discard() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.done = true
+}
+
+// timeOut answers a request whose handler is still running with 503
+func (w *timeoutWriter) timeOut() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done {
+		return
+	}
+	w.done = true
+
+	body := "Request timed out"
+	header := w.ResponseWriter.Header()
+	if w.json {
+		header.Set("Content-Type", "application/json; charset=utf-8")
+		body = `{"error":"` + body + `"}`
+	} else {
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	// The client can read the whole response while the handler carries on,
+	// and won't send another request on a connection it is still holding
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	header.Set("Connection", "close")
+	w.ResponseWriter.WriteHeader(http.StatusServiceUnavailable)
+	w.ResponseWriter.WriteString(body)
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"net/http"
+
+	"htmx-go-app/buildinfo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VersionHandler reports the version, commit and build time of the running server
+func VersionHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
+}
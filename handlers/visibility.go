@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GameVisibilityHandler lets the waiting creator switch the game between public and private
+func GameVisibilityHandler(c *gin.Context) {
+	gameID := c.Param("id")
+	gameData := game.GetGame(gameID)
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	playerID := getPlayerIDFromContext(c)
+	if !game.IsFirstPlayer(gameData, playerID) {
+		renderForbidden(c)
+		return
+	}
+
+	visibility := models.GameVisibility(c.PostForm("visibility"))
+	if err := game.SetGameVisibility(gameData, visibility); err != nil {
+		renderGameError(c, err)
+		return
+	}
+
+	announceSettingsChanged(gameData)
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, renderVisibilityControlHTML(gameID, gameData.Visibility))
+}
+
+// renderVisibilityControlHTML renders the current visibility with a button to flip it
+func renderVisibilityControlHTML(gameID string, visibility models.GameVisibility) string {
+	description := "🌍 Public: listed in the open games lobby."
+	toggleTo := models.VisibilityPrivate
+	toggleLabel := "Make Private"
+	if visibility == models.VisibilityPrivate {
+		description = "🔒 Private: only people with the link or code can join."
+		toggleTo = models.VisibilityPublic
+		toggleLabel = "Make Public"
+	}
+
+	return fmt.Sprintf(`<div id="game-visibility" class="game-visibility" data-visibility="%s"><span>%s</span> <button class="btn btn-secondary btn-small" hx-post="%s" hx-vals='{"visibility": "%s"}' hx-target="#game-visibility" hx-swap="outerHTML">%s</button></div>`,
+		visibility, description, URLPath("/api/game/", gameID, "/visibility"), toggleTo, toggleLabel)
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"htmx-go-app/models"
+	"htmx-go-app/webhooks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// webhookEvents maps lobby lifecycle event types to webhook event names
+var webhookEvents = map[string]string{
+	"game_created":  webhooks.EventGameCreated,
+	"game_filled":   webhooks.EventGameStarted,
+	"game_finished": webhooks.EventGameFinished,
+}
+
+// announceGameLifecycle tells lobby subscribers, configured webhooks and
+// chat services that a game was created, filled or finished. A finished
+// game also changes its players' ratings and streaks on their cards.
+func announceGameLifecycle(eventType string, gameData *models.Game) {
+	broadcastLobbyGameEvent(eventType, gameData)
+	if eventType == "game_finished" {
+		broadcastPlayersUpdate(gameData.ID)
+	}
+	webhooks.Notify(webhookEvents[eventType], newGameSummary(gameData))
+	notifyChatServices(eventType, gameData)
+}
+
+// WebhookDeliveriesHandler lists recent webhook deliveries, newest first. It
+// is part of the admin API, as the log shows where events go and why they
+// failed.
+func WebhookDeliveriesHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"deliveries": webhooks.Deliveries()})
+}
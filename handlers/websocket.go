@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+var (
+	errMissingCell    = errors.New("row and col are required")
+	errUnknownMessage = errors.New("unknown message type")
+)
+
+// wsWriteTimeout bounds how long a ping may block on a stalled connection
+const wsWriteTimeout = 10 * time.Second
+
+// The default upgrader only accepts same-origin connections
+var upgrader = websocket.Upgrader{}
+
+// wsInbound is a message sent by the client over the WebSocket
+type wsInbound struct {
+	Type string `json:"type"`
+	Row  *int   `json:"row"`
+	Col  *int   `json:"col"`
+}
+
+// wsSink writes events to a WebSocket connection as JSON. Writes are
+// serialized because events and move errors are sent from different
+// goroutines. Only Send, which runs on the handler's goroutine, uses c.
+type wsSink struct {
+	c        *gin.Context
+	conn     *websocket.Conn
+	playerID string
+	recorder *streamRecorder
+	mu       sync.Mutex
+}
+
+func (s *wsSink) Send(event models.GameEvent) error {
+	return s.write(newAPIEvent(s.c, event, s.playerID))
+}
+
+// Heartbeat pings the client; a failed ping closes the stream
+func (s *wsSink) Heartbeat() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+}
+
+func (s *wsSink) sendError(err error) error {
+	return s.write(apiEvent{Type: "error", Error: capitalize(err.Error())})
+}
+
+func (s *wsSink) write(message apiEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recorder.recordMessage(message)
+	return s.conn.WriteJSON(message)
+}
+
+// GameWebSocketHandler streams the same game events as GameSSEHandler over a
+// WebSocket, and accepts {"type":"move","row":r,"col":c} messages on it
+func GameWebSocketHandler(c *gin.Context) {
+	gameID := c.Param("id")
+
+	gameData := game.GetGame(gameID)
+	if gameData == nil {
+		renderNotFound(c)
+		return
+	}
+
+	// game_status only re-renders HTML, so it is skipped unless asked for
+	eventTypes, err := eventTypeFilter(c, "game_status")
+	if err != nil {
+		renderAPIError(c, http.StatusBadRequest, "Unknown event type")
+		return
+	}
+
+	release, err := acquireStream(c, gameID)
+	if err != nil {
+		renderAPIError(c, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	defer release()
+
+	playerID := getPlayerIDFromContext(c)
+
+	// Pass along any headers set so far, such as a new player cookie
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, c.Writer.Header())
+	if err != nil {
+		// The upgrader has already written an error response
+		return
+	}
+	defer conn.Close()
+
+	// A hijacked connection outlives the request context, so the read loop
+	// cancels the subscription when the client goes away
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscriber := events.CreateTransportSubscriber(gameID, playerID, events.TransportWebSocket, ctx)
+	subscriber.EventTypes = eventTypes
+	defer events.RemoveGameSubscriber(subscriber)
+
+	// A message can be no bigger than a request body
+	if limit := MaxRequestBodySize.Get(); limit > 0 {
+		conn.SetReadLimit(limit)
+	}
+
+	sink := &wsSink{c: c, conn: conn, playerID: playerID, recorder: streamRecorderOf(c)}
+	if !resumeGameStream(c, subscriber, sink) {
+		if err := sink.Send(models.GameEvent{Type: "initial", GameID: gameID}); err != nil {
+			return
+		}
+	}
+
+	// The read loop gets what it needs from the request up front, since the
+	// gin.Context isn't safe to share with it, and the handler waits for it
+	// to stop before returning the context to gin
+	limitKeys := rateLimitKeys(c)
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		defer cancel()
+		for {
+			var message wsInbound
+			if err := conn.ReadJSON(&message); err != nil {
+				return
+			}
+			if err := handleWebSocketMessage(limitKeys, gameData, playerID, message); err != nil {
+				if sink.sendError(err) != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	events.Serve(subscriber, sink)
+	conn.Close()
+	<-readerDone
+}
+
+// handleWebSocketMessage applies an inbound message. Successful moves are
+// reported back through the broadcaster like any other move.
+func handleWebSocketMessage(limitKeys []string, gameData *models.Game, playerID string, message wsInbound) error {
+	switch message.Type {
+	case "move":
+		if message.Row == nil || message.Col == nil {
+			return errMissingCell
+		}
+		if ok, _ := MoveLimiter.Allow(limitKeys...); !ok {
+			return errRateLimited
+		}
+		return applyMove(gameData, playerID, *message.Row, *message.Col)
+	default:
+		return errUnknownMessage
+	}
+}
@@ -1,9 +1,20 @@
 package main
 
 import (
+	"errors"
 	"html/template"
+	"log"
+	"net/http"
+	"os"
 
+	"htmx-go-app/audit"
+	"htmx-go-app/buildinfo"
+	"htmx-go-app/config"
+	"htmx-go-app/events"
+	"htmx-go-app/game"
 	"htmx-go-app/handlers"
+	"htmx-go-app/rng"
+	"htmx-go-app/webhooks"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-contrib/multitemplate"
@@ -17,35 +28,207 @@ func createMyRender() multitemplate.Renderer {
 		"isHXRequest": func(c *gin.Context) bool {
 			return c.GetHeader("HX-Request") == "true"
 		},
+		"path":         handlers.URLPath,
+		"announcement": handlers.AnnouncementHTML,
+		"brand":        handlers.CurrentBranding,
+		"localTime":    handlers.LocalTime,
 	}
 	
 	// Add templates with base template inheritance
 	r.AddFromFilesFuncs("home.html", funcMap, "templates/layouts/base.html", "templates/pages/home.html")
 	r.AddFromFilesFuncs("game.html", funcMap, "templates/layouts/base.html", "templates/pages/game.html")
 	r.AddFromFilesFuncs("emoji-selection.html", funcMap, "templates/layouts/base.html", "templates/pages/emoji-selection.html")
-	r.AddFromFilesFuncs("game-full.html", funcMap, "templates/layouts/base.html", "templates/pages/game-full.html")
-	r.AddFromFilesFuncs("404.html", funcMap, "templates/layouts/base.html", "templates/pages/404.html")
+	r.AddFromFilesFuncs("join-state.html", funcMap, "templates/layouts/fragment.html", "templates/pages/emoji-selection.html")
+	r.AddFromFilesFuncs("error.html", funcMap, "templates/layouts/base.html", "templates/pages/error.html")
+	r.AddFromFilesFuncs("offline.html", funcMap, "templates/layouts/base.html", "templates/pages/offline.html")
+	r.AddFromFilesFuncs("lobby.html", funcMap, "templates/layouts/base.html", "templates/pages/lobby.html")
+	r.AddFromFilesFuncs("quick-match.html", funcMap, "templates/layouts/base.html", "templates/pages/quick-match.html")
+	r.AddFromFilesFuncs("stats.html", funcMap, "templates/layouts/base.html", "templates/pages/stats.html")
+	r.AddFromFilesFuncs("result.html", funcMap, "templates/layouts/base.html", "templates/pages/result.html")
+	r.AddFromFilesFuncs("puzzles.html", funcMap, "templates/layouts/base.html", "templates/pages/puzzles.html")
+	r.AddFromFilesFuncs("sessions.html", funcMap, "templates/layouts/base.html", "templates/pages/sessions.html")
+	r.AddFromFilesFuncs("history.html", funcMap, "templates/layouts/base.html", "templates/pages/history.html")
 	
 	return r
 }
 
 func main() {
-	r := gin.Default()
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	log.Printf("tic-tac-toe %s", buildinfo.Get())
+
+	currentConfig = cfg
+	applySettings(cfg)
+	handlers.SetMaintenanceMode(cfg.MaintenanceMode)
+	handlers.SecureCookies = cfg.CookieSecure || cfg.TLS()
+	handlers.PublicBaseURL = cfg.BaseURL
+	handlers.BasePath = cfg.BasePath
+	handlers.Headless = cfg.Headless
+	handlers.ReloadConfig = reloadConfig
+	game.OnGameRemoved = handlers.GameRemoved
+	events.OnPresenceChange = handlers.PresenceChanged
+	if cfg.RandomSeed != 0 {
+		rng.Seed(int64(cfg.RandomSeed))
+		log.Printf("warning: randomness is seeded with %d, game IDs and join codes are predictable", cfg.RandomSeed)
+	}
+
+	if cfg.AuditLogFile != "" {
+		if err := audit.Open(cfg.AuditLogFile); err != nil {
+			log.Fatal(err)
+		}
+		defer audit.Close()
+	}
+
+	game.ConfigureStore(cfg.StoreShards)
+	if cfg.SnapshotFile != "" {
+		restored, err := game.LoadSnapshot(cfg.SnapshotFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("restored %d games from %s", restored, cfg.SnapshotFile)
+	}
+
+	if len(cfg.WebhookURLs) > 0 {
+		webhooks.Configure(webhooks.Config{
+			URLs:   cfg.WebhookURLs,
+			Secret: cfg.WebhookSecret,
+		})
+	}
+
+	stopSweep := events.StartSubscriberSweep()
+	defer stopSweep()
+	stopAbandonSweep := handlers.StartAbandonSweep()
+	defer stopAbandonSweep()
+
+	// The nats bus shares game events with other instances
+	if cfg.EventBus == "nats" {
+		bus, err := events.NewNATSBus(cfg.NATSURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer bus.Close()
+		events.UseBus(bus)
+	}
+
+	r := gin.New()
+	// Client IPs key the rate limits and connection caps, so
+	// X-Forwarded-For is only believed from the configured proxies
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("trusted proxies: %v", err)
+	}
+	r.Use(gin.Logger(), gin.CustomRecovery(handlers.RecoveryHandler))
 
 	r.HTMLRender = createMyRender()
-	r.Static("/static", "./static")
+	// Every route lives under the configured base path
+	app := r.Group(handlers.BasePath, handlers.RequireAPIRouteWhenHeadless, handlers.InjectLatency, handlers.RecordStreams, handlers.AuthenticateBot, handlers.CheckSession, handlers.RejectBannedPlayers, handlers.LimitRequestBody, handlers.TrackJoinSource)
+
+	// Event streams stay open for good, so they are registered before the
+	// request timeout is added to the group
+	app.GET("/api/game/:id/events", handlers.GameSSEHandler)
+	app.GET("/api/game/:id/ws", handlers.GameWebSocketHandler)
+	app.GET("/api/lobby/events", handlers.LobbySSEHandler)
+	app.GET("/api/matchmaking/events", handlers.BlockDuringMaintenance, handlers.MatchmakingSSEHandler)
+	app.GET("/api/v1/game/:id/events", handlers.APIGameEventsHandler)
+	app.GET("/api/v1/bots/me/turns", handlers.APIBotTurnsHandler)
+	app.Use(handlers.Timeout)
+
+	app.Static("/static", "./static")
 
 	// Main pages
-	r.GET("/", handlers.HomeHandler)
-	r.GET("/new-game", handlers.NewGameHandler)
-	r.GET("/game/:id", handlers.GamePageHandler)
-	r.GET("/game/:id/select-emoji", handlers.EmojiSelectionHandler)
-	r.POST("/game/:id/select-emoji", handlers.EmojiSelectionSubmitHandler)
+	app.GET("/", handlers.HomeHandler)
+	app.GET("/new-game", handlers.BlockDuringMaintenance, handlers.RateLimit(handlers.CreateGameLimiter), handlers.NewGameHandler)
+	app.POST("/new-game", handlers.BlockDuringMaintenance, handlers.RateLimit(handlers.CreateGameLimiter), handlers.NewGameHandler)
+	app.GET("/join", handlers.BlockDuringMaintenance, handlers.JoinByCodeHandler)
+	app.GET("/join/:token", handlers.BlockDuringMaintenance, handlers.InviteRedeemHandler)
+	app.GET("/continue/:token", handlers.DeviceLinkRedeemHandler)
+	app.GET("/sessions", handlers.SessionsPageHandler)
+	app.POST("/sessions/:session/revoke", handlers.RevokeSessionHandler)
+	app.GET("/history", handlers.HistoryPageHandler)
+	app.GET("/lobby", handlers.LobbyHandler)
+	app.GET("/lobby/join/:id", handlers.BlockDuringMaintenance, handlers.LobbyJoinHandler)
+	app.GET("/quick-match", handlers.BlockDuringMaintenance, handlers.QuickMatchHandler)
+	app.GET("/stats", handlers.StatsPageHandler)
+	app.GET("/puzzles", handlers.PuzzlesHandler)
+	app.GET("/puzzles/:id", handlers.PuzzlePageHandler)
+	app.POST("/puzzles/:id/:row/:col", handlers.PuzzleAnswerHandler)
+	app.GET("/archive/feed.atom", handlers.ArchiveFeedHandler)
+	app.GET("/game/:id", handlers.GamePageHandler)
+	app.GET("/game/:id/select-emoji", handlers.EmojiSelectionHandler)
+	app.GET("/game/:id/join-state", handlers.JoinStateHandler)
+	app.POST("/game/:id/select-emoji", handlers.BlockDuringMaintenance, handlers.EmojiSelectionSubmitHandler)
+	app.GET("/game/:id/qr.png", handlers.QRCodeHandler)
+	app.GET("/game/:id/result", handlers.ResultPageHandler)
+	app.GET("/game/:id/result.svg", handlers.ResultCardSVGHandler)
+
+	// Progressive web app
+	app.GET("/manifest.webmanifest", handlers.ManifestHandler)
+	app.GET("/sw.js", handlers.ServiceWorkerHandler("./static"))
+	app.GET("/offline", handlers.OfflineHandler)
 	
 	// Game API endpoints
-	r.POST("/api/game/:id/move/:row/:col", handlers.GameMoveHandler)
-	r.POST("/api/game/:id/reset", handlers.GameResetHandler)
-	r.GET("/api/game/:id/events", handlers.GameSSEHandler)
+	app.POST("/api/game/:id/move/:row/:col", handlers.RequireGamePlayer, handlers.RateLimit(handlers.MoveLimiter), handlers.GameMoveHandler)
+	app.POST("/api/game/:id/reset", handlers.RequireGamePlayer, handlers.GameResetHandler)
+	app.POST("/api/game/:id/chat", handlers.RequireGamePlayer, handlers.RateLimit(handlers.ChatLimiter), handlers.ChatHandler)
+	app.POST("/api/game/:id/nudge", handlers.RequireGamePlayer, handlers.NudgeHandler)
+	app.POST("/api/game/:id/resume", handlers.RequireGamePlayer, handlers.ResumeGameHandler)
+	app.POST("/api/game/:id/pause", handlers.RequireGamePlayer, handlers.PauseGameHandler)
+	app.POST("/api/game/:id/pause/decline", handlers.RequireGamePlayer, handlers.DeclinePauseHandler)
+	app.POST("/api/game/:id/device-link", handlers.RequireGamePlayer, handlers.CreateDeviceLinkHandler)
+	app.POST("/api/preferences/cues", handlers.ToggleCuesHandler)
+	app.POST("/api/preferences/badges", handlers.TogglePieceBadgesHandler)
+	app.POST("/api/preferences/locale", handlers.SetLocaleHandler)
+	app.DELETE("/api/game/:id", handlers.CancelGameHandler)
+	app.POST("/api/game/:id/visibility", handlers.GameVisibilityHandler)
+	app.POST("/api/game/:id/settings", handlers.GameSettingsHandler)
+	app.POST("/api/game/:id/invites", handlers.CreateInviteHandler)
+	app.GET("/api/game/:id/events/history", handlers.RequirePrivateGamePlayer, handlers.EventHistoryHandler)
+	app.GET("/api/game/:id/fragment/:section", handlers.RequireGamePlayer, handlers.GameFragmentHandler)
+	app.GET("/api/game/:id/board.svg", handlers.BoardSVGHandler)
+	app.GET("/api/game/:id/board.png", handlers.BoardPNGHandler)
+	app.POST("/slack/command", handlers.SlackCommandHandler)
+	app.POST("/telegram/webhook", handlers.TelegramWebhookHandler)
+	app.GET("/telegram/login/:token", handlers.TelegramLoginHandler)
+	app.GET("/metrics", handlers.MetricsHandler)
+	app.GET("/api/version", handlers.VersionHandler)
+
+	// JSON API
+	app.POST("/api/v1/games", handlers.BlockDuringMaintenance, handlers.RateLimit(handlers.CreateGameLimiter), handlers.APICreateGameHandler)
+	app.GET("/api/v1/games", handlers.APIListResultsHandler)
+	app.GET("/api/v1/stats", handlers.APIStatsHandler)
+	app.GET("/api/v1/game/:id", handlers.APIGetGameHandler)
+	app.POST("/api/v1/game/:id/join", handlers.BlockDuringMaintenance, handlers.APIJoinGameHandler)
+	app.POST("/api/v1/game/:id/move", handlers.RateLimit(handlers.MoveLimiter), handlers.APIMoveHandler)
+	app.POST("/api/v1/bots", handlers.BlockDuringMaintenance, handlers.RateLimit(handlers.CreateGameLimiter), handlers.APIRegisterBotHandler)
+	app.GET("/api/v1/bots/me", handlers.APIBotHandler)
+
+	// Admin API
+	admin := app.Group("/api/admin", handlers.RequireAdminKey)
+	admin.GET("/games", handlers.AdminListGamesHandler)
+	admin.DELETE("/games/:id", handlers.AdminDeleteGameHandler)
+	admin.PATCH("/games/:id", handlers.AdminCorrectGameHandler)
+	admin.GET("/bans", handlers.AdminListBansHandler)
+	admin.POST("/players/:id/ban", handlers.AdminBanPlayerHandler)
+	admin.DELETE("/players/:id/ban", handlers.AdminUnbanPlayerHandler)
+	admin.GET("/stats", handlers.AdminStatsHandler)
+	admin.GET("/announcement", handlers.AdminGetAnnouncementHandler)
+	admin.POST("/announcement", handlers.AdminPostAnnouncementHandler)
+	admin.DELETE("/announcement", handlers.AdminClearAnnouncementHandler)
+	admin.GET("/maintenance", handlers.AdminGetMaintenanceHandler)
+	admin.PUT("/maintenance", handlers.AdminSetMaintenanceHandler)
+	admin.POST("/reload", handlers.AdminReloadConfigHandler)
+	admin.GET("/audit", handlers.AdminAuditLogHandler)
+	admin.GET("/bots", handlers.AdminListBotsHandler)
+	admin.DELETE("/bots/:id", handlers.AdminDeleteBotHandler)
+	admin.POST("/scenarios", handlers.AdminCreateScenarioHandler)
+	admin.GET("/backup", handlers.AdminBackupHandler)
+	admin.POST("/restore", handlers.AdminRestoreHandler)
+	admin.GET("/webhooks/deliveries", handlers.WebhookDeliveriesHandler)
 
-	r.Run(":8080")
+	r.NoRoute(handlers.NotFoundHandler)
+
+	if err := run(cfg, r); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
 }
\ No newline at end of file
@@ -3,13 +3,17 @@ package models
 import (
 	"context"
 	"time"
+
+	"htmx-go-app/engine"
 )
 
-type GameBoard [3][3]string
+// GameBoard holds each piece's seat letter, X or O, "" for empty cells
+type GameBoard = engine.Board
 
 type Player struct {
 	ID       string
 	Emoji    string
+	Name     string // optional display name
 	JoinedAt time.Time
 }
 
@@ -20,33 +24,163 @@ const (
 	GameStatusActive   GameStatus = "active"   // Game is being played
 	GameStatusFinished GameStatus = "finished" // Game finished with a winner
 	GameStatusDraw     GameStatus = "draw"     // Game finished in a draw
+	GameStatusPaused   GameStatus = "paused"   // Game on hold until a player resumes it
+)
+
+type GameVisibility string
+
+const (
+	VisibilityPublic  GameVisibility = "public"  // listed in the lobby
+	VisibilityPrivate GameVisibility = "private" // joinable only via direct link or code
+)
+
+// AbandonRule decides what happens to a game when the player to move stops playing
+type AbandonRule string
+
+const (
+	AbandonWin   AbandonRule = "win"   // the waiting player wins
+	AbandonVoid  AbandonRule = "void"  // the game is removed without a result
+	AbandonPause AbandonRule = "pause" // the game is paused until a player resumes it
 )
 
 const MaxPlayersPerGame = 2
 
+const BoardSize = engine.Size // rows and columns on the board
+
 type Game struct {
-	ID          string
-	Board       GameBoard
-	Players     map[string]*Player // playerID -> Player
-	PlayerOrder []string           // track join order
-	Status      GameStatus         // current game status
-	CurrentTurn int                // index into PlayerOrder (0 or 1)
-	Winner      string             // playerID of winner (if any)
-	MoveCount   int                // total moves made
+	ID             string
+	Slug           string // human-friendly join code, e.g. "blue-tiger-42"
+	Board          GameBoard
+	Players        map[string]*Player // playerID -> Player
+	PlayerOrder    []string           // track join order
+	Status         GameStatus         // current game status
+	CurrentTurn    int                // index into PlayerOrder (0 or 1)
+	Winner         string             // playerID of winner (if any)
+	MoveCount      int                // total moves made
+	Version        int                // bumped on every change to the board, players, status or settings
+	Moves          []Move             // moves played so far, in order; empty for games set up mid-play
+	CreatedAt      time.Time          // when the game was created
+	StartedAt      time.Time          // when the second player joined, or the game was last reset
+	FinishedAt     time.Time          // when the game was won or drawn (zero while it is going)
+	Visibility     GameVisibility     // whether the game is listed in the lobby
+	CreatorID      string             // playerID of whoever created the game
+	CreatorIP      string             // address the game was created from, for abuse limits
+	PasswordHash   []byte             // bcrypt hash of the join password (empty if none)
+	Chat           []ChatMessage      // most recent chat messages, oldest first
+	Nudged         bool               // whether the current turn has been nudged by the opponent
+	JoinSource     string             // channel that brought the second player, e.g. "qr" or "lobby"
+	AbandonRule    AbandonRule        // what happens if the player to move goes idle; "" means AbandonWin
+	AbandonedBy    string             // playerID whose idleness ended or paused the game
+	ResumedAt      time.Time          // when the game was last resumed after a pause
+	PauseAsker     string             // playerID waiting for the opponent to agree to pause
+	Correspondence bool               // played by notification: no turn timer, players are told when it's their turn
+	UnclaimedTTL   time.Duration      // how long the game waits for its first player; zero means game.UnclaimedGameTTL
+}
+
+// Move is one move played in a game. The embedded cell gives its Row and Col.
+type Move struct {
+	engine.Cell
+	PlayerID string
+	Number   int       // counted from 1
+	At       time.Time // when it was played
+}
+
+// ChatMessage is a message posted in a game's chat panel
+type ChatMessage struct {
+	PlayerID string
+	Emoji    string
+	Name     string
+	Text     string
+	SentAt   time.Time
+}
+
+// Announcement is a server-wide notice shown at the top of every page
+type Announcement struct {
+	ID        uint64
+	Message   string
+	PostedAt  time.Time
+	ExpiresAt time.Time // zero means it stays up until cleared
+}
+
+// GameOptions are the settings chosen when creating a game
+type GameOptions struct {
+	Visibility     GameVisibility
+	Password       string        // optional join password, only stored hashed
+	CreatorIP      string        // client address, used to cap open games per IP
+	AbandonRule    AbandonRule   // "" means AbandonWin
+	Correspondence bool          // play by notification, with no turn timer
+	UnclaimedTTL   time.Duration // how long to wait for a first player, for games posted as challenges; zero means the default
+}
+
+// Invite is a single-use, time-limited link for joining a game
+type Invite struct {
+	Token     string
+	GameID    string
+	CreatedBy string    // playerID of the creator who issued the invite
+	ExpiresAt time.Time // the invite can't be redeemed after this
+	UsedBy    string    // playerID that redeemed the invite (empty if unused)
+}
+
+// Session is one browser signed in as a player. The token is the secret in
+// the browser's session cookie, handed out once when the session starts;
+// only its hash is kept and saved. The ID names the session in the list of
+// a player's devices.
+type Session struct {
+	ID         string
+	Token      string `json:"-"`
+	TokenHash  [32]byte
+	PlayerID   string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+// DeviceLink lets a player continue a game in another browser, which is
+// signed in as the same player when it follows the link
+type DeviceLink struct {
+	Token     string
+	GameID    string
+	PlayerID  string
+	ExpiresAt time.Time
+}
+
+// Bot is a registered third-party program that plays through the JSON API,
+// authenticating with an API key instead of a cookie
+type Bot struct {
+	ID            string
+	Name          string
+	PlayerID      string // identity used in games, fixed for the bot's lifetime
+	WebhookURL    string // optional; receives a POST whenever it's the bot's turn
+	WebhookSecret string // signs webhook requests
+	KeyHash       [32]byte
+	CreatedAt     time.Time
+}
+
+// TelegramUser links a Telegram account to the player it plays as
+type TelegramUser struct {
+	UserID     int64
+	ChatID     int64  // private chat with the bot, where turns are announced
+	PlayerID   string // identity used in games, fixed for the account
+	LoginToken string // signs the browser in as PlayerID, so moves can be made on the web too
 }
 
 type GameEvent struct {
+	ID     uint64      `json:"id,omitempty"` // Per-game sequence number, 0 for unsequenced events
+	At     time.Time   `json:"at"`           // When a sequenced event was recorded
 	Type   string      `json:"type"`
 	GameID string      `json:"gameId"`
 	Data   interface{} `json:"data"`
 }
 
 type GameSubscriber struct {
-	ID      string
-	GameID  string
-	Channel chan GameEvent
-	Context context.Context
+	ID          string
+	GameID      string
+	PlayerID    string          // Player the connection belongs to, bound at subscribe time
+	Transport   string          // "sse" or "ws"
+	EventTypes  map[string]bool // Event types to deliver; nil delivers all
+	LastEventID uint64          // Highest sequenced event delivered, used to skip replayed duplicates
+	Channel     chan GameEvent
+	Context     context.Context
 }
 
-// Predefined emoji options
-var AvailableEmojis = []string{"🐱", "🚀", "🎨", "🌟", "🔥", "⚡", "🎮", "🦄", "🎯", "🌈"}
\ No newline at end of file
@@ -0,0 +1,46 @@
+// Package notify posts game updates to chat services, so a community can
+// follow games live where it already talks.
+package notify
+
+import (
+	"sync"
+
+	"htmx-go-app/webhooks"
+)
+
+// Config lists the chat services to post to. Services left empty are skipped.
+type Config struct {
+	DiscordWebhookURL string
+}
+
+// Message is a game update to post
+type Message struct {
+	Event    string // webhook event name, shown in the delivery log
+	Title    string
+	Text     string
+	URL      string // link to the game; left out if empty
+	ImageURL string // picture of the board; left out if empty
+}
+
+var (
+	mu     sync.Mutex
+	config Config
+)
+
+// Configure replaces the chat configuration
+func Configure(c Config) {
+	mu.Lock()
+	defer mu.Unlock()
+	config = c
+}
+
+// Post sends the message to every configured service in the background
+func Post(message Message) {
+	mu.Lock()
+	c := config
+	mu.Unlock()
+
+	if c.DiscordWebhookURL != "" {
+		webhooks.Post(c.DiscordWebhookURL, message.Event, discordPayload(message))
+	}
+}
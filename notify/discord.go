@@ -0,0 +1,35 @@
+package notify
+
+// discordColor is the accent stripe of the posted embed
+const discordColor = 0x3498db
+
+type discordMessage struct {
+	Username string         `json:"username"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string        `json:"title"`
+	Description string        `json:"description,omitempty"`
+	URL         string        `json:"url,omitempty"`
+	Color       int           `json:"color"`
+	Image       *discordImage `json:"image,omitempty"`
+}
+
+type discordImage struct {
+	URL string `json:"url"`
+}
+
+// discordPayload formats a message for a Discord webhook, as an embed
+func discordPayload(message Message) discordMessage {
+	embed := discordEmbed{
+		Title:       message.Title,
+		Description: message.Text,
+		URL:         message.URL,
+		Color:       discordColor,
+	}
+	if message.ImageURL != "" {
+		embed.Image = &discordImage{URL: message.ImageURL}
+	}
+	return discordMessage{Username: "Tic-Tac-Toe", Embeds: []discordEmbed{embed}}
+}
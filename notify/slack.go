@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"strings"
+	"sync"
+
+	"htmx-go-app/webhooks"
+)
+
+// slackEvent names Slack replies in the webhook delivery log
+const slackEvent = "slack.reply"
+
+var (
+	slackMu      sync.Mutex
+	slackReplies = make(map[string]string) // game ID -> response URL of the slash command that created it
+)
+
+// slackMessage is a reply to a Slack slash command
+type slackMessage struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// FollowInSlack remembers where in Slack a game was created, so its result
+// can be posted back there
+func FollowInSlack(gameID, responseURL string) {
+	slackMu.Lock()
+	defer slackMu.Unlock()
+	slackReplies[gameID] = responseURL
+}
+
+// PostSlackResult posts the message to the channel the game was created from,
+// if it was created in Slack. Each game's result is posted once.
+func PostSlackResult(gameID string, message Message) {
+	slackMu.Lock()
+	responseURL, ok := slackReplies[gameID]
+	delete(slackReplies, gameID)
+	slackMu.Unlock()
+
+	if ok {
+		webhooks.Post(responseURL, slackEvent, SlackReply(slackText(message)))
+	}
+}
+
+// slackEscaper escapes the characters Slack reads as markup
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// SlackReply formats text as a reply visible to the whole channel. The text
+// is sent as is, so it may contain links and mentions.
+func SlackReply(text string) interface{} {
+	return slackMessage{ResponseType: "in_channel", Text: text}
+}
+
+// slackText formats a message for Slack, with the title linking to the
+// message's URL
+func slackText(message Message) string {
+	title := slackEscaper.Replace(message.Title)
+	text := "*" + title + "*"
+	if message.URL != "" {
+		text = "*<" + message.URL + "|" + title + ">*"
+	}
+	if message.Text != "" {
+		text += "\n" + slackEscaper.Replace(message.Text)
+	}
+	return text
+}
@@ -0,0 +1,155 @@
+// Package ratelimit implements keyed token-bucket rate limiting, used to stop
+// scripted clients from flooding game creation, moves and chat.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"htmx-go-app/clock"
+)
+
+// Limit allows Burst requests per Per, refilling evenly over that window.
+// The zero Limit disables limiting.
+type Limit struct {
+	Burst int
+	Per   time.Duration
+}
+
+// Disabled reports whether the limit lets every request through
+func (l Limit) Disabled() bool {
+	return l.Burst <= 0 || l.Per <= 0
+}
+
+func (l Limit) String() string {
+	if l.Disabled() {
+		return "0"
+	}
+	return fmt.Sprintf("%d/%s", l.Burst, l.Per)
+}
+
+// ParseLimit parses limits written as "<requests>/<duration>", such as
+// "30/1m"; "0" or "" disables the limit
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return Limit{}, nil
+	}
+	count, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q must look like 30/1m", s)
+	}
+	burst, err := strconv.Atoi(count)
+	if err != nil || burst < 0 {
+		return Limit{}, fmt.Errorf("invalid request count in rate limit %q", s)
+	}
+	window, err := time.ParseDuration(per)
+	if err != nil || window <= 0 {
+		return Limit{}, fmt.Errorf("invalid window in rate limit %q", s)
+	}
+	return Limit{Burst: burst, Per: window}, nil
+}
+
+// UnmarshalText lets limits be read from config files as "30/1m"
+func (l *Limit) UnmarshalText(text []byte) error {
+	parsed, err := ParseLimit(string(text))
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter keeps one token bucket per key, such as a client IP or player ID
+type Limiter struct {
+	mu        sync.Mutex
+	limit     Limit
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+// New creates a limiter enforcing limit
+func New(limit Limit) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// SetLimit replaces the limit and forgets all buckets
+func (l *Limiter) SetLimit(limit Limit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = limit
+	l.buckets = make(map[string]*bucket)
+}
+
+// Allow takes a token from each key's bucket. It succeeds only if every key
+// has a token left; otherwise nothing is taken and retryAfter says when the
+// emptiest bucket will have refilled one.
+func (l *Limiter) Allow(keys ...string) (ok bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit.Disabled() {
+		return true, 0
+	}
+
+	now := clock.Now()
+	refill := float64(l.limit.Burst) / l.limit.Per.Seconds() // tokens per second
+	l.prune(now)
+
+	var pending []*bucket
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		b, ok := l.buckets[key]
+		if !ok {
+			b = &bucket{tokens: float64(l.limit.Burst), last: now}
+			l.buckets[key] = b
+		}
+		b.tokens = math.Min(float64(l.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*refill)
+		b.last = now
+
+		if b.tokens < 1 {
+			wait := time.Duration((1 - b.tokens) / refill * float64(time.Second))
+			if wait > retryAfter {
+				retryAfter = wait
+			}
+			continue
+		}
+		pending = append(pending, b)
+	}
+	if retryAfter > 0 {
+		return false, retryAfter
+	}
+
+	for _, b := range pending {
+		b.tokens--
+	}
+	return true, 0
+}
+
+// prune drops buckets idle for a whole window, as they would be full again
+// anyway. It runs at most once per window to keep Allow cheap.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < l.limit.Per {
+		return
+	}
+	l.lastPrune = now
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= l.limit.Per {
+			delete(l.buckets, key)
+		}
+	}
+}
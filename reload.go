@@ -0,0 +1,83 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"htmx-go-app/config"
+	"htmx-go-app/emojis"
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/handlers"
+	"htmx-go-app/notify"
+	"htmx-go-app/telegram"
+)
+
+var (
+	reloadMu      sync.Mutex
+	currentConfig config.Config
+)
+
+// applySettings pushes the settings that can change at runtime into the
+// packages using them. Open event streams and games are left alone, and so
+// is maintenance mode, which admins turn on and off while the server runs.
+func applySettings(cfg config.Config) {
+	events.HeartbeatInterval.Set(cfg.HeartbeatInterval)
+	events.MaxConnectionsPerIP.Set(cfg.MaxStreamsPerIP)
+	events.MaxConnectionsPerGame.Set(cfg.MaxStreamsPerGame)
+	game.TurnReminderDelay.Set(cfg.TurnReminderDelay)
+	game.AbandonAfter.Set(cfg.AbandonAfter)
+	game.InviteTTL.Set(cfg.InviteTTL)
+	game.MaxOpenGamesPerCreator.Set(cfg.MaxOpenGames)
+	game.UnclaimedGameTTL.Set(cfg.UnclaimedGameTTL)
+	game.ChatCooldown.Set(cfg.ChatCooldown)
+	game.MoveDebounceWindow.Set(cfg.MoveDebounce)
+	emojis.Configure(cfg.Emojis)
+	emojis.AllowCustom(cfg.CustomSymbols)
+	handlers.SetBranding(handlers.Branding{SiteName: cfg.SiteName, LogoURL: cfg.LogoURL, ThemeColor: cfg.ThemeColor})
+	handlers.PlayerCookieMaxAge.Set(cfg.CookieMaxAge)
+	game.SessionTTL.Set(cfg.CookieMaxAge)
+	handlers.AdminAPIKey.Set(cfg.AdminAPIKey)
+	handlers.RequestTimeout.Set(cfg.RequestTimeout)
+	handlers.MaxRequestBodySize.Set(int64(cfg.MaxBodyBytes))
+	handlers.CreateGameLimiter.SetLimit(cfg.CreateRateLimit)
+	handlers.MoveLimiter.SetLimit(cfg.MoveRateLimit)
+	handlers.ChatLimiter.SetLimit(cfg.ChatRateLimit)
+	handlers.ChaosLatency.Set(cfg.ChaosLatency)
+	events.ChaosDropRate.Set(cfg.ChaosDropRate)
+	events.ChaosDisconnectRate.Set(cfg.ChaosDisconnectRate)
+	if cfg.ChaosLatency > 0 || cfg.ChaosDropRate > 0 || cfg.ChaosDisconnectRate > 0 {
+		log.Printf("warning: chaos mode is on (latency up to %s, %g of events dropped, %g chance of disconnects)",
+			cfg.ChaosLatency, cfg.ChaosDropRate, cfg.ChaosDisconnectRate)
+	}
+	notify.Configure(notify.Config{DiscordWebhookURL: cfg.DiscordWebhookURL})
+	handlers.SlackSigningSecret.Set(cfg.SlackSigningSecret)
+	telegram.Configure(cfg.TelegramBotToken)
+	handlers.TelegramWebhookSecret.Set(cfg.TelegramWebhookSecret)
+	handlers.StreamRecordDir.Set(cfg.RecordStreamsDir)
+	if cfg.RecordStreamsDir != "" {
+		log.Printf("warning: recording event streams to %s", cfg.RecordStreamsDir)
+	}
+}
+
+// reloadConfig reads the configuration again from the same file,
+// environment and flags as at startup and applies what it can. An invalid
+// configuration is rejected and the running settings are kept.
+func reloadConfig() error {
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		return err
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	if ignored := currentConfig.RestartOnly(cfg); len(ignored) > 0 {
+		log.Printf("config reload: restart to apply %s", strings.Join(ignored, ", "))
+	}
+	applySettings(cfg)
+	currentConfig = cfg
+	log.Printf("config reloaded")
+	return nil
+}
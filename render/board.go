@@ -0,0 +1,193 @@
+// Package render draws game boards as standalone images for sharing,
+// link previews and embedding.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+
+	"htmx-go-app/engine"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+)
+
+const (
+	boardSize   = 300 // width and height of the grid in pixels
+	cellSize    = boardSize / 3
+	padding     = 20
+	captionSize = 50
+	imageWidth  = boardSize + 2*padding
+	imageHeight = boardSize + 2*padding + captionSize
+)
+
+// Seat colors used by the PNG renderer, which has no emoji font to draw with
+var (
+	backgroundColor = color.RGBA{0xec, 0xf0, 0xf1, 0xff}
+	gridColor       = color.RGBA{0x2c, 0x3e, 0x50, 0xff}
+	highlightColor  = color.RGBA{0xf9, 0xe7, 0x9f, 0xff}
+	seatColors      = []color.RGBA{
+		{0xe7, 0x4c, 0x3c, 0xff}, // first player
+		{0x34, 0x98, 0xdb, 0xff}, // second player
+	}
+)
+
+// Caption returns a short text summary of the game, e.g. "🐱 vs 🚀 · 🐱 wins!"
+func Caption(gameData *models.Game) string {
+	players := ""
+	for i, playerID := range gameData.PlayerOrder {
+		if i > 0 {
+			players += " vs "
+		}
+		players += gameData.Players[playerID].Emoji
+	}
+	if players == "" {
+		players = "Tic-Tac-Toe"
+	}
+
+	switch gameData.Status {
+	case models.GameStatusWaiting:
+		return players + " · waiting for opponent"
+	case models.GameStatusDraw:
+		return players + " · it's a draw"
+	case models.GameStatusFinished:
+		if winner, ok := gameData.Players[gameData.Winner]; ok {
+			return players + " · " + winner.Emoji + " wins!"
+		}
+	case models.GameStatusActive:
+		if current, ok := gameData.Players[game.GetCurrentPlayerID(gameData)]; ok {
+			return players + " · " + current.Emoji + " to move"
+		}
+	case models.GameStatusPaused:
+		return players + " · paused"
+	}
+	return players
+}
+
+// isWinningCell reports whether (row, col) is part of the given winning line
+func isWinningCell(line [][2]int, row, col int) bool {
+	for _, cell := range line {
+		if cell[0] == row && cell[1] == col {
+			return true
+		}
+	}
+	return false
+}
+
+// BoardSVG renders the board, including the winning line and a caption, as an SVG document
+func BoardSVG(gameData *models.Game) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		imageWidth, imageHeight, imageWidth, imageHeight)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#ffffff"/>`, imageWidth, imageHeight)
+	writeBoardSVG(&buf, gameData, padding, padding)
+	fmt.Fprintf(&buf, `<text x="%d" y="%d" font-family="sans-serif" font-size="22" text-anchor="middle" fill="#2c3e50">%s</text>`,
+		imageWidth/2, boardSize+2*padding+captionSize/2, html.EscapeString(Caption(gameData)))
+	buf.WriteString(`</svg>`)
+
+	return buf.Bytes()
+}
+
+// writeBoardSVG draws the grid, marks and winning line with the board's top
+// left corner at (left, top)
+func writeBoardSVG(buf *bytes.Buffer, gameData *models.Game, left, top int) {
+	line := game.WinningLine(gameData.Board)
+	board := game.SymbolBoard(gameData)
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			x := left + col*cellSize
+			y := top + row*cellSize
+			fill := "#ecf0f1"
+			if isWinningCell(line, row, col) {
+				fill = "#f9e79f"
+			}
+			fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="#34495e" stroke-width="2"/>`,
+				x, y, cellSize, cellSize, fill)
+			if value := board[row][col]; value != "" {
+				fmt.Fprintf(buf, `<text x="%d" y="%d" font-size="56" text-anchor="middle" dominant-baseline="central">%s</text>`,
+					x+cellSize/2, y+cellSize/2, html.EscapeString(value))
+			}
+		}
+	}
+
+	fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="#2c3e50" stroke-width="4" rx="4"/>`,
+		left, top, boardSize, boardSize)
+}
+
+// BoardPNG renders the board as a PNG image. Emoji can't be drawn without a
+// font, so each seat is drawn as a colored marker: a cross for the first
+// player and a ring for the second.
+func BoardPNG(gameData *models.Game) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, imageWidth, boardSize+2*padding))
+	fillRect(img, img.Bounds(), color.RGBA{0xff, 0xff, 0xff, 0xff})
+
+	line := game.WinningLine(gameData.Board)
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			cell := image.Rect(padding+col*cellSize, padding+row*cellSize, padding+(col+1)*cellSize, padding+(row+1)*cellSize)
+			if isWinningCell(line, row, col) {
+				fillRect(img, cell, highlightColor)
+			} else {
+				fillRect(img, cell, backgroundColor)
+			}
+
+			switch engine.SeatOf(gameData.Board[row][col]) {
+			case 0:
+				drawCross(img, cell, seatColors[0])
+			case 1:
+				drawRing(img, cell, seatColors[1])
+			}
+		}
+	}
+
+	// Grid lines and outer border
+	for i := 0; i <= 3; i++ {
+		offset := padding + i*cellSize
+		fillRect(img, image.Rect(offset-2, padding-2, offset+2, padding+boardSize+2), gridColor)
+		fillRect(img, image.Rect(padding-2, offset-2, padding+boardSize+2, offset+2), gridColor)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(img.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func drawCross(img *image.RGBA, cell image.Rectangle, c color.RGBA) {
+	inset := cellSize / 4
+	size := cellSize - 2*inset
+	for i := 0; i <= size; i++ {
+		for t := -4; t <= 4; t++ {
+			img.SetRGBA(cell.Min.X+inset+i+t, cell.Min.Y+inset+i, c)
+			img.SetRGBA(cell.Max.X-inset-i+t, cell.Min.Y+inset+i, c)
+		}
+	}
+}
+
+func drawRing(img *image.RGBA, cell image.Rectangle, c color.RGBA) {
+	cx := float64(cell.Min.X+cell.Max.X) / 2
+	cy := float64(cell.Min.Y+cell.Max.Y) / 2
+	radius := float64(cellSize) / 4
+	for y := cell.Min.Y; y < cell.Max.Y; y++ {
+		for x := cell.Min.X; x < cell.Max.X; x++ {
+			d := math.Hypot(float64(x)-cx, float64(y)-cy)
+			if math.Abs(d-radius) <= 4 {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
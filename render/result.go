@@ -0,0 +1,76 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"time"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+)
+
+// Result card layout: the board on the left, the text beside it
+const (
+	cardWidth  = boardSize + 3*padding + 300
+	cardHeight = boardSize + 2*padding
+	cardTextX  = boardSize + 2*padding
+)
+
+// PlayerLabel names a player by emoji and, if they gave one, display name
+func PlayerLabel(gameData *models.Game, playerID string) string {
+	player, ok := gameData.Players[playerID]
+	if !ok {
+		return ""
+	}
+	if player.Name == "" {
+		return player.Emoji
+	}
+	return player.Emoji + " " + player.Name
+}
+
+// ResultText states how a finished game ended, e.g. "🐱 Alice wins!"
+func ResultText(gameData *models.Game) string {
+	if gameData.Status == models.GameStatusDraw {
+		return "It's a draw!"
+	}
+	if label := PlayerLabel(gameData, gameData.Winner); label != "" {
+		return label + " wins!"
+	}
+	return Caption(gameData)
+}
+
+// DurationText describes a finished game's length, e.g. "7 moves in 1m5s"
+func DurationText(gameData *models.Game) string {
+	return fmt.Sprintf("%d moves in %s", gameData.MoveCount, game.Duration(gameData).Round(time.Second))
+}
+
+// ResultCardSVG renders a finished game as a card for sharing: the final
+// board next to the players, the result and how long the game took
+func ResultCardSVG(gameData *models.Game) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		cardWidth, cardHeight, cardWidth, cardHeight)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#ffffff"/>`, cardWidth, cardHeight)
+	writeBoardSVG(&buf, gameData, padding, padding)
+
+	text := func(y, size int, weight, value string) {
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" font-family="sans-serif" font-size="%d" font-weight="%s" fill="#2c3e50">%s</text>`,
+			cardTextX, y, size, weight, html.EscapeString(value))
+	}
+	text(padding+30, 20, "normal", "Tic-Tac-Toe")
+	y := padding + 80
+	for i, playerID := range gameData.PlayerOrder {
+		label := PlayerLabel(gameData, playerID)
+		if i > 0 {
+			label = "vs " + label
+		}
+		text(y, 26, "normal", label)
+		y += 40
+	}
+	text(y+30, 30, "bold", ResultText(gameData))
+	text(y+70, 20, "normal", DurationText(gameData))
+	buf.WriteString(`</svg>`)
+
+	return buf.Bytes()
+}
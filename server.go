@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"htmx-go-app/config"
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// run serves handler until SIGINT or SIGTERM, reloading the configuration
+// on SIGHUP. On shutdown it stops accepting requests, closes event streams,
+// waits up to cfg.ShutdownTimeout for in-flight requests and flushes games
+// to the snapshot file
+func run(cfg config.Config, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:    cfg.Addr,
+		Handler: handler,
+		// Per-request limits live in handlers.Timeout so that event streams
+		// can opt out; only the headers are bounded for every connection
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+	}
+	// Streams never finish on their own, so end them as soon as shutdown starts
+	srv.RegisterOnShutdown(events.CloseStreams)
+
+	errc := make(chan error, 1)
+	go func() { errc <- listen(cfg, srv) }()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	// SIGHUP reloads the configuration without dropping connections
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+wait:
+	for {
+		select {
+		case err := <-errc:
+			return err
+		case <-hup:
+			if err := reloadConfig(); err != nil {
+				log.Printf("config reload failed, keeping current settings: %v", err)
+			}
+		case sig := <-stop:
+			log.Printf("received %s, shutting down", sig)
+			break wait
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	shutdownErr := srv.Shutdown(ctx)
+
+	if cfg.SnapshotFile != "" {
+		if err := game.SaveSnapshot(cfg.SnapshotFile); err != nil {
+			return fmt.Errorf("flushing games: %w", err)
+		}
+		log.Printf("saved games to %s", cfg.SnapshotFile)
+	}
+	return shutdownErr
+}
+
+// listen serves on cfg.Addr, over HTTPS when certificate files or autocert
+// domains are configured
+func listen(cfg config.Config, srv *http.Server) error {
+	switch {
+	case len(cfg.AutocertDomains) > 0:
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCacheDir),
+		}
+		srv.TLSConfig = m.TLSConfig()
+		// The redirect listener also answers Let's Encrypt HTTP-01 challenges
+		if cfg.HTTPRedirectAddr != "" {
+			go serveRedirect(cfg.HTTPRedirectAddr, m.HTTPHandler(redirectToHTTPS(cfg.Addr)))
+		}
+		return srv.ListenAndServeTLS("", "")
+
+	case cfg.TLSCertFile != "":
+		if cfg.HTTPRedirectAddr != "" {
+			go serveRedirect(cfg.HTTPRedirectAddr, redirectToHTTPS(cfg.Addr))
+		}
+		return srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+
+	default:
+		return srv.ListenAndServe()
+	}
+}
+
+func serveRedirect(addr string, handler http.Handler) {
+	if err := http.ListenAndServe(addr, handler); err != nil {
+		log.Printf("http redirect listener: %v", err)
+	}
+}
+
+// redirectToHTTPS sends plain HTTP requests to the same URL on the HTTPS
+// listener at tlsAddr
+func redirectToHTTPS(tlsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(tlsAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
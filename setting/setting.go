@@ -0,0 +1,32 @@
+// Package setting holds settings that a config reload can replace while
+// requests are reading them.
+package setting
+
+import "sync/atomic"
+
+// Value is one reloadable setting. The zero Value holds T's zero value.
+type Value[T any] struct {
+	value atomic.Pointer[T]
+}
+
+// New returns a Value holding initial
+func New[T any](initial T) *Value[T] {
+	v := &Value[T]{}
+	v.Set(initial)
+	return v
+}
+
+// Get returns the setting as it stands
+func (v *Value[T]) Get() T {
+	if current := v.value.Load(); current != nil {
+		return *current
+	}
+	var zero T
+	return zero
+}
+
+// Set replaces the setting; requests already in flight may still see the
+// old one
+func (v *Value[T]) Set(value T) {
+	v.value.Store(&value)
+}
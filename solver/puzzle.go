@@ -0,0 +1,191 @@
+package solver
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"htmx-go-app/engine"
+)
+
+// ErrInvalidPuzzle is returned for a puzzle ID that is not a puzzle
+var ErrInvalidPuzzle = errors.New("invalid puzzle")
+
+// PuzzleKind is the task a puzzle sets
+type PuzzleKind string
+
+const (
+	WinInOne      PuzzleKind = "win-in-1"       // complete a line now
+	DontLoseInTwo PuzzleKind = "dont-lose-in-2" // stop the opponent completing one next move, without walking into a later loss
+)
+
+// Difficulty grades a puzzle by how many moves look plausible but fail
+type Difficulty int
+
+const (
+	Easy Difficulty = iota + 1
+	Medium
+	Hard
+)
+
+func (d Difficulty) String() string {
+	switch d {
+	case Easy:
+		return "easy"
+	case Medium:
+		return "medium"
+	default:
+		return "hard"
+	}
+}
+
+// ParseDifficulty reads a difficulty as written by String
+func ParseDifficulty(s string) (Difficulty, bool) {
+	for _, d := range []Difficulty{Easy, Medium, Hard} {
+		if d.String() == s {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// Puzzle is a standard game position with one task for the seat to move
+type Puzzle struct {
+	// ID is the board row by row, "x" for the first seat, "o" for the
+	// second and "." for empty, e.g. "xx.oo...."
+	ID         string
+	Kind       PuzzleKind
+	Difficulty Difficulty
+	// Solutions are the moves that complete the task
+	Solutions []engine.Cell
+	game      *engine.Game
+}
+
+// Position returns the puzzle's game, with X and O as marks, for the caller
+// to keep or play on
+func (p Puzzle) Position() *engine.Game {
+	return p.game.Clone()
+}
+
+// Solves reports whether cell completes the task
+func (p Puzzle) Solves(cell engine.Cell) bool {
+	return slices.Contains(p.Solutions, cell)
+}
+
+var (
+	puzzlesOnce sync.Once
+	puzzles     []Puzzle
+)
+
+// Puzzles lists every puzzle reachable in a standard game, easiest first.
+// They are generated on the first call.
+func Puzzles() []Puzzle {
+	puzzlesOnce.Do(func() {
+		g, _ := engine.NewGame("X", "O", engine.Standard)
+		seen := make(map[position]bool)
+		generatePuzzles(g, seen)
+		slices.SortStableFunc(puzzles, func(a, b Puzzle) int {
+			if a.Difficulty != b.Difficulty {
+				return int(a.Difficulty - b.Difficulty)
+			}
+			return strings.Compare(a.ID, b.ID)
+		})
+	})
+	return puzzles
+}
+
+// PuzzleByID finds the puzzle with the given ID
+func PuzzleByID(id string) (Puzzle, error) {
+	for _, p := range Puzzles() {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return Puzzle{}, ErrInvalidPuzzle
+}
+
+// generatePuzzles walks every game reachable from g, adding each position
+// that sets a task once
+func generatePuzzles(g *engine.Game, seen map[position]bool) {
+	key := positionOf(g)
+	if seen[key] || g.Status() != engine.InProgress {
+		return
+	}
+	seen[key] = true
+
+	if p, ok := classify(g); ok {
+		puzzles = append(puzzles, p)
+	}
+	for _, cell := range g.LegalMoves() {
+		next := g.Clone()
+		next.Play(cell)
+		generatePuzzles(next, seen)
+	}
+}
+
+// classify decides whether g makes a puzzle and grades it. A position is a
+// win in 1 when the seat to move can complete a line, and a don't lose in 2
+// when it can't but the opponent threatens to and the position can still
+// be held to a draw.
+func classify(g *engine.Game) (Puzzle, bool) {
+	eval := Evaluate(g)
+	scores := Scores(g)
+	p := Puzzle{ID: puzzleID(g), game: g}
+
+	var wrong, traps int
+	switch {
+	case eval.Result == Win && eval.Plies == 1:
+		p.Kind = WinInOne
+		p.Solutions = eval.BestMoves
+		for _, s := range scores {
+			if s.Result != Win {
+				wrong++
+			}
+		}
+	case eval.Result == Draw:
+		threatened := false
+		for cell, s := range scores {
+			if s.Result != Loss {
+				p.Solutions = append(p.Solutions, cell)
+				continue
+			}
+			wrong++
+			if s.Plies == 2 {
+				threatened = true
+			} else {
+				traps++ // loses later, to a fork
+			}
+		}
+		if !threatened {
+			return Puzzle{}, false
+		}
+		p.Kind = DontLoseInTwo
+		slices.SortFunc(p.Solutions, func(a, b engine.Cell) int {
+			return (a.Row*engine.Size + a.Col) - (b.Row*engine.Size + b.Col)
+		})
+	default:
+		return Puzzle{}, false
+	}
+
+	// A puzzle gets harder the more wrong moves there are to pick, and a
+	// block that walks into a fork is harder than one that plainly fails
+	switch {
+	case wrong <= 2 && traps == 0:
+		p.Difficulty = Easy
+	case wrong <= 4 && traps == 0:
+		p.Difficulty = Medium
+	default:
+		p.Difficulty = Hard
+	}
+	return p, true
+}
+
+// puzzleID writes g's board in the form of Puzzle.ID
+func puzzleID(g *engine.Game) string {
+	var b strings.Builder
+	for _, cell := range positionOf(g).cells {
+		b.WriteByte(".xo"[cell])
+	}
+	return b.String()
+}
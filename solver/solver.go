@@ -0,0 +1,179 @@
+// Package solver works out the result of any tic-tac-toe position with best
+// play from both sides, and which moves get there. It searches the whole
+// game tree on top of the engine package, remembering positions it has
+// already scored, so after the first few calls evaluating a position is a
+// map lookup.
+//
+//	g, _ := engine.NewGame("X", "O", engine.Standard)
+//	eval := solver.Evaluate(g)
+//	fmt.Println(eval.Result, eval.BestMoves) // draw [a1 b1 c1 ...]
+package solver
+
+import (
+	"sync"
+
+	"htmx-go-app/engine"
+)
+
+// Result is how a position ends for the seat to move when both sides play
+// their best
+type Result int
+
+const (
+	Loss Result = -1
+	Draw Result = 0
+	Win  Result = 1
+)
+
+func (r Result) String() string {
+	switch r {
+	case Win:
+		return "win"
+	case Loss:
+		return "loss"
+	default:
+		return "draw"
+	}
+}
+
+// Evaluation is the verdict on a position
+type Evaluation struct {
+	Result Result
+	// Plies is how many moves are left with best play: a winner takes the
+	// quickest win, a loser holds out as long as possible
+	Plies int
+	// BestMoves are the moves that keep Result and Plies, row by row. It is
+	// empty once the game is over.
+	BestMoves []engine.Cell
+}
+
+// maxScore is the score of a position won on the spot. A win n moves away
+// scores maxScore-n, a loss -(maxScore-n) and a draw 0.
+const maxScore = engine.Size*engine.Size + 1
+
+// position is a game as the search sees it: the seat holding each cell (0
+// for empty), the seat to move and the variant. Marks don't matter, so
+// games with different emojis share their cached scores.
+type position struct {
+	cells   [engine.Size * engine.Size]int8
+	turn    int
+	variant engine.Variant
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[position]int)
+)
+
+// Evaluate scores g for the seat to move. g is left as it is.
+func Evaluate(g *engine.Game) Evaluation {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	eval := evaluation(g, score(g))
+	best := -maxScore - 1
+	for _, cell := range g.LegalMoves() {
+		next := g.Clone()
+		next.Play(cell)
+		s := backUp(score(next))
+		switch {
+		case s > best:
+			best = s
+			eval.BestMoves = []engine.Cell{cell}
+		case s == best:
+			eval.BestMoves = append(eval.BestMoves, cell)
+		}
+	}
+	return eval
+}
+
+// BestMoves returns the moves that keep the best result for the seat to move
+func BestMoves(g *engine.Game) []engine.Cell {
+	return Evaluate(g).BestMoves
+}
+
+// Scores lists the result each legal move leads to for the seat to move,
+// for showing how good or bad every option is
+func Scores(g *engine.Game) map[engine.Cell]Evaluation {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	scores := make(map[engine.Cell]Evaluation)
+	for _, cell := range g.LegalMoves() {
+		next := g.Clone()
+		next.Play(cell)
+		scores[cell] = evaluation(g, backUp(score(next)))
+	}
+	return scores
+}
+
+// evaluation turns a score of g into a result and the moves left. A draw
+// goes on until the board is full.
+func evaluation(g *engine.Game, s int) Evaluation {
+	switch {
+	case s > 0:
+		return Evaluation{Result: Win, Plies: maxScore - s}
+	case s < 0:
+		return Evaluation{Result: Loss, Plies: maxScore + s}
+	case g.Status() != engine.InProgress:
+		return Evaluation{Result: Draw}
+	default:
+		return Evaluation{Result: Draw, Plies: len(g.Board.EmptyCells())}
+	}
+}
+
+// backUp turns the score of the position after a move, which is from the
+// opponent's side, into the score of the move for the player making it
+func backUp(s int) int {
+	switch {
+	case s > 0:
+		return -(s - 1)
+	case s < 0:
+		return -(s + 1)
+	default:
+		return 0
+	}
+}
+
+// score searches g for the seat to move. Callers hold cacheMu.
+func score(g *engine.Game) int {
+	key := positionOf(g)
+	if s, ok := cache[key]; ok {
+		return s
+	}
+
+	var s int
+	switch g.Status() {
+	case engine.Won:
+		s = -maxScore
+		if g.Winner() == g.Turn {
+			s = maxScore
+		}
+	case engine.Drawn:
+		s = 0
+	default:
+		s = -maxScore - 1
+		for _, cell := range g.LegalMoves() {
+			next := g.Clone()
+			next.Play(cell)
+			s = max(s, backUp(score(next)))
+		}
+	}
+
+	cache[key] = s
+	return s
+}
+
+func positionOf(g *engine.Game) position {
+	key := position{turn: g.Turn, variant: g.Variant}
+	for row := range g.Board {
+		for col, mark := range g.Board[row] {
+			for seat, seatMark := range g.Marks {
+				if mark != "" && mark == seatMark {
+					key.cells[row*engine.Size+col] = int8(seat + 1)
+				}
+			}
+		}
+	}
+	return key
+}
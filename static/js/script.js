@@ -1,22 +1,274 @@
 // Global JavaScript for Tic-Tac-Toe Application
 
+// Path prefix the app is served under, rendered by the base layout
+const BASE_PATH = document.body.dataset.basePath || '';
+
 // Common HTMX configuration
 document.body.addEventListener('htmx:configRequest', (event) => {
     event.detail.headers['X-Requested-With'] = 'XMLHttpRequest';
 });
 
-// Game ready event handler for emoji selection page
-document.addEventListener('htmx:sse-message', function(event) {
-    if (event.detail.type === 'game_ready') {
-        // Extract game ID from current URL
-        const currentPath = window.location.pathname;
-        const gameIdMatch = currentPath.match(/\/game\/([^\/]+)\//);
-        if (gameIdMatch) {
-            const gameId = gameIdMatch[1];
-            window.location.href = '/game/' + gameId;
+// Let server-rendered error fragments replace the error region; the server
+// marks them with HX-Retarget so other failed requests are left untouched
+document.body.addEventListener('htmx:beforeSwap', (event) => {
+    const xhr = event.detail.xhr;
+    if (xhr.status >= 400 && xhr.getResponseHeader('HX-Retarget')) {
+        event.detail.shouldSwap = true;
+        event.detail.isError = false;
+    }
+});
+
+// Clear any previous error once a request succeeds
+document.body.addEventListener('htmx:afterRequest', (event) => {
+    const errorRegion = document.getElementById('error-message');
+    if (errorRegion && event.detail.successful) {
+        errorRegion.innerHTML = '';
+    }
+});
+
+// Tell the server the browser's time zone and language once per session, so
+// dates on the pages are written the way the player reads them
+(() => {
+    const locale = {
+        timezone: Intl.DateTimeFormat().resolvedOptions().timeZone || '',
+        language: navigator.language || '',
+    };
+    const reported = locale.timezone + '|' + locale.language;
+    if (sessionStorage.getItem('localeReported') === reported) {
+        return;
+    }
+    fetch(BASE_PATH + '/api/preferences/locale', {
+        method: 'POST',
+        body: new URLSearchParams(locale),
+        credentials: 'same-origin',
+    }).then((response) => {
+        if (response.ok) {
+            sessionStorage.setItem('localeReported', reported);
+        }
+    }).catch(() => {});
+})();
+
+// Keyboard navigation for the board grid: arrow keys move focus between cells
+let focusedCell = null;
+
+document.addEventListener('keydown', (event) => {
+    const cell = event.target.closest('.game-cell');
+    if (!cell) {
+        return;
+    }
+
+    const moves = {
+        ArrowUp: [-1, 0],
+        ArrowDown: [1, 0],
+        ArrowLeft: [0, -1],
+        ArrowRight: [0, 1],
+    };
+    const move = moves[event.key];
+    if (!move) {
+        if (event.key === ' ') {
+            event.preventDefault(); // Don't scroll; the cell triggers on keyup
+        }
+        return;
+    }
+
+    event.preventDefault();
+    const row = (parseInt(cell.dataset.row, 10) + move[0] + 3) % 3;
+    const col = (parseInt(cell.dataset.col, 10) + move[1] + 3) % 3;
+    const next = document.querySelector(`.game-cell[data-row="${row}"][data-col="${col}"]`);
+    if (next) {
+        next.focus();
+    }
+});
+
+// Remember the focused cell so focus survives board swaps
+document.addEventListener('focusin', (event) => {
+    const cell = event.target.closest && event.target.closest('.game-cell');
+    focusedCell = cell ? { row: cell.dataset.row, col: cell.dataset.col } : null;
+});
+
+document.body.addEventListener('htmx:afterSettle', () => {
+    if (focusedCell && !document.activeElement.closest('.game-cell')) {
+        const cell = document.querySelector(`.game-cell[data-row="${focusedCell.row}"][data-col="${focusedCell.col}"]`);
+        if (cell) {
+            cell.focus();
+        }
+    }
+});
+
+// A turn reminder for this player pulses the board until it changes; the
+// next board swap clears the reminder
+htmx.onLoad((element) => {
+    const reminder = document.getElementById('turn-reminder');
+    if (element.id === 'turn-reminder' && element.dataset.active) {
+        const board = document.getElementById('game-board');
+        if (board) {
+            board.classList.add('pulse');
+        }
+    } else if (element.id === 'game-board' && reminder) {
+        reminder.textContent = '';
+    }
+});
+
+// Content swapped in with a data-redirect attribute (e.g. a quick match being
+// found) navigates the page there
+htmx.onLoad((element) => {
+    const target = element.matches && element.matches('[data-redirect]')
+        ? element
+        : element.querySelector && element.querySelector('[data-redirect]');
+    if (target) {
+        window.location.href = target.dataset.redirect;
+    }
+});
+
+// Play a short tone and vibrate when a status update carries a cue. A
+// re-fetch after reconnecting repeats the version already played, which is
+// skipped.
+const CUE_TONES = {
+    your_turn: { frequency: 660, vibrate: [80] },
+    win: { frequency: 880, vibrate: [80, 60, 80, 60, 160] },
+    lose: { frequency: 220, vibrate: [300] },
+    draw: { frequency: 440, vibrate: [120, 80, 120] },
+};
+let lastCueVersion = null;
+let audioContext = null;
+
+function playCue(cue) {
+    const tone = CUE_TONES[cue];
+    if (!tone) {
+        return;
+    }
+    if (navigator.vibrate) {
+        navigator.vibrate(tone.vibrate);
+    }
+    const AudioContext = window.AudioContext || window.webkitAudioContext;
+    if (!AudioContext) {
+        return;
+    }
+    audioContext = audioContext || new AudioContext();
+    const oscillator = audioContext.createOscillator();
+    const gain = audioContext.createGain();
+    oscillator.frequency.value = tone.frequency;
+    gain.gain.setValueAtTime(0.1, audioContext.currentTime);
+    gain.gain.exponentialRampToValueAtTime(0.001, audioContext.currentTime + 0.3);
+    oscillator.connect(gain).connect(audioContext.destination);
+    oscillator.start();
+    oscillator.stop(audioContext.currentTime + 0.3);
+}
+
+htmx.onLoad((element) => {
+    const status = element.id === 'game-status' ? element : document.getElementById('game-status');
+    if (!status || !status.dataset.cue || status.dataset.cueVersion === lastCueVersion) {
+        return;
+    }
+    lastCueVersion = status.dataset.cueVersion;
+    const toggle = document.getElementById('cue-toggle');
+    const muted = status.dataset.cueMuted || (toggle && toggle.getAttribute('aria-pressed') === 'true');
+    if (!muted) {
+        playCue(status.dataset.cue);
+    }
+});
+
+// A celebration event highlights the winning line and throws confetti on
+// both players' pages at once; the winner's page gets their emoji in the mix
+htmx.onLoad((element) => {
+    if (element.id !== 'celebration' || !element.dataset.line) {
+        return;
+    }
+    for (const cell of element.dataset.line.split(' ')) {
+        const [row, col] = cell.split('-');
+        const target = document.querySelector(`#game-board [data-row="${row}"][data-col="${col}"]`);
+        if (target) {
+            target.classList.add('winning-cell');
+        }
+    }
+
+    const pieces = element.dataset.winner ? ['🎉', '✨', element.dataset.emoji] : ['🎉', '✨'];
+    for (let i = 0; i < 30; i++) {
+        const piece = document.createElement('span');
+        piece.className = 'confetti';
+        piece.textContent = pieces[i % pieces.length];
+        piece.style.left = Math.random() * 100 + '%';
+        piece.style.animationDelay = Math.random() * 0.8 + 's';
+        element.appendChild(piece);
+    }
+    setTimeout(() => element.replaceChildren(), 3000);
+});
+
+// Announcements stay dismissed across pages until a new one is posted
+const DISMISSED_ANNOUNCEMENT_KEY = 'dismissedAnnouncement';
+
+function hideDismissedAnnouncement(root) {
+    const banner = root.querySelector && root.querySelector('.announcement');
+    if (banner && localStorage.getItem(DISMISSED_ANNOUNCEMENT_KEY) === banner.dataset.announcementId) {
+        banner.remove();
+    }
+}
+
+document.addEventListener('click', (event) => {
+    const button = event.target.closest('.announcement-dismiss');
+    if (!button) {
+        return;
+    }
+    const banner = button.closest('.announcement');
+    localStorage.setItem(DISMISSED_ANNOUNCEMENT_KEY, banner.dataset.announcementId);
+    banner.remove();
+});
+
+hideDismissedAnnouncement(document);
+document.body.addEventListener('htmx:load', (event) => hideDismissedAnnouncement(event.detail.elt));
+
+// Show a reconnecting banner while the event stream or network is down
+function setConnectionLost(lost) {
+    const banner = document.getElementById('connection-status');
+    if (banner) {
+        banner.hidden = !lost;
+    }
+}
+
+// After the game stream reconnects, re-fetch the sections it keeps up to
+// date in case updates were missed while it was down
+const GAME_FRAGMENTS = { board: 'game-board', status: 'game-status', players: 'game-players' };
+let streamDropped = false;
+
+function refreshGameFragments() {
+    const gameIdMatch = window.location.pathname.match(/\/game\/([^\/]+)$/);
+    if (!gameIdMatch) {
+        return;
+    }
+    for (const [section, id] of Object.entries(GAME_FRAGMENTS)) {
+        if (document.getElementById(id)) {
+            htmx.ajax('GET', BASE_PATH + '/api/game/' + gameIdMatch[1] + '/fragment/' + section, { target: '#' + id, swap: 'outerHTML' });
         }
     }
+}
+
+document.body.addEventListener('htmx:sseError', () => {
+    streamDropped = true;
+    setConnectionLost(true);
+});
+document.body.addEventListener('htmx:sseOpen', () => {
+    setConnectionLost(false);
+    // The join page catches up on anyone who joined before its stream opened
+    const joinState = document.getElementById('join-state');
+    if (joinState) {
+        htmx.trigger(joinState, 'join-refresh');
+    }
+    if (streamDropped) {
+        streamDropped = false;
+        refreshGameFragments();
+    }
 });
+window.addEventListener('offline', () => setConnectionLost(true));
+window.addEventListener('online', () => setConnectionLost(false));
+
+// Register the service worker for installability and the offline shell
+if ('serviceWorker' in navigator) {
+    window.addEventListener('load', () => {
+        navigator.serviceWorker.register(BASE_PATH + '/sw.js', { scope: BASE_PATH + '/' }).catch((err) => {
+            console.warn('Service worker registration failed:', err);
+        });
+    });
+}
 
 // Game events for UI updates (SSE handles most updates automatically)
 // Additional game-specific JavaScript can be added here as needed
\ No newline at end of file
@@ -0,0 +1,69 @@
+// Service worker for the Tic-Tac-Toe app shell
+
+const CACHE_NAME = 'tictactoe-shell-v1';
+// The worker is registered at the app's base path, e.g. /tictactoe/
+const BASE_PATH = new URL(self.registration.scope).pathname.replace(/\/$/, '');
+const OFFLINE_URL = BASE_PATH + '/offline';
+const SHELL_ASSETS = [
+    OFFLINE_URL,
+    BASE_PATH + '/static/css/style.css',
+    BASE_PATH + '/static/js/script.js',
+    BASE_PATH + '/static/icons/icon.svg',
+];
+
+self.addEventListener('install', (event) => {
+    event.waitUntil(
+        caches.open(CACHE_NAME)
+            .then((cache) => cache.addAll(SHELL_ASSETS))
+            .then(() => self.skipWaiting())
+    );
+});
+
+self.addEventListener('activate', (event) => {
+    // Drop caches from previous versions of the shell
+    event.waitUntil(
+        caches.keys()
+            .then((keys) => Promise.all(
+                keys.filter((key) => key !== CACHE_NAME).map((key) => caches.delete(key))
+            ))
+            .then(() => self.clients.claim())
+    );
+});
+
+self.addEventListener('fetch', (event) => {
+    const request = event.request;
+    if (request.method !== 'GET') {
+        return;
+    }
+
+    const url = new URL(request.url);
+    if (url.origin !== self.location.origin || url.pathname.startsWith(BASE_PATH + '/api/')) {
+        // Game state and event streams must always come from the network
+        return;
+    }
+
+    if (request.mode === 'navigate') {
+        // Pages are network-first; fall back to the reconnecting shell
+        event.respondWith(
+            fetch(request).catch(() => caches.match(OFFLINE_URL))
+        );
+        return;
+    }
+
+    if (url.pathname.startsWith(BASE_PATH + '/static/')) {
+        // Static assets are served from cache and refreshed in the background
+        event.respondWith(
+            caches.open(CACHE_NAME).then((cache) =>
+                cache.match(request).then((cached) => {
+                    const network = fetch(request).then((response) => {
+                        if (response.ok) {
+                            cache.put(request, response.clone());
+                        }
+                        return response;
+                    });
+                    return cached || network;
+                })
+            )
+        );
+    }
+});
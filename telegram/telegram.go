@@ -0,0 +1,163 @@
+// Package telegram talks to the Telegram Bot API: it decodes the updates
+// Telegram posts to the bot's webhook and sends the bot's replies.
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// APIURL is the Bot API server, replaced in tests
+var APIURL = "https://api.telegram.org"
+
+// SecretTokenHeader carries the secret token set with the bot's webhook, so
+// updates can be told apart from forged requests
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// Update is an incoming message or button press
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *Message       `json:"message,omitempty"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+type User struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+}
+
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+// CallbackQuery is a press of an inline keyboard button
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data"`
+}
+
+// Keyboard is an inline keyboard, as rows of buttons
+type Keyboard struct {
+	InlineKeyboard [][]Button `json:"inline_keyboard"`
+}
+
+// Button either sends Data back to the bot or, with a URL, opens a link
+type Button struct {
+	Text string `json:"text"`
+	Data string `json:"callback_data,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type sendMessageRequest struct {
+	ChatID      int64     `json:"chat_id"`
+	Text        string    `json:"text"`
+	ReplyMarkup *Keyboard `json:"reply_markup,omitempty"`
+}
+
+type editMessageRequest struct {
+	ChatID      int64     `json:"chat_id"`
+	MessageID   int64     `json:"message_id"`
+	Text        string    `json:"text"`
+	ReplyMarkup *Keyboard `json:"reply_markup,omitempty"`
+}
+
+type answerCallbackRequest struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+}
+
+var (
+	mu      sync.Mutex
+	token   string
+	client  = &http.Client{Timeout: 10 * time.Second}
+	pending sync.WaitGroup
+)
+
+// Configure sets the bot token. An empty token disables the bot.
+func Configure(botToken string) {
+	mu.Lock()
+	defer mu.Unlock()
+	token = botToken
+}
+
+// Enabled reports whether a bot token is configured
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return token != ""
+}
+
+// SendMessage sends text to a chat in the background, with an optional keyboard
+func SendMessage(chatID int64, text string, keyboard *Keyboard) {
+	call("sendMessage", sendMessageRequest{ChatID: chatID, Text: text, ReplyMarkup: keyboard})
+}
+
+// EditMessage replaces the text and keyboard of a message the bot sent
+func EditMessage(chatID, messageID int64, text string, keyboard *Keyboard) {
+	call("editMessageText", editMessageRequest{ChatID: chatID, MessageID: messageID, Text: text, ReplyMarkup: keyboard})
+}
+
+// AnswerCallback acknowledges a button press, showing text to the user if set
+func AnswerCallback(callbackID, text string) {
+	call("answerCallbackQuery", answerCallbackRequest{CallbackQueryID: callbackID, Text: text})
+}
+
+// Wait blocks until all calls in flight have finished. Tests use it.
+func Wait() {
+	pending.Wait()
+}
+
+// call invokes a Bot API method in the background. Failures are logged, not
+// retried: a lost chat message isn't worth delaying the ones after it.
+func call(method string, request interface{}) {
+	mu.Lock()
+	botToken, httpClient := token, client
+	mu.Unlock()
+	if botToken == "" {
+		return
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return
+	}
+
+	pending.Add(1)
+	go func() {
+		defer pending.Done()
+		if err := post(httpClient, APIURL+"/bot"+botToken+"/"+method, body); err != nil {
+			log.Printf("telegram %s: %v", method, err)
+		}
+	}()
+}
+
+func post(httpClient *http.Client, url string, body []byte) error {
+	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The URL holds the bot token, so only the cause is reported
+		if urlErr, ok := err.(interface{ Unwrap() error }); ok {
+			err = urlErr.Unwrap()
+		}
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
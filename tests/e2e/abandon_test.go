@@ -0,0 +1,162 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"htmx-go-app/game"
+	"htmx-go-app/handlers"
+	"htmx-go-app/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startGameWithAbandonRule starts a game like startHTTPGame, created with the
+// given abandonment rule
+func startGameWithAbandonRule(t *testing.T, server *httptest.Server, rule models.AbandonRule) (string, *httpPlayer, *httpPlayer) {
+	playerA := newHTTPPlayer(t, server)
+	playerB := newHTTPPlayer(t, server)
+
+	resp, _ := playerA.post(t, "/new-game", url.Values{"abandon_rule": {string(rule)}})
+	gameID := extractGameID(resp.Request.URL.Path)
+	require.NotEmpty(t, gameID)
+
+	playerA.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+	_, page := playerB.get(t, "/game/"+gameID+"/select-emoji")
+	assert.Contains(t, page, "idle 10m0s")
+	resp, _ = playerB.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+	require.Equal(t, "/game/"+gameID, resp.Request.URL.Path)
+
+	return gameID, playerA, playerB
+}
+
+func TestAbandonment(t *testing.T) {
+	fake := useFakeClock(t)
+	previous := game.AbandonAfter.Get()
+	game.AbandonAfter.Set(10 * time.Minute)
+	t.Cleanup(func() { game.AbandonAfter.Set(previous) })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	t.Run("The rule is shown before joining", func(t *testing.T) {
+		creator := newHTTPPlayer(t, server)
+		resp, _ := creator.post(t, "/new-game", url.Values{"abandon_rule": {"void"}})
+		gameID := extractGameID(resp.Request.URL.Path)
+		creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+
+		_, page := newHTTPPlayer(t, server).get(t, "/game/"+gameID+"/select-emoji")
+		assert.Contains(t, page, "idle 10m0s: game is void")
+
+		resp, body := creator.post(t, "/api/game/"+gameID+"/settings", url.Values{"abandon_rule": {"pause"}})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.Contains(t, body, `<option value="pause" selected>`)
+		_, page = newHTTPPlayer(t, server).get(t, "/game/"+gameID+"/select-emoji")
+		assert.Contains(t, page, "idle 10m0s: game pauses")
+
+		resp, _ = creator.post(t, "/api/game/"+gameID+"/settings", url.Values{"abandon_rule": {"forfeit"}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("An idle player loses under the default rule", func(t *testing.T) {
+		gameID, playerA, playerB := startGameWithAbandonRule(t, server, "")
+		playMoves(t, gameID, playerA, playerB, "0/0")
+		stream := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+		readSSEEvent(t, stream, "initial")
+
+		fake.Advance(9 * time.Minute)
+		handlers.SweepAbandonedGames()
+		assert.Equal(t, models.GameStatusActive, game.GetGame(gameID).Status, "not idle long enough yet")
+
+		fake.Advance(2 * time.Minute)
+		handlers.SweepAbandonedGames()
+		// The status comes along with the board, swapped out of band
+		status := readSSEEvent(t, stream, "game_winner")
+		assert.Contains(t, status, "🏆 🐱 wins!")
+		assert.Contains(t, status, "🚀 stopped playing")
+	})
+
+	t.Run("A void game is removed", func(t *testing.T) {
+		gameID, playerA, _ := startGameWithAbandonRule(t, server, models.AbandonVoid)
+		stream := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+		readSSEEvent(t, stream, "initial")
+
+		fake.Advance(11 * time.Minute)
+		handlers.SweepAbandonedGames()
+		readSSEEvent(t, stream, "game_cancelled")
+		assert.Nil(t, game.GetGame(gameID))
+	})
+
+	t.Run("A move racing the sweep either lands or loses", func(t *testing.T) {
+		gameID, playerA, _ := startGameWithAbandonRule(t, server, "")
+		fake.Advance(11 * time.Minute)
+
+		swept := make(chan struct{})
+		go func() {
+			defer close(swept)
+			handlers.SweepAbandonedGames()
+		}()
+		playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		<-swept
+
+		_, body := playerA.get(t, "/api/v1/game/"+gameID)
+		played := decodeAPIGame(t, body)
+		if played.Status == "finished" {
+			assert.Equal(t, "🚀", played.Winner, "🐱 was idle when swept")
+			assert.Zero(t, played.MoveCount)
+		} else {
+			assert.Equal(t, "active", played.Status)
+			assert.Equal(t, 1, played.MoveCount, "the move kept 🐱 in the game")
+		}
+	})
+
+	t.Run("A correspondence game is never abandoned", func(t *testing.T) {
+		playerA := newHTTPPlayer(t, server)
+		playerB := newHTTPPlayer(t, server)
+		resp, _ := playerA.post(t, "/new-game", url.Values{"correspondence": {"true"}})
+		gameID := extractGameID(resp.Request.URL.Path)
+		playerA.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+		playerB.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+
+		fake.Advance(48 * time.Hour)
+		handlers.SweepAbandonedGames()
+		assert.Equal(t, models.GameStatusActive, game.GetGame(gameID).Status)
+	})
+
+	t.Run("A paused game can be resumed by either player", func(t *testing.T) {
+		gameID, playerA, playerB := startGameWithAbandonRule(t, server, models.AbandonPause)
+		stream := openSSEStream(t, playerB, "/api/game/"+gameID+"/events")
+		readSSEEvent(t, stream, "initial")
+
+		fake.Advance(11 * time.Minute)
+		handlers.SweepAbandonedGames()
+		assert.Contains(t, readSSEEvent(t, stream, "game_paused"), "paused because 🐱 stopped playing")
+
+		resp, body := playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotContains(t, body, "🐱", "no moves while paused")
+
+		_, page := playerA.get(t, "/game/"+gameID)
+		assert.Contains(t, page, `data-testid="resume-game"`)
+		_, status := newHTTPPlayer(t, server).get(t, "/api/game/"+gameID+"/fragment/status")
+		assert.NotContains(t, status, "resume-game", "spectators can't resume")
+
+		resp, body = playerA.htmxPost(t, "/api/game/"+gameID+"/resume")
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.Contains(t, body, "Your turn!")
+		readSSEEvent(t, stream, "game_resumed")
+
+		// The idle clock starts over from the resumption
+		fake.Advance(5 * time.Minute)
+		handlers.SweepAbandonedGames()
+		assert.Equal(t, models.GameStatusActive, game.GetGame(gameID).Status)
+
+		resp, _ = playerA.htmxPost(t, "/api/game/"+gameID+"/resume")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+}
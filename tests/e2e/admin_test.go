@@ -0,0 +1,115 @@
+package e2e
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"htmx-go-app/game"
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminKey = "test-admin-key"
+
+// adminRequest calls the admin API with the given key
+func adminRequest(t *testing.T, server *httptest.Server, key, method, path string) (*http.Response, string) {
+	req, err := http.NewRequest(method, server.URL+path, nil)
+	require.NoError(t, err)
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestAdminAPI(t *testing.T) {
+	handlers.AdminAPIKey.Set(testAdminKey)
+	t.Cleanup(func() { handlers.AdminAPIKey.Set("") })
+
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	gameID, playerA, _ := startHTTPGame(t, server)
+	playerAID := game.GetGame(gameID).PlayerOrder[0]
+
+	t.Run("Requests without the key are rejected", func(t *testing.T) {
+		resp, _ := adminRequest(t, server, "", http.MethodGet, "/api/admin/stats")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp, _ = adminRequest(t, server, "wrong", http.MethodGet, "/api/admin/stats")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Lists games filtered by player", func(t *testing.T) {
+		resp, body := adminRequest(t, server, testAdminKey, http.MethodGet, "/api/admin/games?status=active&player="+playerAID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var listing struct {
+			Games []struct {
+				ID        string   `json:"id"`
+				PlayerIDs []string `json:"playerIds"`
+			} `json:"games"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &listing))
+		require.Len(t, listing.Games, 1)
+		assert.Equal(t, gameID, listing.Games[0].ID)
+		assert.Contains(t, listing.Games[0].PlayerIDs, playerAID)
+	})
+
+	t.Run("Reports server stats", func(t *testing.T) {
+		resp, body := adminRequest(t, server, testAdminKey, http.MethodGet, "/api/admin/stats")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var stats map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(body), &stats))
+		assert.GreaterOrEqual(t, stats["games"], float64(1))
+		assert.Contains(t, stats, "gamesByStatus")
+	})
+
+	t.Run("Banned players are turned away until unbanned", func(t *testing.T) {
+		resp, _ := adminRequest(t, server, testAdminKey, http.MethodPost, "/api/admin/players/"+playerAID+"/ban")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, body := playerA.get(t, "/game/"+gameID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, body, "banned")
+
+		resp, _ = playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		_, body = adminRequest(t, server, testAdminKey, http.MethodGet, "/api/admin/bans")
+		assert.Contains(t, body, playerAID)
+
+		resp, _ = adminRequest(t, server, testAdminKey, http.MethodDelete, "/api/admin/players/"+playerAID+"/ban")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, _ = playerA.get(t, "/game/"+gameID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Deletes a game", func(t *testing.T) {
+		resp, _ := adminRequest(t, server, testAdminKey, http.MethodDelete, "/api/admin/games/"+gameID)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Nil(t, game.GetGame(gameID))
+
+		resp, _ = adminRequest(t, server, testAdminKey, http.MethodDelete, "/api/admin/games/"+gameID)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestAdminAPIDisabledWithoutKey(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	resp, _ := adminRequest(t, server, "", http.MethodGet, "/api/admin/stats")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
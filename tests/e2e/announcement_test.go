@@ -0,0 +1,100 @@
+package e2e
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postAnnouncement posts an announcement through the admin API
+func postAnnouncement(t *testing.T, server *httptest.Server, payload map[string]string) (*http.Response, string) {
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/admin/announcement", bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", testAdminKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestAnnouncements(t *testing.T) {
+	handlers.AdminAPIKey.Set(testAdminKey)
+	t.Cleanup(func() { handlers.AdminAPIKey.Set("") })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { adminRequest(t, server, testAdminKey, http.MethodDelete, "/api/admin/announcement") })
+
+	gameID, playerA, _ := startHTTPGame(t, server)
+	gameStream := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+	readSSEEvent(t, gameStream, "initial")
+
+	visitor := newHTTPPlayer(t, server)
+	lobbyStream := openSSEStream(t, visitor, "/api/lobby/events")
+	readSSEEvent(t, lobbyStream, "lobby_update")
+
+	t.Run("Posting pushes the banner to game and lobby streams", func(t *testing.T) {
+		resp, _ := postAnnouncement(t, server, map[string]string{"message": "Maintenance in <10> minutes"})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		for _, stream := range []*bufio.Reader{gameStream, lobbyStream} {
+			data := readSSEEvent(t, stream, "announcement")
+			assert.Contains(t, data, `class="announcement"`)
+			assert.Contains(t, data, "Maintenance in &lt;10&gt; minutes")
+			assert.Contains(t, data, "announcement-dismiss")
+		}
+	})
+
+	t.Run("Pages render the current banner", func(t *testing.T) {
+		_, body := visitor.get(t, "/")
+		assert.Contains(t, body, "Maintenance in &lt;10&gt; minutes")
+	})
+
+	t.Run("Clearing empties the banner everywhere", func(t *testing.T) {
+		resp, _ := adminRequest(t, server, testAdminKey, http.MethodDelete, "/api/admin/announcement")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		data := readSSEEvent(t, gameStream, "announcement")
+		assert.Equal(t, `<div id="announcement"></div>`, strings.TrimSpace(data))
+
+		_, body := visitor.get(t, "/")
+		assert.NotContains(t, body, "Maintenance")
+	})
+
+	t.Run("Announcements with a duration expire", func(t *testing.T) {
+		fake := useFakeClock(t)
+		resp, _ := postAnnouncement(t, server, map[string]string{"message": "Back soon", "duration": "10m"})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		readSSEEvent(t, gameStream, "announcement")
+
+		fake.Advance(10 * time.Minute)
+		data := readSSEEvent(t, gameStream, "announcement")
+		assert.NotContains(t, data, "Back soon")
+
+		resp, _ = adminRequest(t, server, testAdminKey, http.MethodGet, "/api/admin/announcement")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Empty messages are rejected", func(t *testing.T) {
+		resp, _ := postAnnouncement(t, server, map[string]string{"message": "  "})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
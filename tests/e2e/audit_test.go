@@ -0,0 +1,82 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"htmx-go-app/audit"
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditEntries fetches the audit log through the admin API
+func auditEntries(t *testing.T, server *httptest.Server, query string) []audit.Entry {
+	resp, body := adminRequest(t, server, testAdminKey, http.MethodGet, "/api/admin/audit"+query)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response struct {
+		Entries []audit.Entry `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &response))
+	return response.Entries
+}
+
+func TestAuditLog(t *testing.T) {
+	handlers.AdminAPIKey.Set(testAdminKey)
+	t.Cleanup(func() { handlers.AdminAPIKey.Set("") })
+
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	t.Run("Resets record the player who reset", func(t *testing.T) {
+		gameID, playerA, _ := startHTTPGame(t, server)
+		playerA.htmxPost(t, "/api/game/"+gameID+"/reset")
+
+		entries := auditEntries(t, server, "?game="+gameID)
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionGameReset, entries[0].Action)
+		assert.NotEqual(t, audit.ActorAdmin, entries[0].Actor)
+		assert.NotEmpty(t, entries[0].IP)
+		assert.False(t, entries[0].Time.IsZero())
+	})
+
+	t.Run("Admin actions are recorded newest first", func(t *testing.T) {
+		gameID, _, _ := startHTTPGame(t, server)
+		adminRequest(t, server, testAdminKey, http.MethodPost, "/api/admin/players/audit-target/ban")
+		adminRequest(t, server, testAdminKey, http.MethodDelete, "/api/admin/players/audit-target/ban")
+		adminRequest(t, server, testAdminKey, http.MethodDelete, "/api/admin/games/"+gameID)
+
+		entries := auditEntries(t, server, "?actor=admin&limit=3")
+		require.Len(t, entries, 3)
+		assert.Equal(t, audit.ActionGameDeleted, entries[0].Action)
+		assert.Equal(t, gameID, entries[0].GameID)
+		assert.Equal(t, audit.ActionPlayerUnbanned, entries[1].Action)
+		assert.Equal(t, audit.ActionPlayerBanned, entries[2].Action)
+		assert.Equal(t, "audit-target", entries[2].Target)
+	})
+
+	t.Run("Requires the admin key", func(t *testing.T) {
+		resp, _ := adminRequest(t, server, "", http.MethodGet, "/api/admin/audit")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestAuditLogPersists(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "audit.log")
+
+	require.NoError(t, audit.Open(file))
+	require.NoError(t, audit.Record(audit.Entry{Actor: audit.ActorAdmin, Action: audit.ActionPlayerBanned, Target: "persisted"}))
+	require.NoError(t, audit.Close())
+
+	// Reopening after a restart brings back what was written
+	require.NoError(t, audit.Open(file))
+	defer audit.Close()
+	entries := audit.Entries(0, func(e audit.Entry) bool { return e.Target == "persisted" })
+	require.Len(t, entries, 1)
+	assert.Equal(t, audit.ActionPlayerBanned, entries[0].Action)
+}
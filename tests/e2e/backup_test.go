@@ -0,0 +1,107 @@
+package e2e
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"htmx-go-app/game"
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restoreBackup uploads an archive to the admin restore endpoint
+func restoreBackup(t *testing.T, server *httptest.Server, archive []byte) (*http.Response, string) {
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/admin/restore", bytes.NewReader(archive))
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", testAdminKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	handlers.AdminAPIKey.Set(testAdminKey)
+	t.Cleanup(func() { handlers.AdminAPIKey.Set("") })
+
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	kept, playerA, playerB := startHTTPGame(t, server)
+	playMoves(t, kept, playerA, playerB, "1/1", "0/0")
+	bot, _, err := game.RegisterBot("Backup Bot", "")
+	require.NoError(t, err)
+	game.BanPlayer("backup-banned")
+
+	resp, archive := adminRequest(t, server, testAdminKey, http.MethodGet, "/api/admin/backup")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/gzip", resp.Header.Get("Content-Type"))
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), `filename="tictactoe-backup-`)
+
+	reader, err := gzip.NewReader(strings.NewReader(archive))
+	require.NoError(t, err)
+	var backup game.Backup
+	require.NoError(t, json.NewDecoder(reader).Decode(&backup))
+	assert.Equal(t, game.BackupVersion, backup.Version)
+	assert.Contains(t, backup.BannedPlayers, "backup-banned")
+	var ids []string
+	for _, g := range backup.Games {
+		ids = append(ids, g.ID)
+	}
+	assert.Contains(t, ids, kept)
+
+	// Changes after the backup are undone by restoring it
+	dropped, _, _ := startHTTPGame(t, server)
+	playMoves(t, kept, playerA, playerB, "2/2")
+	game.UnbanPlayer("backup-banned")
+	require.True(t, game.DeleteBot(bot.ID))
+
+	resp, body := restoreBackup(t, server, []byte(archive))
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Contains(t, body, `"games":`)
+
+	assert.Nil(t, game.GetGame(dropped))
+	restored := game.GetGame(kept)
+	require.NotNil(t, restored)
+	assert.Equal(t, 2, restored.MoveCount)
+	assert.True(t, game.IsBanned("backup-banned"))
+	require.NotNil(t, game.BotForPlayer(bot.PlayerID))
+	assert.Equal(t, "Backup Bot", game.BotForPlayer(bot.PlayerID).Name)
+
+	// The restored game carries on where the backup left it
+	resp, _ = playerA.htmxPost(t, "/api/game/"+kept+"/move/2/2")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	t.Run("Plain JSON", func(t *testing.T) {
+		data, err := json.Marshal(game.ExportBackup())
+		require.NoError(t, err)
+		resp, body := restoreBackup(t, server, data)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, body)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		resp, _ := restoreBackup(t, server, []byte("not a backup"))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, body := restoreBackup(t, server, []byte(`{"version": 99}`))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, body, "Unsupported backup version")
+		assert.NotNil(t, game.GetGame(kept), "a rejected backup changes nothing")
+
+		resp, _ = adminRequest(t, server, "", http.MethodGet, "/api/admin/backup")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	game.UnbanPlayer("backup-banned")
+}
@@ -0,0 +1,44 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPieceBadges(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	playMoves(t, gameID, playerA, playerB, "0/0", "1/1")
+	boardPath := "/api/game/" + gameID + "/fragment/board"
+
+	_, board := playerB.get(t, boardPath)
+	assert.NotContains(t, board, "piece-badge", "badges are off by default")
+
+	resp, body := playerB.post(t, "/api/preferences/badges", url.Values{"game": {gameID}})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Contains(t, body, `aria-pressed="true"`)
+	assert.Regexp(t, `id="game-board"[^>]*hx-swap-oob="true"`, body, "the board is redrawn right away")
+	assert.Contains(t, body, `🐱<span class="piece-badge piece-badge-x" aria-hidden="true">X</span>`)
+	assert.Contains(t, body, `🚀<span class="piece-badge piece-badge-o" aria-hidden="true">O</span>`)
+	assert.Contains(t, body, `aria-label="row 2 column 2, 🚀 (O)"`)
+
+	_, board = playerA.get(t, boardPath)
+	assert.NotContains(t, board, "piece-badge", "each player chooses for themselves")
+	_, board = playerB.get(t, boardPath)
+	assert.Contains(t, board, "piece-badge-x")
+
+	_, page := playerB.get(t, "/game/"+gameID)
+	assert.Contains(t, page, `data-testid="badge-toggle"`)
+
+	resp, body = playerB.post(t, "/api/preferences/badges", url.Values{"game": {gameID}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `aria-pressed="false"`)
+	assert.NotContains(t, body, "piece-badge")
+}
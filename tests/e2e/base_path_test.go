@@ -0,0 +1,72 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBasePath(t *testing.T) {
+	handlers.BasePath = "/ttt"
+	t.Cleanup(func() { handlers.BasePath = "" })
+
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	playerA := newHTTPPlayer(t, server)
+	playerB := newHTTPPlayer(t, server)
+
+	t.Run("Routes only answer under the prefix", func(t *testing.T) {
+		resp, body := playerA.get(t, "/ttt/")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, `href="/ttt/new-game"`)
+		assert.Contains(t, body, `src="/ttt/static/js/script.js"`)
+		assert.Contains(t, body, `data-base-path="/ttt"`)
+
+		resp, _ = playerA.get(t, "/new-game")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	var gameID string
+	t.Run("Redirects and game links keep the prefix", func(t *testing.T) {
+		resp, body := playerA.get(t, "/ttt/new-game")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		gameID = extractGameID(resp.Request.URL.Path)
+		require.NotEmpty(t, gameID)
+		assert.Equal(t, "/ttt/game/"+gameID+"/select-emoji", resp.Request.URL.Path)
+		assert.Contains(t, body, `action="/ttt/game/`+gameID+`/select-emoji"`)
+		assert.Contains(t, body, server.URL+"/ttt/game/"+gameID)
+
+		playerA.post(t, "/ttt/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+		resp, body = playerB.post(t, "/ttt/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+		require.Equal(t, "/ttt/game/"+gameID, resp.Request.URL.Path)
+		assert.Contains(t, body, `sse-connect="/ttt/api/game/`+gameID+`/events"`)
+
+		_, body = playerA.get(t, "/ttt/game/"+gameID)
+		assert.Contains(t, body, `hx-post="/ttt/api/game/`+gameID+`/move/0/0"`)
+	})
+
+	t.Run("Moves are accepted under the prefix", func(t *testing.T) {
+		resp, _ := playerA.htmxPost(t, "/ttt/api/game/"+gameID+"/move/0/0")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Manifest and service worker are scoped to the prefix", func(t *testing.T) {
+		resp, body := playerA.get(t, "/ttt/manifest.webmanifest")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var manifest map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(body), &manifest))
+		assert.Equal(t, "/ttt/", manifest["start_url"])
+		assert.Equal(t, "/ttt/", manifest["scope"])
+
+		resp, _ = playerA.get(t, "/ttt/sw.js")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "/ttt/", resp.Header.Get("Service-Worker-Allowed"))
+	})
+}
@@ -0,0 +1,78 @@
+package e2e
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardImages(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+	playerB.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+
+	viewer := newHTTPPlayer(t, server)
+
+	t.Run("SVG shows the current board", func(t *testing.T) {
+		resp, body := viewer.get(t, "/api/game/"+gameID+"/board.svg")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "image/svg+xml", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+		assert.Contains(t, body, "<svg")
+		assert.Contains(t, body, ">🐱</text>")
+		assert.Contains(t, body, ">🚀</text>")
+		assert.Contains(t, body, "🐱 vs 🚀 · 🐱 to move")
+	})
+
+	t.Run("PNG is a valid image", func(t *testing.T) {
+		resp, body := viewer.get(t, "/api/game/"+gameID+"/board.png")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+		_, err := png.Decode(bytes.NewReader([]byte(body)))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Finished board is cacheable", func(t *testing.T) {
+		playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/1")
+		playerB.htmxPost(t, "/api/game/"+gameID+"/move/2/2")
+		playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/2")
+
+		resp, body := viewer.get(t, "/api/game/"+gameID+"/board.svg")
+		assert.Contains(t, resp.Header.Get("Cache-Control"), "max-age")
+		assert.Contains(t, body, "🐱 wins!")
+	})
+
+	t.Run("Unknown game is not found", func(t *testing.T) {
+		resp, _ := viewer.get(t, "/api/game/doesnotexist/board.svg")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestInviteQRCode(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	creator := newHTTPPlayer(t, server)
+	resp, _ := creator.get(t, "/new-game")
+	gameID := extractGameID(resp.Request.URL.Path)
+	_, body := creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+	assert.Contains(t, body, `<img src="/game/`+gameID+`/qr.png"`)
+
+	resp, body = creator.get(t, "/game/"+gameID+"/qr.png")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+	_, err := png.Decode(bytes.NewReader([]byte(body)))
+	assert.NoError(t, err)
+
+	resp, _ = creator.get(t, "/game/doesnotexist/qr.png")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
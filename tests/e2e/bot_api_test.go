@@ -0,0 +1,180 @@
+package e2e
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"htmx-go-app/handlers"
+	"htmx-go-app/webhooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registeredBot struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	PlayerID      string `json:"playerId"`
+	APIKey        string `json:"apiKey"`
+	WebhookSecret string `json:"webhookSecret"`
+}
+
+// bearerTransport sends a bot's API key with every request
+type bearerTransport struct {
+	apiKey string
+}
+
+func (b bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newBotPlayer is a client without cookies that authenticates as a bot
+func newBotPlayer(server *httptest.Server, apiKey string) *httpPlayer {
+	return &httpPlayer{
+		client:  &http.Client{Transport: bearerTransport{apiKey}},
+		baseURL: server.URL,
+	}
+}
+
+func registerBot(t *testing.T, server *httptest.Server, payload map[string]string) registeredBot {
+	resp, body := newHTTPPlayer(t, server).postJSON(t, "/api/v1/bots", payload)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+
+	var bot registeredBot
+	require.NoError(t, json.Unmarshal([]byte(body), &bot))
+	require.NotEmpty(t, bot.APIKey)
+	return bot
+}
+
+func TestBotPlaysHumanOverTurnStream(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	bot := registerBot(t, server, map[string]string{"name": "MinimaxBot"})
+	botPlayer := newBotPlayer(server, bot.APIKey)
+	turns := openSSEStream(t, botPlayer, "/api/v1/bots/me/turns")
+
+	human := newHTTPPlayer(t, server)
+	resp, body := human.postJSON(t, "/api/v1/games", map[string]interface{}{"emoji": "🐱"})
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	gameID := decodeAPIGame(t, body).ID
+
+	resp, body = botPlayer.postJSON(t, "/api/v1/game/"+gameID+"/join", map[string]string{"emoji": "🚀"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	joined := decodeAPIGame(t, body)
+	require.NotNil(t, joined.You)
+	assert.Equal(t, bot.PlayerID, joined.You.PlayerID, "the bot plays under its own identity")
+	assert.Equal(t, "MinimaxBot", joined.Players[1].Name, "the seat is named after the bot")
+
+	resp, body = human.postJSON(t, "/api/v1/game/"+gameID+"/move", map[string]int{"row": 1, "col": 1})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	var turn eventMessageResponse
+	require.NoError(t, json.Unmarshal([]byte(readSSEEvent(t, turns, "your_turn")), &turn))
+	assert.Equal(t, gameID, turn.GameID)
+	require.NotNil(t, turn.Game)
+	assert.Equal(t, "🐱", turn.Game.Board[1][1])
+	assert.True(t, turn.Game.You.YourTurn)
+
+	resp, body = botPlayer.get(t, "/api/v1/bots/me")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Contains(t, body, gameID, "the game is listed as waiting on the bot")
+
+	resp, body = botPlayer.postJSON(t, "/api/v1/game/"+gameID+"/move", map[string]int{"row": 0, "col": 0})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, "🚀", decodeAPIGame(t, body).Board[0][0])
+}
+
+func TestBotTurnWebhook(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []receivedWebhook
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, receivedWebhook{
+			event:     r.Header.Get("X-Webhook-Event"),
+			signature: r.Header.Get(webhooks.SignatureHeader),
+			body:      body,
+		})
+	}))
+	t.Cleanup(receiver.Close)
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	// Bot endpoints often carry their own token in the URL
+	bot := registerBot(t, server, map[string]string{"name": "HookBot", "webhookUrl": receiver.URL + "/hooks/s3cret-token?token=s3cret"})
+	require.NotEmpty(t, bot.WebhookSecret)
+	botPlayer := newBotPlayer(server, bot.APIKey)
+
+	// The bot creates the game, so it moves first as soon as someone joins
+	resp, body := botPlayer.postJSON(t, "/api/v1/games", map[string]interface{}{"emoji": "🚀"})
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	gameID := decodeAPIGame(t, body).ID
+
+	resp, body = newHTTPPlayer(t, server).postJSON(t, "/api/v1/game/"+gameID+"/join", map[string]string{"emoji": "🐱"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	webhooks.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 1)
+	hook := received[0]
+	assert.Equal(t, webhooks.EventBotTurn, hook.event)
+	assert.Equal(t, webhooks.Sign(bot.WebhookSecret, hook.body), hook.signature)
+
+	var payload struct {
+		Data eventMessageResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(hook.body, &payload))
+	assert.Equal(t, "your_turn", payload.Data.Type)
+	assert.Equal(t, gameID, payload.Data.GameID)
+	require.NotNil(t, payload.Data.Game)
+	assert.True(t, payload.Data.Game.You.YourTurn)
+
+	logged := false
+	for _, delivery := range webhooks.Deliveries() {
+		logged = logged || delivery.Target == receiver.URL
+		assert.NotContains(t, delivery.Target+delivery.Error, "s3cret", "the bot's token stays out of the delivery log")
+	}
+	assert.True(t, logged, "turns are logged under the bot's host")
+}
+
+func TestBotAPIKeys(t *testing.T) {
+	handlers.AdminAPIKey.Set(testAdminKey)
+	t.Cleanup(func() { handlers.AdminAPIKey.Set("") })
+
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	resp, body := newHTTPPlayer(t, server).postJSON(t, "/api/v1/bots", map[string]string{"name": ""})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+	resp, body = newHTTPPlayer(t, server).postJSON(t, "/api/v1/bots", map[string]string{"name": "Bot", "webhookUrl": "ftp://example.com"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+
+	resp, _ = newBotPlayer(server, "ttt_wrong").get(t, "/api/v1/bots/me")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp, _ = newHTTPPlayer(t, server).get(t, "/api/v1/bots/me")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "a cookie is not a bot")
+
+	bot := registerBot(t, server, map[string]string{"name": "RevokedBot"})
+	resp, body = adminRequest(t, server, testAdminKey, http.MethodGet, "/api/admin/bots")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Contains(t, body, bot.ID)
+	assert.NotContains(t, body, bot.APIKey, "keys are never listed")
+
+	resp, _ = adminRequest(t, server, testAdminKey, http.MethodDelete, "/api/admin/bots/"+bot.ID)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, _ = newBotPlayer(server, bot.APIKey).get(t, "/api/v1/bots/me")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "a revoked key is rejected")
+}
@@ -0,0 +1,65 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"htmx-go-app/config"
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranding(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	visitor := newHTTPPlayer(t, server)
+
+	t.Run("Defaults", func(t *testing.T) {
+		_, page := visitor.get(t, "/")
+		assert.Contains(t, page, `<meta name="theme-color" content="#2c3e50">`)
+		assert.Contains(t, page, `data-testid="site-name">Tic-Tac-Toe</a>`)
+		assert.NotContains(t, page, `data-testid="brand-logo"`)
+	})
+
+	t.Run("Applied at render time", func(t *testing.T) {
+		handlers.SetBranding(handlers.Branding{SiteName: "Noughts & Crosses", LogoURL: "/static/icons/icon.svg", ThemeColor: "#0a7d32"})
+		t.Cleanup(func() { handlers.SetBranding(handlers.DefaultBranding) })
+
+		_, page := visitor.get(t, "/lobby")
+		assert.Contains(t, page, `<meta name="theme-color" content="#0a7d32">`)
+		assert.Contains(t, page, `--brand-color: #0a7d32`)
+		assert.Contains(t, page, `Noughts &amp; Crosses</a>`)
+		assert.Contains(t, page, `<img src="/static/icons/icon.svg" alt="" class="brand-logo" data-testid="brand-logo">`)
+
+		resp, body := visitor.get(t, "/manifest.webmanifest")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var manifest map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(body), &manifest))
+		assert.Equal(t, "Noughts & Crosses", manifest["name"])
+		assert.Equal(t, "#0a7d32", manifest["theme_color"])
+	})
+
+	t.Run("Configuration", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", "")
+
+		cfg, err := config.Load([]string{"-site-name", "Office TTT", "-theme-color", "#123abc", "-logo-url", "https://cdn.example.com/logo.png"})
+		require.NoError(t, err)
+		assert.Equal(t, "Office TTT", cfg.SiteName)
+		assert.Equal(t, "#123abc", cfg.ThemeColor)
+		assert.Equal(t, "https://cdn.example.com/logo.png", cfg.LogoURL)
+
+		for _, args := range [][]string{
+			{"-site-name", " "},
+			{"-theme-color", "red"},
+			{"-theme-color", "#123abc; background: url(x)"},
+			{"-logo-url", "javascript:alert(1)"},
+		} {
+			_, err := config.Load(args)
+			assert.Error(t, err, args)
+		}
+	})
+}
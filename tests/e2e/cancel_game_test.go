@@ -0,0 +1,67 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"htmx-go-app/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelGame(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	// createWaitingGame creates a game whose creator has picked an emoji
+	createWaitingGame := func(t *testing.T) (string, *httpPlayer) {
+		creator := newHTTPPlayer(t, server)
+		resp, _ := creator.get(t, "/new-game")
+		gameID := extractGameID(resp.Request.URL.Path)
+		creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+		return gameID, creator
+	}
+
+	t.Run("The waiting page offers to cancel", func(t *testing.T) {
+		gameID, creator := createWaitingGame(t)
+		_, page := creator.get(t, "/game/"+gameID+"/select-emoji")
+		assert.Contains(t, page, `hx-delete="/api/game/`+gameID+`"`)
+		assert.Contains(t, page, `sse-swap="game_cancelled"`)
+	})
+
+	t.Run("The creator cancels and subscribers are sent home", func(t *testing.T) {
+		gameID, creator := createWaitingGame(t)
+		slug := game.GetGame(gameID).Slug
+		stream := openSSEStream(t, creator, "/api/game/"+gameID+"/events")
+		readSSEEvent(t, stream, "initial")
+
+		resp, _ := creator.do(t, http.MethodDelete, "/api/game/"+gameID, nil, true)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "/", resp.Header.Get("HX-Redirect"))
+
+		data := readSSEEvent(t, stream, "game_cancelled")
+		assert.Contains(t, data, `data-redirect="/"`)
+
+		assert.Nil(t, game.GetGame(gameID), "the ID is freed")
+		assert.Nil(t, game.ResolveGameCode(slug), "the join code is freed")
+		resp, _ = newHTTPPlayer(t, server).get(t, "/game/"+gameID+"/select-emoji")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Only the creator can cancel", func(t *testing.T) {
+		gameID, _ := createWaitingGame(t)
+		resp, _ := newHTTPPlayer(t, server).do(t, http.MethodDelete, "/api/game/"+gameID, nil, true)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.NotNil(t, game.GetGame(gameID))
+	})
+
+	t.Run("Started games can't be cancelled", func(t *testing.T) {
+		gameID, playerA, _ := startHTTPGame(t, server)
+		resp, _ := playerA.do(t, http.MethodDelete, "/api/game/"+gameID, nil, true)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.NotNil(t, game.GetGame(gameID))
+	})
+}
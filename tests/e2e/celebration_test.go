@@ -0,0 +1,50 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCelebration(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	streamA := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+	streamB := openSSEStream(t, playerB, "/api/game/"+gameID+"/events")
+	jsonStream := openSSEStream(t, playerB, "/api/v1/game/"+gameID+"/events?types=celebration")
+	readSSEEvent(t, streamA, "initial")
+	readSSEEvent(t, streamB, "initial")
+	readSSEEvent(t, jsonStream, "initial")
+
+	playMoves(t, gameID, playerA, playerB, "0/0", "1/0", "0/1", "1/1", "0/2")
+
+	t.Run("Both players get the winning line", func(t *testing.T) {
+		winner := readSSEEvent(t, streamA, "celebration")
+		assert.Contains(t, winner, `data-line="0-0 0-1 0-2"`)
+		assert.Contains(t, winner, `data-emoji="🐱"`)
+		assert.Contains(t, winner, `data-winner="true"`)
+
+		loser := readSSEEvent(t, streamB, "celebration")
+		assert.Contains(t, loser, `data-line="0-0 0-1 0-2"`)
+		assert.NotContains(t, loser, "data-winner")
+	})
+
+	t.Run("JSON clients get the winning line on the game", func(t *testing.T) {
+		var event struct {
+			Type string `json:"type"`
+			Game struct {
+				Winner      string   `json:"winner"`
+				WinningLine [][2]int `json:"winningLine"`
+			} `json:"game"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(readSSEEvent(t, jsonStream, "celebration")), &event))
+		assert.Equal(t, "celebration", event.Type)
+		assert.Equal(t, "🐱", event.Game.Winner)
+		assert.Equal(t, [][2]int{{0, 0}, {0, 1}, {0, 2}}, event.Game.WinningLine)
+	})
+}
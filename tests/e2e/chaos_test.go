@@ -0,0 +1,83 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"htmx-go-app/events"
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/require"
+)
+
+// useChaos sets the stream chaos rates for one test
+func useChaos(t *testing.T, dropRate, disconnectRate float64) {
+	events.ChaosDropRate.Set(dropRate)
+	events.ChaosDisconnectRate.Set(disconnectRate)
+	t.Cleanup(func() {
+		events.ChaosDropRate.Set(0)
+		events.ChaosDisconnectRate.Set(0)
+	})
+}
+
+func TestChaosDroppedEvents(t *testing.T) {
+	previous := events.HeartbeatInterval.Get()
+	events.HeartbeatInterval.Set(100 * time.Millisecond)
+	t.Cleanup(func() { events.HeartbeatInterval.Set(previous) })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	stream := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+	readSSEEvent(t, stream, "initial")
+
+	useChaos(t, 1, 0)
+	playMoves(t, gameID, playerA, playerB, "0/0")
+
+	// The move is long handled by the second heartbeat, yet never arrives
+	heartbeats := 0
+	for heartbeats < 2 {
+		line, err := stream.ReadString('\n')
+		require.NoError(t, err)
+		require.False(t, strings.HasPrefix(line, "event: "), "unexpected %q", line)
+		if strings.HasPrefix(line, ":") {
+			heartbeats++
+		}
+	}
+}
+
+func TestChaosDisconnects(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	stream := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+	readSSEEvent(t, stream, "initial")
+
+	useChaos(t, 0, 1)
+	playMoves(t, gameID, playerA, playerB, "0/0")
+	readSSEEvent(t, stream, "move")
+
+	// The server ends the stream right after the event
+	for {
+		if _, err := stream.ReadString('\n'); err != nil {
+			break
+		}
+	}
+}
+
+func TestChaosLatency(t *testing.T) {
+	handlers.ChaosLatency.Set(20 * time.Millisecond)
+	t.Cleanup(func() { handlers.ChaosLatency.Set(0) })
+
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	player := newHTTPPlayer(t, server)
+	resp, _ := player.get(t, "/")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,67 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"htmx-go-app/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameChat(t *testing.T) {
+	previous := game.ChatCooldown.Get()
+	game.ChatCooldown.Set(time.Hour)
+	t.Cleanup(func() { game.ChatCooldown.Set(previous) })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	chatPath := "/api/game/" + gameID + "/chat"
+
+	_, page := playerA.get(t, "/game/"+gameID)
+	assert.Contains(t, page, `id="chat-messages"`)
+	assert.Contains(t, page, `hx-post="`+chatPath+`"`)
+
+	stream := openSSEStream(t, playerB, "/api/game/"+gameID+"/events")
+	readSSEEvent(t, stream, "initial")
+
+	t.Run("messages are escaped and broadcast", func(t *testing.T) {
+		resp, _ := playerA.do(t, http.MethodPost, chatPath, url.Values{"message": {"gg <script>alert(1)</script>\nnice"}}, true)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		message := readSSEEvent(t, stream, "chat")
+		assert.Contains(t, message, `<span class="chat-author">🐱</span>`)
+		assert.Contains(t, message, "gg &lt;script&gt;alert(1)&lt;/script&gt; nice")
+		assert.NotContains(t, message, "<script>")
+
+		_, page := playerB.get(t, "/game/"+gameID)
+		assert.Contains(t, page, "gg &lt;script&gt;", "history is shown on the game page")
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		resp, body := playerA.do(t, http.MethodPost, chatPath, url.Values{"message": {"again"}}, true)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Contains(t, body, "too quickly")
+	})
+
+	t.Run("invalid messages", func(t *testing.T) {
+		resp, _ := playerB.do(t, http.MethodPost, chatPath, url.Values{"message": {"   "}}, true)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, _ = playerB.do(t, http.MethodPost, chatPath, url.Values{"message": {strings.Repeat("x", game.MaxChatMessageLength+1)}}, true)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("spectators cannot chat", func(t *testing.T) {
+		spectator := newHTTPPlayer(t, server)
+		resp, _ := spectator.do(t, http.MethodPost, chatPath, url.Values{"message": {"hi"}}, true)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
@@ -0,0 +1,40 @@
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"htmx-go-app/clock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// useFakeClock stops the server's clock for the rest of the test; it only
+// moves when the test advances it. It starts an hour back, so games the test
+// finishes don't turn up as recent results in tests run after it.
+func useFakeClock(t *testing.T) *clock.Fake {
+	fake := clock.NewFake(time.Now().Add(-time.Hour))
+	clock.Use(fake)
+	t.Cleanup(func() { clock.Use(nil) })
+	return fake
+}
+
+func TestFakeClock(t *testing.T) {
+	fake := useFakeClock(t)
+	start := clock.Now()
+
+	var fired []string
+	clock.AfterFunc(2*time.Minute, func() { fired = append(fired, "second") })
+	clock.AfterFunc(time.Minute, func() { fired = append(fired, "first") })
+	stopped := clock.AfterFunc(time.Minute, func() { fired = append(fired, "stopped") })
+	assert.True(t, stopped.Stop())
+
+	fake.Advance(59 * time.Second)
+	assert.Empty(t, fired, "nothing is due yet")
+
+	fake.Advance(2 * time.Minute)
+	assert.Equal(t, []string{"first", "second"}, fired, "due timers run in order")
+	assert.Equal(t, 0, fake.Pending())
+	assert.Equal(t, start.Add(179*time.Second), clock.Now())
+	assert.False(t, stopped.Stop(), "a stopped timer stays stopped")
+}
@@ -54,62 +54,62 @@ func TestCompleteGameFlow(t *testing.T) {
 
 		// Player A move 1: (0,0)
 		t.Log("Player A move 1: (0,0)")
-		err = userAPage.Locator(".game-cell").Nth(0).Click()
+		err = userAPage.Locator("[data-testid=cell-0-0]").Click()
 		require.NoError(t, err)
 		time.Sleep(200 * time.Millisecond)
 
-		cell1, _ := userAPage.Locator(".game-cell").Nth(0).TextContent()
+		cell1, _ := userAPage.Locator("[data-testid=cell-0-0]").TextContent()
 		t.Logf("Cell (0,0): '%s'", cell1)
 		assert.Equal(t, "🐱", cell1, "Player A should place emoji in (0,0)")
 
 		// Player B move 1: (1,0)
 		t.Log("Player B move 1: (1,0)")
 		time.Sleep(200 * time.Millisecond)
-		err = userBPage.Locator(".game-cell").Nth(3).Click()
+		err = userBPage.Locator("[data-testid=cell-1-0]").Click()
 		require.NoError(t, err)
 		time.Sleep(200 * time.Millisecond)
 
-		cell2, _ := userBPage.Locator(".game-cell").Nth(3).TextContent()
+		cell2, _ := userBPage.Locator("[data-testid=cell-1-0]").TextContent()
 		t.Logf("Cell (1,0): '%s'", cell2)
 		assert.Equal(t, "🚀", cell2, "Player B should place emoji in (1,0)")
 
 		// Player A move 2: (0,1)
 		t.Log("Player A move 2: (0,1)")
 		time.Sleep(200 * time.Millisecond)
-		err = userAPage.Locator(".game-cell").Nth(1).Click()
+		err = userAPage.Locator("[data-testid=cell-0-1]").Click()
 		require.NoError(t, err)
 		time.Sleep(200 * time.Millisecond)
 
-		cell3, _ := userAPage.Locator(".game-cell").Nth(1).TextContent()
+		cell3, _ := userAPage.Locator("[data-testid=cell-0-1]").TextContent()
 		t.Logf("Cell (0,1): '%s'", cell3)
 		assert.Equal(t, "🐱", cell3, "Player A should place emoji in (0,1)")
 
 		// Player B move 2: (1,1)
 		t.Log("Player B move 2: (1,1)")
 		time.Sleep(200 * time.Millisecond)
-		err = userBPage.Locator(".game-cell").Nth(4).Click()
+		err = userBPage.Locator("[data-testid=cell-1-1]").Click()
 		require.NoError(t, err)
 		time.Sleep(200 * time.Millisecond)
 
-		cell4, _ := userBPage.Locator(".game-cell").Nth(4).TextContent()
+		cell4, _ := userBPage.Locator("[data-testid=cell-1-1]").TextContent()
 		t.Logf("Cell (1,1): '%s'", cell4)
 		assert.Equal(t, "🚀", cell4, "Player B should place emoji in (1,1)")
 
 		// Player A WINNING move: (0,2)
 		t.Log("Player A WINNING move: (0,2)")
 		time.Sleep(200 * time.Millisecond)
-		err = userAPage.Locator(".game-cell").Nth(2).Click()
+		err = userAPage.Locator("[data-testid=cell-0-2]").Click()
 		require.NoError(t, err)
 		time.Sleep(1000 * time.Millisecond) // Give time for winner detection
 
-		cell5, _ := userAPage.Locator(".game-cell").Nth(2).TextContent()
+		cell5, _ := userAPage.Locator("[data-testid=cell-0-2]").TextContent()
 		t.Logf("Cell (0,2): '%s'", cell5)
 		assert.Equal(t, "🐱", cell5, "Player A should place winning emoji in (0,2)")
 
 		// Check for winner announcement
-		gameResultVisible, err := userAPage.Locator(".game-result").IsVisible()
+		gameResultVisible, err := userAPage.Locator("[data-testid=game-result]").IsVisible()
 		if err == nil && gameResultVisible {
-			gameResult, _ := userAPage.Locator(".game-result").TextContent()
+			gameResult, _ := userAPage.Locator("[data-testid=game-result]").TextContent()
 			t.Logf("Game result: %s", gameResult)
 			
 			if gameResult != "" {
@@ -123,11 +123,11 @@ func TestCompleteGameFlow(t *testing.T) {
 
 		// Test that no more moves are allowed
 		t.Log("Testing that no more moves are allowed...")
-		err = userBPage.Locator(".game-cell").Nth(5).Click()
+		err = userBPage.Locator("[data-testid=cell-1-2]").Click()
 		require.NoError(t, err)
 		time.Sleep(500 * time.Millisecond)
 
-		cell6, _ := userBPage.Locator(".game-cell").Nth(5).TextContent()
+		cell6, _ := userBPage.Locator("[data-testid=cell-1-2]").TextContent()
 		t.Logf("Cell (1,2) after game over: '%s'", cell6)
 		
 		if cell6 == "" {
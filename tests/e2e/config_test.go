@@ -0,0 +1,118 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"htmx-go-app/config"
+	"htmx-go-app/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, ":8080", cfg.Addr)
+	assert.Equal(t, "memory", cfg.StoreBackend)
+	assert.Equal(t, 15*time.Second, cfg.HeartbeatInterval)
+	assert.Equal(t, 30*time.Minute, cfg.InviteTTL)
+	assert.Zero(t, cfg.AbandonAfter, "idle games are never forfeited unless configured")
+	assert.Equal(t, "local", cfg.EventBus)
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+addr: ":9000"
+base_url: "https://ttt.example.com"
+turn_reminder_delay: 1m
+chat_cooldown: 5s
+move_rate_limit: 60/30s
+webhook_urls:
+  - https://hooks.example.com/a
+`), 0o600))
+
+	t.Setenv("CONFIG_FILE", file)
+	t.Setenv("TURN_REMINDER_DELAY", "45s")
+	t.Setenv("COOKIE_SECURE", "true")
+
+	cfg, err := config.Load([]string{"-addr", ":9100"})
+	require.NoError(t, err)
+
+	assert.Equal(t, ":9100", cfg.Addr, "flag beats file")
+	assert.Equal(t, 45*time.Second, cfg.TurnReminderDelay, "env beats file")
+	assert.Equal(t, 5*time.Second, cfg.ChatCooldown, "file beats default")
+	assert.Equal(t, "https://ttt.example.com", cfg.BaseURL)
+	assert.Equal(t, []string{"https://hooks.example.com/a"}, cfg.WebhookURLs)
+	assert.True(t, cfg.CookieSecure)
+	assert.Equal(t, ratelimit.Limit{Burst: 60, Per: 30 * time.Second}, cfg.MoveRateLimit)
+}
+
+func TestConfigRejectsInvalidValues(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	_, err := config.Load([]string{"-heartbeat-interval", "soon"})
+	assert.Error(t, err)
+
+	_, err = config.Load([]string{"-store", "postgres"})
+	assert.Error(t, err)
+
+	t.Setenv("EVENT_BUS", "kafka")
+	_, err = config.Load(nil)
+	assert.Error(t, err)
+}
+
+func TestConfigTLS(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	assert.False(t, cfg.TLS())
+
+	cfg, err = config.Load([]string{"-tls-cert", "cert.pem", "-tls-key", "key.pem"})
+	require.NoError(t, err)
+	assert.True(t, cfg.TLS())
+
+	cfg, err = config.Load([]string{"-autocert-domains", "ttt.example.com, www.ttt.example.com"})
+	require.NoError(t, err)
+	assert.True(t, cfg.TLS())
+	assert.Equal(t, []string{"ttt.example.com", "www.ttt.example.com"}, cfg.AutocertDomains)
+
+	_, err = config.Load([]string{"-tls-cert", "cert.pem"})
+	assert.Error(t, err, "certificate without key")
+
+	_, err = config.Load([]string{"-tls-cert", "cert.pem", "-tls-key", "key.pem", "-autocert-domains", "ttt.example.com"})
+	assert.Error(t, err, "both certificate files and autocert")
+}
+
+func TestConfigTrustedProxies(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.TrustedProxies, "no proxy is trusted by default")
+
+	cfg, err = config.Load([]string{"-trusted-proxies", "10.0.0.0/8, 192.168.1.2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.2"}, cfg.TrustedProxies)
+
+	_, err = config.Load([]string{"-trusted-proxies", "proxy.example.com"})
+	assert.Error(t, err, "hosts aren't resolved")
+}
+
+func TestConfigBasePathIsNormalized(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	for input, want := range map[string]string{"": "", "/": "", "tictactoe": "/tictactoe", "/tictactoe/": "/tictactoe"} {
+		cfg, err := config.Load([]string{"-base-path", input})
+		require.NoError(t, err)
+		assert.Equal(t, want, cfg.BasePath, "input %q", input)
+	}
+}
@@ -0,0 +1,87 @@
+package e2e
+
+import (
+	"bufio"
+	"net/http"
+	"testing"
+
+	"htmx-go-app/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readRejection reads the retry hint and connection_rejected event sent to
+// a stream over a connection ceiling
+func readRejection(t *testing.T, reader *bufio.Reader) string {
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "retry: 30000\n", line, "browsers are told to back off")
+	return readSSEEvent(t, reader, "connection_rejected")
+}
+
+func TestStreamConnectionLimits(t *testing.T) {
+	t.Run("Per game", func(t *testing.T) {
+		events.MaxConnectionsPerGame.Set(2)
+		t.Cleanup(func() { events.MaxConnectionsPerGame.Set(0) })
+
+		server := newServerFromIP(t, "203.0.113.20")
+		gameID, playerA, playerB := startHTTPGame(t, server)
+
+		readSSEEvent(t, openSSEStream(t, playerA, "/api/game/"+gameID+"/events"), "initial")
+		readSSEEvent(t, openSSEStream(t, playerB, "/api/game/"+gameID+"/events"), "initial")
+
+		spectator := newHTTPPlayer(t, server)
+		data := readRejection(t, openSSEStream(t, spectator, "/api/game/"+gameID+"/events"))
+		assert.Contains(t, data, "Too many people are connected to this game")
+		assert.Contains(t, data, `data-status="429"`)
+
+		data = readRejection(t, openSSEStream(t, spectator, "/api/v1/game/"+gameID+"/events"))
+		assert.Contains(t, data, `"type":"connection_rejected"`)
+
+		// Other games and the lobby are unaffected
+		otherID, otherA, _ := startHTTPGame(t, server)
+		readSSEEvent(t, openSSEStream(t, otherA, "/api/game/"+otherID+"/events"), "initial")
+		readSSEEvent(t, openSSEStream(t, spectator, "/api/lobby/events"), "lobby_update")
+	})
+
+	t.Run("Per IP", func(t *testing.T) {
+		events.MaxConnectionsPerIP.Set(1)
+		t.Cleanup(func() { events.MaxConnectionsPerIP.Set(0) })
+
+		server := newServerFromIP(t, "203.0.113.21")
+		visitor := newHTTPPlayer(t, server)
+
+		readSSEEvent(t, openSSEStream(t, visitor, "/api/lobby/events"), "lobby_update")
+		data := readRejection(t, openSSEStream(t, visitor, "/api/lobby/events"))
+		assert.Contains(t, data, "Too many open connections from your address")
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/matchmaking/events", nil)
+		require.NoError(t, err)
+		resp, err := visitor.client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data = readRejection(t, bufio.NewReader(resp.Body))
+		assert.Contains(t, data, "Too many open connections from your address")
+	})
+}
+
+func TestAcquireConnectionRelease(t *testing.T) {
+	events.MaxConnectionsPerIP.Set(1)
+	t.Cleanup(func() { events.MaxConnectionsPerIP.Set(0) })
+
+	release, err := events.AcquireConnection("198.51.100.1", "game1")
+	require.NoError(t, err)
+
+	_, err = events.AcquireConnection("198.51.100.1", "game2")
+	assert.ErrorIs(t, err, events.ErrTooManyConnectionsFromIP)
+
+	release()
+	release() // releasing twice must not free a second slot
+
+	release, err = events.AcquireConnection("198.51.100.1", "game2")
+	require.NoError(t, err)
+	_, err = events.AcquireConnection("198.51.100.1", "game3")
+	assert.ErrorIs(t, err, events.ErrTooManyConnectionsFromIP)
+	release()
+}
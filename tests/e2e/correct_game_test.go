@@ -0,0 +1,113 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"htmx-go-app/audit"
+	"htmx-go-app/game"
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func correctGame(t *testing.T, server *httptest.Server, gameID string, correction interface{}) (*http.Response, string) {
+	data, err := json.Marshal(correction)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPatch, server.URL+"/api/admin/games/"+gameID, bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", testAdminKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestAdminCorrectGame(t *testing.T) {
+	handlers.AdminAPIKey.Set(testAdminKey)
+	t.Cleanup(func() { handlers.AdminAPIKey.Set("") })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	t.Run("Clearing a winning cell reopens the game", func(t *testing.T) {
+		gameID, playerA, playerB := startHTTPGame(t, server)
+		playMoves(t, gameID, playerA, playerB, "0/0", "1/0", "0/1", "1/1", "0/2")
+		stream := openSSEStream(t, playerB, "/api/v1/game/"+gameID+"/events")
+		readSSEEvent(t, stream, "initial")
+
+		resp, body := correctGame(t, server, gameID, map[string]interface{}{
+			"clearCell": map[string]int{"row": 0, "col": 2},
+			"turn":      1,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		corrected := decodeAPIGame(t, body)
+		assert.Equal(t, "active", corrected.Status)
+		assert.Empty(t, corrected.Winner)
+		assert.Equal(t, 4, corrected.MoveCount)
+		assert.Equal(t, "🚀", corrected.CurrentTurn)
+
+		var event eventMessageResponse
+		require.NoError(t, json.Unmarshal([]byte(readSSEEvent(t, stream, "game_corrected")), &event))
+		require.NotNil(t, event.Game)
+		assert.Empty(t, event.Game.Board[0][2])
+		assert.True(t, event.Game.You.YourTurn)
+
+		resp, _ = playerB.htmxPost(t, "/api/game/"+gameID+"/move/2/2")
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "B plays the turn handed over")
+
+		entries := auditEntries(t, server, "?game="+gameID)
+		require.NotEmpty(t, entries)
+		assert.Equal(t, audit.ActionGameCorrected, entries[0].Action)
+		assert.Equal(t, audit.ActorAdmin, entries[0].Actor)
+		assert.Equal(t, "cleared 0/2, turn 1", entries[0].Detail)
+	})
+
+	t.Run("The result can be settled", func(t *testing.T) {
+		gameID, playerA, playerB := startHTTPGame(t, server)
+		playMoves(t, gameID, playerA, playerB, "0/0")
+		playerBID := game.GetGame(gameID).PlayerOrder[1]
+
+		resp, body := correctGame(t, server, gameID, map[string]string{"winner": playerBID})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		corrected := decodeAPIGame(t, body)
+		assert.Equal(t, "finished", corrected.Status)
+		assert.Equal(t, "🚀", corrected.Winner)
+
+		resp, body = correctGame(t, server, gameID, map[string]string{"winner": ""})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.Equal(t, "draw", decodeAPIGame(t, body).Status)
+	})
+
+	t.Run("Invalid corrections change nothing", func(t *testing.T) {
+		gameID, playerA, playerB := startHTTPGame(t, server)
+		playMoves(t, gameID, playerA, playerB, "1/1")
+
+		for name, correction := range map[string]interface{}{
+			"empty cell": map[string]interface{}{"clearCell": map[string]int{"row": 0, "col": 0}, "turn": 0},
+			"off board":  map[string]interface{}{"clearCell": map[string]int{"row": 3, "col": 0}},
+			"bad seat":   map[string]int{"turn": 2},
+			"stranger":   map[string]string{"winner": "nobody"},
+			"no change":  map[string]string{},
+		} {
+			resp, body := correctGame(t, server, gameID, correction)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, name+": "+body)
+		}
+		gameData := game.GetGame(gameID)
+		assert.Equal(t, 1, gameData.MoveCount)
+		assert.Equal(t, 1, gameData.CurrentTurn)
+
+		resp, _ := correctGame(t, server, "missing", map[string]int{"turn": 0})
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,79 @@
+package e2e
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/webhooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrespondenceGame(t *testing.T) {
+	fake := useFakeClock(t)
+	previous := game.AbandonAfter.Get()
+	game.AbandonAfter.Set(10 * time.Minute)
+	t.Cleanup(func() { game.AbandonAfter.Set(previous) })
+
+	var (
+		mu    sync.Mutex
+		turns []map[string]interface{}
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload webhooks.Payload
+		if json.Unmarshal(body, &payload) != nil || payload.Event != webhooks.EventGameTurn {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		turns = append(turns, payload.Data.(map[string]interface{}))
+	}))
+	t.Cleanup(receiver.Close)
+	webhooks.Configure(webhooks.Config{URLs: []string{receiver.URL}})
+	t.Cleanup(func() {
+		webhooks.Wait()
+		webhooks.Configure(webhooks.Config{})
+	})
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	playerA := newHTTPPlayer(t, server)
+	playerB := newHTTPPlayer(t, server)
+	resp, _ := playerA.post(t, "/new-game", url.Values{"correspondence": {"true"}})
+	gameID := extractGameID(resp.Request.URL.Path)
+	require.NotEmpty(t, gameID)
+	playerA.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+	_, page := playerB.get(t, "/game/"+gameID+"/select-emoji")
+	assert.Contains(t, page, "📬 correspondence")
+	assert.NotContains(t, page, "idle 10m0s", "correspondence games have no turn timer")
+	playerB.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+
+	playMoves(t, gameID, playerA, playerB, "0/0")
+	webhooks.Wait()
+
+	mu.Lock()
+	require.Len(t, turns, 2, "one notice when the game starts and one after the move")
+	assert.Equal(t, gameID, turns[0]["gameId"])
+	assert.Equal(t, "🐱", turns[0]["playerToMove"])
+	assert.Equal(t, "🚀", turns[1]["playerToMove"])
+	mu.Unlock()
+
+	// The game waits days for the next move
+	fake.Advance(72 * time.Hour)
+	assert.Equal(t, models.GameStatusActive, game.GetGame(gameID).Status)
+
+	resp, body := playerB.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Contains(t, body, "🚀")
+}
@@ -0,0 +1,75 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCues(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	statusPath := "/api/game/" + gameID + "/fragment/status"
+	spectator := newHTTPPlayer(t, server)
+
+	t.Run("The player to move gets the your_turn cue", func(t *testing.T) {
+		_, status := playerA.get(t, statusPath)
+		assert.Contains(t, status, `data-cue="your_turn"`)
+
+		_, status = playerB.get(t, statusPath)
+		assert.NotContains(t, status, "data-cue=")
+		_, status = spectator.get(t, statusPath)
+		assert.NotContains(t, status, "data-cue=")
+	})
+
+	t.Run("The JSON API carries the cue", func(t *testing.T) {
+		resp, body := playerA.get(t, "/api/v1/game/"+gameID)
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		var view struct {
+			You struct {
+				Cue string `json:"cue"`
+			} `json:"you"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &view))
+		assert.Equal(t, "your_turn", view.You.Cue)
+	})
+
+	t.Run("Muting is remembered and marked on the status", func(t *testing.T) {
+		resp, body := playerB.htmxPost(t, "/api/preferences/cues")
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.Contains(t, body, "Sounds Off")
+		assert.Contains(t, body, `aria-pressed="true"`)
+
+		_, page := playerB.get(t, "/game/"+gameID)
+		assert.Contains(t, page, "Sounds Off")
+	})
+
+	t.Run("A finished game cues a win, a loss and nothing for spectators", func(t *testing.T) {
+		playMoves(t, gameID, playerA, playerB, "0/0", "1/0", "0/1", "1/1", "0/2")
+
+		_, status := playerA.get(t, statusPath)
+		assert.Contains(t, status, `data-cue="win"`)
+		assert.NotContains(t, status, "data-cue-muted")
+
+		_, status = playerB.get(t, statusPath)
+		assert.Contains(t, status, `data-cue="lose"`)
+		assert.Contains(t, status, `data-cue-muted="true"`)
+
+		_, status = spectator.get(t, statusPath)
+		assert.NotContains(t, status, "data-cue=")
+	})
+
+	t.Run("Unmuting turns the cues back on", func(t *testing.T) {
+		_, body := playerB.htmxPost(t, "/api/preferences/cues")
+		assert.Contains(t, body, "Sounds On")
+
+		_, status := playerB.get(t, statusPath)
+		assert.NotContains(t, status, "data-cue-muted")
+	})
+}
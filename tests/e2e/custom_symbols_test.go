@@ -0,0 +1,102 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"htmx-go-app/emojis"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/tttest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSymbol(t *testing.T) {
+	for _, symbol := range []string{"A", "Ω", "\u00e9", "e\u0301", "👍🏽", "❤\ufe0f", "🇨🇭", "👨\u200d👩\u200d👧", "🏴\U000e0067\U000e0062\U000e0073\U000e0063\U000e0074\U000e007f"} {
+		assert.NoError(t, emojis.CheckSymbol(symbol), "%q is one character", symbol)
+	}
+	for _, symbol := range []string{"", " ", "ab", "🐱🚀", "\u0301", "\u200b", "🇨🇭🇩", "👨\u200d", "<", "&", `"`} {
+		assert.Error(t, emojis.CheckSymbol(symbol), "%q is not a usable symbol", symbol)
+	}
+}
+
+func TestCustomSymbols(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	newGame := func(t *testing.T) (string, *httpPlayer) {
+		creator := newHTTPPlayer(t, server)
+		resp, _ := creator.get(t, "/new-game")
+		gameID := extractGameID(resp.Request.URL.Path)
+		require.NotEmpty(t, gameID)
+		return gameID, creator
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		gameID, creator := newGame(t)
+		_, page := creator.get(t, "/game/"+gameID+"/select-emoji")
+		assert.NotContains(t, page, `data-testid="custom-symbol"`)
+
+		resp, _ := creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"symbol": {"A"}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	emojis.AllowCustom(true)
+	t.Cleanup(func() { emojis.AllowCustom(false) })
+
+	t.Run("typed symbols play like emojis", func(t *testing.T) {
+		gameID, playerA := newGame(t)
+		playerB := newHTTPPlayer(t, server)
+		_, page := playerA.get(t, "/game/"+gameID+"/select-emoji")
+		assert.Contains(t, page, `data-testid="custom-symbol"`)
+
+		playerA.post(t, "/game/"+gameID+"/select-emoji", url.Values{"symbol": {"é"}})
+		resp, body := playerB.post(t, "/game/"+gameID+"/select-emoji", url.Values{"symbol": {"é"}})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode, "the same letter typed another way is taken")
+		resp, body = playerB.post(t, "/game/"+gameID+"/select-emoji", url.Values{"symbol": {"ab"}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, body, "single character")
+		resp, _ = playerB.post(t, "/game/"+gameID+"/select-emoji", url.Values{"symbol": {"X"}})
+		require.Equal(t, "/game/"+gameID, resp.Request.URL.Path)
+
+		gameData := game.GetGame(gameID)
+		require.NotNil(t, gameData)
+		assert.Equal(t, "é", gameData.Players[gameData.PlayerOrder[0]].Emoji, "stored composed")
+
+		playMoves(t, gameID, playerA, playerB, "0/0", "1/1")
+		assert.Equal(t, models.GameBoard{{"X", "", ""}, {"", "O", ""}, {"", "", ""}}, gameData.Board,
+			"the board holds seats, so a player called X in seat O is no trouble")
+
+		_, board := playerA.get(t, "/api/game/"+gameID+"/fragment/board")
+		assert.Contains(t, board, `aria-label="row 1 column 1, é"`)
+		assert.Contains(t, board, `aria-label="row 2 column 2, X"`)
+
+		_, body = playerA.get(t, "/api/v1/game/"+gameID)
+		state := decodeAPIGame(t, body)
+		assert.Equal(t, "é", state.Board[0][0], "the API shows symbols")
+		assert.Equal(t, "X", state.Board[1][1])
+	})
+}
+
+func TestRestoreEmojiBoards(t *testing.T) {
+	gameData, a, b := tttest.StartGame(t)
+	tttest.Play(t, gameData, "0/0", "1/1")
+
+	backup := game.ExportBackup()
+	backup.Version = 1
+	for _, saved := range backup.Games {
+		if saved.ID == gameData.ID {
+			saved.Board = models.GameBoard{{gameData.Players[a].Emoji, "", ""}, {"", gameData.Players[b].Emoji, ""}, {"", "", ""}}
+		}
+	}
+	_, err := game.RestoreBackup(backup)
+	require.NoError(t, err)
+
+	restored := game.GetGame(gameData.ID)
+	require.NotNil(t, restored)
+	assert.Equal(t, models.GameBoard{{"X", "", ""}, {"", "O", ""}, {"", "", ""}}, restored.Board, "emoji boards are rewritten to seats")
+}
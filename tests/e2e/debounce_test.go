@@ -0,0 +1,77 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"htmx-go-app/engine"
+	"htmx-go-app/game"
+	"htmx-go-app/tttest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveDebouncing(t *testing.T) {
+	fake := useFakeClock(t)
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	move := map[string]int{"row": 1, "col": 1}
+
+	t.Run("Simultaneous duplicates collapse into one move", func(t *testing.T) {
+		var wg sync.WaitGroup
+		results := make([]apiGameResponse, 2)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				resp, body := playerA.postJSON(t, "/api/v1/game/"+gameID+"/move", move)
+				assert.Equal(t, http.StatusOK, resp.StatusCode, body)
+				results[i] = decodeAPIGame(t, body)
+			}(i)
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			assert.Equal(t, 1, result.MoveCount, "both get the result of the one move")
+			assert.Equal(t, "🐱", result.Board[1][1])
+		}
+	})
+
+	t.Run("A late repeat is rejected", func(t *testing.T) {
+		fake.Advance(time.Second)
+		resp, _ := playerA.postJSON(t, "/api/v1/game/"+gameID+"/move", move)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("A repeat by the other player is not a duplicate", func(t *testing.T) {
+		resp, body := playerB.postJSON(t, "/api/v1/game/"+gameID+"/move", map[string]int{"row": 0, "col": 0})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+		resp, _ = playerA.postJSON(t, "/api/v1/game/"+gameID+"/move", map[string]int{"row": 0, "col": 0})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode, "the cell is taken")
+	})
+}
+
+func TestMoveDebounceFollowsVersion(t *testing.T) {
+	useFakeClock(t)
+	g, playerA, playerB := tttest.StartGame(t)
+
+	_, err := game.MakeMoveOnce(g, playerA, 1, 1)
+	require.NoError(t, err)
+	_, err = game.MakeMoveOnce(g, playerB, 0, 0)
+	require.NoError(t, err)
+
+	cleared, turn := engine.Cell{Row: 1, Col: 1}, 0
+	require.NoError(t, game.CorrectGame(g, game.Correction{ClearCell: &cleared, Turn: &turn}))
+
+	duplicate, err := game.MakeMoveOnce(g, playerA, 1, 1)
+	require.NoError(t, err)
+	assert.False(t, duplicate, "the game changed since, so the same move again is a new one")
+	assert.Equal(t, 2, g.MoveCount)
+}
@@ -50,14 +50,14 @@ func TestDebugMove(t *testing.T) {
 
 		// Try to make a move with Player A
 		t.Log("Player A attempting to click first cell...")
-		err = userAPage.Locator(".game-cell").First().Click()
+		err = userAPage.Locator("[data-testid=cell-0-0]").Click()
 		require.NoError(t, err)
 
 		// Wait to see what happens
 		time.Sleep(2000 * time.Millisecond)
 
 		// Check if move was successful
-		firstCellContent, err := userAPage.Locator(".game-cell").First().TextContent()
+		firstCellContent, err := userAPage.Locator("[data-testid=cell-0-0]").TextContent()
 		require.NoError(t, err)
 
 		t.Logf("First cell content after move: '%s'", firstCellContent)
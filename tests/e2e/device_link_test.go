@@ -0,0 +1,75 @@
+package e2e
+
+import (
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"htmx-go-app/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var deviceLinkPattern = regexp.MustCompile(`value="[^"]*(/continue/[^"]+)"`)
+
+// createDeviceLink asks for a device link as player and returns its path
+func createDeviceLink(t *testing.T, player *httpPlayer, gameID string) string {
+	resp, body := player.htmxPost(t, "/api/game/"+gameID+"/device-link")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	match := deviceLinkPattern.FindStringSubmatch(body)
+	require.NotNil(t, match, body)
+	return html.UnescapeString(match[1])
+}
+
+func TestContinueOnAnotherDevice(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	_, page := playerA.get(t, "/game/"+gameID)
+	assert.Contains(t, page, `data-testid="continue-elsewhere"`)
+
+	link := createDeviceLink(t, playerA, gameID)
+	phone := newHTTPPlayer(t, server)
+	resp, page := phone.get(t, link)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/game/"+gameID, resp.Request.URL.Path)
+	assert.Contains(t, page, "Your turn!")
+
+	// Both of A's browsers follow the game, and either can move
+	laptopStream := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+	readSSEEvent(t, laptopStream, "initial")
+	resp, _ = phone.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, readSSEEvent(t, laptopStream, "move"), "🐱")
+
+	playMoves(t, gameID, playerB, playerA, "0/0")
+	resp, _ = playerA.htmxPost(t, "/api/game/"+gameID+"/move/2/2")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, game.GetGame(gameID).MoveCount)
+
+	t.Run("Links are single use", func(t *testing.T) {
+		resp, _ := newHTTPPlayer(t, server).get(t, link)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Links expire", func(t *testing.T) {
+		fake := useFakeClock(t)
+		link := createDeviceLink(t, playerB, gameID)
+		fake.Advance(game.DeviceLinkTTL + time.Second)
+
+		resp, _ := newHTTPPlayer(t, server).get(t, link)
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+	})
+
+	t.Run("Only players get links", func(t *testing.T) {
+		resp, body := newHTTPPlayer(t, server).htmxPost(t, "/api/game/"+gameID+"/device-link")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.False(t, strings.Contains(body, "/continue/"))
+	})
+}
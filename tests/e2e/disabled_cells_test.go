@@ -0,0 +1,36 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisabledCellsReflectTurnState(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+
+	// Player A moves first, so only their board is clickable
+	_, pageA := playerA.get(t, "/game/"+gameID)
+	_, pageB := playerB.get(t, "/game/"+gameID)
+	assert.Equal(t, 9, strings.Count(pageA, "hx-post=\"/api/game/"+gameID+"/move/"))
+	assert.Equal(t, 0, strings.Count(pageB, "hx-post=\"/api/game/"+gameID+"/move/"))
+	assert.Equal(t, 9, strings.Count(pageB, `class="game-cell disabled"`))
+	assert.Equal(t, 9, strings.Count(pageB, `aria-disabled="true"`))
+
+	// After moving, player A's returned board is fully disabled
+	resp, board := playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotContains(t, board, "hx-post")
+	assert.Contains(t, board, `aria-label="row 1 column 1, 🐱" aria-disabled="true">🐱</div>`)
+
+	// Player B can now play every cell except the occupied one
+	_, pageB = playerB.get(t, "/game/"+gameID)
+	assert.Equal(t, 8, strings.Count(pageB, "hx-post=\"/api/game/"+gameID+"/move/"))
+	assert.NotContains(t, pageB, "/move/0/0")
+}
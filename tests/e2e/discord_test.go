@@ -0,0 +1,95 @@
+package e2e
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"htmx-go-app/handlers"
+	"htmx-go-app/notify"
+	"htmx-go-app/webhooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Image       *struct {
+		URL string `json:"url"`
+	} `json:"image"`
+}
+
+func TestDiscordNotifications(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		embeds []discordEmbed
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var message struct {
+			Embeds []discordEmbed `json:"embeds"`
+		}
+		if err := json.Unmarshal(body, &message); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		embeds = append(embeds, message.Embeds...)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(receiver.Close)
+
+	// Discord webhook URLs carry their token in the path
+	webhookURL := receiver.URL + "/api/webhooks/1234/s3cret-token"
+	notify.Configure(notify.Config{DiscordWebhookURL: webhookURL})
+	t.Cleanup(func() { notify.Configure(notify.Config{}) })
+	handlers.PublicBaseURL = "https://ttt.example"
+	t.Cleanup(func() { handlers.PublicBaseURL = "" })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	playMoves(t, gameID, playerA, playerB, "0/0", "1/0", "0/1", "1/1", "0/2")
+	webhooks.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var started, finished *discordEmbed
+	for i, embed := range embeds {
+		switch {
+		case embed.URL == "https://ttt.example/game/"+gameID:
+			started = &embeds[i]
+		case embed.URL == "https://ttt.example/game/"+gameID+"/result":
+			finished = &embeds[i]
+		}
+	}
+
+	require.NotNil(t, started, "game start is posted")
+	assert.Equal(t, "Game started", started.Title)
+	assert.Equal(t, "🐱 vs 🚀", started.Description)
+	assert.Nil(t, started.Image)
+
+	require.NotNil(t, finished, "result is posted")
+	assert.Equal(t, "🐱 wins!", finished.Title)
+	assert.True(t, strings.HasPrefix(finished.Description, "🐱 vs 🚀 · 5 moves in"), finished.Description)
+	require.NotNil(t, finished.Image)
+	assert.Equal(t, "https://ttt.example/api/game/"+gameID+"/board.png", finished.Image.URL)
+
+	logged := false
+	for _, delivery := range webhooks.Deliveries() {
+		logged = logged || delivery.Target == receiver.URL
+		assert.NotContains(t, delivery.Target+delivery.Error, "s3cret", "the token stays out of the delivery log")
+	}
+	assert.True(t, logged, "posts are logged under the Discord host")
+}
@@ -90,7 +90,7 @@ func TestEmojiSelection(t *testing.T) {
 		userAPage.WaitForURL("**/game/**/select-emoji")
 		
 		// User A selects first emoji (🐱)
-		err = userAPage.Click(".emoji-option:nth-child(1)")
+		err = userAPage.Click("[data-testid='emoji-option-🐱']")
 		require.NoError(t, err)
 
 		// User A should stay on emoji selection page in waiting state
@@ -108,7 +108,7 @@ func TestEmojiSelection(t *testing.T) {
 		assert.Contains(t, waitingMessage, "Waiting for opponent", "Should show waiting message")
 
 		// Get game URL for User B
-		gameURL, err := userAPage.Locator(".url-input").GetAttribute("value")
+		gameURL, err := userAPage.Locator("[data-testid=game-url]").GetAttribute("value")
 		require.NoError(t, err)
 		require.NotEmpty(t, gameURL)
 
@@ -126,12 +126,12 @@ func TestEmojiSelection(t *testing.T) {
 		assert.False(t, shareVisible, "User B should not see game sharing section")
 
 		// First emoji should be disabled/unavailable for User B
-		firstEmojiDisabled, err := userBPage.Locator(".emoji-option:nth-child(1)").GetAttribute("disabled")
+		firstEmojiDisabled, err := userBPage.Locator("[data-testid='emoji-option-🐱']").GetAttribute("disabled")
 		require.NoError(t, err)
 		assert.NotNil(t, firstEmojiDisabled, "First emoji should be disabled for User B")
 
 		// User B selects second emoji (🚀)
-		err = userBPage.Click(".emoji-option:nth-child(2)")
+		err = userBPage.Click("[data-testid='emoji-option-🚀']")
 		require.NoError(t, err)
 
 		// Both users should be redirected to game page simultaneously
@@ -144,12 +144,12 @@ func TestEmojiSelection(t *testing.T) {
 		assert.Equal(t, gameIDFromA, gameIDFromB, "Both players should be in the same game")
 
 		// Verify both players see both emojis in player indicator
-		userAIndicator, err := userAPage.Locator(".players-display").TextContent()
+		userAIndicator, err := userAPage.Locator("[data-testid=game-players]").TextContent()
 		require.NoError(t, err)
 		assert.Contains(t, userAIndicator, "🐱", "User A should see their emoji")
 		assert.Contains(t, userAIndicator, "🚀", "User A should see User B's emoji")
 
-		userBIndicator, err := userBPage.Locator(".players-display").TextContent()
+		userBIndicator, err := userBPage.Locator("[data-testid=game-players]").TextContent()
 		require.NoError(t, err)
 		assert.Contains(t, userBIndicator, "🐱", "User B should see User A's emoji")
 		assert.Contains(t, userBIndicator, "🚀", "User B should see their emoji")
@@ -184,7 +184,7 @@ func TestEmojiSelection(t *testing.T) {
 		require.NoError(t, err)
 
 		userAPage.WaitForURL("**/select-emoji")
-		err = userAPage.Click(".emoji-option:nth-child(1)") // 🐱
+		err = userAPage.Click("[data-testid='emoji-option-🐱']") // 🐱
 		require.NoError(t, err)
 
 		userAPage.WaitForURL("**/game/**")
@@ -194,46 +194,46 @@ func TestEmojiSelection(t *testing.T) {
 		require.NoError(t, err)
 
 		userBPage.WaitForURL("**/select-emoji")
-		err = userBPage.Click(".emoji-option:nth-child(2)") // 🚀
+		err = userBPage.Click("[data-testid='emoji-option-🚀']") // 🚀
 		require.NoError(t, err)
 
 		userBPage.WaitForURL("**/game/**")
 
 		// User A makes first move
 		t.Log("User A making move...")
-		err = userAPage.Locator(".game-cell").First().Click()
+		err = userAPage.Locator("[data-testid=cell-0-0]").Click()
 		require.NoError(t, err)
 
 		// Wait for move to be processed
-		_, err = userAPage.WaitForFunction(`document.querySelector('.game-cell').textContent === '🐱'`, nil)
+		_, err = userAPage.WaitForFunction(`document.querySelector('[data-testid=cell-0-0]').textContent === '🐱'`, nil)
 		require.NoError(t, err)
 
 		// Verify User A sees their emoji in the cell
-		userAFirstCell, err := userAPage.Locator(".game-cell").First().TextContent()
+		userAFirstCell, err := userAPage.Locator("[data-testid=cell-0-0]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, "🐱", userAFirstCell, "User A should see their emoji in the cell")
 
 		// Verify User B also sees User A's emoji (real-time sync)
 		time.Sleep(1 * time.Second) // Give SSE time to sync
-		userBFirstCell, err := userBPage.Locator(".game-cell").First().TextContent()
+		userBFirstCell, err := userBPage.Locator("[data-testid=cell-0-0]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, "🐱", userBFirstCell, "User B should see User A's emoji in the cell")
 
 		// User B makes second move
 		t.Log("User B making move...")
-		err = userBPage.Locator(".game-cell").Nth(4).Click() // Center cell
+		err = userBPage.Locator("[data-testid=cell-1-1]").Click() // Center cell
 		require.NoError(t, err)
 
 		// Wait for move to be processed
-		_, err = userBPage.WaitForFunction(`document.querySelectorAll('.game-cell')[4].textContent === '🚀'`, nil)
+		_, err = userBPage.WaitForFunction(`document.querySelector('[data-testid=cell-1-1]').textContent === '🚀'`, nil)
 		require.NoError(t, err)
 
 		// Verify both players see both emojis in their respective cells
-		userACenterCell, err := userAPage.Locator(".game-cell").Nth(4).TextContent()
+		userACenterCell, err := userAPage.Locator("[data-testid=cell-1-1]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, "🚀", userACenterCell, "User A should see User B's emoji in center cell")
 
-		userBCenterCell, err := userBPage.Locator(".game-cell").Nth(4).TextContent()
+		userBCenterCell, err := userBPage.Locator("[data-testid=cell-1-1]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, "🚀", userBCenterCell, "User B should see their emoji in center cell")
 	})
@@ -262,14 +262,14 @@ func TestEmojiSelection(t *testing.T) {
 		require.NoError(t, err)
 
 		userAPage.WaitForURL("**/select-emoji")
-		err = userAPage.Click(".emoji-option:nth-child(1)") // 🐱
+		err = userAPage.Click("[data-testid='emoji-option-🐱']") // 🐱
 		require.NoError(t, err)
 
 		// User A should be in waiting state
 		userAPage.WaitForSelector(".waiting-state")
 
 		// Get game URL for User B
-		gameURL, err := userAPage.Locator(".url-input").GetAttribute("value")
+		gameURL, err := userAPage.Locator("[data-testid=game-url]").GetAttribute("value")
 		require.NoError(t, err)
 
 		// User B joins and selects emoji to start game
@@ -277,7 +277,7 @@ func TestEmojiSelection(t *testing.T) {
 		require.NoError(t, err)
 
 		userBPage.WaitForURL("**/select-emoji")
-		err = userBPage.Click(".emoji-option:nth-child(2)") // 🚀
+		err = userBPage.Click("[data-testid='emoji-option-🚀']") // 🚀
 		require.NoError(t, err)
 
 		// Both users should enter the game
@@ -285,9 +285,9 @@ func TestEmojiSelection(t *testing.T) {
 		userBPage.WaitForURL("**/game/**")
 
 		// User A makes a move
-		err = userAPage.Locator(".game-cell").First().Click()
+		err = userAPage.Locator("[data-testid=cell-0-0]").Click()
 		require.NoError(t, err)
-		_, err = userAPage.WaitForFunction(`document.querySelector('.game-cell').textContent === '🐱'`, nil)
+		_, err = userAPage.WaitForFunction(`document.querySelector('[data-testid=cell-0-0]').textContent === '🐱'`, nil)
 		require.NoError(t, err)
 
 		// Refresh User A's page
@@ -299,11 +299,11 @@ func TestEmojiSelection(t *testing.T) {
 		assert.NotContains(t, finalURL, "/select-emoji", "Should not redirect to emoji selection after refresh")
 
 		// Should still see the emoji in the cell and player display
-		firstCell, err := userAPage.Locator(".game-cell").First().TextContent()
+		firstCell, err := userAPage.Locator("[data-testid=cell-0-0]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, "🐱", firstCell, "Should persist emoji in game cell after refresh")
 
-		playerDisplay, err := userAPage.Locator(".players-display").TextContent()
+		playerDisplay, err := userAPage.Locator("[data-testid=game-players]").TextContent()
 		require.NoError(t, err)
 		assert.Contains(t, playerDisplay, "🐱", "Should persist emoji in player display after refresh")
 		assert.Contains(t, playerDisplay, "🚀", "Should persist both player emojis after refresh")
@@ -341,11 +341,11 @@ func TestEmojiSelection(t *testing.T) {
 		require.NoError(t, err)
 
 		userAPage.WaitForURL("**/select-emoji")
-		err = userAPage.Click(".emoji-option:nth-child(1)") // 🐱
+		err = userAPage.Click("[data-testid='emoji-option-🐱']") // 🐱
 		require.NoError(t, err)
 
 		// Get game URL
-		gameURL, err := userAPage.Locator(".url-input").GetAttribute("value")
+		gameURL, err := userAPage.Locator("[data-testid=game-url]").GetAttribute("value")
 		require.NoError(t, err)
 
 		// User B joins and selects emoji
@@ -354,7 +354,7 @@ func TestEmojiSelection(t *testing.T) {
 		require.NoError(t, err)
 
 		userBPage.WaitForURL("**/select-emoji")
-		err = userBPage.Click(".emoji-option:nth-child(2)") // 🚀
+		err = userBPage.Click("[data-testid='emoji-option-🚀']") // 🚀
 		require.NoError(t, err)
 
 		// Both should be in game now
@@ -0,0 +1,71 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"htmx-go-app/emojis"
+	"htmx-go-app/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmojiVariants(t *testing.T) {
+	mage := emojis.Variants("🧙")
+	assert.Len(t, mage, 17, "five skin tones, then a woman and a man in each tone")
+	assert.Equal(t, "🧙🏻", mage[0])
+	assert.Contains(t, mage, "🧙\u200d♀\ufe0f")
+	assert.Contains(t, mage, "🧙🏿\u200d♂\ufe0f")
+	assert.Len(t, emojis.Variants("👍"), 5, "hands only come in skin tones")
+	assert.Nil(t, emojis.Variants("🐱"))
+	assert.Nil(t, emojis.Variants("🧙🏽"), "variants have no variants of their own")
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	join := func(t *testing.T, creatorEmoji string) (string, *httpPlayer) {
+		creator := newHTTPPlayer(t, server)
+		resp, _ := creator.get(t, "/new-game")
+		gameID := extractGameID(resp.Request.URL.Path)
+		require.NotEmpty(t, gameID)
+		resp, body := creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {creatorEmoji}})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		return gameID, creator
+	}
+
+	t.Run("picked from the selection page", func(t *testing.T) {
+		gameID, _ := join(t, "🧙🏽")
+		opponent := newHTTPPlayer(t, server)
+		_, page := opponent.get(t, "/game/"+gameID+"/select-emoji")
+		assert.Contains(t, page, `data-testid="emoji-variants-🧙"`)
+		assert.Regexp(t, `data-testid="emoji-option-🧙🏽" disabled`, page, "a taken variant can't be picked")
+		assert.Regexp(t, `data-testid="emoji-option-🧙🏾">`, page)
+
+		resp, body := opponent.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🧙"}})
+		require.Equal(t, "/game/"+gameID, resp.Request.URL.Path, "the plain emoji doesn't collide with a toned one: %s", body)
+	})
+
+	t.Run("spellings are canonical", func(t *testing.T) {
+		gameID, _ := join(t, "🧙\ufe0f")
+		gameData := game.GetGame(gameID)
+		require.NotNil(t, gameData)
+		assert.Equal(t, "🧙", gameData.Players[gameData.PlayerOrder[0]].Emoji, "stored as the catalog spells it")
+
+		opponent := newHTTPPlayer(t, server)
+		resp, _ := opponent.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🧙"}})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		resp, _ = opponent.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🧙\u200d♀"}})
+		require.Equal(t, "/game/"+gameID, resp.Request.URL.Path)
+		assert.Equal(t, "🧙\u200d♀\ufe0f", gameData.Players[gameData.PlayerOrder[1]].Emoji)
+	})
+
+	t.Run("only the catalog's emojis have variants", func(t *testing.T) {
+		gameID, _ := join(t, "🐱")
+		opponent := newHTTPPlayer(t, server)
+		resp, _ := opponent.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"👍🏽"}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
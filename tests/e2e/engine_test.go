@@ -0,0 +1,80 @@
+package e2e
+
+import (
+	"testing"
+
+	"htmx-go-app/engine"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(row, col int) engine.Cell {
+	return engine.Cell{Row: row, Col: col}
+}
+
+// play makes each move in turn, failing the test on a rejected move
+func play(t *testing.T, g *engine.Game, cells ...engine.Cell) {
+	for _, cell := range cells {
+		require.NoError(t, g.Play(cell))
+	}
+}
+
+func TestEngine(t *testing.T) {
+	t.Run("Completing a line wins", func(t *testing.T) {
+		g, err := engine.NewGame("X", "O", engine.Standard)
+		require.NoError(t, err)
+		play(t, g, at(0, 0), at(1, 0), at(0, 1), at(1, 1))
+		assert.Equal(t, engine.InProgress, g.Status())
+		assert.Len(t, g.LegalMoves(), 5)
+
+		play(t, g, at(0, 2))
+		assert.Equal(t, engine.Won, g.Status())
+		assert.Equal(t, 0, g.Winner())
+		mark, line := g.Board.Line()
+		assert.Equal(t, "X", mark)
+		assert.Equal(t, []engine.Cell{at(0, 0), at(0, 1), at(0, 2)}, line)
+
+		assert.ErrorIs(t, g.Play(at(2, 2)), engine.ErrGameOver)
+		assert.Empty(t, g.LegalMoves())
+	})
+
+	t.Run("A full board without a line is a draw", func(t *testing.T) {
+		g, _ := engine.NewGame("X", "O", engine.Standard)
+		play(t, g, at(0, 0), at(0, 1), at(0, 2), at(1, 1), at(1, 0),
+			at(1, 2), at(2, 1), at(2, 0), at(2, 2))
+		assert.Equal(t, engine.Drawn, g.Status())
+		assert.Equal(t, -1, g.Winner())
+	})
+
+	t.Run("In misère the line's owner loses", func(t *testing.T) {
+		g, _ := engine.NewGame("X", "O", engine.Misere)
+		play(t, g, at(0, 0), at(1, 0), at(0, 1), at(1, 1), at(0, 2))
+		assert.Equal(t, engine.Won, g.Status())
+		assert.Equal(t, 1, g.Winner())
+	})
+
+	t.Run("Bad moves are rejected without changing the game", func(t *testing.T) {
+		g, _ := engine.NewGame("X", "O", "")
+		assert.Equal(t, engine.Standard, g.Variant)
+		play(t, g, at(1, 1))
+
+		assert.ErrorIs(t, g.Play(at(1, 1)), engine.ErrCellOccupied)
+		assert.ErrorIs(t, g.Play(at(3, 0)), engine.ErrInvalidCell)
+		assert.Equal(t, 1, g.Turn)
+		assert.Len(t, g.Moves, 1)
+	})
+
+	t.Run("Clones are independent", func(t *testing.T) {
+		g, _ := engine.NewGame("X", "O", engine.Standard)
+		clone := g.Clone()
+		play(t, clone, at(0, 0))
+		assert.Empty(t, g.Moves)
+		assert.Equal(t, "", g.Board.At(at(0, 0)))
+	})
+
+	t.Run("Players need distinct marks", func(t *testing.T) {
+		_, err := engine.NewGame("X", "X", engine.Standard)
+		assert.ErrorIs(t, err, engine.ErrInvalidMarks)
+	})
+}
@@ -0,0 +1,52 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorPages(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	t.Run("Unknown game renders full 404 page", func(t *testing.T) {
+		player := newHTTPPlayer(t, server)
+		resp, body := player.get(t, "/game/doesnotexist")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Contains(t, body, "<!DOCTYPE html>")
+		assert.Contains(t, body, "Game Not Found")
+	})
+
+	t.Run("HTMX request receives an error fragment", func(t *testing.T) {
+		player := newHTTPPlayer(t, server)
+		resp, body := player.htmxPost(t, "/api/game/doesnotexist/reset")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "#error-message", resp.Header.Get("HX-Retarget"))
+		assert.NotContains(t, body, "<!DOCTYPE html>")
+		assert.Contains(t, body, `class="error-fragment"`)
+	})
+
+	t.Run("Outsider move is forbidden", func(t *testing.T) {
+		gameID, _, _ := startHTTPGame(t, server)
+		outsider := newHTTPPlayer(t, server)
+		resp, body := outsider.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, body, "Not Your Game")
+	})
+
+	t.Run("Taken emoji is a conflict", func(t *testing.T) {
+		playerA := newHTTPPlayer(t, server)
+		resp, _ := playerA.get(t, "/new-game")
+		gameID := extractGameID(resp.Request.URL.Path)
+		playerA.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+
+		playerB := newHTTPPlayer(t, server)
+		resp, body := playerB.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Contains(t, body, "Emoji already taken")
+	})
+}
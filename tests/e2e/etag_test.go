@@ -0,0 +1,111 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conditionalGet fetches path as player, sending etag in If-None-Match
+func conditionalGet(t *testing.T, player *httpPlayer, path, etag string) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, player.baseURL+path, nil)
+	require.NoError(t, err)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := player.client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp
+}
+
+func TestConditionalGet(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	gameID, playerA, playerB := startHTTPGame(t, server)
+
+	// Each endpoint is checked across one move, A's then B's
+	endpoints := []struct {
+		path  string
+		mover *httpPlayer
+		move  string
+	}{
+		{"/api/v1/game/" + gameID, playerA, "/0/0"},
+		{"/api/game/" + gameID + "/board.svg", playerB, "/1/0"},
+		{"/api/game/" + gameID + "/fragment/board", playerA, "/2/2"},
+	}
+	for _, endpoint := range endpoints {
+		t.Run(endpoint.path, func(t *testing.T) {
+			resp := conditionalGet(t, playerA, endpoint.path, "")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			etag := resp.Header.Get("ETag")
+			require.NotEmpty(t, etag)
+
+			resp = conditionalGet(t, playerA, endpoint.path, etag)
+			assert.Equal(t, http.StatusNotModified, resp.StatusCode, "nothing changed")
+
+			resp = conditionalGet(t, playerA, endpoint.path, "W/"+etag)
+			assert.Equal(t, http.StatusNotModified, resp.StatusCode, "weak comparison")
+
+			resp, _ = endpoint.mover.htmxPost(t, "/api/game/"+gameID+"/move"+endpoint.move)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			resp = conditionalGet(t, playerA, endpoint.path, etag)
+			assert.Equal(t, http.StatusOK, resp.StatusCode, "a move changes the tag")
+			assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+		})
+	}
+
+	t.Run("Tags are per player", func(t *testing.T) {
+		path := "/api/v1/game/" + gameID
+		etag := conditionalGet(t, playerA, path, "").Header.Get("ETag")
+
+		resp := conditionalGet(t, playerB, path, etag)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Vary"), "Cookie")
+	})
+
+	t.Run("Revalidating renders nothing", func(t *testing.T) {
+		path := "/api/game/" + gameID + "/fragment/status"
+		etag := conditionalGet(t, playerB, path, "").Header.Get("ETag")
+
+		before := scrapeMetrics(t, playerB)
+		resp := conditionalGet(t, playerB, path, etag)
+		require.Equal(t, http.StatusNotModified, resp.StatusCode)
+		after := scrapeMetrics(t, playerB)
+		assert.Equal(t, before["tictactoe_fragment_renders_total"], after["tictactoe_fragment_renders_total"])
+		assert.Equal(t, before["tictactoe_fragment_cache_hits_total"], after["tictactoe_fragment_cache_hits_total"])
+	})
+
+	t.Run("Preferences change the tag", func(t *testing.T) {
+		path := "/api/game/" + gameID + "/fragment/board"
+		etag := conditionalGet(t, playerA, path, "").Header.Get("ETag")
+
+		resp, _ := playerA.htmxPost(t, "/api/preferences/badges")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		t.Cleanup(func() { playerA.htmxPost(t, "/api/preferences/badges") })
+
+		resp = conditionalGet(t, playerA, path, etag)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "the board now shows piece badges")
+	})
+
+	t.Run("Settings change the tag", func(t *testing.T) {
+		creator := newHTTPPlayer(t, server)
+		resp, _ := creator.get(t, "/new-game")
+		waitingID := extractGameID(resp.Request.URL.Path)
+		require.NotEmpty(t, waitingID)
+		creator.post(t, "/game/"+waitingID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+
+		path := "/api/v1/game/" + waitingID
+		etag := conditionalGet(t, creator, path, "").Header.Get("ETag")
+		resp, _ = creator.do(t, http.MethodPost, "/api/game/"+waitingID+"/visibility", url.Values{"visibility": {"private"}}, true)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = conditionalGet(t, creator, path, etag)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "the game went private")
+	})
+}
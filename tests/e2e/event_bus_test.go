@@ -0,0 +1,63 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"htmx-go-app/events"
+	"htmx-go-app/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingBus captures published events in place of a real NATS connection
+type recordingBus struct {
+	mu        sync.Mutex
+	published []models.GameEvent
+}
+
+func (b *recordingBus) Publish(event models.GameEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = append(b.published, event)
+	return nil
+}
+
+func (b *recordingBus) Close() error { return nil }
+
+func (b *recordingBus) eventsFor(gameID string) []models.GameEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var matching []models.GameEvent
+	for _, event := range b.published {
+		if event.GameID == gameID {
+			matching = append(matching, event)
+		}
+	}
+	return matching
+}
+
+func TestEventBusPublishing(t *testing.T) {
+	bus := &recordingBus{}
+	events.UseBus(bus)
+	t.Cleanup(func() { events.UseBus(nil) })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, _ := startHTTPGame(t, server)
+	resp, body := playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	published := bus.eventsFor(gameID)
+	require.GreaterOrEqual(t, len(published), 2)
+
+	// A coalesced move is published as separate, sequenced board and status events
+	move, status := published[len(published)-2], published[len(published)-1]
+	assert.Equal(t, "move", move.Type)
+	assert.Equal(t, "game_status", status.Type)
+	assert.Equal(t, move.ID+1, status.ID)
+}
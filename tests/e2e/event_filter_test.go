@@ -0,0 +1,51 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventTypeFiltering(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	spectator := newHTTPPlayer(t, server)
+
+	movesOnly := openSSEStream(t, spectator, "/api/game/"+gameID+"/events?types=move,game_winner")
+	assert.Equal(t, "initial", readNextSSEEvent(t, movesOnly).Type)
+	jsonDefault := openSSEStream(t, spectator, "/api/v1/game/"+gameID+"/events")
+	assert.Equal(t, "initial", readNextSSEEvent(t, jsonDefault).Type)
+	jsonStatus := openSSEStream(t, spectator, "/api/v1/game/"+gameID+"/events?types=game_status")
+	assert.Equal(t, "initial", readNextSSEEvent(t, jsonStatus).Type)
+
+	moves := []struct {
+		player *httpPlayer
+		path   string
+	}{
+		{playerA, "/move/0/0"},
+		{playerB, "/move/1/1"},
+	}
+	for i, move := range moves {
+		resp, body := move.player.htmxPost(t, "/api/game/"+gameID+move.path)
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+		// Status updates in between are filtered out in the broadcaster
+		assert.Equal(t, "move", readNextSSEEvent(t, movesOnly).Type, "move %d", i)
+		assert.Equal(t, "move", readNextSSEEvent(t, jsonDefault).Type, "move %d", i)
+		assert.Equal(t, "game_status", readNextSSEEvent(t, jsonStatus).Type, "move %d", i)
+	}
+
+	t.Run("unknown types are rejected", func(t *testing.T) {
+		resp, _ := spectator.get(t, "/api/game/"+gameID+"/events?types=move,bogus")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, body := spectator.get(t, "/api/v1/game/"+gameID+"/events?types=bogus")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, body, "Unknown event type")
+	})
+}
@@ -0,0 +1,119 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type historyResponse struct {
+	Events []struct {
+		ID   uint64                 `json:"id"`
+		Type string                 `json:"type"`
+		Data map[string]interface{} `json:"data"`
+	} `json:"events"`
+	HasMore   bool   `json:"hasMore"`
+	NextAfter uint64 `json:"nextAfter"`
+}
+
+func TestEventHistory(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	for _, move := range []struct {
+		player *httpPlayer
+		cell   string
+	}{
+		{playerA, "0/0"}, {playerB, "1/1"}, {playerA, "2/2"},
+	} {
+		resp, body := move.player.htmxPost(t, "/api/game/"+gameID+"/move/"+move.cell)
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	}
+
+	history := func(query string) historyResponse {
+		resp, body := playerA.get(t, "/api/game/"+gameID+"/events/history"+query)
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		var page historyResponse
+		require.NoError(t, json.Unmarshal([]byte(body), &page))
+		return page
+	}
+
+	t.Run("type filter", func(t *testing.T) {
+		page := history("?types=move")
+		require.Len(t, page.Events, 3)
+		assert.False(t, page.HasMore)
+
+		first := page.Events[0]
+		assert.Equal(t, "move", first.Type)
+		assert.Equal(t, "🐱", first.Data["player"], "player IDs are replaced with emojis")
+		assert.NotContains(t, first.Data, "playerID")
+		assert.EqualValues(t, 0, first.Data["row"])
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		var seen []uint64
+		query := "?limit=2"
+		for {
+			page := history(query)
+			for _, event := range page.Events {
+				seen = append(seen, event.ID)
+			}
+			if !page.HasMore {
+				break
+			}
+			require.Len(t, page.Events, 2)
+			query = "?limit=2&after=" + strconv.FormatUint(page.NextAfter, 10)
+		}
+
+		require.NotEmpty(t, seen)
+		for i := 1; i < len(seen); i++ {
+			assert.Equal(t, seen[i-1]+1, seen[i], "pages continue without gaps")
+		}
+		assert.Len(t, seen, len(history("?limit=200").Events))
+	})
+
+	t.Run("player IDs are never exposed", func(t *testing.T) {
+		_, body := playerA.get(t, "/api/game/"+gameID+"/events/history?limit=200")
+		serverURL, err := url.Parse(server.URL)
+		require.NoError(t, err)
+		for _, player := range []*httpPlayer{playerA, playerB} {
+			for _, cookie := range player.client.Jar.Cookies(serverURL) {
+				assert.NotContains(t, body, cookie.Value)
+			}
+		}
+	})
+
+	t.Run("private games are for their players", func(t *testing.T) {
+		privateA, privateB := newHTTPPlayer(t, server), newHTTPPlayer(t, server)
+		resp, body := privateA.postJSON(t, "/api/v1/games", map[string]interface{}{
+			"emoji":   "🐱",
+			"options": map[string]interface{}{"visibility": "private"},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+		privateID := decodeAPIGame(t, body).ID
+		resp, body = privateB.postJSON(t, "/api/v1/game/"+privateID+"/join", map[string]string{"emoji": "🚀"})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+		resp, body = newHTTPPlayer(t, server).get(t, "/api/game/"+privateID+"/events/history")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Forbidden"}`, body)
+		resp, _ = privateB.get(t, "/api/game/"+privateID+"/events/history")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp, _ = newHTTPPlayer(t, server).get(t, "/api/game/"+gameID+"/events/history")
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "public games can be watched by anyone")
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"?limit=0", "?limit=1000", "?after=x", "?types=bogus"} {
+			resp, _ := playerA.get(t, "/api/game/"+gameID+"/events/history"+query)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+		}
+	})
+}
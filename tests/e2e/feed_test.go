@@ -0,0 +1,56 @@
+package e2e
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveFeed(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	playMoves(t, gameID, playerA, playerB, "0/0", "1/0", "0/1", "1/1", "0/2")
+
+	privateA, privateB := newHTTPPlayer(t, server), newHTTPPlayer(t, server)
+	resp, body := privateA.postJSON(t, "/api/v1/games", map[string]interface{}{
+		"emoji":   "🐱",
+		"options": map[string]interface{}{"visibility": "private"},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	privateID := decodeAPIGame(t, body).ID
+	resp, body = privateB.postJSON(t, "/api/v1/game/"+privateID+"/join", map[string]string{"emoji": "🚀"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	playMoves(t, privateID, privateA, privateB, "0/0", "1/0", "0/1", "1/1", "0/2")
+
+	resp, body = playerA.get(t, "/archive/feed.atom")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	var feed struct {
+		Title   string `xml:"title"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Title   string `xml:"title"`
+			Summary string `xml:"summary"`
+			Link    struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(body), &feed), body)
+	require.NotEmpty(t, feed.Entries)
+
+	for _, entry := range feed.Entries {
+		assert.NotContains(t, entry.ID, privateID, "private games are left out")
+	}
+	newest := feed.Entries[0]
+	assert.Equal(t, server.URL+"/game/"+gameID+"/result", newest.Link.Href, "newest first")
+	assert.Equal(t, "🐱 wins!", newest.Title)
+	assert.Contains(t, newest.Summary, "🐱 vs 🚀 · 5 moves in")
+}
@@ -0,0 +1,70 @@
+package e2e
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFragmentCache(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	eventsPath := "/api/game/" + gameID + "/events"
+
+	streamB := openSSEStream(t, playerB, eventsPath)
+	readSSEEvent(t, streamB, "initial")
+	spectators := make([]*bufio.Reader, 5)
+	for i := range spectators {
+		spectators[i] = openSSEStream(t, newHTTPPlayer(t, server), eventsPath)
+		readSSEEvent(t, spectators[i], "initial")
+	}
+
+	t.Run("Spectators share one rendering per move", func(t *testing.T) {
+		before := scrapeMetrics(t, playerA)
+		resp, body := playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+		for _, spectator := range spectators {
+			board := readSSEEvent(t, spectator, "move")
+			assert.Contains(t, board, `aria-label="row 1 column 1, 🐱"`)
+			assert.NotContains(t, board, "hx-post", "spectators can't move")
+		}
+		assert.Contains(t, readSSEEvent(t, streamB, "move"), "hx-post", "the player to move can")
+
+		after := scrapeMetrics(t, playerA)
+		renders := after["tictactoe_fragment_renders_total"] - before["tictactoe_fragment_renders_total"]
+		hits := after["tictactoe_fragment_cache_hits_total"] - before["tictactoe_fragment_cache_hits_total"]
+		assert.LessOrEqual(t, renders, 4.0, "a board and a status for each of the two views")
+		assert.GreaterOrEqual(t, hits, float64(len(spectators)))
+	})
+
+	t.Run("A move invalidates the cached fragments", func(t *testing.T) {
+		_, before := playerB.get(t, "/api/game/"+gameID+"/fragment/board")
+		_, again := playerB.get(t, "/api/game/"+gameID+"/fragment/board")
+		assert.Equal(t, before, again)
+
+		resp, _ := playerB.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		_, after := playerB.get(t, "/api/game/"+gameID+"/fragment/board")
+		assert.Contains(t, after, `aria-label="row 2 column 2, 🚀"`)
+		assert.NotContains(t, after, "hx-post", "no longer B's turn")
+		_, status := playerA.get(t, "/api/game/"+gameID+"/fragment/status")
+		assert.Contains(t, status, "Your turn!")
+	})
+
+	t.Run("A reset invalidates the cached fragments", func(t *testing.T) {
+		resp, _ := playerA.htmxPost(t, "/api/game/"+gameID+"/reset")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		_, board := playerA.get(t, "/api/game/"+gameID+"/fragment/board")
+		assert.NotContains(t, board, "🚀")
+		assert.Contains(t, board, `aria-label="row 1 column 1, empty"`)
+	})
+}
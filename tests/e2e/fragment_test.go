@@ -0,0 +1,70 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameFragments(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	fragmentPath := "/api/game/" + gameID + "/fragment/"
+
+	t.Run("Board is rendered for the requesting player", func(t *testing.T) {
+		resp, board := playerA.get(t, fragmentPath+"board")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, board, `id="game-board"`)
+		assert.Contains(t, board, `hx-post="/api/game/`+gameID+`/move/0/0"`, "it's A's turn")
+
+		_, board = playerB.get(t, fragmentPath+"board")
+		assert.NotContains(t, board, "hx-post", "B has to wait")
+	})
+
+	t.Run("Status follows the game", func(t *testing.T) {
+		_, status := playerA.get(t, fragmentPath+"status")
+		assert.Contains(t, status, `id="game-status"`)
+		assert.Contains(t, status, "Your turn")
+
+		playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		_, status = playerA.get(t, fragmentPath+"status")
+		assert.Contains(t, status, "🚀's turn")
+	})
+
+	t.Run("Players", func(t *testing.T) {
+		_, players := playerB.get(t, fragmentPath+"players")
+		assert.Contains(t, players, `id="game-players"`)
+		assert.Regexp(t, `player-card-0.*🐱.*vs.*player-card-1.*🚀`, players)
+	})
+
+	t.Run("Stable test IDs", func(t *testing.T) {
+		_, board := playerB.get(t, fragmentPath+"board")
+		assert.Contains(t, board, `data-testid="game-board"`)
+		assert.Contains(t, board, `data-testid="cell-0-0"`)
+		assert.Contains(t, board, `data-testid="cell-2-2"`)
+
+		_, status := playerB.get(t, fragmentPath+"status")
+		assert.Contains(t, status, `data-testid="game-status"`)
+		assert.Contains(t, status, `data-testid="turn-indicator"`)
+
+		_, players := playerB.get(t, fragmentPath+"players")
+		assert.Contains(t, players, `data-testid="game-players"`)
+
+		playMoves(t, gameID, playerB, playerA, "1/0", "0/1", "1/1", "0/2")
+		_, status = playerA.get(t, fragmentPath+"status")
+		assert.Contains(t, status, `data-testid="game-result"`)
+		assert.Contains(t, status, "🐱 wins!")
+	})
+
+	t.Run("Unknown sections and games are not found", func(t *testing.T) {
+		resp, _ := playerA.get(t, fragmentPath+"scoreboard")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, _ = playerA.get(t, "/api/game/nope/fragment/board")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
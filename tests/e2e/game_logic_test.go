@@ -47,10 +47,10 @@ func TestTicTacToeGameLogic(t *testing.T) {
 		gameID := setupTwoPlayerGame(t, server.URL, userAPage, userBPage)
 
 		// Verify Player 1 (🐱) turn indicator is shown
-		err = userAPage.Locator(".turn-indicator").WaitFor()
+		err = userAPage.Locator("[data-testid=turn-indicator]").WaitFor()
 		require.NoError(t, err)
 
-		turnIndicator, err := userAPage.Locator(".turn-indicator").TextContent()
+		turnIndicator, err := userAPage.Locator("[data-testid=turn-indicator]").TextContent()
 		require.NoError(t, err)
 
 		// Clean up whitespace for comparison
@@ -65,19 +65,19 @@ func TestTicTacToeGameLogic(t *testing.T) {
 		assert.Contains(t, strings.ToLower(turnIndicator), "turn", "Should indicate it's their turn")
 
 		// Player 1 makes first move (top-left)
-		err = userAPage.Locator(".game-cell").First().Click()
+		err = userAPage.Locator("[data-testid=cell-0-0]").Click()
 		require.NoError(t, err)
 
 		// Wait for move to process
-		_, err = userAPage.WaitForFunction(`document.querySelector('.game-cell').textContent === '🐱'`, nil)
+		_, err = userAPage.WaitForFunction(`document.querySelector('[data-testid=cell-0-0]').textContent === '🐱'`, nil)
 		require.NoError(t, err)
 
 		// Wait for page reload and verify turn indicator now shows Player 2's turn
 		time.Sleep(1500 * time.Millisecond) // Allow SSE to trigger page reload
-		err = userAPage.Locator(".turn-indicator").WaitFor()
+		err = userAPage.Locator("[data-testid=turn-indicator]").WaitFor()
 		require.NoError(t, err)
 
-		turnIndicator, err = userAPage.Locator(".turn-indicator").TextContent()
+		turnIndicator, err = userAPage.Locator("[data-testid=turn-indicator]").TextContent()
 		require.NoError(t, err)
 
 		// Clean up whitespace for comparison
@@ -91,26 +91,26 @@ func TestTicTacToeGameLogic(t *testing.T) {
 		assert.Contains(t, turnIndicator, "🚀", "Should show Player 2's turn after Player 1 moves")
 
 		// Verify Player 1 cannot make another move immediately
-		err = userAPage.Locator(".game-cell").Nth(1).Click()
+		err = userAPage.Locator("[data-testid=cell-0-1]").Click()
 		require.NoError(t, err)
 
 		// Wait a bit and verify the cell is still empty (move should be rejected)
 		time.Sleep(500 * time.Millisecond)
-		secondCellContent, err := userAPage.Locator(".game-cell").Nth(1).TextContent()
+		secondCellContent, err := userAPage.Locator("[data-testid=cell-0-1]").TextContent()
 		require.NoError(t, err)
 		assert.Empty(t, secondCellContent, "Player 1 should not be able to move when it's Player 2's turn")
 
 		// Player 2 makes their move (center)
-		err = userBPage.Locator(".game-cell").Nth(4).Click()
+		err = userBPage.Locator("[data-testid=cell-1-1]").Click()
 		require.NoError(t, err)
 
 		// Wait for move to process
-		_, err = userBPage.WaitForFunction(`document.querySelectorAll('.game-cell')[4].textContent === '🚀'`, nil)
+		_, err = userBPage.WaitForFunction(`document.querySelector('[data-testid=cell-1-1]').textContent === '🚀'`, nil)
 		require.NoError(t, err)
 
 		// Verify turn indicator shows Player 1's turn again
 		time.Sleep(500 * time.Millisecond) // Allow SSE to update
-		turnIndicator, err = userBPage.Locator(".turn-indicator").TextContent()
+		turnIndicator, err = userBPage.Locator("[data-testid=turn-indicator]").TextContent()
 		require.NoError(t, err)
 		assert.Contains(t, turnIndicator, "🐱", "Should show Player 1's turn after Player 2 moves")
 
@@ -140,67 +140,67 @@ func TestTicTacToeGameLogic(t *testing.T) {
 		// Player 1: (0,0), Player 2: (1,0), Player 1: (0,1), Player 2: (1,1), Player 1: (0,2) - WINS
 
 		// Move 1: Player 1 (0,0)
-		err = userAPage.Locator(".game-cell").Nth(0).Click() // Top-left
+		err = userAPage.Locator("[data-testid=cell-0-0]").Click() // Top-left
 		require.NoError(t, err)
-		_, err = userAPage.WaitForFunction(`document.querySelectorAll('.game-cell')[0].textContent === '🐱'`, nil)
+		_, err = userAPage.WaitForFunction(`document.querySelector('[data-testid=cell-0-0]').textContent === '🐱'`, nil)
 		require.NoError(t, err)
 
 		// Move 2: Player 2 (1,0)
 		time.Sleep(200 * time.Millisecond)
-		err = userBPage.Locator(".game-cell").Nth(3).Click() // Middle-left
+		err = userBPage.Locator("[data-testid=cell-1-0]").Click() // Middle-left
 		require.NoError(t, err)
-		_, err = userBPage.WaitForFunction(`document.querySelectorAll('.game-cell')[3].textContent === '🚀'`, nil)
+		_, err = userBPage.WaitForFunction(`document.querySelector('[data-testid=cell-1-0]').textContent === '🚀'`, nil)
 		require.NoError(t, err)
 
 		// Move 3: Player 1 (0,1)
 		time.Sleep(200 * time.Millisecond)
-		err = userAPage.Locator(".game-cell").Nth(1).Click() // Top-middle
+		err = userAPage.Locator("[data-testid=cell-0-1]").Click() // Top-middle
 		require.NoError(t, err)
-		_, err = userAPage.WaitForFunction(`document.querySelectorAll('.game-cell')[1].textContent === '🐱'`, nil)
+		_, err = userAPage.WaitForFunction(`document.querySelector('[data-testid=cell-0-1]').textContent === '🐱'`, nil)
 		require.NoError(t, err)
 
 		// Move 4: Player 2 (1,1)
 		time.Sleep(200 * time.Millisecond)
-		err = userBPage.Locator(".game-cell").Nth(4).Click() // Center
+		err = userBPage.Locator("[data-testid=cell-1-1]").Click() // Center
 		require.NoError(t, err)
-		_, err = userBPage.WaitForFunction(`document.querySelectorAll('.game-cell')[4].textContent === '🚀'`, nil)
+		_, err = userBPage.WaitForFunction(`document.querySelector('[data-testid=cell-1-1]').textContent === '🚀'`, nil)
 		require.NoError(t, err)
 
 		// Move 5: Player 1 (0,2) - WINNING MOVE
 		time.Sleep(200 * time.Millisecond)
-		err = userAPage.Locator(".game-cell").Nth(2).Click() // Top-right
+		err = userAPage.Locator("[data-testid=cell-0-2]").Click() // Top-right
 		require.NoError(t, err)
-		_, err = userAPage.WaitForFunction(`document.querySelectorAll('.game-cell')[2].textContent === '🐱'`, nil)
+		_, err = userAPage.WaitForFunction(`document.querySelector('[data-testid=cell-0-2]').textContent === '🐱'`, nil)
 		require.NoError(t, err)
 
 		// Wait for winner detection
 		time.Sleep(1000 * time.Millisecond)
 
 		// Verify winner announcement is shown
-		gameResult, err := userAPage.Locator(".game-result").TextContent()
+		gameResult, err := userAPage.Locator("[data-testid=game-result]").TextContent()
 		require.NoError(t, err)
 		assert.Contains(t, gameResult, "🐱", "Winner announcement should show Player 1's emoji")
 		assert.Contains(t, gameResult, "wins", "Should announce Player 1 as winner")
 
 		// Verify both players see the same result
-		gameResultB, err := userBPage.Locator(".game-result").TextContent()
+		gameResultB, err := userBPage.Locator("[data-testid=game-result]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, gameResult, gameResultB, "Both players should see the same winner announcement")
 
 		// Verify no more moves are allowed
-		err = userBPage.Locator(".game-cell").Nth(5).Click() // Try to click bottom-middle
+		err = userBPage.Locator("[data-testid=cell-1-2]").Click() // Try to click bottom-middle
 		require.NoError(t, err)
 
 		// Wait and verify the cell is still empty (game should be finished)
 		time.Sleep(500 * time.Millisecond)
-		cellContent, err := userBPage.Locator(".game-cell").Nth(5).TextContent()
+		cellContent, err := userBPage.Locator("[data-testid=cell-1-2]").TextContent()
 		require.NoError(t, err)
 		assert.Empty(t, cellContent, "No moves should be allowed after game is won")
 
 		// Verify turn indicator is hidden or shows game over
-		turnIndicatorVisible, err := userAPage.Locator(".turn-indicator").IsVisible()
+		turnIndicatorVisible, err := userAPage.Locator("[data-testid=turn-indicator]").IsVisible()
 		if err == nil && turnIndicatorVisible {
-			turnText, _ := userAPage.Locator(".turn-indicator").TextContent()
+			turnText, _ := userAPage.Locator("[data-testid=turn-indicator]").TextContent()
 			assert.NotContains(t, turnText, "turn", "Turn indicator should not show active turn when game is over")
 		}
 
@@ -252,14 +252,14 @@ func TestTicTacToeGameLogic(t *testing.T) {
 		for i, move := range moves {
 			t.Logf("Move %d: %s clicking cell %d", i+1, move.player, move.index)
 
-			err = move.page.Locator(".game-cell").Nth(move.index).Click()
+			err = move.page.Locator("[data-testid^=cell-]").Nth(move.index).Click()
 			require.NoError(t, err)
 
 			// Wait for move to be processed
 			time.Sleep(300 * time.Millisecond)
 
 			// Verify the move was placed (except we don't know what the final emoji will be)
-			cellContent, err := move.page.Locator(".game-cell").Nth(move.index).TextContent()
+			cellContent, err := move.page.Locator("[data-testid^=cell-]").Nth(move.index).TextContent()
 			require.NoError(t, err)
 			assert.NotEmpty(t, cellContent, "Cell should have emoji after move")
 		}
@@ -268,12 +268,12 @@ func TestTicTacToeGameLogic(t *testing.T) {
 		time.Sleep(1000 * time.Millisecond)
 
 		// Verify draw announcement is shown
-		gameResult, err := userAPage.Locator(".game-result").TextContent()
+		gameResult, err := userAPage.Locator("[data-testid=game-result]").TextContent()
 		require.NoError(t, err)
 		assert.Contains(t, strings.ToLower(gameResult), "draw", "Should announce draw result")
 
 		// Verify both players see the same result
-		gameResultB, err := userBPage.Locator(".game-result").TextContent()
+		gameResultB, err := userBPage.Locator("[data-testid=game-result]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, gameResult, gameResultB, "Both players should see the same draw announcement")
 
@@ -338,14 +338,14 @@ func TestTicTacToeGameLogic(t *testing.T) {
 				// Play the game: Player1 takes winning cells, Player2 takes others
 				for i := 0; i < 3; i++ {
 					// Player 1 move (winning combination)
-					err = userAPage.Locator(".game-cell").Nth(combo.cells[i]).Click()
+					err = userAPage.Locator("[data-testid^=cell-]").Nth(combo.cells[i]).Click()
 					require.NoError(t, err)
 					time.Sleep(200 * time.Millisecond)
 
 					if i < 2 { // Don't let Player 2 move after Player 1 wins
 						// Player 2 move (non-winning cell)
 						if i < len(otherCells) {
-							err = userBPage.Locator(".game-cell").Nth(otherCells[i]).Click()
+							err = userBPage.Locator("[data-testid^=cell-]").Nth(otherCells[i]).Click()
 							require.NoError(t, err)
 							time.Sleep(200 * time.Millisecond)
 						}
@@ -356,7 +356,7 @@ func TestTicTacToeGameLogic(t *testing.T) {
 				time.Sleep(1000 * time.Millisecond)
 
 				// Verify Player 1 wins
-				gameResult, err := userAPage.Locator(".game-result").TextContent()
+				gameResult, err := userAPage.Locator("[data-testid=game-result]").TextContent()
 				require.NoError(t, err)
 				assert.Contains(t, gameResult, "🐱", "Player 1 should win with "+combo.name)
 				assert.Contains(t, strings.ToLower(gameResult), "win", "Should announce win")
@@ -377,11 +377,11 @@ func setupTwoPlayerGame(t *testing.T, serverURL string, userAPage, userBPage pla
 	require.NoError(t, err)
 
 	userAPage.WaitForURL("**/select-emoji")
-	err = userAPage.Click(".emoji-option:nth-child(1)") // 🐱
+	err = userAPage.Click("[data-testid='emoji-option-🐱']") // 🐱
 	require.NoError(t, err)
 
 	// Get game URL
-	gameURL, err := userAPage.Locator(".url-input").GetAttribute("value")
+	gameURL, err := userAPage.Locator("[data-testid=game-url]").GetAttribute("value")
 	require.NoError(t, err)
 
 	// User B joins and selects emoji
@@ -389,7 +389,7 @@ func setupTwoPlayerGame(t *testing.T, serverURL string, userAPage, userBPage pla
 	require.NoError(t, err)
 
 	userBPage.WaitForURL("**/select-emoji")
-	err = userBPage.Click(".emoji-option:nth-child(2)") // 🚀
+	err = userBPage.Click("[data-testid='emoji-option-🚀']") // 🚀
 	require.NoError(t, err)
 
 	// Both should enter the game
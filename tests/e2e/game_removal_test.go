@@ -0,0 +1,61 @@
+package e2e
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"htmx-go-app/game"
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameRemovalClosesStreams(t *testing.T) {
+	handlers.AdminAPIKey.Set(testAdminKey)
+	t.Cleanup(func() { handlers.AdminAPIKey.Set("") })
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	t.Run("Deleted games end their streams", func(t *testing.T) {
+		gameID, playerA, playerB := startHTTPGame(t, server)
+		streamA := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+		readSSEEvent(t, streamA, "initial")
+		streamB := openSSEStream(t, playerB, "/api/v1/game/"+gameID+"/events")
+		readSSEEvent(t, streamB, "initial")
+		require.Equal(t, 2, subscriberCount(gameID))
+
+		resp, _ := adminRequest(t, server, testAdminKey, http.MethodDelete, "/api/admin/games/"+gameID)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		assert.Contains(t, readSSEEvent(t, streamA, "game_cancelled"), "data-redirect")
+		assert.Contains(t, readSSEEvent(t, streamB, "game_cancelled"), `"type":"game_cancelled"`)
+		for _, stream := range []io.Reader{streamA, streamB} {
+			_, err := io.ReadAll(stream)
+			assert.NoError(t, err, "the server closes the stream")
+		}
+		assert.Equal(t, 0, subscriberCount(gameID))
+	})
+
+	t.Run("Expired games end their streams", func(t *testing.T) {
+		fake := useFakeClock(t)
+		creator := newHTTPPlayer(t, server)
+		resp, _ := creator.get(t, "/new-game")
+		gameID := extractGameID(resp.Request.URL.Path)
+		stream := openSSEStream(t, creator, "/api/game/"+gameID+"/events")
+		readSSEEvent(t, stream, "initial")
+
+		// Unclaimed games are cleared out when the next game is created
+		fake.Advance(game.UnclaimedGameTTL.Get() + time.Second)
+		newHTTPPlayer(t, server).get(t, "/new-game")
+
+		readSSEEvent(t, stream, "game_cancelled")
+		_, err := io.ReadAll(stream)
+		assert.NoError(t, err, "the server closes the stream")
+		assert.Nil(t, game.GetGame(gameID))
+		assert.Equal(t, 0, subscriberCount(gameID))
+	})
+}
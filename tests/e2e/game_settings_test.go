@@ -0,0 +1,69 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreStartSettings(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close) // runs after the stream cleanup below
+
+	creator := newHTTPPlayer(t, server)
+	resp, _ := creator.get(t, "/new-game")
+	gameID := extractGameID(resp.Request.URL.Path)
+	creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+	settingsPath := "/api/game/" + gameID + "/settings"
+
+	_, page := creator.get(t, "/game/"+gameID+"/select-emoji")
+	assert.Contains(t, page, `id="game-settings"`)
+	assert.Contains(t, page, `hx-post="`+settingsPath+`"`)
+
+	joiner := newHTTPPlayer(t, server)
+	_, page = joiner.get(t, "/game/"+gameID+"/select-emoji")
+	assert.Contains(t, page, "🌍 Public game")
+	assert.Contains(t, page, `sse-swap="settings_changed"`)
+
+	stream := openSSEStream(t, joiner, "/api/game/"+gameID+"/events")
+	readSSEEvent(t, stream, "initial")
+
+	t.Run("The creator changes the rules and joiners see them", func(t *testing.T) {
+		resp, fragment := creator.do(t, http.MethodPost, settingsPath, url.Values{"visibility": {"private"}, "password": {"secret"}}, true)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, fragment, "Remove Password")
+		assert.Equal(t, models.VisibilityPrivate, game.GetGame(gameID).Visibility)
+
+		rules := readSSEEvent(t, stream, "settings_changed")
+		assert.Contains(t, rules, "🔒 Private game")
+		assert.Contains(t, rules, "password required")
+
+		_, page := joiner.get(t, "/game/"+gameID+"/select-emoji")
+		assert.Contains(t, page, `id="game-password"`, "the password prompt appears")
+	})
+
+	t.Run("The password can be removed again", func(t *testing.T) {
+		resp, fragment := creator.do(t, http.MethodPost, settingsPath, url.Values{"clear_password": {"true"}}, true)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, fragment, "Set Password")
+		assert.NotContains(t, readSSEEvent(t, stream, "settings_changed"), "password required")
+	})
+
+	t.Run("Only the creator may change settings", func(t *testing.T) {
+		resp, _ := joiner.do(t, http.MethodPost, settingsPath, url.Values{"visibility": {"public"}}, true)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Settings are locked once the game starts", func(t *testing.T) {
+		joiner.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+		resp, _ := creator.do(t, http.MethodPost, settingsPath, url.Values{"visibility": {"public"}}, true)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+}
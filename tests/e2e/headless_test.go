@@ -0,0 +1,50 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadlessMode(t *testing.T) {
+	handlers.Headless = true
+	t.Cleanup(func() { handlers.Headless = false })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close) // runs after the stream cleanup below
+	player := newHTTPPlayer(t, server)
+
+	t.Run("HTML pages are gone", func(t *testing.T) {
+		for _, path := range []string{"/", "/lobby", "/new-game", "/static/css/style.css", "/no-such-page"} {
+			resp, body := player.get(t, path)
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+			assert.Contains(t, resp.Header.Get("Content-Type"), "application/json", path)
+			assert.Contains(t, body, `"error"`, path)
+		}
+	})
+
+	t.Run("The JSON API and its event stream still work", func(t *testing.T) {
+		resp, body := player.postJSON(t, "/api/v1/games", map[string]string{"emoji": "🐱"})
+		require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+		var created struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &created))
+
+		resp, _ = player.get(t, "/api/v1/game/"+created.ID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp, _ = player.get(t, "/api/version")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		readSSEEvent(t, openSSEStream(t, player, "/api/v1/game/"+created.ID+"/events"), "initial")
+
+		resp, _ = player.get(t, "/api/game/"+created.ID+"/fragment/board")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, "htmx fragments are HTML")
+	})
+}
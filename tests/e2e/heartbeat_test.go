@@ -0,0 +1,48 @@
+package e2e
+
+import (
+	"bufio"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"htmx-go-app/events"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readSSEComment reads lines until an SSE comment (": ...") arrives
+func readSSEComment(t *testing.T, reader *bufio.Reader) string {
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if strings.HasPrefix(line, ":") {
+			return strings.TrimSpace(strings.TrimPrefix(line, ":"))
+		}
+	}
+}
+
+func TestSSEHeartbeats(t *testing.T) {
+	previous := events.HeartbeatInterval.Get()
+	events.HeartbeatInterval.Set(50 * time.Millisecond)
+	t.Cleanup(func() { events.HeartbeatInterval.Set(previous) })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, _ := startHTTPGame(t, server)
+
+	streams := map[string]string{
+		"game":       "/api/game/" + gameID + "/events",
+		"json game":  "/api/v1/game/" + gameID + "/events",
+		"lobby":      "/api/lobby/events",
+		"quickmatch": "/api/matchmaking/events",
+	}
+	for name, path := range streams {
+		t.Run(name, func(t *testing.T) {
+			stream := openSSEStream(t, playerA, path)
+			require.Equal(t, "keep-alive", readSSEComment(t, stream))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// httpPlayer is a browserless client with its own cookie jar, used for
+// tests that only need to assert server-rendered output.
+type httpPlayer struct {
+	client  *http.Client
+	baseURL string
+}
+
+func newHTTPPlayer(t *testing.T, server *httptest.Server) *httpPlayer {
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	return &httpPlayer{
+		client:  &http.Client{Jar: jar},
+		baseURL: server.URL,
+	}
+}
+
+func (p *httpPlayer) do(t *testing.T, method, path string, form url.Values, htmx bool) (*http.Response, string) {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, p.baseURL+path, body)
+	require.NoError(t, err)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if htmx {
+		req.Header.Set("HX-Request", "true")
+	}
+
+	resp, err := p.client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(data)
+}
+
+// postJSON sends payload as a JSON body and returns the raw response body
+func (p *httpPlayer) postJSON(t *testing.T, path string, payload interface{}) (*http.Response, string) {
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, p.baseURL+path, bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func (p *httpPlayer) get(t *testing.T, path string) (*http.Response, string) {
+	return p.do(t, http.MethodGet, path, nil, false)
+}
+
+func (p *httpPlayer) post(t *testing.T, path string, form url.Values) (*http.Response, string) {
+	return p.do(t, http.MethodPost, path, form, false)
+}
+
+func (p *httpPlayer) htmxPost(t *testing.T, path string) (*http.Response, string) {
+	return p.do(t, http.MethodPost, path, nil, true)
+}
+
+// startHTTPGame creates a game as player A (🐱) and joins it as player B (🚀)
+func startHTTPGame(t *testing.T, server *httptest.Server) (string, *httpPlayer, *httpPlayer) {
+	playerA := newHTTPPlayer(t, server)
+	playerB := newHTTPPlayer(t, server)
+
+	resp, _ := playerA.get(t, "/new-game")
+	gameID := extractGameID(resp.Request.URL.Path)
+	require.NotEmpty(t, gameID)
+
+	playerA.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+	resp, _ = playerB.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+	require.Equal(t, "/game/"+gameID, resp.Request.URL.Path)
+
+	return gameID, playerA, playerB
+}
@@ -0,0 +1,68 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"htmx-go-app/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInviteTokens(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	creator := newHTTPPlayer(t, server)
+	resp, _ := creator.get(t, "/new-game")
+	gameID := extractGameID(resp.Request.URL.Path)
+	creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+
+	outsider := newHTTPPlayer(t, server)
+	resp, _ = outsider.htmxPost(t, "/api/game/"+gameID+"/invites")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "only the creator may issue invites")
+
+	resp, fragment := creator.htmxPost(t, "/api/game/"+gameID+"/invites")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	matches := regexp.MustCompile(`value="[^"]*(/join/[A-Za-z0-9_-]+)"`).FindStringSubmatch(fragment)
+	require.Len(t, matches, 2)
+	invitePath := matches[1]
+
+	invited := newHTTPPlayer(t, server)
+	resp, _ = invited.get(t, invitePath)
+	assert.Equal(t, "/game/"+gameID+"/select-emoji", resp.Request.URL.Path)
+
+	// The invited player can follow the link again, but nobody else can use it
+	resp, _ = invited.get(t, invitePath)
+	assert.Equal(t, "/game/"+gameID+"/select-emoji", resp.Request.URL.Path)
+
+	resp, _ = outsider.get(t, invitePath)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, _ = outsider.get(t, "/join/not-a-real-token")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestInviteExpiry(t *testing.T) {
+	fake := useFakeClock(t)
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	creator := newHTTPPlayer(t, server)
+	resp, _ := creator.get(t, "/new-game")
+	gameID := extractGameID(resp.Request.URL.Path)
+	creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+
+	_, fragment := creator.htmxPost(t, "/api/game/"+gameID+"/invites")
+	matches := regexp.MustCompile(`value="[^"]*(/join/[A-Za-z0-9_-]+)"`).FindStringSubmatch(fragment)
+	require.Len(t, matches, 2)
+
+	fake.Advance(game.InviteTTL.Get() + time.Second)
+	resp, _ = newHTTPPlayer(t, server).get(t, matches[1])
+	assert.Equal(t, http.StatusGone, resp.StatusCode)
+}
@@ -0,0 +1,53 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinByCode(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	creator := newHTTPPlayer(t, server)
+	resp, _ := creator.get(t, "/new-game")
+	gameID := extractGameID(resp.Request.URL.Path)
+	_, body := creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+
+	matches := regexp.MustCompile(`Join code: <code>([a-z]+-[a-z]+-[0-9]+)</code>`).FindStringSubmatch(body)
+	require.Len(t, matches, 2, "waiting page should show the join code")
+	code := matches[1]
+
+	t.Run("Code resolves to emoji selection", func(t *testing.T) {
+		joiner := newHTTPPlayer(t, server)
+		resp, _ := joiner.get(t, "/join?code="+url.QueryEscape(code))
+		assert.Equal(t, "/game/"+gameID+"/select-emoji", resp.Request.URL.Path)
+	})
+
+	t.Run("Typed codes are normalized", func(t *testing.T) {
+		typed := " " + strings.ToUpper(strings.ReplaceAll(code, "-", " ")) + " "
+		joiner := newHTTPPlayer(t, server)
+		resp, _ := joiner.get(t, "/join?code="+url.QueryEscape(typed))
+		assert.Equal(t, "/game/"+gameID+"/select-emoji", resp.Request.URL.Path)
+	})
+
+	t.Run("Hex game ID is accepted too", func(t *testing.T) {
+		joiner := newHTTPPlayer(t, server)
+		resp, _ := joiner.get(t, "/join?code="+gameID)
+		assert.Equal(t, "/game/"+gameID+"/select-emoji", resp.Request.URL.Path)
+	})
+
+	t.Run("Unknown code is not found", func(t *testing.T) {
+		joiner := newHTTPPlayer(t, server)
+		resp, body := joiner.get(t, "/join?code=no-such-code-1")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Contains(t, body, "no-such-code-1")
+	})
+}
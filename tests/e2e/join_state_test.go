@@ -0,0 +1,53 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinStateMachine(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	creator := newHTTPPlayer(t, server)
+	opponent := newHTTPPlayer(t, server)
+	latecomer := newHTTPPlayer(t, server)
+	resp, page := creator.get(t, "/new-game")
+	gameID := extractGameID(resp.Request.URL.Path)
+	require.NotEmpty(t, gameID)
+	assert.Contains(t, page, `data-join-state="selecting"`)
+	statePath := "/game/" + gameID + "/join-state"
+
+	creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+	_, page = creator.get(t, "/game/"+gameID+"/select-emoji")
+	assert.Contains(t, page, `data-join-state="waiting"`)
+	assert.Contains(t, page, `hx-trigger="sse:join_state, join-refresh"`)
+
+	_, fragment := creator.get(t, statePath)
+	assert.Contains(t, fragment, `data-join-state="waiting"`)
+	assert.NotContains(t, fragment, "<html", "only the fragment is sent")
+
+	creatorStream := openSSEStream(t, creator, "/api/game/"+gameID+"/events")
+	latecomerStream := openSSEStream(t, latecomer, "/api/game/"+gameID+"/events")
+	resp, _ = opponent.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+	require.Equal(t, "/game/"+gameID, resp.Request.URL.Path)
+
+	assert.Equal(t, "ready", readSSEEvent(t, creatorStream, "join_state"), "each page hears its own state")
+	assert.Equal(t, "full", readSSEEvent(t, latecomerStream, "join_state"))
+
+	_, fragment = creator.get(t, statePath)
+	assert.Contains(t, fragment, `data-join-state="ready"`)
+	assert.Contains(t, fragment, `data-redirect="/game/`+gameID+`"`, "the waiting page moves on to the game")
+	resp, _ = creator.get(t, "/game/"+gameID+"/select-emoji")
+	assert.Equal(t, "/game/"+gameID, resp.Request.URL.Path, "a fresh load goes straight to the game")
+
+	resp, page = latecomer.get(t, "/game/"+gameID+"/select-emoji")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, page, `data-join-state="full"`)
+	assert.Contains(t, page, `class="game-full"`)
+}
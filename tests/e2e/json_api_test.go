@@ -0,0 +1,186 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// apiGameResponse mirrors the fields of the JSON game representation used in tests
+type apiGameResponse struct {
+	ID          string              `json:"id"`
+	Code        string              `json:"code"`
+	Status      string              `json:"status"`
+	Visibility  string              `json:"visibility"`
+	HasPassword bool                `json:"hasPassword"`
+	Board       [3][3]string        `json:"board"`
+	CurrentTurn string              `json:"currentTurn"`
+	Winner      string              `json:"winner"`
+	MoveCount   int                 `json:"moveCount"`
+	Duration    float64             `json:"durationSeconds"`
+	Players     []apiPlayerResponse `json:"players"`
+	You         *struct {
+		PlayerID string `json:"playerId"`
+		Emoji    string `json:"emoji"`
+		Seat     int    `json:"seat"`
+		YourTurn bool   `json:"yourTurn"`
+	} `json:"you"`
+}
+
+type apiPlayerResponse struct {
+	Emoji        string  `json:"emoji"`
+	Name         string  `json:"name"`
+	Seat         int     `json:"seat"`
+	ThinkSeconds float64 `json:"averageThinkSeconds"`
+}
+
+func decodeAPIGame(t *testing.T, body string) apiGameResponse {
+	var game apiGameResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &game), body)
+	return game
+}
+
+func TestJSONGameAPI(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	creator := newHTTPPlayer(t, server)
+	joiner := newHTTPPlayer(t, server)
+
+	resp, body := creator.postJSON(t, "/api/v1/games", map[string]interface{}{
+		"emoji": "🐱",
+		"name":  "Alice",
+		"options": map[string]interface{}{
+			"visibility": "private",
+			"password":   "secret",
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	created := decodeAPIGame(t, body)
+	assert.Equal(t, "waiting", created.Status)
+	assert.Equal(t, "private", created.Visibility)
+	assert.True(t, created.HasPassword)
+	assert.NotEmpty(t, created.Code)
+	require.NotNil(t, created.You)
+	assert.Equal(t, "🐱", created.You.Emoji)
+
+	t.Run("Join requires the password", func(t *testing.T) {
+		resp, _ := joiner.postJSON(t, "/api/v1/game/"+created.ID+"/join", map[string]string{"emoji": "🚀"})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Taken emoji is a conflict", func(t *testing.T) {
+		resp, body := joiner.postJSON(t, "/api/v1/game/"+created.ID+"/join", map[string]string{"emoji": "🐱", "password": "secret"})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "emoji already taken"}`, body)
+	})
+
+	t.Run("Join starts the game", func(t *testing.T) {
+		resp, body := joiner.postJSON(t, "/api/v1/game/"+created.ID+"/join", map[string]string{"emoji": "🚀", "name": "Bob", "password": "secret"})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		joined := decodeAPIGame(t, body)
+		assert.Equal(t, "active", joined.Status)
+		assert.Equal(t, "🐱", joined.CurrentTurn)
+		assert.Equal(t, []apiPlayerResponse{{Emoji: "🐱", Name: "Alice", Seat: 0}, {Emoji: "🚀", Name: "Bob", Seat: 1}}, joined.Players)
+		require.NotNil(t, joined.You)
+		assert.Equal(t, 1, joined.You.Seat)
+		assert.False(t, joined.You.YourTurn)
+	})
+
+	t.Run("Get returns the viewer's perspective", func(t *testing.T) {
+		_, body := creator.get(t, "/api/v1/game/"+created.ID)
+		game := decodeAPIGame(t, body)
+		require.NotNil(t, game.You)
+		assert.True(t, game.You.YourTurn)
+
+		outsider := newHTTPPlayer(t, server)
+		_, body = outsider.get(t, "/api/v1/game/"+created.ID)
+		assert.Nil(t, decodeAPIGame(t, body).You)
+		assert.NotContains(t, body, "player_", "other players' IDs must not leak")
+	})
+
+	t.Run("Unknown game is a JSON 404", func(t *testing.T) {
+		resp, body := joiner.postJSON(t, "/api/v1/game/doesnotexist/join", map[string]string{"emoji": "🚀"})
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Game not found"}`, body)
+	})
+}
+
+func TestJSONMoveAPI(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	playerA := newHTTPPlayer(t, server)
+	playerB := newHTTPPlayer(t, server)
+
+	_, body := playerA.postJSON(t, "/api/v1/games", map[string]string{"emoji": "🐱"})
+	gameID := decodeAPIGame(t, body).ID
+	playerB.postJSON(t, "/api/v1/game/"+gameID+"/join", map[string]string{"emoji": "🚀"})
+
+	move := func(player *httpPlayer, row, col int) (*http.Response, string) {
+		return player.postJSON(t, "/api/v1/game/"+gameID+"/move", map[string]int{"row": row, "col": col})
+	}
+
+	resp, body := move(playerA, 0, 0)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	state := decodeAPIGame(t, body)
+	assert.Equal(t, "🐱", state.Board[0][0])
+	assert.Equal(t, "🚀", state.CurrentTurn)
+
+	resp, _ = move(playerA, 1, 1)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode, "not your turn")
+
+	resp, _ = move(playerB, 0, 0)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode, "cell occupied")
+
+	resp, _ = move(playerB, 3, 0)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "off the board")
+
+	resp, _ = newHTTPPlayer(t, server).postJSON(t, "/api/v1/game/"+gameID+"/move", map[string]int{"row": 1, "col": 1})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "outsiders can't move")
+
+	resp, _ = playerB.postJSON(t, "/api/v1/game/"+gameID+"/move", map[string]int{"row": 1})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "col is required")
+
+	// Play out a win for player A along the top row
+	move(playerB, 1, 0)
+	move(playerA, 0, 1)
+	move(playerB, 1, 1)
+	resp, body = move(playerA, 0, 2)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	state = decodeAPIGame(t, body)
+	assert.Equal(t, "finished", state.Status)
+	assert.Equal(t, "🐱", state.Winner)
+
+	resp, _ = move(playerB, 2, 2)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode, "game is over")
+}
+
+func TestJSONEventStream(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+
+	stream := openSSEStream(t, playerB, "/api/v1/game/"+gameID+"/events")
+
+	var initial eventMessageResponse
+	require.NoError(t, json.Unmarshal([]byte(readSSEEvent(t, stream, "initial")), &initial))
+	require.NotNil(t, initial.Game)
+	assert.Equal(t, gameID, initial.Game.ID)
+	require.NotNil(t, initial.Game.You)
+	assert.False(t, initial.Game.You.YourTurn)
+
+	resp, body := playerA.postJSON(t, "/api/v1/game/"+gameID+"/move", map[string]int{"row": 2, "col": 0})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	var move eventMessageResponse
+	require.NoError(t, json.Unmarshal([]byte(readSSEEvent(t, stream, "move")), &move))
+	assert.Equal(t, "move", move.Type)
+	assert.Equal(t, "🐱", move.Game.Board[2][0])
+	assert.True(t, move.Game.You.YourTurn)
+}
@@ -0,0 +1,202 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readSSEEvent reads the next event of the given type from an SSE stream and returns its data
+func readSSEEvent(t *testing.T, reader *bufio.Reader, eventType string) string {
+	current := ""
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: ") && current == eventType:
+			return strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// openSSEStream connects to an SSE endpoint and returns a reader for its events
+func openSSEStream(t *testing.T, player *httpPlayer, path string) *bufio.Reader {
+	return openSSEStreamWithHeader(t, player, path, nil)
+}
+
+// openSSEStreamWithHeader connects to an SSE endpoint with extra request headers
+func openSSEStreamWithHeader(t *testing.T, player *httpPlayer, path string, header http.Header) *bufio.Reader {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, player.baseURL+path, nil)
+	require.NoError(t, err)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := player.client.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	return bufio.NewReader(resp.Body)
+}
+
+func TestLobby(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close) // runs after the stream cleanups below
+
+	visitor := newHTTPPlayer(t, server)
+	stream := openSSEStream(t, visitor, "/api/lobby/events")
+	readSSEEvent(t, stream, "lobby_update") // initial list
+
+	// A created game only shows up once its creator picked an emoji
+	creator := newHTTPPlayer(t, server)
+	resp, _ := creator.get(t, "/new-game")
+	gameID := extractGameID(resp.Request.URL.Path)
+
+	_, page := visitor.get(t, "/lobby")
+	assert.NotContains(t, page, `data-game-id="`+gameID+`"`)
+
+	readSSEEvent(t, stream, "game_created")
+	update := readSSEEvent(t, stream, "lobby_update")
+	assert.NotContains(t, update, `data-game-id="`+gameID+`"`)
+
+	creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🦄"}})
+
+	update = readSSEEvent(t, stream, "lobby_update")
+	assert.Contains(t, update, `data-game-id="`+gameID+`"`)
+	assert.Contains(t, update, `<span class="lobby-creator">🦄</span>`)
+	assert.Contains(t, update, `href="/lobby/join/`+gameID+`"`)
+
+	_, page = visitor.get(t, "/lobby")
+	assert.Contains(t, page, `data-game-id="`+gameID+`"`)
+	assert.Contains(t, page, "3×3 · created just now")
+
+	// Once the opponent joins, the game leaves the lobby
+	joiner := newHTTPPlayer(t, server)
+	joiner.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+
+	update = readSSEEvent(t, stream, "lobby_update")
+	assert.NotContains(t, update, `data-game-id="`+gameID+`"`)
+}
+
+func TestLobbyLifecycleEvents(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	watcher := newHTTPPlayer(t, server)
+	stream := openSSEStream(t, watcher, "/api/lobby/events")
+	readSSEEvent(t, stream, "lobby_update")
+
+	type lobbyEvent struct {
+		GameID  string   `json:"gameId"`
+		Status  string   `json:"status"`
+		Players []string `json:"players"`
+		Winner  string   `json:"winner"`
+	}
+	readLobbyEvent := func(eventType string) lobbyEvent {
+		var event lobbyEvent
+		require.NoError(t, json.Unmarshal([]byte(readSSEEvent(t, stream, eventType)), &event))
+		return event
+	}
+
+	// Private games are never announced
+	private := newHTTPPlayer(t, server)
+	resp, body := private.postJSON(t, "/api/v1/games", map[string]interface{}{
+		"options": map[string]string{"visibility": "private"},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+
+	created := readLobbyEvent("game_created")
+	assert.Equal(t, gameID, created.GameID)
+	assert.Equal(t, "waiting", created.Status)
+	assert.Empty(t, created.Players)
+
+	filled := readLobbyEvent("game_filled")
+	assert.Equal(t, gameID, filled.GameID)
+	assert.Equal(t, []string{"🐱", "🚀"}, filled.Players)
+
+	for _, move := range []struct {
+		player *httpPlayer
+		cell   string
+	}{
+		{playerA, "0/0"}, {playerB, "1/0"},
+		{playerA, "0/1"}, {playerB, "1/1"},
+		{playerA, "0/2"},
+	} {
+		resp, body := move.player.htmxPost(t, "/api/game/"+gameID+"/move/"+move.cell)
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	}
+
+	finished := readLobbyEvent("game_finished")
+	assert.Equal(t, gameID, finished.GameID)
+	assert.Equal(t, "finished", finished.Status)
+	assert.Equal(t, "🐱", finished.Winner)
+}
+
+func TestGameVisibility(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	visitor := newHTTPPlayer(t, server)
+
+	t.Run("Private games are hidden from the lobby", func(t *testing.T) {
+		creator := newHTTPPlayer(t, server)
+		resp, _ := creator.get(t, "/new-game?visibility=private")
+		gameID := extractGameID(resp.Request.URL.Path)
+		_, body := creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+		assert.Contains(t, body, `data-visibility="private"`)
+
+		_, page := visitor.get(t, "/lobby")
+		assert.NotContains(t, page, gameID)
+
+		resp, _ = visitor.get(t, "/lobby/join/"+gameID)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		// The direct link still works
+		resp, _ = visitor.get(t, "/game/"+gameID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "/game/"+gameID+"/select-emoji", resp.Request.URL.Path)
+	})
+
+	t.Run("Creator can toggle visibility while waiting", func(t *testing.T) {
+		creator := newHTTPPlayer(t, server)
+		resp, _ := creator.get(t, "/new-game")
+		gameID := extractGameID(resp.Request.URL.Path)
+		creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+
+		resp, _ = visitor.get(t, "/lobby/join/"+gameID)
+		assert.Equal(t, "/game/"+gameID+"/select-emoji", resp.Request.URL.Path)
+
+		resp, _ = visitor.do(t, http.MethodPost, "/api/game/"+gameID+"/visibility", url.Values{"visibility": {"private"}}, true)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, "only the creator may change visibility")
+
+		resp, fragment := creator.do(t, http.MethodPost, "/api/game/"+gameID+"/visibility", url.Values{"visibility": {"private"}}, true)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, fragment, `data-visibility="private"`)
+
+		_, page := visitor.get(t, "/lobby")
+		assert.NotContains(t, page, gameID)
+	})
+
+	t.Run("Visibility is locked once the game starts", func(t *testing.T) {
+		gameID, playerA, _ := startHTTPGame(t, server)
+		resp, _ := playerA.do(t, http.MethodPost, "/api/game/"+gameID+"/visibility", url.Values{"visibility": {"private"}}, true)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
@@ -0,0 +1,64 @@
+package e2e
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"htmx-go-app/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocaleAwareTimestamps(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	playMoves(t, gameID, playerA, playerB, "0/0", "1/0", "0/1", "1/1", "0/2")
+	gameData := game.GetGame(gameID)
+	joined := gameData.Players[gameData.PlayerOrder[0]].JoinedAt
+	finished := gameData.FinishedAt
+
+	t.Run("The language comes from Accept-Language until the browser reports one", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/history", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept-Language", "de-CH,de;q=0.9,en;q=0.8")
+		resp, err := playerA.client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Contains(t, string(body), joined.UTC().Format("02.01.2006 15:04 MST"), "German date order, in UTC without a time zone")
+		assert.Contains(t, string(body), `<time datetime="`+finished.UTC().Format(time.RFC3339)+`">`)
+	})
+
+	t.Run("The reported time zone and language are used", func(t *testing.T) {
+		resp, _ := playerA.post(t, "/api/preferences/locale", url.Values{"timezone": {"America/New_York"}, "language": {"en-US"}})
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		newYork, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+		_, history := playerA.get(t, "/history")
+		assert.Contains(t, history, "joined "+`<time datetime="`+joined.UTC().Format(time.RFC3339)+`">`+joined.In(newYork).Format("Jan 2, 2006 3:04 PM MST"))
+
+		_, result := playerA.get(t, "/game/"+gameID+"/result")
+		assert.Contains(t, result, finished.In(newYork).Format("Jan 2, 2006 3:04 PM MST"))
+
+		_, other := playerB.get(t, "/game/"+gameID+"/result")
+		assert.Contains(t, other, finished.UTC().Format("2006-01-02 15:04 UTC"), "other players keep their own locale")
+	})
+
+	t.Run("Unknown time zones and malformed languages are rejected", func(t *testing.T) {
+		resp, _ := playerA.post(t, "/api/preferences/locale", url.Values{"timezone": {"Mars/Olympus"}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp, _ = playerA.post(t, "/api/preferences/locale", url.Values{"timezone": {"Local"}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp, _ = playerA.post(t, "/api/preferences/locale", url.Values{"language": {"<script>"}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,87 @@
+package e2e
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setMaintenance toggles maintenance mode through the admin API
+func setMaintenance(t *testing.T, server *httptest.Server, enabled bool) {
+	body := `{"enabled": false}`
+	if enabled {
+		body = `{"enabled": true}`
+	}
+	req, err := http.NewRequest(http.MethodPut, server.URL+"/api/admin/maintenance", bytes.NewBufferString(body))
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", testAdminKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestMaintenanceMode(t *testing.T) {
+	handlers.AdminAPIKey.Set(testAdminKey)
+	t.Cleanup(func() {
+		handlers.AdminAPIKey.Set("")
+		handlers.SetMaintenanceMode(false)
+	})
+
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	// One game in progress and one waiting for an opponent before maintenance starts
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	waitingCreator := newHTTPPlayer(t, server)
+	resp, _ := waitingCreator.get(t, "/new-game")
+	waitingID := extractGameID(resp.Request.URL.Path)
+	waitingCreator.post(t, "/game/"+waitingID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+
+	setMaintenance(t, server, true)
+
+	t.Run("New games are blocked with a friendly page", func(t *testing.T) {
+		resp, body := newHTTPPlayer(t, server).get(t, "/new-game")
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Contains(t, body, "Down for Maintenance")
+
+		resp, body = newHTTPPlayer(t, server).postJSON(t, "/api/v1/games", map[string]string{})
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Contains(t, body, "maintenance")
+	})
+
+	t.Run("Joining is blocked", func(t *testing.T) {
+		resp, _ := newHTTPPlayer(t, server).post(t, "/game/"+waitingID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+		resp, _ = newHTTPPlayer(t, server).get(t, "/quick-match")
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	t.Run("Games in progress can be finished", func(t *testing.T) {
+		for i, move := range []struct {
+			player *httpPlayer
+			cell   string
+		}{
+			{playerA, "0/0"}, {playerB, "1/0"}, {playerA, "0/1"}, {playerB, "1/1"}, {playerA, "0/2"},
+		} {
+			resp, _ := move.player.htmxPost(t, "/api/game/"+gameID+"/move/"+move.cell)
+			require.Equal(t, http.StatusOK, resp.StatusCode, "move %d", i)
+		}
+	})
+
+	t.Run("Turning it off lets new games start again", func(t *testing.T) {
+		setMaintenance(t, server, false)
+		resp, _ := newHTTPPlayer(t, server).get(t, "/new-game")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
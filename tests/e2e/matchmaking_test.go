@@ -0,0 +1,36 @@
+package e2e
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuickMatch(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	playerA := newHTTPPlayer(t, server)
+	playerB := newHTTPPlayer(t, server)
+
+	_, page := playerA.get(t, "/quick-match")
+	assert.Contains(t, page, `sse-connect="/api/matchmaking/events"`)
+	playerB.get(t, "/quick-match")
+
+	streamA := openSSEStream(t, playerA, "/api/matchmaking/events")
+	streamB := openSSEStream(t, playerB, "/api/matchmaking/events")
+
+	redirect := regexp.MustCompile(`data-redirect="(/game/[a-f0-9]+/select-emoji)"`)
+	matchA := redirect.FindStringSubmatch(readSSEEvent(t, streamA, "match_found"))
+	matchB := redirect.FindStringSubmatch(readSSEEvent(t, streamB, "match_found"))
+	require.Len(t, matchA, 2)
+	require.Len(t, matchB, 2)
+	assert.Equal(t, matchA[1], matchB[1], "both players are sent to the same game")
+
+	// The matched game is private, so it never shows up in the lobby
+	_, lobby := playerA.get(t, "/lobby")
+	assert.NotContains(t, lobby, extractGameID(matchA[1]))
+}
@@ -0,0 +1,63 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"htmx-go-app/game"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGameMembership(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	outsider := newHTTPPlayer(t, server)
+	gamePath := "/api/game/" + gameID
+
+	t.Run("outsiders get a forbidden fragment", func(t *testing.T) {
+		for _, request := range []struct{ method, path string }{
+			{http.MethodPost, gamePath + "/move/0/0"},
+			{http.MethodPost, gamePath + "/reset"},
+			{http.MethodPost, gamePath + "/chat"},
+			{http.MethodPost, gamePath + "/nudge"},
+			{http.MethodGet, gamePath + "/fragment/board"},
+			{http.MethodGet, gamePath + "/fragment/status"},
+		} {
+			resp, body := outsider.do(t, request.method, request.path, url.Values{"message": {"hi"}}, true)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode, request.path)
+			assert.Contains(t, body, `data-status="403"`, request.path)
+		}
+
+		gameData := game.GetGame(gameID)
+		assert.Equal(t, 0, gameData.MoveCount)
+		assert.Empty(t, gameData.Chat)
+	})
+
+	t.Run("public fragments stay open", func(t *testing.T) {
+		resp, body := outsider.get(t, gamePath+"/fragment/players")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Regexp(t, `🐱.*vs.*🚀`, body)
+
+		resp, _ = outsider.get(t, gamePath+"/fragment/move-order")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("players are let through", func(t *testing.T) {
+		resp, _ := playerA.htmxPost(t, gamePath+"/move/1/1")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp, _ = playerB.get(t, gamePath+"/fragment/status")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp, _ = playerB.htmxPost(t, gamePath+"/reset")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("missing games are not found", func(t *testing.T) {
+		resp, _ := outsider.htmxPost(t, "/api/game/nope/reset")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
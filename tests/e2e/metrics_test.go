@@ -0,0 +1,61 @@
+package e2e
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrapeMetrics fetches /metrics and returns its samples keyed by name and labels
+func scrapeMetrics(t *testing.T, player *httpPlayer) map[string]float64 {
+	resp, body := player.get(t, "/metrics")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+
+	samples := make(map[string]float64)
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		space := strings.LastIndex(line, " ")
+		value, err := strconv.ParseFloat(line[space+1:], 64)
+		require.NoError(t, err, line)
+		samples[line[:space]] = value
+	}
+	return samples
+}
+
+func TestMetrics(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	before := scrapeMetrics(t, playerA)
+
+	stream := openSSEStream(t, playerB, "/api/game/"+gameID+"/events")
+	readSSEEvent(t, stream, "initial")
+	dialGameWebSocket(t, playerA, gameID)
+	lobby := openSSEStream(t, playerA, "/api/lobby/events")
+	readSSEEvent(t, lobby, "lobby_update")
+
+	resp, body := playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	readSSEEvent(t, stream, "move")
+
+	after := scrapeMetrics(t, playerA)
+	assert.Equal(t, 2.0, after[`tictactoe_game_subscribers{game="`+gameID+`"}`])
+	assert.GreaterOrEqual(t, after[`tictactoe_connections{transport="sse"}`], before[`tictactoe_connections{transport="sse"}`]+2)
+	assert.GreaterOrEqual(t, after[`tictactoe_connections{transport="ws"}`], before[`tictactoe_connections{transport="ws"}`]+1)
+	assert.GreaterOrEqual(t, after["tictactoe_lobby_subscribers"], 1.0)
+	assert.Greater(t, after["tictactoe_events_queued_total"], before["tictactoe_events_queued_total"])
+	assert.Greater(t, after["tictactoe_events_sent_total"], before["tictactoe_events_sent_total"])
+	assert.Contains(t, after, "tictactoe_events_dropped_total")
+}
@@ -5,9 +5,12 @@ import (
 	"html/template"
 	"net/http/httptest"
 	"regexp"
+	"sync"
 	"testing"
 	"time"
 
+	"htmx-go-app/events"
+	"htmx-go-app/game"
 	"htmx-go-app/handlers"
 
 	"github.com/gin-gonic/gin"
@@ -25,36 +28,152 @@ func createTestRender() multitemplate.Renderer {
 		"isHXRequest": func(c *gin.Context) bool {
 			return c.GetHeader("HX-Request") == "true"
 		},
+		"path":         handlers.URLPath,
+		"announcement": handlers.AnnouncementHTML,
+		"brand":        handlers.CurrentBranding,
+		"localTime":    handlers.LocalTime,
 	}
 	
 	// Add templates with base template inheritance using test paths
 	r.AddFromFilesFuncs("home.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/home.html")
 	r.AddFromFilesFuncs("game.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/game.html")
 	r.AddFromFilesFuncs("emoji-selection.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/emoji-selection.html")
-	r.AddFromFilesFuncs("game-full.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/game-full.html")
-	r.AddFromFilesFuncs("404.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/404.html")
+	r.AddFromFilesFuncs("join-state.html", funcMap, "../../templates/layouts/fragment.html", "../../templates/pages/emoji-selection.html")
+	r.AddFromFilesFuncs("error.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/error.html")
+	r.AddFromFilesFuncs("offline.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/offline.html")
+	r.AddFromFilesFuncs("lobby.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/lobby.html")
+	r.AddFromFilesFuncs("quick-match.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/quick-match.html")
+	r.AddFromFilesFuncs("stats.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/stats.html")
+	r.AddFromFilesFuncs("result.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/result.html")
+	r.AddFromFilesFuncs("puzzles.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/puzzles.html")
+	r.AddFromFilesFuncs("sessions.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/sessions.html")
+	r.AddFromFilesFuncs("history.html", funcMap, "../../templates/layouts/base.html", "../../templates/pages/history.html")
 	
 	return r
 }
 
+// hooksOnce wires the game and event hooks the first time a router is set
+// up. They are package globals read by every running server, so assigning
+// them again while an earlier test's server is still going is a data race.
+var hooksOnce sync.Once
+
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
-	r := gin.Default()
+	r := gin.New()
+	r.SetTrustedProxies(nil)
+	r.Use(gin.Logger(), gin.CustomRecovery(handlers.RecoveryHandler))
 
 	r.HTMLRender = createTestRender()
-	r.Static("/static", "../../static")
+	hooksOnce.Do(func() {
+		game.OnGameRemoved = handlers.GameRemoved
+		events.OnPresenceChange = handlers.PresenceChanged
+	})
+	// Every route lives under the configured base path
+	app := r.Group(handlers.BasePath, handlers.RequireAPIRouteWhenHeadless, handlers.InjectLatency, handlers.RecordStreams, handlers.AuthenticateBot, handlers.CheckSession, handlers.RejectBannedPlayers, handlers.LimitRequestBody, handlers.TrackJoinSource)
+
+	// Event streams stay open for good, so they are registered before the
+	// request timeout is added to the group
+	app.GET("/api/game/:id/events", handlers.GameSSEHandler)
+	app.GET("/api/game/:id/ws", handlers.GameWebSocketHandler)
+	app.GET("/api/lobby/events", handlers.LobbySSEHandler)
+	app.GET("/api/matchmaking/events", handlers.BlockDuringMaintenance, handlers.MatchmakingSSEHandler)
+	app.GET("/api/v1/game/:id/events", handlers.APIGameEventsHandler)
+	app.GET("/api/v1/bots/me/turns", handlers.APIBotTurnsHandler)
+	app.Use(handlers.Timeout)
+
+	app.Static("/static", "../../static")
 
 	// Main pages
-	r.GET("/", handlers.HomeHandler)
-	r.GET("/new-game", handlers.NewGameHandler)
-	r.GET("/game/:id", handlers.GamePageHandler)
-	r.GET("/game/:id/select-emoji", handlers.EmojiSelectionHandler)
-	r.POST("/game/:id/select-emoji", handlers.EmojiSelectionSubmitHandler)
+	app.GET("/", handlers.HomeHandler)
+	app.GET("/new-game", handlers.BlockDuringMaintenance, handlers.RateLimit(handlers.CreateGameLimiter), handlers.NewGameHandler)
+	app.POST("/new-game", handlers.BlockDuringMaintenance, handlers.RateLimit(handlers.CreateGameLimiter), handlers.NewGameHandler)
+	app.GET("/join", handlers.BlockDuringMaintenance, handlers.JoinByCodeHandler)
+	app.GET("/join/:token", handlers.BlockDuringMaintenance, handlers.InviteRedeemHandler)
+	app.GET("/continue/:token", handlers.DeviceLinkRedeemHandler)
+	app.GET("/sessions", handlers.SessionsPageHandler)
+	app.POST("/sessions/:session/revoke", handlers.RevokeSessionHandler)
+	app.GET("/history", handlers.HistoryPageHandler)
+	app.GET("/lobby", handlers.LobbyHandler)
+	app.GET("/lobby/join/:id", handlers.BlockDuringMaintenance, handlers.LobbyJoinHandler)
+	app.GET("/quick-match", handlers.BlockDuringMaintenance, handlers.QuickMatchHandler)
+	app.GET("/stats", handlers.StatsPageHandler)
+	app.GET("/puzzles", handlers.PuzzlesHandler)
+	app.GET("/puzzles/:id", handlers.PuzzlePageHandler)
+	app.POST("/puzzles/:id/:row/:col", handlers.PuzzleAnswerHandler)
+	app.GET("/archive/feed.atom", handlers.ArchiveFeedHandler)
+	app.GET("/game/:id", handlers.GamePageHandler)
+	app.GET("/game/:id/select-emoji", handlers.EmojiSelectionHandler)
+	app.GET("/game/:id/join-state", handlers.JoinStateHandler)
+	app.POST("/game/:id/select-emoji", handlers.BlockDuringMaintenance, handlers.EmojiSelectionSubmitHandler)
+	app.GET("/game/:id/qr.png", handlers.QRCodeHandler)
+	app.GET("/game/:id/result", handlers.ResultPageHandler)
+	app.GET("/game/:id/result.svg", handlers.ResultCardSVGHandler)
+
+	// Progressive web app
+	app.GET("/manifest.webmanifest", handlers.ManifestHandler)
+	app.GET("/sw.js", handlers.ServiceWorkerHandler("../../static"))
+	app.GET("/offline", handlers.OfflineHandler)
 
 	// Game API endpoints
-	r.POST("/api/game/:id/move/:row/:col", handlers.GameMoveHandler)
-	r.POST("/api/game/:id/reset", handlers.GameResetHandler)
-	r.GET("/api/game/:id/events", handlers.GameSSEHandler)
+	app.POST("/api/game/:id/move/:row/:col", handlers.RequireGamePlayer, handlers.RateLimit(handlers.MoveLimiter), handlers.GameMoveHandler)
+	app.POST("/api/game/:id/reset", handlers.RequireGamePlayer, handlers.GameResetHandler)
+	app.POST("/api/game/:id/chat", handlers.RequireGamePlayer, handlers.RateLimit(handlers.ChatLimiter), handlers.ChatHandler)
+	app.POST("/api/game/:id/nudge", handlers.RequireGamePlayer, handlers.NudgeHandler)
+	app.POST("/api/game/:id/resume", handlers.RequireGamePlayer, handlers.ResumeGameHandler)
+	app.POST("/api/game/:id/pause", handlers.RequireGamePlayer, handlers.PauseGameHandler)
+	app.POST("/api/game/:id/pause/decline", handlers.RequireGamePlayer, handlers.DeclinePauseHandler)
+	app.POST("/api/game/:id/device-link", handlers.RequireGamePlayer, handlers.CreateDeviceLinkHandler)
+	app.POST("/api/preferences/cues", handlers.ToggleCuesHandler)
+	app.POST("/api/preferences/badges", handlers.TogglePieceBadgesHandler)
+	app.POST("/api/preferences/locale", handlers.SetLocaleHandler)
+	app.DELETE("/api/game/:id", handlers.CancelGameHandler)
+	app.POST("/api/game/:id/visibility", handlers.GameVisibilityHandler)
+	app.POST("/api/game/:id/settings", handlers.GameSettingsHandler)
+	app.POST("/api/game/:id/invites", handlers.CreateInviteHandler)
+	app.GET("/api/game/:id/events/history", handlers.RequirePrivateGamePlayer, handlers.EventHistoryHandler)
+	app.GET("/api/game/:id/fragment/:section", handlers.RequireGamePlayer, handlers.GameFragmentHandler)
+	app.GET("/api/game/:id/board.svg", handlers.BoardSVGHandler)
+	app.GET("/api/game/:id/board.png", handlers.BoardPNGHandler)
+	app.POST("/slack/command", handlers.SlackCommandHandler)
+	app.POST("/telegram/webhook", handlers.TelegramWebhookHandler)
+	app.GET("/telegram/login/:token", handlers.TelegramLoginHandler)
+	app.GET("/metrics", handlers.MetricsHandler)
+	app.GET("/api/version", handlers.VersionHandler)
+
+	// JSON API
+	app.POST("/api/v1/games", handlers.BlockDuringMaintenance, handlers.RateLimit(handlers.CreateGameLimiter), handlers.APICreateGameHandler)
+	app.GET("/api/v1/games", handlers.APIListResultsHandler)
+	app.GET("/api/v1/stats", handlers.APIStatsHandler)
+	app.GET("/api/v1/game/:id", handlers.APIGetGameHandler)
+	app.POST("/api/v1/game/:id/join", handlers.BlockDuringMaintenance, handlers.APIJoinGameHandler)
+	app.POST("/api/v1/game/:id/move", handlers.RateLimit(handlers.MoveLimiter), handlers.APIMoveHandler)
+	app.POST("/api/v1/bots", handlers.BlockDuringMaintenance, handlers.RateLimit(handlers.CreateGameLimiter), handlers.APIRegisterBotHandler)
+	app.GET("/api/v1/bots/me", handlers.APIBotHandler)
+
+	// Admin API
+	admin := app.Group("/api/admin", handlers.RequireAdminKey)
+	admin.GET("/games", handlers.AdminListGamesHandler)
+	admin.DELETE("/games/:id", handlers.AdminDeleteGameHandler)
+	admin.PATCH("/games/:id", handlers.AdminCorrectGameHandler)
+	admin.GET("/bans", handlers.AdminListBansHandler)
+	admin.POST("/players/:id/ban", handlers.AdminBanPlayerHandler)
+	admin.DELETE("/players/:id/ban", handlers.AdminUnbanPlayerHandler)
+	admin.GET("/stats", handlers.AdminStatsHandler)
+	admin.GET("/announcement", handlers.AdminGetAnnouncementHandler)
+	admin.POST("/announcement", handlers.AdminPostAnnouncementHandler)
+	admin.DELETE("/announcement", handlers.AdminClearAnnouncementHandler)
+	admin.GET("/maintenance", handlers.AdminGetMaintenanceHandler)
+	admin.PUT("/maintenance", handlers.AdminSetMaintenanceHandler)
+	admin.POST("/reload", handlers.AdminReloadConfigHandler)
+	admin.GET("/audit", handlers.AdminAuditLogHandler)
+	admin.GET("/bots", handlers.AdminListBotsHandler)
+	admin.DELETE("/bots/:id", handlers.AdminDeleteBotHandler)
+	admin.POST("/scenarios", handlers.AdminCreateScenarioHandler)
+	admin.GET("/backup", handlers.AdminBackupHandler)
+	admin.POST("/restore", handlers.AdminRestoreHandler)
+	admin.GET("/webhooks/deliveries", handlers.WebhookDeliveriesHandler)
+
+	r.NoRoute(handlers.NotFoundHandler)
 
 	return r
 }
@@ -112,12 +231,12 @@ func TestMultiUserGameplay(t *testing.T) {
 		userAPage.WaitForURL("**/game/**/select-emoji")
 
 		// User A selects first emoji
-		err = userAPage.Click(".emoji-option:nth-child(1)")
+		err = userAPage.Click("[data-testid='emoji-option-🐱']")
 		require.NoError(t, err)
 
 		// User A should be in waiting state, get game URL from sharing section
 		userAPage.WaitForSelector(".waiting-state")
-		gameURL, err := userAPage.Locator(".url-input").GetAttribute("value")
+		gameURL, err := userAPage.Locator("[data-testid=game-url]").GetAttribute("value")
 		require.NoError(t, err)
 
 		// Extract game ID from URL
@@ -133,7 +252,7 @@ func TestMultiUserGameplay(t *testing.T) {
 		userBPage.WaitForURL("**/select-emoji")
 
 		// User B selects second emoji
-		err = userBPage.Click(".emoji-option:nth-child(2)")
+		err = userBPage.Click("[data-testid='emoji-option-🚀']")
 		require.NoError(t, err)
 
 		// Both users should enter game simultaneously
@@ -151,9 +270,9 @@ func TestMultiUserGameplay(t *testing.T) {
 		t.Logf("Both users see game title: %s", userATitle)
 
 		// Verify both users see empty game board
-		userACells, err := userAPage.Locator(".game-cell").All()
+		userACells, err := userAPage.Locator("[data-testid^=cell-]").All()
 		require.NoError(t, err)
-		userBCells, err := userBPage.Locator(".game-cell").All()
+		userBCells, err := userBPage.Locator("[data-testid^=cell-]").All()
 		require.NoError(t, err)
 
 		assert.Len(t, userACells, 9, "Should have 9 cells")
@@ -161,15 +280,15 @@ func TestMultiUserGameplay(t *testing.T) {
 
 		// Step 3: User A makes first move (top-left corner)
 		t.Log("User A: Making first move (top-left)...")
-		err = userAPage.Locator(".game-cell").First().Click()
+		err = userAPage.Locator("[data-testid=cell-0-0]").Click()
 		require.NoError(t, err)
 
 		// Wait for HTMX to update DOM for User A
-		_, err = userAPage.WaitForFunction(`document.querySelector('.game-cell').textContent === '🐱'`, nil)
+		_, err = userAPage.WaitForFunction(`document.querySelector('[data-testid=cell-0-0]').textContent === '🐱'`, nil)
 		require.NoError(t, err)
 
 		// Verify User A sees their move
-		userAFirstCell, err := userAPage.Locator(".game-cell").First().TextContent()
+		userAFirstCell, err := userAPage.Locator("[data-testid=cell-0-0]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, "🐱", userAFirstCell)
 		t.Log("User A: Successfully placed emoji in first cell")
@@ -181,7 +300,7 @@ func TestMultiUserGameplay(t *testing.T) {
 		// Give some time for potential real-time sync (there isn't any)
 		time.Sleep(2 * time.Second)
 
-		userBFirstCell, err := userBPage.Locator(".game-cell").First().TextContent()
+		userBFirstCell, err := userBPage.Locator("[data-testid=cell-0-0]").TextContent()
 		require.NoError(t, err)
 
 		// This assertion was expected to FAIL, but now should PASS due to SSE implementation
@@ -193,26 +312,26 @@ func TestMultiUserGameplay(t *testing.T) {
 		_, err = userBPage.Reload()
 		require.NoError(t, err)
 
-		userBFirstCellAfterRefresh, err := userBPage.Locator(".game-cell").First().TextContent()
+		userBFirstCellAfterRefresh, err := userBPage.Locator("[data-testid=cell-0-0]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, "🐱", userBFirstCellAfterRefresh, "User B should see User A's emoji after refresh")
 		t.Log("User B: Can see User A's emoji after page refresh")
 
 		// Step 6: User B makes second move (center cell)
 		t.Log("User B: Making second move (center)...")
-		err = userBPage.Locator(".game-cell").Nth(4).Click() // Center cell
+		err = userBPage.Locator("[data-testid=cell-1-1]").Click() // Center cell
 		require.NoError(t, err)
 
 		// Wait for HTMX to update DOM for User B
-		_, err = userBPage.WaitForFunction(`document.querySelectorAll('.game-cell:not(:empty)').length === 2`, nil)
+		_, err = userBPage.WaitForFunction(`document.querySelectorAll('[data-testid^=cell-]:not(:empty)').length === 2`, nil)
 		require.NoError(t, err)
 
 		// Verify User B sees both moves with different emojis
-		userBCatCells, err := userBPage.Locator(".game-cell").Filter(playwright.LocatorFilterOptions{
+		userBCatCells, err := userBPage.Locator("[data-testid^=cell-]").Filter(playwright.LocatorFilterOptions{
 			HasText: "🐱",
 		}).Count()
 		require.NoError(t, err)
-		userBRocketCells, err := userBPage.Locator(".game-cell").Filter(playwright.LocatorFilterOptions{
+		userBRocketCells, err := userBPage.Locator("[data-testid^=cell-]").Filter(playwright.LocatorFilterOptions{
 			HasText: "🚀",
 		}).Count()
 		require.NoError(t, err)
@@ -224,11 +343,11 @@ func TestMultiUserGameplay(t *testing.T) {
 		t.Log("User A: Checking if User B's move is visible (THIS SHOULD ALSO FAIL)...")
 		time.Sleep(2 * time.Second)
 
-		userACatCells, err := userAPage.Locator(".game-cell").Filter(playwright.LocatorFilterOptions{
+		userACatCells, err := userAPage.Locator("[data-testid^=cell-]").Filter(playwright.LocatorFilterOptions{
 			HasText: "🐱",
 		}).Count()
 		require.NoError(t, err)
-		userARocketCells, err := userAPage.Locator(".game-cell").Filter(playwright.LocatorFilterOptions{
+		userARocketCells, err := userAPage.Locator("[data-testid^=cell-]").Filter(playwright.LocatorFilterOptions{
 			HasText: "🚀",
 		}).Count()
 		require.NoError(t, err)
@@ -243,11 +362,11 @@ func TestMultiUserGameplay(t *testing.T) {
 		_, err = userAPage.Reload()
 		require.NoError(t, err)
 
-		userACatCellsAfterRefresh, err := userAPage.Locator(".game-cell").Filter(playwright.LocatorFilterOptions{
+		userACatCellsAfterRefresh, err := userAPage.Locator("[data-testid^=cell-]").Filter(playwright.LocatorFilterOptions{
 			HasText: "🐱",
 		}).Count()
 		require.NoError(t, err)
-		userARocketCellsAfterRefresh, err := userAPage.Locator(".game-cell").Filter(playwright.LocatorFilterOptions{
+		userARocketCellsAfterRefresh, err := userAPage.Locator("[data-testid^=cell-]").Filter(playwright.LocatorFilterOptions{
 			HasText: "🚀",
 		}).Count()
 		require.NoError(t, err)
@@ -260,14 +379,14 @@ func TestMultiUserGameplay(t *testing.T) {
 		require.NoError(t, err)
 
 		// User A should see empty board immediately
-		_, err = userAPage.WaitForFunction(`document.querySelectorAll('.game-cell:not(:empty)').length === 0`, nil)
+		_, err = userAPage.WaitForFunction(`document.querySelectorAll('[data-testid^=cell-]:not(:empty)').length === 0`, nil)
 		require.NoError(t, err)
 
 		// User B should see reset after refresh (no real-time sync)
 		_, err = userBPage.Reload()
 		require.NoError(t, err)
 
-		nonEmptyUserBCellsAfterReset, err := userBPage.Locator(".game-cell:not(:empty)").Count()
+		nonEmptyUserBCellsAfterReset, err := userBPage.Locator("[data-testid^=cell-]:not(:empty)").Count()
 		require.NoError(t, err)
 		assert.Equal(t, 0, nonEmptyUserBCellsAfterReset, "User B should see empty board after reset and refresh")
 
@@ -317,12 +436,12 @@ func TestMultipleGamesIsolation(t *testing.T) {
 		require.NoError(t, err)
 
 		game1Page.WaitForURL("**/select-emoji")
-		err = game1Page.Click(".emoji-option:nth-child(1)") // 🐱
+		err = game1Page.Click("[data-testid='emoji-option-🐱']") // 🐱
 		require.NoError(t, err)
 
 		// Game 1 will be in waiting state since no second player
 		game1Page.WaitForSelector(".waiting-state")
-		game1URL, err := game1Page.Locator(".url-input").GetAttribute("value")
+		game1URL, err := game1Page.Locator("[data-testid=game-url]").GetAttribute("value")
 		require.NoError(t, err)
 		game1ID := extractGameID(game1URL)
 
@@ -335,12 +454,12 @@ func TestMultipleGamesIsolation(t *testing.T) {
 		require.NoError(t, err)
 
 		game2Page.WaitForURL("**/select-emoji")
-		err = game2Page.Click(".emoji-option:nth-child(2)") // 🚀
+		err = game2Page.Click("[data-testid='emoji-option-🚀']") // 🚀
 		require.NoError(t, err)
 
 		// Game 2 will be in waiting state since no second player
 		game2Page.WaitForSelector(".waiting-state")
-		game2URL, err := game2Page.Locator(".url-input").GetAttribute("value")
+		game2URL, err := game2Page.Locator("[data-testid=game-url]").GetAttribute("value")
 		require.NoError(t, err)
 		game2ID := extractGameID(game2URL)
 
@@ -368,7 +487,7 @@ func TestMultipleGamesIsolation(t *testing.T) {
 		_, err = game1Player2Page.Goto(game1URL)
 		require.NoError(t, err)
 		game1Player2Page.WaitForURL("**/select-emoji")
-		err = game1Player2Page.Click(".emoji-option:nth-child(3)") // 🎨
+		err = game1Player2Page.Click("[data-testid='emoji-option-🎨']") // 🎨
 		require.NoError(t, err)
 
 		// Both should enter Game 1
@@ -380,7 +499,7 @@ func TestMultipleGamesIsolation(t *testing.T) {
 		_, err = game2Player2Page.Goto(game2URL)
 		require.NoError(t, err)
 		game2Player2Page.WaitForURL("**/select-emoji")
-		err = game2Player2Page.Click(".emoji-option:nth-child(4)") // 🌟
+		err = game2Player2Page.Click("[data-testid='emoji-option-🌟']") // 🌟
 		require.NoError(t, err)
 
 		// Both should enter Game 2
@@ -389,51 +508,51 @@ func TestMultipleGamesIsolation(t *testing.T) {
 
 		// Make moves in Game 1
 		t.Log("Making moves in Game 1...")
-		err = game1Page.Locator(".game-cell").First().Click()
+		err = game1Page.Locator("[data-testid=cell-0-0]").Click()
 		require.NoError(t, err)
-		_, err = game1Page.WaitForFunction(`document.querySelector('.game-cell').textContent === '🐱'`, nil)
+		_, err = game1Page.WaitForFunction(`document.querySelector('[data-testid=cell-0-0]').textContent === '🐱'`, nil)
 		require.NoError(t, err)
 
-		err = game1Page.Locator(".game-cell").Nth(4).Click()
+		err = game1Page.Locator("[data-testid=cell-1-1]").Click()
 		require.NoError(t, err)
-		_, err = game1Page.WaitForFunction(`document.querySelectorAll('.game-cell:not(:empty)').length === 2`, nil)
+		_, err = game1Page.WaitForFunction(`document.querySelectorAll('[data-testid^=cell-]:not(:empty)').length === 2`, nil)
 		require.NoError(t, err)
 
 		// Make different moves in Game 2
 		t.Log("Making moves in Game 2...")
-		err = game2Page.Locator(".game-cell").Nth(2).Click() // Top-right
+		err = game2Page.Locator("[data-testid=cell-0-2]").Click() // Top-right
 		require.NoError(t, err)
-		_, err = game2Page.WaitForFunction(`document.querySelector('.game-cell:nth-child(3)').textContent === '🚀'`, nil)
+		_, err = game2Page.WaitForFunction(`document.querySelector('[data-testid=cell-0-2]').textContent === '🚀'`, nil)
 		require.NoError(t, err)
 
-		err = game2Page.Locator(".game-cell").Nth(6).Click() // Bottom-left
+		err = game2Page.Locator("[data-testid=cell-2-0]").Click() // Bottom-left
 		require.NoError(t, err)
-		_, err = game2Page.WaitForFunction(`document.querySelectorAll('.game-cell:not(:empty)').length === 2`, nil)
+		_, err = game2Page.WaitForFunction(`document.querySelectorAll('[data-testid^=cell-]:not(:empty)').length === 2`, nil)
 		require.NoError(t, err)
 
 		// Verify Game 1 state
-		game1CatCount, err := game1Page.Locator(".game-cell").Filter(playwright.LocatorFilterOptions{
+		game1CatCount, err := game1Page.Locator("[data-testid^=cell-]").Filter(playwright.LocatorFilterOptions{
 			HasText: "🐱",
 		}).Count()
 		require.NoError(t, err)
 		assert.Equal(t, 2, game1CatCount)
 
-		game1FirstCell, err := game1Page.Locator(".game-cell").First().TextContent()
+		game1FirstCell, err := game1Page.Locator("[data-testid=cell-0-0]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, "🐱", game1FirstCell, "Game 1 first cell should be 🐱")
 
 		// Verify Game 2 state
-		game2RocketCount, err := game2Page.Locator(".game-cell").Filter(playwright.LocatorFilterOptions{
+		game2RocketCount, err := game2Page.Locator("[data-testid^=cell-]").Filter(playwright.LocatorFilterOptions{
 			HasText: "🚀",
 		}).Count()
 		require.NoError(t, err)
 		assert.Equal(t, 2, game2RocketCount)
 
-		game2FirstCell, err := game2Page.Locator(".game-cell").First().TextContent()
+		game2FirstCell, err := game2Page.Locator("[data-testid=cell-0-0]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, "", game2FirstCell, "Game 2 first cell should be empty")
 
-		game2ThirdCell, err := game2Page.Locator(".game-cell").Nth(2).TextContent()
+		game2ThirdCell, err := game2Page.Locator("[data-testid=cell-0-2]").TextContent()
 		require.NoError(t, err)
 		assert.Equal(t, "🚀", game2ThirdCell, "Game 2 third cell should be 🚀")
 
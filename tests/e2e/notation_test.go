@@ -0,0 +1,67 @@
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"htmx-go-app/engine"
+	"htmx-go-app/game"
+	"htmx-go-app/tttest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveNotation(t *testing.T) {
+	t.Run("Cells", func(t *testing.T) {
+		assert.Equal(t, "a1", at(0, 0).String())
+		assert.Equal(t, "b2", at(1, 1).String())
+		assert.Equal(t, "c3", at(2, 2).String())
+
+		cell, err := engine.ParseCell("c1")
+		require.NoError(t, err)
+		assert.Equal(t, at(0, 2), cell)
+		for _, bad := range []string{"", "d1", "a4", "a0", "b22", "B2"} {
+			_, err := engine.ParseCell(bad)
+			assert.ErrorIs(t, err, engine.ErrInvalidNotation, bad)
+		}
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		moves := []engine.Move{{Seat: 0, Cell: at(1, 1)}, {Seat: 1, Cell: at(0, 0)}, {Seat: 0, Cell: at(2, 2)}}
+		notation := engine.FormatMoves(moves)
+		assert.Equal(t, "1. X b2 2. O a1 3. X c3", notation)
+
+		parsed, err := engine.ParseMoves(notation)
+		require.NoError(t, err)
+		assert.Equal(t, moves, parsed)
+
+		parsed, err = engine.ParseMoves("  ")
+		require.NoError(t, err)
+		assert.Empty(t, parsed)
+	})
+
+	t.Run("Malformed notation", func(t *testing.T) {
+		for _, bad := range []string{"1. X", "2. X b2", "1. Z b2", "1. X z9", "1 X b2"} {
+			_, err := engine.ParseMoves(bad)
+			assert.ErrorIs(t, err, engine.ErrInvalidNotation, bad)
+		}
+	})
+
+	t.Run("Played games record structured moves", func(t *testing.T) {
+		fake := useFakeClock(t)
+		g, a, b := tttest.StartGame(t)
+		require.NoError(t, tttest.Move(t, g, a, "1/1"))
+		fake.Advance(3 * time.Second)
+		require.NoError(t, tttest.Move(t, g, b, "0/0"))
+
+		require.Len(t, g.Moves, 2)
+		assert.Equal(t, at(1, 1), g.Moves[0].Cell)
+		assert.Equal(t, a, g.Moves[0].PlayerID)
+		assert.Equal(t, 1, g.Moves[0].Number)
+		assert.Equal(t, b, g.Moves[1].PlayerID)
+		assert.Equal(t, 2, g.Moves[1].Number)
+		assert.Equal(t, 3*time.Second, g.Moves[1].At.Sub(g.Moves[0].At))
+		assert.Equal(t, "1. X b2 2. O a1", game.Notation(g))
+	})
+}
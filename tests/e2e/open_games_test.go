@@ -0,0 +1,79 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"htmx-go-app/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServerFromIP serves the app as if every request came from ip, so
+// per-IP limits don't see games created by other tests
+func newServerFromIP(t *testing.T, ip string) *httptest.Server {
+	router := setupRouter()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.RemoteAddr = ip + ":40000"
+		router.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenGameLimit(t *testing.T) {
+	game.MaxOpenGamesPerCreator.Set(2)
+	t.Cleanup(func() { game.MaxOpenGamesPerCreator.Set(0) })
+
+	server := newServerFromIP(t, "203.0.113.7")
+	creator := newHTTPPlayer(t, server)
+
+	var gameIDs []string
+	for i := 0; i < 2; i++ {
+		resp, _ := creator.get(t, "/new-game")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		gameIDs = append(gameIDs, extractGameID(resp.Request.URL.Path))
+	}
+
+	resp, body := creator.get(t, "/new-game")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Contains(t, body, "Too many open games")
+
+	// The cap is per IP too, so a fresh cookie doesn't get around it
+	resp, _ = newHTTPPlayer(t, server).get(t, "/new-game")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	// Once a game starts it no longer counts as open
+	opponent := newHTTPPlayer(t, server)
+	creator.post(t, "/game/"+gameIDs[0]+"/select-emoji", url.Values{"emoji": {"🐱"}})
+	opponent.post(t, "/game/"+gameIDs[0]+"/select-emoji", url.Values{"emoji": {"🚀"}})
+
+	resp, _ = creator.get(t, "/new-game")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestUnclaimedGamesAreRemoved(t *testing.T) {
+	fake := useFakeClock(t)
+
+	server := newServerFromIP(t, "203.0.113.8")
+	player := newHTTPPlayer(t, server)
+
+	resp, _ := player.get(t, "/new-game")
+	unclaimed := extractGameID(resp.Request.URL.Path)
+	resp, _ = player.get(t, "/new-game")
+	claimed := extractGameID(resp.Request.URL.Path)
+	player.post(t, "/game/"+claimed+"/select-emoji", url.Values{"emoji": {"🐱"}})
+
+	fake.Advance(game.UnclaimedGameTTL.Get() + time.Second)
+	player.get(t, "/new-game") // creating a game sweeps stale ones
+
+	assert.Nil(t, game.GetGame(unclaimed), "nobody picked an emoji")
+	assert.NotNil(t, game.GetGame(claimed), "the creator is waiting for an opponent")
+
+	resp, _ = player.get(t, "/game/"+unclaimed+"/select-emoji")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
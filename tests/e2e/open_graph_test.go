@@ -0,0 +1,43 @@
+package e2e
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenGraphPreview(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	t.Run("Invite link for a waiting game", func(t *testing.T) {
+		creator := newHTTPPlayer(t, server)
+		resp, _ := creator.get(t, "/new-game")
+		gameID := extractGameID(resp.Request.URL.Path)
+		creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+
+		crawler := newHTTPPlayer(t, server)
+		_, body := crawler.get(t, "/game/"+gameID)
+		assert.Contains(t, body, `<meta property="og:title" content="Join my Tic-Tac-Toe game!">`)
+		assert.Contains(t, body, `<meta property="og:description" content="🐱 · waiting for opponent · pick an emoji and play">`)
+		assert.Contains(t, body, `<meta property="og:image" content="`+server.URL+`/api/game/`+gameID+`/board.png">`)
+		assert.Contains(t, body, `<meta name="twitter:card" content="summary_large_image">`)
+	})
+
+	t.Run("Link for a game in progress", func(t *testing.T) {
+		gameID, _, _ := startHTTPGame(t, server)
+
+		crawler := newHTTPPlayer(t, server)
+		_, body := crawler.get(t, "/game/"+gameID)
+		assert.Contains(t, body, `<meta property="og:title" content="Tic-Tac-Toe: 🐱 vs 🚀 · 🐱 to move">`)
+		assert.Contains(t, body, `<meta property="og:url" content="`+server.URL+`/game/`+gameID+`">`)
+	})
+
+	t.Run("Home page has no game preview", func(t *testing.T) {
+		visitor := newHTTPPlayer(t, server)
+		_, body := visitor.get(t, "/")
+		assert.NotContains(t, body, "og:image")
+	})
+}
@@ -0,0 +1,47 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordProtectedGame(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	creator := newHTTPPlayer(t, server)
+	resp, _ := creator.post(t, "/new-game", url.Values{"password": {"hunter2"}})
+	gameID := extractGameID(resp.Request.URL.Path)
+	require.NotEmpty(t, gameID)
+
+	// The creator is never asked for the password
+	_, body := creator.get(t, "/game/"+gameID+"/select-emoji")
+	assert.NotContains(t, body, `name="password"`)
+	creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+
+	joiner := newHTTPPlayer(t, server)
+	_, body = joiner.get(t, "/game/"+gameID+"/select-emoji")
+	assert.Contains(t, body, `name="password"`)
+
+	t.Run("Wrong password is rejected", func(t *testing.T) {
+		resp, body := joiner.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}, "password": {"nope"}})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, body, "Incorrect password")
+	})
+
+	t.Run("Missing password is rejected", func(t *testing.T) {
+		resp, _ := joiner.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Correct password joins the game", func(t *testing.T) {
+		resp, _ := joiner.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}, "password": {"hunter2"}})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "/game/"+gameID, resp.Request.URL.Path)
+	})
+}
@@ -0,0 +1,75 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseByAgreement(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	t.Run("Both players agree to pause and one resumes from history", func(t *testing.T) {
+		gameID, playerA, playerB := startHTTPGame(t, server)
+		playMoves(t, gameID, playerA, playerB, "0/0")
+		streamA := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+		readSSEEvent(t, streamA, "initial")
+		streamB := openSSEStream(t, playerB, "/api/game/"+gameID+"/events")
+		readSSEEvent(t, streamB, "initial")
+
+		resp, body := playerA.htmxPost(t, "/api/game/"+gameID+"/pause")
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.Contains(t, body, "Waiting for your opponent")
+		assert.Contains(t, readSSEEvent(t, streamB, "pause_request"), "🐱 wants to pause")
+		_, page := playerB.get(t, "/game/"+gameID)
+		assert.Contains(t, page, `data-testid="agree-pause"`)
+
+		resp, _ = playerA.htmxPost(t, "/api/game/"+gameID+"/pause")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode, "asking twice isn't agreeing")
+
+		resp, body = playerB.htmxPost(t, "/api/game/"+gameID+"/pause")
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.Contains(t, readSSEEvent(t, streamA, "game_paused"), "This game is paused.")
+		assert.Equal(t, models.GameStatusPaused, game.GetGame(gameID).Status)
+
+		resp, body = playerB.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotContains(t, body, "🚀", "no moves while paused")
+
+		_, history := playerA.get(t, "/history")
+		assert.Contains(t, history, "⏸️ paused")
+		assert.Contains(t, history, `data-testid="resume-`+gameID+`"`)
+		_, history = newHTTPPlayer(t, server).get(t, "/history")
+		assert.NotContains(t, history, gameID, "only the player's own games are listed")
+
+		resp, _ = playerA.do(t, http.MethodPost, "/api/game/"+gameID+"/resume", url.Values{"redirect": {"true"}}, true)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "/game/"+gameID, resp.Header.Get("HX-Redirect"))
+		assert.Contains(t, readSSEEvent(t, streamB, "game_resumed"), "Your turn!")
+		assert.Equal(t, models.GameStatusActive, game.GetGame(gameID).Status)
+	})
+
+	t.Run("The opponent can decline", func(t *testing.T) {
+		gameID, playerA, playerB := startHTTPGame(t, server)
+		streamA := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+		readSSEEvent(t, streamA, "initial")
+
+		resp, _ := playerB.htmxPost(t, "/api/game/"+gameID+"/pause/decline")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode, "nothing to decline yet")
+
+		playerA.htmxPost(t, "/api/game/"+gameID+"/pause")
+		readSSEEvent(t, streamA, "pause_request")
+		resp, body := playerB.htmxPost(t, "/api/game/"+gameID+"/pause/decline")
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.NotContains(t, readSSEEvent(t, streamA, "pause_request"), "Waiting")
+		assert.Equal(t, models.GameStatusActive, game.GetGame(gameID).Status)
+	})
+}
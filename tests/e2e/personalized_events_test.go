@@ -0,0 +1,40 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalizedGameEvents(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	spectator := newHTTPPlayer(t, server)
+
+	path := "/api/game/" + gameID + "/events"
+	streamA := openSSEStream(t, playerA, path)
+	streamB := openSSEStream(t, playerB, path)
+	streamSpectator := openSSEStream(t, spectator, path)
+	readSSEEvent(t, streamA, "initial")
+	readSSEEvent(t, streamB, "initial")
+	readSSEEvent(t, streamSpectator, "initial")
+
+	resp, body := playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	// Each connection gets the status for its own player along with the move
+	assert.Contains(t, readSSEEvent(t, streamB, "move"), "Your turn!")
+	assert.NotContains(t, readSSEEvent(t, streamA, "move"), "Your turn!")
+	assert.NotContains(t, readSSEEvent(t, streamSpectator, "move"), "Your turn!")
+
+	// Boards are only clickable for the player whose turn it is
+	resp, body = playerB.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Contains(t, readSSEEvent(t, streamA, "move"), "hx-post")
+	assert.NotContains(t, readSSEEvent(t, streamSpectator, "move"), "hx-post")
+}
@@ -0,0 +1,64 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayerCards(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	path := "/api/game/" + gameID + "/events"
+
+	_, page := playerA.get(t, "/game/"+gameID)
+	assert.Contains(t, page, `data-testid="player-card-0"`)
+	assert.Contains(t, page, "⭐ 1000")
+	assert.Regexp(t, `player-card-1.*data-connected="false">offline`, page, "🚀 hasn't opened the game yet")
+
+	streamA := openSSEStream(t, playerA, path)
+	readSSEEvent(t, streamA, "initial")
+
+	t.Run("Connection status follows the opponent", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := playerB.client.Do(req)
+		require.NoError(t, err)
+		readSSEEvent(t, bufio.NewReader(resp.Body), "initial")
+
+		assert.Regexp(t, `player-card-1.*data-connected="true">online`, readSSEEvent(t, streamA, "players_update"))
+
+		cancel()
+		resp.Body.Close()
+		assert.Regexp(t, `player-card-1.*data-connected="false">offline`, readSSEEvent(t, streamA, "players_update"))
+	})
+
+	t.Run("Ratings and streaks change with results", func(t *testing.T) {
+		playMoves(t, gameID, playerA, playerB, "0/0", "1/0", "0/1", "1/1", "0/2")
+		cards := readSSEEvent(t, streamA, "players_update")
+		assert.Regexp(t, `player-card-0.*⭐ 1016.*player-card-1.*⭐ 984`, cards)
+		assert.NotContains(t, cards, "🔥", "one win isn't a streak")
+
+		// 🐱 wins a rematch against the same opponent
+		resp, _ := playerA.get(t, "/new-game")
+		rematchID := extractGameID(resp.Request.URL.Path)
+		playerA.post(t, "/game/"+rematchID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+		playerB.post(t, "/game/"+rematchID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+		playMoves(t, rematchID, playerA, playerB, "0/0", "1/0", "0/1", "1/1", "0/2")
+
+		_, players := playerB.get(t, "/api/game/"+rematchID+"/fragment/players")
+		assert.Regexp(t, `player-card-0.*🔥 2`, players)
+		assert.Contains(t, players, "Rating over 2 finished games")
+	})
+}
@@ -0,0 +1,115 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"htmx-go-app/engine"
+	"htmx-go-app/solver"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPuzzleGenerator(t *testing.T) {
+	puzzles := solver.Puzzles()
+	require.NotEmpty(t, puzzles)
+
+	kinds := map[solver.PuzzleKind]int{}
+	difficulties := map[solver.Difficulty]int{}
+	for i, p := range puzzles {
+		kinds[p.Kind]++
+		difficulties[p.Difficulty]++
+		if i > 0 {
+			assert.LessOrEqual(t, puzzles[i-1].Difficulty, p.Difficulty, "easiest first")
+		}
+	}
+	assert.NotZero(t, kinds[solver.WinInOne])
+	assert.NotZero(t, kinds[solver.DontLoseInTwo])
+	assert.NotZero(t, difficulties[solver.Easy])
+	assert.NotZero(t, difficulties[solver.Medium])
+	assert.NotZero(t, difficulties[solver.Hard])
+
+	t.Run("Win in 1", func(t *testing.T) {
+		// X has a1 and b1, O has a2 and b2, X to move
+		p, err := solver.PuzzleByID("xx.oo....")
+		require.NoError(t, err)
+		assert.Equal(t, solver.WinInOne, p.Kind)
+		assert.Equal(t, []engine.Cell{at(0, 2)}, p.Solutions)
+		assert.True(t, p.Solves(at(0, 2)))
+		assert.False(t, p.Solves(at(1, 2)))
+	})
+
+	t.Run("Don't lose in 2", func(t *testing.T) {
+		// X has a1 and b1, O has b2, O to move
+		p, err := solver.PuzzleByID("xx..o....")
+		require.NoError(t, err)
+		assert.Equal(t, solver.DontLoseInTwo, p.Kind)
+		assert.Equal(t, []engine.Cell{at(0, 2)}, p.Solutions)
+		assert.Equal(t, 1, p.Position().Turn)
+
+		// Solving on the returned position leaves the puzzle as it was
+		require.NoError(t, p.Position().Play(at(0, 2)))
+		assert.Equal(t, "", p.Position().Board.At(at(0, 2)))
+	})
+
+	t.Run("Not a puzzle", func(t *testing.T) {
+		for _, id := range []string{".........", "xxx......", "bogus"} {
+			_, err := solver.PuzzleByID(id)
+			assert.ErrorIs(t, err, solver.ErrInvalidPuzzle, id)
+		}
+	})
+}
+
+func TestPracticePuzzlesPage(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	player := newHTTPPlayer(t, server)
+
+	t.Run("Random puzzle of a difficulty", func(t *testing.T) {
+		resp, body := player.get(t, "/puzzles?difficulty=hard")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		id := strings.TrimPrefix(resp.Request.URL.Path, "/puzzles/")
+		p, err := solver.PuzzleByID(id)
+		require.NoError(t, err)
+		assert.Equal(t, solver.Hard, p.Difficulty)
+		assert.Contains(t, body, `data-testid="puzzle-board"`)
+		assert.Contains(t, body, "(hard)")
+		assert.Contains(t, body, `href="/puzzles?difficulty=hard" class="btn btn-primary" data-testid="next-puzzle"`)
+	})
+
+	t.Run("Answers", func(t *testing.T) {
+		resp, body := player.get(t, "/puzzles/xx.oo....")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, "X to move and win")
+		assert.Contains(t, body, `hx-post="/puzzles/xx.oo..../0/2"`)
+
+		resp, body = player.htmxPost(t, "/puzzles/xx.oo..../0/2")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, "Correct!")
+		assert.NotContains(t, body, "hx-post", "the board is settled once answered")
+
+		_, body = player.htmxPost(t, "/puzzles/xx.oo..../2/2")
+		assert.Contains(t, body, "Not quite.")
+		assert.Contains(t, body, "Solutions: row 1 column 3.")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for path, status := range map[string]int{
+			"/puzzles/.........":     http.StatusNotFound,
+			"/puzzles/xx.oo..../0/0": http.StatusBadRequest,
+			"/puzzles/xx.oo..../3/0": http.StatusBadRequest,
+			"/puzzles/xxxxxxxxx/0/0": http.StatusNotFound,
+		} {
+			method := http.MethodGet
+			if strings.Count(path, "/") > 2 {
+				method = http.MethodPost
+			}
+			resp, _ := player.do(t, method, path, nil, true)
+			assert.Equal(t, status, resp.StatusCode, fmt.Sprint(method, " ", path))
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPWAAssets(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	client := newHTTPPlayer(t, server)
+
+	t.Run("Manifest is served with the manifest content type", func(t *testing.T) {
+		resp, body := client.get(t, "/manifest.webmanifest")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "application/manifest+json")
+
+		var manifest map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(body), &manifest))
+		assert.Equal(t, "standalone", manifest["display"])
+		assert.Equal(t, "/", manifest["start_url"])
+	})
+
+	t.Run("Service worker is served uncached from the root scope", func(t *testing.T) {
+		resp, body := client.get(t, "/sw.js")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "application/javascript")
+		assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+		assert.Equal(t, "/", resp.Header.Get("Service-Worker-Allowed"))
+		assert.Contains(t, body, "/offline")
+	})
+
+	t.Run("Offline shell shows the reconnecting state", func(t *testing.T) {
+		resp, body := client.get(t, "/offline")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, "Reconnecting")
+	})
+}
@@ -0,0 +1,102 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"htmx-go-app/handlers"
+	"htmx-go-app/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// limitFor applies limit to limiter for the rest of the test
+func limitFor(t *testing.T, limiter *ratelimit.Limiter, limit ratelimit.Limit) {
+	limiter.SetLimit(limit)
+	t.Cleanup(func() { limiter.SetLimit(ratelimit.Limit{}) })
+}
+
+func TestRateLimiting(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	t.Run("Game creation is limited per client", func(t *testing.T) {
+		limitFor(t, handlers.CreateGameLimiter, ratelimit.Limit{Burst: 2, Per: time.Minute})
+		player := newHTTPPlayer(t, server)
+
+		for i := 0; i < 2; i++ {
+			resp, _ := player.get(t, "/new-game")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		resp, body := player.get(t, "/new-game")
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Contains(t, body, "Slow Down")
+		assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+
+		// A fresh cookie doesn't help, the IP is limited too
+		resp, _ = newHTTPPlayer(t, server).get(t, "/new-game")
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+		resp, body = newHTTPPlayer(t, server).postJSON(t, "/api/v1/games", map[string]string{})
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Contains(t, body, `"error"`)
+	})
+
+	t.Run("A spoofed X-Forwarded-For doesn't get a fresh bucket", func(t *testing.T) {
+		limitFor(t, handlers.CreateGameLimiter, ratelimit.Limit{Burst: 1, Per: time.Minute})
+
+		newGame := func(forwardedFor string) int {
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/new-game", nil)
+			require.NoError(t, err)
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+		require.Equal(t, http.StatusOK, newGame("203.0.113.1"))
+		assert.Equal(t, http.StatusTooManyRequests, newGame("203.0.113.2"), "no proxy is trusted, so the header is ignored")
+	})
+
+	t.Run("Moves over the limit get an HTMX error fragment", func(t *testing.T) {
+		gameID, playerA, playerB := startHTTPGame(t, server)
+		limitFor(t, handlers.MoveLimiter, ratelimit.Limit{Burst: 2, Per: time.Minute})
+
+		resp, _ := playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp, _ = playerB.htmxPost(t, "/api/game/"+gameID+"/move/1/0")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, body := playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/1")
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "#error-message", resp.Header.Get("HX-Retarget"))
+		assert.Contains(t, body, `data-status="429"`)
+	})
+
+	t.Run("Disabled limits let everything through", func(t *testing.T) {
+		player := newHTTPPlayer(t, server)
+		for i := 0; i < 5; i++ {
+			resp, _ := player.get(t, "/new-game")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+	})
+}
+
+func TestParseRateLimit(t *testing.T) {
+	limit, err := ratelimit.ParseLimit("30/1m")
+	require.NoError(t, err)
+	assert.Equal(t, ratelimit.Limit{Burst: 30, Per: time.Minute}, limit)
+
+	limit, err = ratelimit.ParseLimit("0")
+	require.NoError(t, err)
+	assert.True(t, limit.Disabled())
+
+	for _, invalid := range []string{"30", "x/1m", "30/soon", "30/-1s"} {
+		_, err := ratelimit.ParseLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,92 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// joinSourceCounts fetches the games per join source from the admin stats
+func joinSourceCounts(t *testing.T, server *httptest.Server) map[string]int {
+	t.Helper()
+	resp, body := adminRequest(t, server, testAdminKey, http.MethodGet, "/api/admin/stats")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	var stats struct {
+		JoinSources map[string]int `json:"joinSources"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &stats))
+	return stats.JoinSources
+}
+
+// createWaitingGame creates a public game and picks the creator's emoji
+func createWaitingGame(t *testing.T, server *httptest.Server) (string, *httpPlayer, string) {
+	creator := newHTTPPlayer(t, server)
+	resp, _ := creator.get(t, "/new-game")
+	gameID := extractGameID(resp.Request.URL.Path)
+	require.NotEmpty(t, gameID)
+	_, page := creator.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+	return gameID, creator, page
+}
+
+func TestJoinSourceTracking(t *testing.T) {
+	handlers.AdminAPIKey.Set(testAdminKey)
+	t.Cleanup(func() { handlers.AdminAPIKey.Set("") })
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	t.Run("Share links are tagged", func(t *testing.T) {
+		gameID, _, page := createWaitingGame(t, server)
+		assert.Contains(t, page, `value="`+server.URL+`/game/`+gameID+`?src=link"`)
+		assert.Contains(t, page, `href="mailto:?subject=Play%20tic-tac-toe%20with%20me&amp;body=`)
+		assert.Contains(t, page, url.PathEscape(server.URL+"/game/"+gameID+"?src=email"))
+	})
+
+	for _, tc := range []struct {
+		name   string
+		path   func(gameID string) string
+		source string
+	}{
+		{"QR code", func(gameID string) string { return "/game/" + gameID + "?src=qr" }, "qr"},
+		{"Discord", func(gameID string) string { return "/game/" + gameID + "?src=discord" }, "discord"},
+		{"Lobby", func(gameID string) string { return "/lobby/join/" + gameID }, "lobby"},
+		{"Untagged link", func(gameID string) string { return "/game/" + gameID }, "direct"},
+		{"Unknown tag", func(gameID string) string { return "/game/" + gameID + "?src=spam" }, "direct"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			before := joinSourceCounts(t, server)
+			gameID, _, _ := createWaitingGame(t, server)
+
+			joiner := newHTTPPlayer(t, server)
+			resp, _ := joiner.get(t, tc.path(gameID))
+			require.Equal(t, "/game/"+gameID+"/select-emoji", resp.Request.URL.Path)
+			resp, _ = joiner.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+			require.Equal(t, "/game/"+gameID, resp.Request.URL.Path)
+
+			assert.Equal(t, before[tc.source]+1, joinSourceCounts(t, server)[tc.source])
+		})
+	}
+
+	t.Run("A source counts for one join only", func(t *testing.T) {
+		joiner := newHTTPPlayer(t, server)
+		first, _, _ := createWaitingGame(t, server)
+		joiner.get(t, "/game/"+first+"?src=qr")
+		joiner.post(t, "/game/"+first+"/select-emoji", url.Values{"emoji": {"🚀"}})
+
+		before := joinSourceCounts(t, server)
+		second, _, _ := createWaitingGame(t, server)
+		joiner.get(t, "/game/"+second)
+		joiner.post(t, "/game/"+second+"/select-emoji", url.Values{"emoji": {"🚀"}})
+
+		after := joinSourceCounts(t, server)
+		assert.Equal(t, before["qr"], after["qr"])
+		assert.Equal(t, before["direct"]+1, after["direct"])
+	})
+}
@@ -0,0 +1,85 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"htmx-go-app/config"
+	"htmx-go-app/emojis"
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRestartOnly(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	before, err := config.Load(nil)
+	require.NoError(t, err)
+	after, err := config.Load([]string{"-addr", ":9000", "-chat-cooldown", "5s", "-emojis", "🐙,🦊"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"addr"}, before.RestartOnly(after), "timers and emojis apply without a restart")
+	assert.Empty(t, before.RestartOnly(before))
+
+	toggled, err := config.Load([]string{"-maintenance", "true"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"maintenance_mode"}, before.RestartOnly(toggled),
+		"a reload leaves maintenance mode to the admin API")
+
+	_, err = config.Load([]string{"-emojis", "🐙,🐙"})
+	assert.Error(t, err, "duplicate emojis are rejected")
+}
+
+func TestAdminReloadConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("emojis: [🐙, 🦊, 🐢]\n"), 0o600))
+	t.Setenv("CONFIG_FILE", file)
+
+	// Stands in for main's reload, which applies every runtime setting
+	handlers.ReloadConfig = func() error {
+		cfg, err := config.Load(nil)
+		if err != nil {
+			return err
+		}
+		emojis.Configure(cfg.Emojis)
+		return nil
+	}
+	handlers.AdminAPIKey.Set(testAdminKey)
+	t.Cleanup(func() {
+		handlers.ReloadConfig = nil
+		handlers.AdminAPIKey.Set("")
+		emojis.Configure(emojis.Default)
+	})
+
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	t.Run("Reloaded settings apply to new requests", func(t *testing.T) {
+		resp, body := adminRequest(t, server, testAdminKey, http.MethodPost, "/api/admin/reload")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"reloaded": true}`, body)
+
+		_, page := newHTTPPlayer(t, server).get(t, "/new-game")
+		assert.Contains(t, page, "🐙")
+		assert.NotContains(t, page, "🚀")
+	})
+
+	t.Run("An invalid file keeps the running settings", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(file, []byte("emojis: [🐙]\n"), 0o600))
+
+		resp, body := adminRequest(t, server, testAdminKey, http.MethodPost, "/api/admin/reload")
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Contains(t, body, "at least two emojis")
+		assert.Equal(t, []string{"🐙", "🦊", "🐢"}, emojis.List())
+	})
+
+	t.Run("Requires the admin key", func(t *testing.T) {
+		resp, _ := adminRequest(t, server, "", http.MethodPost, "/api/admin/reload")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,120 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sseEvent struct {
+	ID   uint64
+	Type string
+	Data string
+}
+
+// readNextSSEEvent reads the next complete event, including its id
+func readNextSSEEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	var event sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && event.Type != "":
+			return event
+		case strings.HasPrefix(line, "id: "):
+			event.ID, err = strconv.ParseUint(strings.TrimPrefix(line, "id: "), 10, 64)
+			require.NoError(t, err)
+		case strings.HasPrefix(line, "event: "):
+			event.Type = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.Data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestLastEventIDReplay(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	path := "/api/game/" + gameID + "/events"
+
+	move := func(player *httpPlayer, row, col int) {
+		resp, body := player.htmxPost(t, fmt.Sprintf("/api/game/%s/move/%d/%d", gameID, row, col))
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	}
+
+	// Connect, see one move, then drop the connection
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+	require.NoError(t, err)
+	resp, err := playerB.client.Do(req)
+	require.NoError(t, err)
+	stream := bufio.NewReader(resp.Body)
+
+	initial := readNextSSEEvent(t, stream)
+	assert.Equal(t, "initial", initial.Type)
+	assert.Zero(t, initial.ID, "initial state is not part of the sequence")
+
+	move(playerA, 0, 0)
+	first := readNextSSEEvent(t, stream)
+	require.Equal(t, "move", first.Type)
+	require.NotZero(t, first.ID)
+	assert.Contains(t, first.Data, `hx-swap-oob="true"`, "the status update is coalesced into the move")
+	cancel()
+	resp.Body.Close()
+
+	// Moves made while disconnected
+	move(playerB, 1, 1)
+	move(playerA, 2, 2)
+
+	t.Run("header", func(t *testing.T) {
+		stream := openSSEStreamWithHeader(t, playerB, path, http.Header{
+			"Last-Event-ID": {strconv.FormatUint(first.ID, 10)},
+		})
+
+		var replayed []sseEvent
+		for len(replayed) < 4 {
+			event := readNextSSEEvent(t, stream)
+			require.NotEqual(t, "initial", event.Type, "a resumed stream replays instead of resending state")
+			replayed = append(replayed, event)
+		}
+
+		for i, event := range replayed {
+			assert.Equal(t, first.ID+uint64(i)+1, event.ID, "events are replayed in order without gaps")
+		}
+		// Coalesced updates are buffered as separate board and status events
+		assert.Equal(t, "move", replayed[0].Type)
+		assert.Contains(t, replayed[0].Data, "🚀")
+		assert.Equal(t, "game_status", replayed[1].Type)
+		assert.Equal(t, "move", replayed[2].Type)
+		assert.Equal(t, "game_status", replayed[3].Type)
+
+		// Live events continue the sequence, a coalesced update taking the status event's ID
+		move(playerB, 0, 1)
+		live := readNextSSEEvent(t, stream)
+		assert.Equal(t, "move", live.Type)
+		assert.Equal(t, replayed[3].ID+2, live.ID)
+	})
+
+	t.Run("query parameter", func(t *testing.T) {
+		stream := openSSEStream(t, playerB, path+"?"+url.Values{"lastEventId": {strconv.FormatUint(first.ID, 10)}}.Encode())
+		assert.Equal(t, first.ID+1, readNextSSEEvent(t, stream).ID)
+	})
+
+	t.Run("unknown id falls back to full state", func(t *testing.T) {
+		stream := openSSEStreamWithHeader(t, playerB, path, http.Header{"Last-Event-ID": {"999999"}})
+		assert.Equal(t, "initial", readNextSSEEvent(t, stream).Type)
+	})
+}
@@ -0,0 +1,61 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultCard(t *testing.T) {
+	fake := useFakeClock(t)
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	resultPath := "/game/" + gameID + "/result"
+
+	t.Run("Unfinished games have no card yet", func(t *testing.T) {
+		resp, _ := playerA.get(t, resultPath)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "/game/"+gameID, resp.Request.URL.Path, "redirected to the game")
+
+		resp, _ = playerA.get(t, resultPath+".svg")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	fake.Advance(5 * time.Second)
+	playMoves(t, gameID, playerA, playerB, "0/0", "1/0", "0/1", "1/1")
+	fake.Advance(30 * time.Second)
+	playMoves(t, gameID, playerA, playerB, "0/2")
+
+	t.Run("Page", func(t *testing.T) {
+		visitor := newHTTPPlayer(t, server)
+		resp, page := visitor.get(t, resultPath)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, page, "🐱 wins!")
+		assert.Contains(t, page, "🐱 vs 🚀")
+		assert.Contains(t, page, "5 moves in 35s")
+		assert.Contains(t, page, `data-testid="result-card"`)
+		assert.Contains(t, page, `<meta property="og:url" content="`+server.URL+resultPath+`"`)
+		assert.Contains(t, page, `/api/game/`+gameID+`/board.png`)
+	})
+
+	t.Run("Image", func(t *testing.T) {
+		resp, svg := playerB.get(t, resultPath+".svg")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "image/svg+xml", resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Get("Cache-Control"), "max-age")
+		assert.Contains(t, svg, "🐱 wins!")
+		assert.Contains(t, svg, "vs 🚀")
+		assert.Contains(t, svg, "5 moves in 35s")
+	})
+
+	t.Run("Linked from the finished game", func(t *testing.T) {
+		_, status := playerA.get(t, "/api/game/"+gameID+"/fragment/status")
+		assert.Contains(t, status, `href="`+resultPath+`"`)
+	})
+}
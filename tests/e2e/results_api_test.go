@@ -0,0 +1,100 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type resultsPage struct {
+	Games []struct {
+		ID      string `json:"id"`
+		Result  string `json:"result"`
+		Players []struct {
+			Emoji string `json:"emoji"`
+		} `json:"players"`
+		Winner *struct {
+			Emoji string `json:"emoji"`
+		} `json:"winner"`
+		Moves           int     `json:"moves"`
+		Notation        string  `json:"notation"`
+		DurationSeconds float64 `json:"durationSeconds"`
+	} `json:"games"`
+	Next string `json:"next"`
+}
+
+// playMoves makes alternating moves, A first, given as "row/col"
+func playMoves(t *testing.T, gameID string, playerA, playerB *httpPlayer, moves ...string) {
+	for i, move := range moves {
+		player := playerA
+		if i%2 == 1 {
+			player = playerB
+		}
+		resp, _ := player.htmxPost(t, "/api/game/"+gameID+"/move/"+move)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+}
+
+func TestResultsAPI(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	since := time.Now().UTC().Format(time.RFC3339Nano)
+
+	won, a1, b1 := startHTTPGame(t, server)
+	playMoves(t, won, a1, b1, "0/0", "1/0", "0/1", "1/1", "0/2")
+	drawn, a2, b2 := startHTTPGame(t, server)
+	playMoves(t, drawn, a2, b2, "0/0", "0/1", "0/2", "1/1", "1/0", "1/2", "2/1", "2/0", "2/2")
+	startHTTPGame(t, server) // still going, so not listed
+
+	getPage := func(t *testing.T, query url.Values) resultsPage {
+		resp, body := newHTTPPlayer(t, server).get(t, "/api/v1/games?"+query.Encode())
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		var page resultsPage
+		require.NoError(t, json.Unmarshal([]byte(body), &page))
+		return page
+	}
+
+	t.Run("Finished games are summarized oldest first", func(t *testing.T) {
+		page := getPage(t, url.Values{"status": {"finished"}, "since": {since}})
+		require.Len(t, page.Games, 2)
+		assert.Empty(t, page.Next)
+
+		assert.Equal(t, won, page.Games[0].ID)
+		assert.Equal(t, "win", page.Games[0].Result)
+		require.NotNil(t, page.Games[0].Winner)
+		assert.Equal(t, "🐱", page.Games[0].Winner.Emoji)
+		assert.Equal(t, 5, page.Games[0].Moves)
+		assert.Equal(t, "1. X a1 2. O a2 3. X b1 4. O b2 5. X c1", page.Games[0].Notation)
+		assert.Len(t, page.Games[0].Players, 2)
+		assert.GreaterOrEqual(t, page.Games[0].DurationSeconds, 0.0)
+
+		assert.Equal(t, drawn, page.Games[1].ID)
+		assert.Equal(t, "draw", page.Games[1].Result)
+		assert.Nil(t, page.Games[1].Winner)
+		assert.Equal(t, 9, page.Games[1].Moves)
+	})
+
+	t.Run("Pages follow the cursor", func(t *testing.T) {
+		first := getPage(t, url.Values{"since": {since}, "limit": {"1"}})
+		require.Len(t, first.Games, 1)
+		assert.Equal(t, won, first.Games[0].ID)
+		require.NotEmpty(t, first.Next)
+
+		second := getPage(t, url.Values{"since": {since}, "limit": {"1"}, "cursor": {first.Next}})
+		require.Len(t, second.Games, 1)
+		assert.Equal(t, drawn, second.Games[0].ID)
+	})
+
+	t.Run("Bad parameters are rejected", func(t *testing.T) {
+		for _, query := range []string{"status=active", "since=yesterday", "limit=0", "cursor=%25%25"} {
+			resp, _ := newHTTPPlayer(t, server).get(t, "/api/v1/games?"+query)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+		}
+	})
+}
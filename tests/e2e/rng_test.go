@@ -0,0 +1,35 @@
+package e2e
+
+import (
+	"testing"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/rng"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeededRandomness(t *testing.T) {
+	t.Cleanup(func() { rng.Use(nil) })
+
+	createSeeded := func() *models.Game {
+		rng.Seed(42)
+		created, err := game.CreateGame("seeded-creator", models.GameOptions{Visibility: models.VisibilityPublic})
+		require.NoError(t, err)
+		game.DeleteGame(created.ID)
+		return created
+	}
+	first, second := createSeeded(), createSeeded()
+	assert.Equal(t, first.ID, second.ID, "a seed reproduces game IDs")
+	assert.Equal(t, first.Slug, second.Slug, "a seed reproduces join codes")
+
+	rng.Seed(42)
+	playerA := game.GeneratePlayerID()
+	rng.Seed(42)
+	assert.NotEqual(t, playerA, game.GeneratePlayerID(), "player IDs are session secrets and ignore the seed")
+
+	rng.Use(nil)
+	assert.NotEqual(t, rng.Hex(8), rng.Hex(8))
+}
@@ -0,0 +1,145 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"htmx-go-app/game"
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type scenarioResponse struct {
+	apiGameResponse
+	PlayerIDs []string `json:"playerIds"`
+}
+
+func postScenario(t *testing.T, server *httptest.Server, spec interface{}) (*http.Response, string) {
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/admin/scenarios", bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", testAdminKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+// playerWithID is a client holding an existing player's cookie
+func playerWithID(t *testing.T, server *httptest.Server, playerID string) *httpPlayer {
+	player := newHTTPPlayer(t, server)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	player.client.Jar.SetCookies(serverURL, []*http.Cookie{{Name: "player_id", Value: playerID}})
+	return player
+}
+
+func TestScenarios(t *testing.T) {
+	handlers.AdminAPIKey.Set(testAdminKey)
+	t.Cleanup(func() { handlers.AdminAPIKey.Set("") })
+
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	players := []map[string]string{{"emoji": "🐱", "name": "Alice"}, {"emoji": "🚀"}}
+
+	t.Run("A game can start one move from a win", func(t *testing.T) {
+		resp, body := postScenario(t, server, map[string]interface{}{
+			"players": players,
+			"board":   [][]string{{"🐱", "🐱", ""}, {"🚀", "🚀", ""}, {"", "", ""}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+
+		var created scenarioResponse
+		require.NoError(t, json.Unmarshal([]byte(body), &created))
+		assert.Equal(t, "active", created.Status)
+		assert.Equal(t, 4, created.MoveCount)
+		assert.Equal(t, "🐱", created.CurrentTurn, "the turn follows from the marks")
+		require.Len(t, created.PlayerIDs, 2)
+
+		alice := playerWithID(t, server, created.PlayerIDs[0])
+		resp, _ = alice.htmxPost(t, "/api/game/"+created.ID+"/move/0/2")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		impostor := playerWithID(t, server, created.PlayerIDs[0])
+		resp, _ = impostor.htmxPost(t, "/api/game/"+created.ID+"/reset")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, "a seat is claimed by the first browser only")
+
+		_, body = alice.get(t, "/api/v1/game/"+created.ID)
+		game := decodeAPIGame(t, body)
+		assert.Equal(t, "finished", game.Status)
+		assert.Equal(t, "🐱", game.Winner)
+	})
+
+	t.Run("The turn can be given explicitly", func(t *testing.T) {
+		resp, body := postScenario(t, server, map[string]interface{}{
+			"players": players,
+			"board":   [][]string{{"", "", ""}, {"", "🐱", ""}, {"", "", ""}},
+			"turn":    0,
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+		assert.Equal(t, "🐱", decodeAPIGame(t, body).CurrentTurn)
+	})
+
+	t.Run("A finished board finishes the game", func(t *testing.T) {
+		resp, body := postScenario(t, server, map[string]interface{}{
+			"players": players,
+			"board":   [][]string{{"🚀", "🚀", "🚀"}, {"🐱", "🐱", ""}, {"🐱", "", ""}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+		created := decodeAPIGame(t, body)
+		assert.Equal(t, "finished", created.Status)
+		assert.Equal(t, "🚀", created.Winner)
+	})
+
+	t.Run("Moves in notation are replayed", func(t *testing.T) {
+		resp, body := postScenario(t, server, map[string]interface{}{
+			"players": players,
+			"moves":   "1. X b2 2. O a1 3. X a3 4. O c1 5. X b1 6. O c3 7. X b3",
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+
+		var created scenarioResponse
+		require.NoError(t, json.Unmarshal([]byte(body), &created))
+		assert.Equal(t, "finished", created.Status)
+		assert.Equal(t, "🐱", created.Winner)
+		assert.Equal(t, [3][3]string{{"🚀", "🐱", "🚀"}, {"", "🐱", ""}, {"🐱", "🐱", "🚀"}}, created.Board)
+		assert.Equal(t, "1. X b2 2. O a1 3. X a3 4. O c1 5. X b1 6. O c3 7. X b3", game.Notation(game.GetGame(created.ID)))
+	})
+
+	t.Run("Invalid scenarios are rejected", func(t *testing.T) {
+		for name, spec := range map[string]interface{}{
+			"no players":      map[string]interface{}{"players": []map[string]string{}},
+			"foreign mark":    map[string]interface{}{"players": players, "board": [][]string{{"🐶", "", ""}, {"", "", ""}, {"", "", ""}}},
+			"bad turn":        map[string]interface{}{"players": players, "turn": 2},
+			"lone player":     map[string]interface{}{"players": players[:1], "board": [][]string{{"🐱", "", ""}, {"", "", ""}, {"", "", ""}}},
+			"duplicate emoji": map[string]interface{}{"players": []map[string]string{{"emoji": "🐱"}, {"emoji": "🐱"}}},
+			"bad notation":    map[string]interface{}{"players": players, "moves": "1. X b2 3. O a1"},
+			"illegal move":    map[string]interface{}{"players": players, "moves": "1. X b2 2. O b2"},
+			"out of turn":     map[string]interface{}{"players": players, "moves": "1. O b2"},
+			"moves and board": map[string]interface{}{"players": players, "moves": "1. X b2", "board": [][]string{{"🐱", "", ""}, {"", "", ""}, {"", "", ""}}},
+		} {
+			stored := len(game.Snapshot(nil))
+			resp, body := postScenario(t, server, spec)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "%s: %s", name, body)
+			assert.Len(t, game.Snapshot(nil), stored, "%s: no half set up game is left behind", name)
+		}
+	})
+
+	t.Run("Scenarios need the admin key", func(t *testing.T) {
+		resp, _ := newHTTPPlayer(t, server).postJSON(t, "/api/admin/scenarios", map[string]interface{}{"players": players})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,53 @@
+package e2e
+
+import (
+	"testing"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/tttest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Rule checks that run against the game package through tttest, without
+// a server or browser
+
+func TestServiceWinAndDraw(t *testing.T) {
+	won, a, _ := tttest.StartGame(t)
+	tttest.Play(t, won, "0/0", "1/0", "0/1", "1/1", "0/2")
+	assert.Equal(t, models.GameStatusFinished, won.Status)
+	assert.Equal(t, a, won.Winner)
+	assert.Equal(t, []string{"🐱🐱🐱", "🚀🚀.", "..."}, tttest.Rows(won))
+
+	drawn, _, _ := tttest.StartGame(t)
+	tttest.Play(t, drawn, "0/0", "0/1", "0/2", "1/1", "1/0", "1/2", "2/1", "2/0", "2/2")
+	assert.Equal(t, models.GameStatusDraw, drawn.Status)
+	assert.Empty(t, drawn.Winner)
+	assert.False(t, drawn.FinishedAt.IsZero())
+}
+
+func TestServiceRejectedMoves(t *testing.T) {
+	g, a, b := tttest.StartGame(t)
+
+	assert.ErrorIs(t, tttest.Move(t, g, b, "0/0"), game.ErrNotYourTurn)
+	assert.ErrorIs(t, tttest.Move(t, g, a, "3/0"), game.ErrInvalidCell)
+	assert.ErrorIs(t, tttest.Move(t, g, game.GeneratePlayerID(), "0/0"), game.ErrNotAPlayer)
+
+	tttest.Play(t, g, "1/1")
+	assert.ErrorIs(t, tttest.Move(t, g, b, "1/1"), game.ErrCellOccupied)
+
+	tttest.Play(t, g, "0/0", "2/2", "0/1", "0/2", "2/0", "1/0", "1/2", "2/1")
+	assert.ErrorIs(t, tttest.Move(t, g, a, "0/0"), game.ErrGameNotActive, "no moves after the game ends")
+}
+
+func TestServiceJoining(t *testing.T) {
+	g := tttest.NewGame(t)
+	tttest.Join(t, g, tttest.EmojiA)
+	assert.Equal(t, models.GameStatusWaiting, g.Status)
+
+	assert.ErrorIs(t, game.AddPlayerToGame(g, game.GeneratePlayerID(), tttest.EmojiA, ""), game.ErrEmojiTaken)
+	tttest.Join(t, g, tttest.EmojiB)
+	assert.Equal(t, models.GameStatusActive, g.Status)
+	assert.ErrorIs(t, game.AddPlayerToGame(g, game.GeneratePlayerID(), "🐶", ""), game.ErrGameFull)
+}
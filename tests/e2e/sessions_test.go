@@ -0,0 +1,146 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"htmx-go-app/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	sessionIDPattern      = regexp.MustCompile(`data-testid="session-([0-9a-f]+)"`)
+	currentSessionPattern = regexp.MustCompile(`data-testid="session-([0-9a-f]+)"><span>[^<]*</span> <strong data-testid="current-session">`)
+)
+
+// currentSessionID reads the session of the player's own browser off the
+// devices page
+func currentSessionID(t *testing.T, player *httpPlayer) string {
+	_, page := player.get(t, "/sessions")
+	match := currentSessionPattern.FindStringSubmatch(page)
+	require.NotNil(t, match, page)
+	return match[1]
+}
+
+func TestSessions(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, laptop, playerB := startHTTPGame(t, server)
+	playerID := game.GetGame(gameID).PlayerOrder[0]
+	phone := newHTTPPlayer(t, server)
+	phone.get(t, createDeviceLink(t, laptop, gameID))
+
+	t.Run("Lists the player's devices", func(t *testing.T) {
+		require.Len(t, game.PlayerSessions(playerID), 2)
+
+		resp, page := laptop.get(t, "/sessions")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, sessionIDPattern.FindAllStringSubmatch(page, -1), 2)
+		assert.Contains(t, page, `data-testid="current-session"`)
+
+		_, page = playerB.get(t, "/sessions")
+		assert.Len(t, sessionIDPattern.FindAllStringSubmatch(page, -1), 1, "only your own devices")
+	})
+
+	t.Run("Signing a device out", func(t *testing.T) {
+		phoneSession := currentSessionID(t, phone)
+		resp, _ := playerB.htmxPost(t, "/sessions/"+phoneSession+"/revoke")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, "only your own devices")
+
+		resp, _ = laptop.htmxPost(t, "/sessions/"+phoneSession+"/revoke")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, game.PlayerSessions(playerID), 1)
+
+		// The phone is no longer the player and can't move for them
+		_, page := phone.get(t, "/game/"+gameID)
+		assert.NotContains(t, page, "Your turn!")
+		resp, _ = phone.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, 0, game.GetGame(gameID).MoveCount)
+
+		// While the laptop carries on
+		resp, _ = laptop.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 1, game.GetGame(gameID).MoveCount)
+	})
+
+	t.Run("The current device can't sign itself out", func(t *testing.T) {
+		resp, _ := laptop.htmxPost(t, "/sessions/"+currentSessionID(t, laptop)+"/revoke")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("A copied player cookie isn't enough", func(t *testing.T) {
+		thief := playerWithID(t, server, playerID)
+		resp, _ := thief.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Unknown player IDs don't get a session", func(t *testing.T) {
+		unknownID := game.GeneratePlayerID()
+		unknown := playerWithID(t, server, unknownID)
+		resp, _ := unknown.get(t, "/sessions")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, game.PlayerSessions(unknownID), "they carry on as a new visitor")
+	})
+}
+
+func TestSessionsOutlastRestartsAndIdleness(t *testing.T) {
+	fake := useFakeClock(t)
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, laptop, _ := startHTTPGame(t, server)
+	playerID := game.GetGame(gameID).PlayerOrder[0]
+	phone := newHTTPPlayer(t, server)
+	phone.get(t, createDeviceLink(t, laptop, gameID))
+	resp, _ := laptop.htmxPost(t, "/sessions/"+currentSessionID(t, phone)+"/revoke")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	t.Run("Signed-out devices stay out after a restart", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "games.json")
+		require.NoError(t, game.SaveSnapshot(path))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), playerID)
+		assert.NotContains(t, string(data), sessionToken(t, laptop, server), "only token hashes are saved")
+
+		_, err = game.LoadSnapshot(path)
+		require.NoError(t, err)
+		resp, _ := phone.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		resp, _ = laptop.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "live sessions carry on")
+	})
+
+	t.Run("A seated player's ID stays guarded once their sessions expire", func(t *testing.T) {
+		fake.Advance(game.SessionTTL.Get() + time.Hour)
+		newHTTPPlayer(t, server).get(t, "/new-game") // starts a session, sweeping idle ones
+
+		assert.Empty(t, game.PlayerSessions(playerID))
+		thief := playerWithID(t, server, playerID)
+		resp, _ := thief.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+// sessionToken reads the session cookie a player's browser holds
+func sessionToken(t *testing.T, player *httpPlayer, server *httptest.Server) string {
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	for _, cookie := range player.client.Jar.Cookies(serverURL) {
+		if cookie.Name == "session_id" {
+			return cookie.Value
+		}
+	}
+	t.Fatal("no session cookie")
+	return ""
+}
@@ -0,0 +1,109 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"htmx-go-app/events"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/tttest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownClosesEventStreams(t *testing.T) {
+	server := httptest.NewUnstartedServer(setupRouter())
+	server.Config.RegisterOnShutdown(events.CloseStreams)
+	server.Start()
+	t.Cleanup(server.Close)
+
+	gameID, playerA, _ := startHTTPGame(t, server)
+	stream := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+	readSSEEvent(t, stream, "initial")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, server.Config.Shutdown(ctx), "open streams must not hold up shutdown")
+
+	_, err := io.ReadAll(stream)
+	assert.NoError(t, err, "stream ends cleanly")
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	gameID, playerA, _ := startHTTPGame(t, server)
+	resp, _ := playerA.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	saved := game.GetGame(gameID)
+
+	path := filepath.Join(t.TempDir(), "games.json")
+	require.NoError(t, game.SaveSnapshot(path))
+
+	restored, err := game.LoadSnapshot(path)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, restored, 1)
+
+	loaded := game.GetGame(gameID)
+	require.NotNil(t, loaded)
+	assert.Equal(t, saved.Board, loaded.Board)
+	assert.Equal(t, saved.PlayerOrder, loaded.PlayerOrder)
+	assert.Equal(t, saved.Players[saved.PlayerOrder[0]].Emoji, loaded.Players[loaded.PlayerOrder[0]].Emoji)
+	assert.Same(t, loaded, game.ResolveGameCode(loaded.Slug), "join code still resolves")
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files left behind")
+}
+
+func TestSnapshotVersions(t *testing.T) {
+	g, _, _ := tttest.StartGame(t)
+	tttest.Play(t, g, "1/1")
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "games.json")
+	require.NoError(t, game.SaveSnapshot(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var file struct {
+		Version int `json:"version"`
+	}
+	require.NoError(t, json.Unmarshal(data, &file))
+	assert.Equal(t, game.SnapshotVersion, file.Version)
+
+	t.Run("Unversioned files are version 1", func(t *testing.T) {
+		saved, err := json.Marshal([]*models.Game{g})
+		require.NoError(t, err)
+		legacy := filepath.Join(dir, "legacy.json")
+		require.NoError(t, os.WriteFile(legacy, saved, 0o600))
+
+		restored, err := game.LoadSnapshot(legacy)
+		require.NoError(t, err)
+		assert.Equal(t, 1, restored)
+		assert.Equal(t, g.Board, game.GetGame(g.ID).Board)
+	})
+
+	t.Run("Newer versions are refused", func(t *testing.T) {
+		future := filepath.Join(dir, "future.json")
+		require.NoError(t, os.WriteFile(future, []byte(`{"version": 99, "games": []}`), 0o600))
+
+		_, err := game.LoadSnapshot(future)
+		assert.ErrorIs(t, err, game.ErrUnsupportedSnapshot)
+	})
+}
+
+func TestLoadSnapshotMissingFile(t *testing.T) {
+	restored, err := game.LoadSnapshot(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+	assert.Zero(t, restored)
+}
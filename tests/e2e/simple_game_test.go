@@ -49,11 +49,11 @@ func TestSimpleGameSetup(t *testing.T) {
 		require.NoError(t, err)
 
 		userAPage.WaitForURL("**/select-emoji")
-		err = userAPage.Click(".emoji-option:nth-child(1)") // 🐱
+		err = userAPage.Click("[data-testid='emoji-option-🐱']") // 🐱
 		require.NoError(t, err)
 
 		// Get game URL
-		gameURL, err := userAPage.Locator(".url-input").GetAttribute("value")
+		gameURL, err := userAPage.Locator("[data-testid=game-url]").GetAttribute("value")
 		require.NoError(t, err)
 
 		// User B joins and selects emoji
@@ -61,7 +61,7 @@ func TestSimpleGameSetup(t *testing.T) {
 		require.NoError(t, err)
 
 		userBPage.WaitForURL("**/select-emoji")
-		err = userBPage.Click(".emoji-option:nth-child(2)") // 🚀
+		err = userBPage.Click("[data-testid='emoji-option-🚀']") // 🚀
 		require.NoError(t, err)
 
 		// Both should enter the game
@@ -71,14 +71,14 @@ func TestSimpleGameSetup(t *testing.T) {
 		time.Sleep(500 * time.Millisecond) // Allow page to fully load
 
 		// Check if turn indicator exists
-		turnIndicatorVisible, err := userAPage.Locator(".turn-indicator").IsVisible()
+		turnIndicatorVisible, err := userAPage.Locator("[data-testid=turn-indicator]").IsVisible()
 		if err != nil {
 			t.Logf("Could not check turn indicator visibility: %v", err)
 		} else if !turnIndicatorVisible {
 			t.Logf("Turn indicator not visible")
 		} else {
 			t.Logf("Turn indicator is visible!")
-			turnText, _ := userAPage.Locator(".turn-indicator").TextContent()
+			turnText, _ := userAPage.Locator("[data-testid=turn-indicator]").TextContent()
 			t.Logf("Turn indicator text: %s", turnText)
 		}
 
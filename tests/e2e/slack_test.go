@@ -0,0 +1,182 @@
+package e2e
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"htmx-go-app/game"
+	"htmx-go-app/handlers"
+	"htmx-go-app/models"
+	"htmx-go-app/webhooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type slackReply struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// postSlackCommand sends a slash command signed with secret, as Slack would
+func postSlackCommand(t *testing.T, server *httptest.Server, secret string, form url.Values) (*http.Response, slackReply) {
+	t.Helper()
+	body := form.Encode()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/slack/command", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var reply slackReply
+	if resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	}
+	return resp, reply
+}
+
+func TestSlackCommand(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		results []slackReply
+	)
+	channel := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var reply slackReply
+		_ = json.Unmarshal(body, &reply)
+
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, reply)
+	}))
+	t.Cleanup(channel.Close)
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+	command := url.Values{
+		"command":      {"/tictactoe"},
+		"text":         {"<@U0BOB|bob>"},
+		"user_id":      {"U0ALICE"},
+		"response_url": {channel.URL + "/commands/T0TEAM/1234/s3cret-token"}, // a bearer URL
+	}
+
+	t.Run("disabled without a signing secret", func(t *testing.T) {
+		resp, _ := postSlackCommand(t, server, "", command)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	handlers.SlackSigningSecret.Set("slack-secret")
+	t.Cleanup(func() { handlers.SlackSigningSecret.Set("") })
+
+	t.Run("rejects bad signatures", func(t *testing.T) {
+		resp, _ := postSlackCommand(t, server, "wrong-secret", command)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("explains usage without a user", func(t *testing.T) {
+		resp, reply := postSlackCommand(t, server, "slack-secret", url.Values{"command": {"/tictactoe"}, "text": {"hello"}})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "ephemeral", reply.ResponseType)
+		assert.Equal(t, "Usage: /tictactoe @user", reply.Text)
+	})
+
+	resp, reply := postSlackCommand(t, server, "slack-secret", command)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "in_channel", reply.ResponseType)
+	assert.Contains(t, reply.Text, "<@U0ALICE> challenged <@U0BOB|bob> to tic-tac-toe")
+
+	link := regexp.MustCompile(`<(http[^|]+/game/(\w+))\?src=slack\|join the game>`).FindStringSubmatch(reply.Text)
+	require.NotNil(t, link, reply.Text)
+	gameID := link[2]
+
+	playerA := newHTTPPlayer(t, server)
+	playerB := newHTTPPlayer(t, server)
+	playerA.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🐱"}})
+	playerB.post(t, "/game/"+gameID+"/select-emoji", url.Values{"emoji": {"🚀"}})
+	playMoves(t, gameID, playerA, playerB, "0/0", "1/0", "0/1", "1/1", "0/2")
+	webhooks.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, results, 1, "the result is posted back once")
+	assert.Equal(t, "in_channel", results[0].ResponseType)
+	assert.True(t, strings.HasPrefix(results[0].Text, "*🐱 wins!*\n🐱 vs 🚀 · 5 moves in"), results[0].Text)
+
+	logged := false
+	for _, delivery := range webhooks.Deliveries() {
+		logged = logged || delivery.Target == channel.URL
+		assert.NotContains(t, delivery.Target+delivery.Error, "s3cret", "the response URL stays out of the delivery log")
+	}
+	assert.True(t, logged, "the reply is logged under Slack's host")
+}
+
+func TestSlackChallenges(t *testing.T) {
+	handlers.SlackSigningSecret.Set("slack-secret")
+	t.Cleanup(func() { handlers.SlackSigningSecret.Set("") })
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	challenge := func(t *testing.T, userID string) (string, slackReply) {
+		resp, reply := postSlackCommand(t, server, "slack-secret", url.Values{
+			"command": {"/tictactoe"},
+			"text":    {"<@U0BOB|bob>"},
+			"team_id": {"T0TEAM"},
+			"user_id": {userID},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		link := regexp.MustCompile(`/game/(\w+)\?src=slack`).FindStringSubmatch(reply.Text)
+		if link == nil {
+			return "", reply
+		}
+		return link[1], reply
+	}
+
+	t.Run("challenges outlast the unclaimed game sweep", func(t *testing.T) {
+		gameID, reply := challenge(t, "U0ALICE")
+		require.NotEmpty(t, gameID, reply.Text)
+
+		defaultTTL := game.UnclaimedGameTTL.Get()
+		game.UnclaimedGameTTL.Set(time.Millisecond)
+		t.Cleanup(func() { game.UnclaimedGameTTL.Set(defaultTTL) })
+		time.Sleep(10 * time.Millisecond)
+		_, err := game.CreateGame("", models.GameOptions{Visibility: models.VisibilityPrivate}) // sweeps
+		require.NoError(t, err)
+
+		assert.NotNil(t, game.GetGame(gameID), "the link posted to the channel still works")
+	})
+
+	t.Run("the open games limit is per Slack user", func(t *testing.T) {
+		game.MaxOpenGamesPerCreator.Set(2)
+		t.Cleanup(func() { game.MaxOpenGamesPerCreator.Set(0) })
+
+		for i := 0; i < 2; i++ {
+			gameID, reply := challenge(t, "U0CAROL")
+			require.NotEmpty(t, gameID, reply.Text)
+		}
+		gameID, reply := challenge(t, "U0CAROL")
+		assert.Empty(t, gameID)
+		assert.Equal(t, "ephemeral", reply.ResponseType)
+
+		gameID, reply = challenge(t, "U0DAVE")
+		assert.NotEmpty(t, gameID, "others in the workspace can still challenge: %s", reply.Text)
+	})
+}
@@ -0,0 +1,68 @@
+package e2e
+
+import (
+	"testing"
+
+	"htmx-go-app/engine"
+	"htmx-go-app/solver"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolver(t *testing.T) {
+	newGame := func(t *testing.T, variant engine.Variant, cells ...engine.Cell) *engine.Game {
+		g, err := engine.NewGame("X", "O", variant)
+		require.NoError(t, err)
+		play(t, g, cells...)
+		return g
+	}
+
+	t.Run("Empty board is a draw", func(t *testing.T) {
+		g := newGame(t, engine.Standard)
+		eval := solver.Evaluate(g)
+		assert.Equal(t, solver.Draw, eval.Result)
+		assert.Equal(t, 9, eval.Plies)
+		assert.Len(t, eval.BestMoves, 9)
+		assert.Len(t, g.Board.EmptyCells(), 9, "evaluating leaves the game as it is")
+	})
+
+	t.Run("Takes a win in one", func(t *testing.T) {
+		g := newGame(t, engine.Standard, at(0, 0), at(1, 0), at(0, 1), at(1, 1))
+		eval := solver.Evaluate(g)
+		assert.Equal(t, solver.Win, eval.Result)
+		assert.Equal(t, 1, eval.Plies)
+		assert.Equal(t, []engine.Cell{at(0, 2)}, eval.BestMoves)
+	})
+
+	t.Run("Sees a forced loss", func(t *testing.T) {
+		// X threatens both a3 and c2, O can only block one
+		g := newGame(t, engine.Standard, at(0, 0), at(0, 1), at(1, 1), at(2, 2), at(1, 0))
+		eval := solver.Evaluate(g)
+		assert.Equal(t, solver.Loss, eval.Result)
+		assert.Equal(t, 2, eval.Plies)
+		assert.NotEmpty(t, eval.BestMoves)
+	})
+
+	t.Run("Scores every move", func(t *testing.T) {
+		g := newGame(t, engine.Standard, at(0, 0), at(1, 0), at(0, 1), at(1, 1))
+		scores := solver.Scores(g)
+		assert.Len(t, scores, 5)
+		assert.Equal(t, solver.Evaluation{Result: solver.Win, Plies: 1}, scores[at(0, 2)])
+		assert.Equal(t, solver.Evaluation{Result: solver.Loss, Plies: 2}, scores[at(2, 2)])
+	})
+
+	t.Run("Misere avoids completing a line", func(t *testing.T) {
+		g := newGame(t, engine.Misere, at(0, 0), at(1, 0), at(0, 1), at(2, 2))
+		assert.NotContains(t, solver.BestMoves(g), at(0, 2))
+		assert.Equal(t, solver.Evaluation{Result: solver.Loss, Plies: 1}, solver.Scores(g)[at(0, 2)])
+	})
+
+	t.Run("Finished game has no moves", func(t *testing.T) {
+		g := newGame(t, engine.Standard, at(0, 0), at(1, 0), at(0, 1), at(1, 1), at(0, 2))
+		eval := solver.Evaluate(g)
+		assert.Equal(t, solver.Loss, eval.Result)
+		assert.Equal(t, 0, eval.Plies)
+		assert.Empty(t, eval.BestMoves)
+	})
+}
@@ -0,0 +1,106 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"htmx-go-app/analytics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type statsResponse struct {
+	Stats            analytics.Stats `json:"stats"`
+	MostPopularEmoji string          `json:"mostPopularEmoji"`
+}
+
+func fetchStats(t *testing.T, player *httpPlayer) statsResponse {
+	resp, body := player.get(t, "/api/v1/stats")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var stats statsResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &stats))
+	return stats
+}
+
+func TestGameplayStats(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	visitor := newHTTPPlayer(t, server)
+	before := fetchStats(t, visitor).Stats
+
+	won, a1, b1 := startHTTPGame(t, server)
+	playMoves(t, won, a1, b1, "1/1", "0/0", "0/1", "2/2", "2/1")
+	drawn, a2, b2 := startHTTPGame(t, server)
+	playMoves(t, drawn, a2, b2, "1/1", "0/0", "0/1", "2/1", "1/0", "1/2", "0/2", "2/0", "2/2")
+	active, a3, b3 := startHTTPGame(t, server)
+	playMoves(t, active, a3, b3, "0/0")
+
+	t.Run("JSON", func(t *testing.T) {
+		response := fetchStats(t, visitor)
+		after := response.Stats
+		assert.Equal(t, before.Games+3, after.Games)
+		assert.Equal(t, before.FinishedGames+2, after.FinishedGames)
+		assert.Equal(t, before.Draws+1, after.Draws)
+		assert.Equal(t, before.FirstPlayerWins+1, after.FirstPlayerWins)
+		assert.Equal(t, before.FirstMoves[1][1]+2, after.FirstMoves[1][1])
+		assert.Equal(t, before.FirstMoves[0][0]+1, after.FirstMoves[0][0])
+		assert.Equal(t, before.CellPlays[1][1]+2, after.CellPlays[1][1])
+		assert.Equal(t, before.CellPlays[0][0]+3, after.CellPlays[0][0])
+		assert.Greater(t, after.AverageMoves, 0.0)
+		assert.InDelta(t, float64(after.FirstPlayerWins)/float64(after.FinishedGames), after.FirstPlayerWinRate, 1e-9)
+
+		require.Len(t, after.Openings, 3)
+		center, corner := after.Openings[0], after.Openings[1]
+		assert.Equal(t, analytics.OpeningCenter, center.Opening)
+		assert.Equal(t, before.Openings[0].Games+2, center.Games, "the unfinished game is left out")
+		assert.Equal(t, before.Openings[0].FirstPlayerWins+1, center.FirstPlayerWins)
+		assert.Equal(t, before.Openings[0].Draws+1, center.Draws)
+		assert.Equal(t, before.Openings[1], corner)
+		assert.InDelta(t, float64(center.Draws)/float64(center.Games), center.DrawRate(), 1e-9)
+
+		require.NotEmpty(t, after.Emojis)
+		assert.Equal(t, after.Emojis[0].Emoji, response.MostPopularEmoji)
+		for i := 1; i < len(after.Emojis); i++ {
+			assert.GreaterOrEqual(t, after.Emojis[i-1].Count, after.Emojis[i].Count)
+		}
+	})
+
+	t.Run("Page", func(t *testing.T) {
+		resp, page := visitor.get(t, "/stats")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, page, "Game Stats")
+		assert.Contains(t, page, "Opening moves")
+		assert.Contains(t, page, `data-testid="stats-popular-emoji"`)
+		assert.Contains(t, page, `data-testid="stats-openings"`)
+		assert.Contains(t, page, `data-testid="stats-heatmap"`)
+		assert.Contains(t, page, `data-testid="stats-opening-center"`)
+		assert.Contains(t, page, `data-testid="stats-opening-edge"`)
+	})
+
+	t.Run("Move order of a finished game", func(t *testing.T) {
+		_, fragment := visitor.get(t, "/api/game/"+won+"/fragment/move-order")
+		assert.Contains(t, fragment, `data-testid="move-order"`)
+		assert.Contains(t, fragment, `aria-label="row 2 column 2, 🐱, move 1"`)
+		assert.Contains(t, fragment, `aria-label="row 3 column 2, 🐱, move 5"`)
+		assert.Contains(t, fragment, `aria-label="row 2 column 1, empty"`)
+
+		_, fragment = visitor.get(t, "/api/game/"+active+"/fragment/move-order")
+		assert.Equal(t, `<div id="move-order"></div>`, fragment, "shown once the game is over")
+
+		_, page := a1.get(t, "/game/"+won)
+		assert.Contains(t, page, `data-testid="move-order"`)
+	})
+}
+
+func TestOpeningOf(t *testing.T) {
+	assert.Equal(t, analytics.OpeningCenter, analytics.OpeningOf(1, 1))
+	for _, cell := range [][2]int{{0, 0}, {0, 2}, {2, 0}, {2, 2}} {
+		assert.Equal(t, analytics.OpeningCorner, analytics.OpeningOf(cell[0], cell[1]))
+	}
+	for _, cell := range [][2]int{{0, 1}, {1, 0}, {1, 2}, {2, 1}} {
+		assert.Equal(t, analytics.OpeningEdge, analytics.OpeningOf(cell[0], cell[1]))
+	}
+}
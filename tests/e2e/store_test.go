@@ -0,0 +1,105 @@
+package e2e
+
+import (
+	"sync"
+	"testing"
+
+	"htmx-go-app/engine"
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+	"htmx-go-app/tttest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSnapshot(t *testing.T) {
+	g, playerA, _ := tttest.StartGame(t)
+	tttest.Play(t, g, "1/1")
+
+	var copied *models.Game
+	for _, snapshot := range game.Snapshot(nil) {
+		if snapshot.ID == g.ID {
+			copied = snapshot
+		}
+	}
+	require.NotNil(t, copied)
+	assert.NotSame(t, g, copied)
+	assert.Equal(t, g.Board, copied.Board)
+	assert.Equal(t, g.Players[playerA].Emoji, copied.Players[playerA].Emoji)
+
+	t.Run("Copies don't follow later moves", func(t *testing.T) {
+		tttest.Play(t, g, "0/0")
+		assert.Equal(t, 1, copied.MoveCount)
+		assert.Len(t, copied.Moves, 1)
+		assert.Empty(t, copied.Board[0][0])
+	})
+
+	t.Run("Changing a copy leaves the game alone", func(t *testing.T) {
+		copied.Players[playerA].Emoji = "🦊"
+		copied.PlayerOrder[0] = "someone_else"
+		assert.Equal(t, tttest.EmojiA, g.Players[playerA].Emoji)
+		assert.Equal(t, playerA, g.PlayerOrder[0])
+	})
+
+	t.Run("Filter and order", func(t *testing.T) {
+		only := game.Snapshot(func(candidate *models.Game) bool { return candidate.ID == g.ID })
+		require.Len(t, only, 1)
+		assert.Equal(t, g.ID, only[0].ID)
+	})
+
+	t.Run("Range stops when asked", func(t *testing.T) {
+		tttest.NewGame(t)
+		visited := 0
+		game.Range(func(*models.Game) bool {
+			visited++
+			return false
+		})
+		assert.Equal(t, 1, visited)
+	})
+}
+
+// TestStoreSnapshotDuringMoves copies games over and over while they are
+// played; run with -race to check the copies are taken under the lock
+// moves hold
+func TestStoreSnapshotDuringMoves(t *testing.T) {
+	played := make(map[string]bool)
+	var mu sync.Mutex
+	ours := func(candidate *models.Game) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return played[candidate.ID]
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			for _, copied := range game.Snapshot(ours) {
+				assert.Len(t, copied.Moves, copied.MoveCount, "a copy never sees a move half applied")
+			}
+		}
+	}()
+
+	for range 50 {
+		g, playerA, playerB := tttest.StartGame(t)
+		mu.Lock()
+		played[g.ID] = true
+		mu.Unlock()
+		for i, cell := range []engine.Cell{{Row: 0, Col: 0}, {Row: 1, Col: 1}, {Row: 0, Col: 1}, {Row: 2, Col: 2}, {Row: 0, Col: 2}} {
+			player := []string{playerA, playerB}[i%2]
+			_, err := game.MakeMoveOnce(g, player, cell.Row, cell.Col)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, models.GameStatusFinished, g.Status)
+	}
+	close(done)
+	wg.Wait()
+}
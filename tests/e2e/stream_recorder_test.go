@@ -0,0 +1,55 @@
+package e2e
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"htmx-go-app/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamRecorder(t *testing.T) {
+	dir := t.TempDir()
+	handlers.StreamRecordDir.Set(dir)
+	t.Cleanup(func() { handlers.StreamRecordDir.Set("") })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	logs, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, logs, "plain requests aren't recorded")
+
+	stream := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+	readSSEEvent(t, stream, "initial")
+	playMoves(t, gameID, playerA, playerB, "1/1")
+	readSSEEvent(t, stream, "move")
+
+	conn := dialGameWebSocket(t, playerB, gameID)
+	defer conn.Close()
+	var message eventMessageResponse
+	require.NoError(t, conn.ReadJSON(&message))
+
+	logs, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, logs, 2, "one file per connection")
+
+	var recorded string
+	for _, entry := range logs {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		require.NoError(t, err)
+		recorded += string(data)
+	}
+	assert.Contains(t, recorded, "# GET /api/game/"+gameID+"/events")
+	assert.Contains(t, recorded, "# GET /api/game/"+gameID+"/ws")
+	stamp := `\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d(\.\d+)?Z `
+	assert.Regexp(t, regexp.MustCompile(`(?m)^`+stamp+`event: initial$`), recorded)
+	assert.Regexp(t, regexp.MustCompile(`(?m)^`+stamp+`event: move$`), recorded)
+	assert.Regexp(t, regexp.MustCompile(`(?m)^`+stamp+`\{"type":"initial"`), recorded)
+}
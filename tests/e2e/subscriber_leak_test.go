@@ -0,0 +1,107 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"htmx-go-app/events"
+	"htmx-go-app/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// subscriberCount is the number of subscribers registered for a game
+func subscriberCount(gameID string) int {
+	return events.Snapshot().GameSubscribers[gameID]
+}
+
+// silentContext reports it is done without ever closing Done, as a context
+// implementation with a bug might. Only the sweep can notice it.
+type silentContext struct{ context.Context }
+
+func (silentContext) Err() error { return errors.New("gone") }
+
+func TestSubscriberLifecycle(t *testing.T) {
+	t.Run("Cancelled contexts unregister immediately", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		subscriber := events.CreateGameSubscriber("leak-cancel", "player", ctx)
+		require.Equal(t, 1, subscriberCount("leak-cancel"))
+
+		cancel()
+		require.Eventually(t, func() bool { return subscriberCount("leak-cancel") == 0 }, time.Second, time.Millisecond)
+		_, open := <-subscriber.Channel
+		assert.False(t, open, "the channel is closed")
+
+		assert.NotPanics(t, func() { events.RemoveGameSubscriber(subscriber) }, "removing again is harmless")
+	})
+
+	t.Run("Broadcasts racing cancellation", func(t *testing.T) {
+		var subscribers []*models.GameSubscriber
+		var cancels []context.CancelFunc
+		for i := 0; i < 20; i++ {
+			ctx, cancel := context.WithCancel(context.Background())
+			subscribers = append(subscribers, events.CreateGameSubscriber("leak-race", "player", ctx))
+			cancels = append(cancels, cancel)
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				events.BroadcastGameEvent("leak-race", models.GameEvent{Type: "chat", GameID: "leak-race"})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i, cancel := range cancels {
+				cancel()
+				events.RemoveGameSubscriber(subscribers[i])
+			}
+		}()
+		wg.Wait()
+
+		require.Eventually(t, func() bool { return subscriberCount("leak-race") == 0 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("The sweep removes subscribers that never signalled", func(t *testing.T) {
+		live := events.CreateGameSubscriber("leak-sweep", "player", context.Background())
+		defer events.RemoveGameSubscriber(live)
+		events.CreateGameSubscriber("leak-sweep", "player", silentContext{context.Background()})
+		require.Equal(t, 2, subscriberCount("leak-sweep"))
+
+		assert.Equal(t, 1, events.SweepSubscribers())
+		assert.Equal(t, 1, subscriberCount("leak-sweep"), "live subscribers stay")
+	})
+
+	t.Run("Closed streams leave nothing behind", func(t *testing.T) {
+		server := httptest.NewServer(setupRouter())
+		defer server.Close()
+		gameID, playerA, _ := startHTTPGame(t, server)
+
+		before := runtime.NumGoroutine()
+		for i := 0; i < 10; i++ {
+			ctx, cancel := context.WithCancel(context.Background())
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/game/"+gameID+"/events", nil)
+			require.NoError(t, err)
+			resp, err := playerA.client.Do(req)
+			require.NoError(t, err)
+			_, err = bufio.NewReader(resp.Body).ReadString('\n')
+			require.NoError(t, err)
+			cancel()
+			resp.Body.Close()
+		}
+
+		require.Eventually(t, func() bool { return subscriberCount(gameID) == 0 }, 2*time.Second, 5*time.Millisecond)
+		require.Eventually(t, func() bool { return runtime.NumGoroutine() <= before+2 }, 2*time.Second, 10*time.Millisecond,
+			"goroutines: %d before, %d after", before, runtime.NumGoroutine())
+	})
+}
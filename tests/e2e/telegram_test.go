@@ -0,0 +1,193 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"htmx-go-app/handlers"
+	"htmx-go-app/telegram"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// telegramCall is a Bot API request the bot made
+type telegramCall struct {
+	Method      string
+	ChatID      int64  `json:"chat_id"`
+	Text        string `json:"text"`
+	CallbackID  string `json:"callback_query_id"`
+	ReplyMarkup *struct {
+		InlineKeyboard [][]struct {
+			Text string `json:"text"`
+			Data string `json:"callback_data"`
+			URL  string `json:"url"`
+		} `json:"inline_keyboard"`
+	} `json:"reply_markup"`
+}
+
+// fakeBotAPI records the Bot API calls made with the token
+type fakeBotAPI struct {
+	mu    sync.Mutex
+	calls []telegramCall
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method, ok := strings.CutPrefix(r.URL.Path, "/bottest-token/")
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	call := telegramCall{Method: method}
+	_ = json.Unmarshal(body, &call)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+}
+
+// take returns the calls made since the last take, once they have all arrived
+func (f *fakeBotAPI) take() []telegramCall {
+	telegram.Wait()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := f.calls
+	f.calls = nil
+	return calls
+}
+
+// messagesTo returns the texts of the messages sent to a chat
+func messagesTo(calls []telegramCall, chatID int64) []telegramCall {
+	var messages []telegramCall
+	for _, call := range calls {
+		if call.Method == "sendMessage" && call.ChatID == chatID {
+			messages = append(messages, call)
+		}
+	}
+	return messages
+}
+
+func postTelegramUpdate(t *testing.T, server *httptest.Server, secret string, update interface{}) int {
+	t.Helper()
+	body, err := json.Marshal(update)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/telegram/webhook", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(telegram.SecretTokenHeader, secret)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func telegramMessage(userID int64, name, text string) map[string]interface{} {
+	return map[string]interface{}{"message": map[string]interface{}{
+		"message_id": 1,
+		"from":       map[string]interface{}{"id": userID, "first_name": name},
+		"chat":       map[string]interface{}{"id": userID},
+		"text":       text,
+	}}
+}
+
+func telegramPress(userID int64, data string) map[string]interface{} {
+	return map[string]interface{}{"callback_query": map[string]interface{}{
+		"id":      "press-" + data,
+		"from":    map[string]interface{}{"id": userID, "first_name": "x"},
+		"message": map[string]interface{}{"message_id": 7, "chat": map[string]interface{}{"id": userID}},
+		"data":    data,
+	}}
+}
+
+func TestTelegramBot(t *testing.T) {
+	botAPI := &fakeBotAPI{}
+	api := httptest.NewServer(botAPI)
+	t.Cleanup(api.Close)
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	const alice, bob = 1001, 1002
+	t.Run("disabled without a bot token", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, postTelegramUpdate(t, server, "", telegramMessage(alice, "Alice", "/new")))
+	})
+
+	originalAPIURL := telegram.APIURL
+	telegram.APIURL = api.URL
+	telegram.Configure("test-token")
+	handlers.TelegramWebhookSecret.Set("hook-secret")
+	handlers.PublicBaseURL = server.URL
+	t.Cleanup(func() {
+		telegram.Configure("")
+		telegram.APIURL = originalAPIURL
+		handlers.TelegramWebhookSecret.Set("")
+		handlers.PublicBaseURL = ""
+	})
+
+	t.Run("rejects a wrong secret token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, postTelegramUpdate(t, server, "nope", telegramMessage(alice, "Alice", "/new")))
+	})
+
+	require.Equal(t, http.StatusOK, postTelegramUpdate(t, server, "hook-secret", telegramMessage(alice, "Alice", "/new")))
+	created := messagesTo(botAPI.take(), alice)
+	require.Len(t, created, 1)
+	code := regexp.MustCompile(`/join (\S+)`).FindStringSubmatch(created[0].Text)
+	require.NotNil(t, code, created[0].Text)
+
+	require.Equal(t, http.StatusOK, postTelegramUpdate(t, server, "hook-secret", telegramMessage(bob, "Bob", "/join "+code[1])))
+	turn := messagesTo(botAPI.take(), alice)
+	require.Len(t, turn, 1, "Alice moves first")
+	assert.Equal(t, "Your turn! 🐱 Alice vs 🚀 Bob", turn[0].Text)
+	require.NotNil(t, turn[0].ReplyMarkup)
+	keyboard := turn[0].ReplyMarkup.InlineKeyboard
+	require.Len(t, keyboard, 4, "three rows of cells and a link to the web")
+	gameID := strings.Split(keyboard[0][0].Data, ":")[1]
+
+	t.Run("rejects moves out of turn", func(t *testing.T) {
+		postTelegramUpdate(t, server, "hook-secret", telegramPress(bob, "move:"+gameID+":1:1"))
+		calls := botAPI.take()
+		require.Len(t, calls, 1)
+		assert.Equal(t, "answerCallbackQuery", calls[0].Method)
+		assert.Equal(t, "Not your turn.", calls[0].Text)
+	})
+
+	for i, cell := range []string{"0:0", "1:0", "0:1", "1:1"} {
+		player, opponent := int64(alice), int64(bob)
+		if i%2 == 1 {
+			player, opponent = bob, alice
+		}
+		postTelegramUpdate(t, server, "hook-secret", telegramPress(player, "move:"+gameID+":"+cell))
+		calls := botAPI.take()
+		require.Len(t, messagesTo(calls, opponent), 1, "the opponent is told it's their turn")
+	}
+
+	t.Run("plays on the web through the sign-in link", func(t *testing.T) {
+		aliceWeb := newHTTPPlayer(t, server)
+		link, err := url.Parse(keyboard[3][0].URL)
+		require.NoError(t, err)
+		resp, _ := aliceWeb.get(t, link.RequestURI())
+		require.Equal(t, "/game/"+gameID, resp.Request.URL.Path)
+
+		_, body := aliceWeb.get(t, "/api/v1/game/"+gameID)
+		game := decodeAPIGame(t, body)
+		require.NotNil(t, game.You)
+		assert.True(t, game.You.YourTurn)
+
+		resp, body = aliceWeb.postJSON(t, "/api/v1/game/"+gameID+"/move", map[string]int{"row": 0, "col": 2})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	})
+
+	calls := botAPI.take()
+	require.Len(t, messagesTo(calls, alice), 1)
+	assert.Equal(t, "You win! 🏆 🐱 Alice vs 🚀 Bob", messagesTo(calls, alice)[0].Text)
+	require.Len(t, messagesTo(calls, bob), 1)
+	assert.Equal(t, "🐱 Alice wins. 🐱 Alice vs 🚀 Bob", messagesTo(calls, bob)[0].Text)
+}
@@ -0,0 +1,146 @@
+package e2e
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"htmx-go-app/handlers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestBodyLimit(t *testing.T) {
+	handlers.MaxRequestBodySize.Set(1024)
+	t.Cleanup(func() { handlers.MaxRequestBodySize.Set(0) })
+
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	gameID, playerA, _ := startHTTPGame(t, server)
+
+	t.Run("Oversized form posts get an error page", func(t *testing.T) {
+		resp, body := playerA.post(t, "/api/game/"+gameID+"/chat", url.Values{"message": {strings.Repeat("a", 2048)}})
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		assert.Contains(t, body, "Too Large")
+	})
+
+	t.Run("Oversized JSON requests get a JSON error", func(t *testing.T) {
+		resp, body := playerA.postJSON(t, "/api/v1/games", map[string]string{"padding": strings.Repeat("a", 2048)})
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		assert.Contains(t, body, `"error"`)
+	})
+
+	t.Run("Small requests still work", func(t *testing.T) {
+		resp, _ := playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+	t.Run("Oversized WebSocket messages close the socket", func(t *testing.T) {
+		conn := dialGameWebSocket(t, playerA, gameID)
+		readWSMessage(t, conn, "initial")
+		require.NoError(t, conn.WriteJSON(map[string]string{"type": strings.Repeat("a", 2048)}))
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				assert.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "closed with %v", err)
+				break
+			}
+		}
+	})
+}
+
+func TestRequestTimeout(t *testing.T) {
+	handlers.RequestTimeout.Set(200 * time.Millisecond)
+	t.Cleanup(func() { handlers.RequestTimeout.Set(0) })
+
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	gameID, playerA, playerB := startHTTPGame(t, server)
+
+	t.Run("Clients sending their body too slowly are cut off", func(t *testing.T) {
+		body, writer := io.Pipe()
+		defer writer.Close()
+		go writer.Write([]byte("message=hel")) // and never the rest
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/api/game/"+gameID+"/chat", body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			resp, err := playerA.client.Do(req)
+			if err == nil {
+				assert.NotEqual(t, http.StatusNoContent, resp.StatusCode)
+				resp.Body.Close()
+			}
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(3 * time.Second):
+			t.Fatal("the slow request was never ended")
+		}
+	})
+
+	t.Run("Slow handlers get a timeout response", func(t *testing.T) {
+		released := make(chan struct{})
+		slow := func(c *gin.Context) {
+			<-released
+			c.String(http.StatusOK, "too late")
+		}
+		r := gin.New()
+		r.Use(handlers.Timeout)
+		r.GET("/slow", slow)
+		r.GET("/api/v1/slow", slow)
+		r.GET("/quick", func(c *gin.Context) {
+			c.Header("X-Quick", "yes")
+			c.String(http.StatusCreated, "in time")
+		})
+		slowServer := httptest.NewServer(r)
+		defer slowServer.Close()
+		defer close(released) // before Close, which waits for the handlers
+
+		start := time.Now()
+		resp, err := http.Get(slowServer.URL + "/slow")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Contains(t, string(body), "timed out")
+		assert.Less(t, time.Since(start), 2*time.Second, "answered without waiting for the handler")
+
+		resp, err = http.Get(slowServer.URL + "/api/v1/slow")
+		require.NoError(t, err)
+		body, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.JSONEq(t, `{"error":"Request timed out"}`, string(body))
+
+		resp, err = http.Get(slowServer.URL + "/quick")
+		require.NoError(t, err)
+		body, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "yes", resp.Header.Get("X-Quick"))
+		assert.Equal(t, "in time", string(body))
+	})
+
+	t.Run("Event streams outlive the timeout", func(t *testing.T) {
+		stream := openSSEStream(t, playerB, "/api/game/"+gameID+"/events")
+		readSSEEvent(t, stream, "initial")
+
+		time.Sleep(3 * handlers.RequestTimeout.Get())
+
+		resp, _ := playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		readSSEEvent(t, stream, "move")
+	})
+}
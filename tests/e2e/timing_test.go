@@ -0,0 +1,83 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"htmx-go-app/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveTiming(t *testing.T) {
+	fake := useFakeClock(t)
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	since := clock.Now().UTC().Format(time.RFC3339)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	// 🐱 thinks 2s, 4s and 6s; 🚀 thinks 10s and 20s
+	for i, step := range []struct {
+		think time.Duration
+		move  string
+	}{{2 * time.Second, "0/0"}, {10 * time.Second, "1/0"}, {4 * time.Second, "0/1"}, {20 * time.Second, "1/1"}, {6 * time.Second, "0/2"}} {
+		fake.Advance(step.think)
+		player := playerA
+		if i%2 == 1 {
+			player = playerB
+		}
+		resp, _ := player.htmxPost(t, "/api/game/"+gameID+"/move/"+step.move)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	t.Run("Game JSON", func(t *testing.T) {
+		_, body := playerA.get(t, "/api/v1/game/"+gameID)
+		finished := decodeAPIGame(t, body)
+		assert.Equal(t, "finished", finished.Status)
+		assert.Equal(t, 42.0, finished.Duration)
+		assert.Equal(t, 4.0, finished.Players[0].ThinkSeconds)
+		assert.Equal(t, 15.0, finished.Players[1].ThinkSeconds)
+	})
+
+	t.Run("Results listing", func(t *testing.T) {
+		_, body := playerA.get(t, "/api/v1/games?since="+since)
+		var page struct {
+			Games []struct {
+				ID              string              `json:"id"`
+				DurationSeconds float64             `json:"durationSeconds"`
+				Players         []apiPlayerResponse `json:"players"`
+			} `json:"games"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &page))
+		found := false
+		for _, result := range page.Games {
+			if result.ID == gameID {
+				found = true
+				assert.Equal(t, 42.0, result.DurationSeconds)
+				assert.Equal(t, 4.0, result.Players[0].ThinkSeconds)
+			}
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("Post-game summary", func(t *testing.T) {
+		_, status := playerB.get(t, "/api/game/"+gameID+"/fragment/status")
+		assert.Contains(t, status, `data-testid="game-summary"`)
+		assert.Contains(t, status, "Game took 42s · 🐱 thought 4s per move · 🚀 thought 15s per move")
+	})
+
+	t.Run("Unfinished games have no duration", func(t *testing.T) {
+		activeID, activeA, activeB := startHTTPGame(t, server)
+		fake.Advance(3 * time.Second)
+		playMoves(t, activeID, activeA, activeB, "1/1")
+		_, body := activeA.get(t, "/api/v1/game/"+activeID)
+		active := decodeAPIGame(t, body)
+		assert.Zero(t, active.Duration)
+		assert.Equal(t, 3.0, active.Players[0].ThinkSeconds)
+		assert.Zero(t, active.Players[1].ThinkSeconds, "🚀 hasn't moved")
+	})
+}
@@ -0,0 +1,63 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"htmx-go-app/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTurnReminders(t *testing.T) {
+	fake := useFakeClock(t)
+	previous := game.TurnReminderDelay.Get()
+	game.TurnReminderDelay.Set(time.Minute)
+	t.Cleanup(func() { game.TurnReminderDelay.Set(previous) })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	path := "/api/game/" + gameID + "/events"
+
+	t.Run("idle player is reminded", func(t *testing.T) {
+		streamA := openSSEStream(t, playerA, path)
+		streamB := openSSEStream(t, playerB, path)
+		readSSEEvent(t, streamA, "initial")
+		readSSEEvent(t, streamB, "initial")
+
+		fake.Advance(time.Minute)
+		assert.Contains(t, readSSEEvent(t, streamA, "turn_reminder"), "It's your turn!")
+		assert.NotContains(t, readSSEEvent(t, streamB, "turn_reminder"), "data-active")
+	})
+
+	t.Run("waiting player can nudge once per turn", func(t *testing.T) {
+		game.TurnReminderDelay.Set(0)
+		resp, body := playerA.htmxPost(t, "/api/game/"+gameID+"/move/0/0")
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+		streamB := openSSEStream(t, playerB, path)
+		readSSEEvent(t, streamB, "initial")
+
+		resp, _ = playerB.htmxPost(t, "/api/game/"+gameID+"/nudge")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode, "can't nudge on your own turn")
+
+		resp, _ = playerA.htmxPost(t, "/api/game/"+gameID+"/nudge")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Contains(t, readSSEEvent(t, streamB, "turn_reminder"), "Your opponent nudged you")
+
+		resp, body = playerA.htmxPost(t, "/api/game/"+gameID+"/nudge")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Contains(t, body, "already nudged")
+
+		// A new turn allows a new nudge
+		resp, _ = playerB.htmxPost(t, "/api/game/"+gameID+"/move/1/1")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp, _ = playerB.htmxPost(t, "/api/game/"+gameID+"/nudge")
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+}
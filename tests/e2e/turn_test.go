@@ -51,7 +51,7 @@ func TestTurnAlternation(t *testing.T) {
 		time.Sleep(500 * time.Millisecond)
 
 		// Verify Player 1 (🐱) turn indicator is shown
-		turnIndicator, err := userAPage.Locator(".turn-indicator").TextContent()
+		turnIndicator, err := userAPage.Locator("[data-testid=turn-indicator]").TextContent()
 		require.NoError(t, err)
 		
 		// Clean up whitespace for comparison
@@ -66,11 +66,11 @@ func TestTurnAlternation(t *testing.T) {
 
 		// Player 1 makes first move (top-left)
 		t.Log("Player 1 making move...")
-		err = userAPage.Locator(".game-cell").First().Click()
+		err = userAPage.Locator("[data-testid=cell-0-0]").Click()
 		require.NoError(t, err)
 
 		// Wait for move to process
-		_, err = userAPage.WaitForFunction(`document.querySelector('.game-cell').textContent === '🐱'`, nil)
+		_, err = userAPage.WaitForFunction(`document.querySelector('[data-testid=cell-0-0]').textContent === '🐱'`, nil)
 		require.NoError(t, err)
 		t.Log("Player 1 move completed")
 
@@ -78,8 +78,8 @@ func TestTurnAlternation(t *testing.T) {
 		time.Sleep(1000 * time.Millisecond)
 
 		// Check turn indicator on both pages
-		turnIndicatorA, _ := userAPage.Locator(".turn-indicator").TextContent()
-		turnIndicatorB, _ := userBPage.Locator(".turn-indicator").TextContent()
+		turnIndicatorA, _ := userAPage.Locator("[data-testid=turn-indicator]").TextContent()
+		turnIndicatorB, _ := userBPage.Locator("[data-testid=turn-indicator]").TextContent()
 		
 		// Clean up whitespace
 		turnIndicatorA = strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(turnIndicatorA, "\n", " "), "\t", " "))
@@ -105,12 +105,12 @@ func TestTurnAlternation(t *testing.T) {
 
 		// Test that Player 1 cannot move again immediately (turn enforcement)
 		t.Log("Testing turn enforcement...")
-		err = userAPage.Locator(".game-cell").Nth(1).Click()
+		err = userAPage.Locator("[data-testid=cell-0-1]").Click()
 		require.NoError(t, err)
 
 		// Wait and check that the second cell is still empty
 		time.Sleep(500 * time.Millisecond)
-		secondCellContent, _ := userAPage.Locator(".game-cell").Nth(1).TextContent()
+		secondCellContent, _ := userAPage.Locator("[data-testid=cell-0-1]").TextContent()
 		t.Logf("Second cell content after Player 1's invalid move: '%s'", secondCellContent)
 		
 		if secondCellContent == "" {
@@ -121,11 +121,11 @@ func TestTurnAlternation(t *testing.T) {
 
 		// Player 2 makes valid move
 		t.Log("Player 2 making move...")
-		err = userBPage.Locator(".game-cell").Nth(4).Click() // Center cell
+		err = userBPage.Locator("[data-testid=cell-1-1]").Click() // Center cell
 		require.NoError(t, err)
 
 		// Wait for move to process
-		_, err = userBPage.WaitForFunction(`document.querySelectorAll('.game-cell')[4].textContent === '🚀'`, nil)
+		_, err = userBPage.WaitForFunction(`document.querySelector('[data-testid=cell-1-1]').textContent === '🚀'`, nil)
 		require.NoError(t, err)
 		t.Log("Player 2 move completed")
 
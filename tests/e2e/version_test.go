@@ -0,0 +1,48 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"htmx-go-app/buildinfo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionEndpoint(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	client := newHTTPPlayer(t, server)
+
+	t.Run("Unstamped builds report dev", func(t *testing.T) {
+		resp, body := client.get(t, "/api/version")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var info buildinfo.Info
+		require.NoError(t, json.Unmarshal([]byte(body), &info))
+		assert.Equal(t, "dev", info.Version)
+		assert.NotEmpty(t, info.GoVersion)
+	})
+
+	t.Run("Link-time values are reported", func(t *testing.T) {
+		buildinfo.Version = "v1.2.0"
+		buildinfo.Commit = "3f2c1ab9d0e4"
+		buildinfo.BuildTime = "2025-01-02T15:04:05Z"
+		t.Cleanup(func() {
+			buildinfo.Version = "dev"
+			buildinfo.Commit = ""
+			buildinfo.BuildTime = ""
+		})
+
+		_, body := client.get(t, "/api/version")
+		var info buildinfo.Info
+		require.NoError(t, json.Unmarshal([]byte(body), &info))
+		assert.Equal(t, "v1.2.0", info.Version)
+		assert.Equal(t, "3f2c1ab9d0e4", info.Commit)
+		assert.Equal(t, "2025-01-02T15:04:05Z", info.BuildTime)
+		assert.Equal(t, "v1.2.0 (3f2c1ab, 2025-01-02T15:04:05Z)", info.String())
+	})
+}
@@ -0,0 +1,131 @@
+package e2e
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"htmx-go-app/handlers"
+	"htmx-go-app/webhooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedWebhook struct {
+	event     string
+	signature string
+	body      []byte
+}
+
+func TestWebhooks(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []receivedWebhook
+		failures = 1 // the first game.created request fails to exercise retries
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 && r.Header.Get("X-Webhook-Event") == webhooks.EventGameCreated {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received = append(received, receivedWebhook{
+			event:     r.Header.Get("X-Webhook-Event"),
+			signature: r.Header.Get(webhooks.SignatureHeader),
+			body:      body,
+		})
+	}))
+	t.Cleanup(receiver.Close)
+
+	webhooks.Configure(webhooks.Config{
+		URLs:       []string{receiver.URL},
+		Secret:     "s3cret",
+		RetryDelay: time.Millisecond,
+	})
+	t.Cleanup(func() { webhooks.Configure(webhooks.Config{}) })
+
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+	for _, move := range []struct {
+		player *httpPlayer
+		cell   string
+	}{
+		{playerA, "0/0"}, {playerB, "1/0"},
+		{playerA, "0/1"}, {playerB, "1/1"},
+		{playerA, "0/2"},
+	} {
+		resp, body := move.player.htmxPost(t, "/api/game/"+gameID+"/move/"+move.cell)
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	}
+	webhooks.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	events := map[string]webhooks.Payload{}
+	for _, hook := range received {
+		assert.Equal(t, webhooks.Sign("s3cret", hook.body), hook.signature, "requests are signed")
+
+		var payload webhooks.Payload
+		require.NoError(t, json.Unmarshal(hook.body, &payload))
+		assert.Equal(t, hook.event, payload.Event)
+		if data, ok := payload.Data.(map[string]interface{}); ok && data["gameId"] == gameID {
+			events[payload.Event] = payload
+		}
+	}
+
+	require.Contains(t, events, webhooks.EventGameCreated, "delivered after a retry")
+	require.Contains(t, events, webhooks.EventGameStarted)
+	require.Contains(t, events, webhooks.EventGameFinished)
+	assert.Equal(t, "🐱", events[webhooks.EventGameFinished].Data.(map[string]interface{})["winner"])
+
+	t.Run("delivery log", func(t *testing.T) {
+		handlers.AdminAPIKey.Set(testAdminKey)
+		t.Cleanup(func() { handlers.AdminAPIKey.Set("") })
+
+		resp, _ := playerA.get(t, "/api/admin/webhooks/deliveries")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "the log is for admins")
+		resp, _ = playerA.get(t, "/api/webhooks/deliveries")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, body := adminRequest(t, server, testAdminKey, http.MethodGet, "/api/admin/webhooks/deliveries")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var log struct {
+			Deliveries []webhooks.Delivery `json:"deliveries"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &log))
+		require.Len(t, log.Deliveries, 3)
+
+		var retried webhooks.Delivery
+		for _, delivery := range log.Deliveries {
+			if delivery.Event == webhooks.EventGameCreated {
+				retried = delivery
+			}
+		}
+		assert.Equal(t, receiver.URL, retried.Target, "the receiver's URL has no path to leave out")
+		assert.Equal(t, 2, retried.Attempts)
+		assert.True(t, retried.Delivered)
+		assert.Equal(t, http.StatusOK, retried.StatusCode)
+
+		// Failed or not, a delivery only shows where it went
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		webhooks.Post(closed.URL+"/hooks/s3cret-token?key=s3cret", webhooks.EventBotTurn, nil)
+		webhooks.Wait()
+		_, body = adminRequest(t, server, testAdminKey, http.MethodGet, "/api/admin/webhooks/deliveries")
+		assert.Contains(t, body, `"target":"`+closed.URL+`"`)
+		assert.NotContains(t, body, "s3cret", "the path and query are left out")
+	})
+}
@@ -0,0 +1,88 @@
+package e2e
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventMessageResponse mirrors the JSON events sent over WebSocket and JSON SSE
+type eventMessageResponse struct {
+	Type   string           `json:"type"`
+	GameID string           `json:"gameId"`
+	Game   *apiGameResponse `json:"game"`
+	Error  string           `json:"error"`
+}
+
+func dialGameWebSocket(t *testing.T, player *httpPlayer, gameID string) *websocket.Conn {
+	dialer := websocket.Dialer{Jar: player.client.Jar}
+	url := "ws" + strings.TrimPrefix(player.baseURL, "http") + "/api/game/" + gameID + "/ws"
+	conn, _, err := dialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readWSMessage(t *testing.T, conn *websocket.Conn, messageType string) eventMessageResponse {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		var message eventMessageResponse
+		require.NoError(t, conn.ReadJSON(&message))
+		if message.Type == messageType {
+			return message
+		}
+	}
+}
+
+func TestGameWebSocket(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	t.Cleanup(server.Close)
+
+	gameID, playerA, playerB := startHTTPGame(t, server)
+
+	connA := dialGameWebSocket(t, playerA, gameID)
+	connB := dialGameWebSocket(t, playerB, gameID)
+
+	initial := readWSMessage(t, connA, "initial")
+	require.NotNil(t, initial.Game)
+	assert.Equal(t, gameID, initial.Game.ID)
+	require.NotNil(t, initial.Game.You)
+	assert.True(t, initial.Game.You.YourTurn)
+	readWSMessage(t, connB, "initial")
+
+	t.Run("moves sent over the socket are broadcast", func(t *testing.T) {
+		require.NoError(t, connA.WriteJSON(map[string]interface{}{"type": "move", "row": 1, "col": 1}))
+
+		forA := readWSMessage(t, connA, "move")
+		assert.Equal(t, "🐱", forA.Game.Board[1][1])
+		assert.False(t, forA.Game.You.YourTurn)
+
+		forB := readWSMessage(t, connB, "move")
+		assert.Equal(t, "🐱", forB.Game.Board[1][1])
+		assert.True(t, forB.Game.You.YourTurn)
+	})
+
+	t.Run("rejected moves are reported to the sender", func(t *testing.T) {
+		require.NoError(t, connA.WriteJSON(map[string]interface{}{"type": "move", "row": 0, "col": 0}))
+		message := readWSMessage(t, connA, "error")
+		assert.NotEmpty(t, message.Error)
+
+		require.NoError(t, connA.WriteJSON(map[string]interface{}{"type": "chat"}))
+		message = readWSMessage(t, connA, "error")
+		assert.Contains(t, message.Error, "Unknown message type")
+	})
+
+	t.Run("SSE subscribers see moves made over the socket", func(t *testing.T) {
+		stream := openSSEStream(t, playerA, "/api/game/"+gameID+"/events")
+		readSSEEvent(t, stream, "initial")
+
+		require.NoError(t, connB.WriteJSON(map[string]interface{}{"type": "move", "row": 0, "col": 0}))
+		board := readSSEEvent(t, stream, "move")
+		assert.Contains(t, board, "🚀")
+	})
+}
@@ -0,0 +1,113 @@
+// Package tttest drives games through the game package directly, without a
+// server or browser, so game rules can be covered by fast Go tests:
+//
+//	g, a, b := tttest.StartGame(t)
+//	tttest.Play(t, g, "0/0", "1/0", "0/1", "1/1", "0/2")
+//	if g.Winner != a { ... }
+//
+// Games are removed from the store when the test finishes. Moves made here
+// skip the handlers, so no events are broadcast.
+package tttest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"htmx-go-app/game"
+	"htmx-go-app/models"
+)
+
+// Emojis the two players of StartGame pick
+const (
+	EmojiA = "🐱"
+	EmojiB = "🚀"
+)
+
+// NewGame creates a public game with a new creator who hasn't picked an
+// emoji yet
+func NewGame(t testing.TB) *models.Game {
+	t.Helper()
+	return NewGameWithOptions(t, models.GameOptions{Visibility: models.VisibilityPublic})
+}
+
+// NewGameWithOptions creates a game with the given options
+func NewGameWithOptions(t testing.TB, options models.GameOptions) *models.Game {
+	t.Helper()
+
+	created, err := game.CreateGame(game.GeneratePlayerID(), options)
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+	t.Cleanup(func() { game.DeleteGame(created.ID) })
+	return created
+}
+
+// Join adds a new player with emoji to the game and returns their ID. The
+// first player to join is the creator.
+func Join(t testing.TB, g *models.Game, emoji string) string {
+	t.Helper()
+
+	playerID := g.CreatorID
+	if len(g.Players) > 0 {
+		playerID = game.GeneratePlayerID()
+	}
+	if err := game.AddPlayerToGame(g, playerID, emoji, ""); err != nil {
+		t.Fatalf("join game as %s: %v", emoji, err)
+	}
+	return playerID
+}
+
+// StartGame creates a game and joins two players, returning the game and the
+// IDs of the player who moves first (EmojiA) and second (EmojiB)
+func StartGame(t testing.TB) (*models.Game, string, string) {
+	t.Helper()
+
+	g := NewGame(t)
+	a := Join(t, g, EmojiA)
+	b := Join(t, g, EmojiB)
+	return g, a, b
+}
+
+// Move makes a single move as playerID, returning the game's error for a
+// rejected move. cell is "row/col", counted from 0.
+func Move(t testing.TB, g *models.Game, playerID, cell string) error {
+	t.Helper()
+
+	var row, col int
+	if _, err := fmt.Sscanf(cell, "%d/%d", &row, &col); err != nil {
+		t.Fatalf("move %q: cells are written row/col", cell)
+	}
+	return game.MakeMove(g, playerID, row, col)
+}
+
+// Play makes each move as whichever player's turn it is, failing the test
+// on the first rejected move
+func Play(t testing.TB, g *models.Game, cells ...string) {
+	t.Helper()
+
+	for i, cell := range cells {
+		if err := Move(t, g, game.GetCurrentPlayerID(g), cell); err != nil {
+			t.Fatalf("move %d (%s): %v", i+1, cell, err)
+		}
+	}
+}
+
+// Rows renders the board one string per row, with the players' emojis for
+// their pieces and "." for empty cells, for comparing against an expected
+// layout
+func Rows(g *models.Game) []string {
+	board := game.SymbolBoard(g)
+	rows := make([]string, len(board))
+	for r, cells := range board {
+		var row strings.Builder
+		for _, mark := range cells {
+			if mark == "" {
+				mark = "."
+			}
+			row.WriteString(mark)
+		}
+		rows[r] = row.String()
+	}
+	return rows
+}
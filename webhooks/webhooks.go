@@ -0,0 +1,251 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"sync"
+	"time"
+
+	"htmx-go-app/clock"
+)
+
+// Lifecycle events sent to webhooks
+const (
+	EventGameCreated  = "game.created"
+	EventGameStarted  = "game.started"
+	EventGameFinished = "game.finished"
+	EventBotTurn      = "bot.turn"  // sent only to the bot whose turn it is
+	EventGameTurn     = "game.turn" // a correspondence game is waiting on a player
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, keyed
+// with the configured secret, as "sha256=<hex>"
+const SignatureHeader = "X-Webhook-Signature"
+
+// MaxLoggedDeliveries bounds the in-memory delivery log
+const MaxLoggedDeliveries = 100
+
+// Config lists the URLs that receive lifecycle events
+type Config struct {
+	URLs        []string
+	Secret      string
+	MaxAttempts int           // Attempts per delivery, including the first
+	RetryDelay  time.Duration // Delay before the first retry, doubled after each failure
+	Timeout     time.Duration // Per-attempt request timeout
+}
+
+// Payload is the JSON body of a webhook request
+type Payload struct {
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Delivery records the outcome of sending one event to one URL. Webhook URLs
+// often carry a credential in their path, so only their scheme and host are
+// kept.
+type Delivery struct {
+	Target     string    `json:"target"`
+	Event      string    `json:"event"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Delivered  bool      `json:"delivered"`
+	At         time.Time `json:"at"`
+}
+
+var (
+	mu         sync.Mutex
+	config     Config
+	client     = &http.Client{}
+	deliveries []Delivery
+	pending    sync.WaitGroup
+)
+
+// Configure replaces the webhook configuration. An empty URL list disables webhooks.
+func Configure(c Config) {
+	c = withDefaults(c)
+
+	mu.Lock()
+	defer mu.Unlock()
+	config = c
+	client = &http.Client{Timeout: c.Timeout}
+	deliveries = nil
+}
+
+// withDefaults fills in the retry policy settings left unset
+func withDefaults(c Config) Config {
+	if c.MaxAttempts < 1 {
+		c.MaxAttempts = 3
+	}
+	if c.RetryDelay <= 0 {
+		c.RetryDelay = time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 5 * time.Second
+	}
+	return c
+}
+
+// Notify sends the event to every configured URL in the background
+func Notify(event string, data interface{}) {
+	mu.Lock()
+	c, httpClient := config, client
+	mu.Unlock()
+
+	if len(c.URLs) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(Payload{Event: event, Timestamp: clock.Now().UTC(), Data: data})
+	if err != nil {
+		return
+	}
+
+	for _, url := range c.URLs {
+		pending.Add(1)
+		go func(url string) {
+			defer pending.Done()
+			record(deliver(httpClient, c, url, event, body))
+		}(url)
+	}
+}
+
+// Send delivers one event to a single URL in the background, signed with
+// secret, using the configured retry policy. It is for per-recipient hooks
+// such as bot turn notifications, and works even with no URLs configured.
+func Send(url, secret, event string, data interface{}) {
+	send(url, secret, event, Payload{Event: event, Timestamp: clock.Now().UTC(), Data: data})
+}
+
+// Post delivers body as JSON to url in the background, as is and unsigned,
+// for services that expect their own request format, such as chat webhooks.
+// Deliveries are retried and logged like any other.
+func Post(url, event string, body interface{}) {
+	send(url, "", event, body)
+}
+
+func send(url, secret, event string, payload interface{}) {
+	mu.Lock()
+	c, httpClient := config, client
+	mu.Unlock()
+	c = withDefaults(c)
+	c.Secret = secret
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	pending.Add(1)
+	go func() {
+		defer pending.Done()
+		record(deliver(httpClient, c, url, event, body))
+	}()
+}
+
+// Wait blocks until in-flight deliveries, including retries, have finished
+func Wait() {
+	pending.Wait()
+}
+
+// Deliveries returns the most recent deliveries, newest first
+func Deliveries() []Delivery {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result := make([]Delivery, len(deliveries))
+	for i, delivery := range deliveries {
+		result[len(deliveries)-1-i] = delivery
+	}
+	return result
+}
+
+// redact returns the scheme and host of a webhook URL, leaving out the path,
+// query and user info that may grant access to it
+func redact(rawURL string) string {
+	u, err := neturl.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "(invalid URL)"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// Sign computes the signature header value for a request body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver posts the body to url, retrying with exponential backoff on
+// network errors and non-2xx responses
+func deliver(httpClient *http.Client, c Config, url, event string, body []byte) Delivery {
+	delivery := Delivery{Target: redact(url), Event: event}
+	delay := c.RetryDelay
+
+	for attempt := 1; attempt <= c.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		delivery.Attempts = attempt
+		delivery.At = time.Now()
+
+		statusCode, err := post(httpClient, c.Secret, url, event, body)
+		delivery.StatusCode = statusCode
+		if err == nil {
+			delivery.Delivered = true
+			delivery.Error = ""
+			return delivery
+		}
+		// Client errors quote the URL, so only the cause is kept
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		delivery.Error = err.Error()
+	}
+
+	return delivery
+}
+
+func post(httpClient *http.Client, secret, url, event string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", event)
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func record(delivery Delivery) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	deliveries = append(deliveries, delivery)
+	if len(deliveries) > MaxLoggedDeliveries {
+		deliveries = deliveries[len(deliveries)-MaxLoggedDeliveries:]
+	}
+}